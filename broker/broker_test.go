@@ -31,6 +31,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -364,6 +365,109 @@ func TestBroker(t *testing.T) {
 	log.Debugln("Finished HTTPS client call to the origin server")
 }
 
+// End-to-end test of concurrent connections multiplexed over a single
+// persistent tunnel
+func TestBrokerTunnel(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	ctx, cancel, egrp := test_utils.TestContext(context.Background(), t)
+	defer func() { require.NoError(t, egrp.Wait()) }()
+	defer cancel()
+
+	Setup(t, ctx, egrp)
+	require.NoError(t, param.Transport_BrokerTunnelsPerService.Set(1))
+	require.NoError(t, param.Transport_BrokerTunnelMaxStreams.Set(64))
+	require.NoError(t, param.Transport_BrokerTunnelLifetime.Set(time.Hour))
+
+	engine := setupTestEngine()
+	rootGroup := engine.Group("/")
+	RegisterBroker(ctx, rootGroup)
+	RegisterBrokerCallback(ctx, rootGroup)
+	registry.RegisterRegistryAPI(rootGroup)
+	egrp.Go(func() error {
+		<-ctx.Done()
+		return database.ShutdownDB()
+	})
+	require.NoError(t, runTestEngine(ctx, engine, egrp))
+	require.NoError(t, server_utils.WaitUntilWorking(ctx, "GET", param.Server_ExternalWebUrl.GetString()+"/", "Web UI", http.StatusNotFound, false))
+
+	require.NoError(t, param.Set(param.Federation_BrokerUrl, param.Server_ExternalWebUrl.GetString()))
+	require.NoError(t, param.Set(param.Federation_RegistryUrl, param.Server_ExternalWebUrl.GetString()))
+	externalWebUrl, err := url.Parse(param.Server_ExternalWebUrl.GetString())
+	require.NoError(t, err)
+	servicePrefix := "/caches/" + externalWebUrl.Hostname()
+
+	// The service side serves HTTPS on each listener it receives, as the
+	// origin's broker listener does
+	listenerChan := make(chan any)
+	require.NoError(t, LaunchRequestMonitor(ctx, egrp, server_structs.CacheType, externalWebUrl.Host, servicePrefix, listenerChan))
+	var callbacks atomic.Int32
+	egrp.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case res := <-listenerChan:
+				brokerListener, ok := res.(BrokerListener)
+				if !assert.True(t, ok, "callback failed: %v", res) {
+					continue
+				}
+				callbacks.Add(1)
+				srv := &http.Server{Handler: http.HandlerFunc(getHelloWorldHandler(t))}
+				go func() {
+					_ = srv.Serve(brokerListener.Listener)
+				}()
+				egrp.Go(func() error {
+					<-ctx.Done()
+					return srv.Close()
+				})
+			}
+		}
+	})
+
+	brokerUrl := param.Server_ExternalWebUrl.GetString() + "/api/v1.0/broker/reverse"
+	info := brokerPrefixInfo{ServerType: server_structs.CacheType, BrokerUrl: brokerUrl, Prefix: servicePrefix, Tunnel: true}
+	pool := newTunnelPool()
+	defer pool.Close()
+
+	// Each request is a new connection, so each is a new stream in the tunnel
+	tr := config.GetTransport().Clone()
+	tr.DisableKeepAlives = true
+	tr.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return pool.dial(ctx, addr, info)
+	}
+	client := http.Client{Transport: tr}
+	reqCtx, reqCancel := context.WithTimeout(ctx, 20*time.Second)
+	defer reqCancel()
+	reqGroup, reqCtx := errgroup.WithContext(reqCtx)
+	for idx := 0; idx < 10; idx++ {
+		reqGroup.Go(func() error {
+			req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, "https://"+externalWebUrl.Host+"/", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode != http.StatusOK || string(body) != "Hello world" {
+				return errors.Errorf("unexpected response (status %d): %s", resp.StatusCode, string(body))
+			}
+			return nil
+		})
+	}
+	require.NoError(t, reqGroup.Wait())
+
+	pool.mutex.Lock()
+	assert.Len(t, pool.tunnels[externalWebUrl.Host], 1, "all connections should share a single tunnel")
+	pool.mutex.Unlock()
+	assert.Eventually(t, func() bool { return callbacks.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
 // Ensure the retrieve handler times out
 func TestRetrieveTimeout(t *testing.T) {
 	t.Cleanup(test_utils.SetupTestLogging(t))
@@ -51,6 +51,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 type (
@@ -92,9 +93,15 @@ type (
 
 	// Struct holding pending requests waiting on an origin callback
 	pendingReversals struct {
-		channel chan http.ResponseWriter
+		channel chan reversalCallback
 		prefix  string
 	}
+
+	// An origin's callback, passed from the HTTP handler to the waiting requester
+	reversalCallback struct {
+		writer http.ResponseWriter
+		tunnel bool // Origin agreed to multiplex streams over the connection
+	}
 )
 
 var (
@@ -206,6 +213,15 @@ func generateRequestId() string {
 
 // Given an origin's broker URL, return a connected socket to the origin
 func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string) (conn net.Conn, err error) {
+	conn, _, err = connectToService(ctx, brokerUrl, prefix, originName, false)
+	return
+}
+
+// Connect to the origin through the broker.  If tunnel is set, the origin is
+// asked to keep the connection as a multiplexed tunnel; tunneled reports
+// whether it agreed.  Older origins ignore the request, returning a plain
+// connection.
+func connectToService(ctx context.Context, brokerUrl, prefix, originName string, tunnel bool) (conn net.Conn, tunneled bool, err error) {
 
 	// Ensure we have a local CA for signing an origin host certificate.
 	if err = config.GenerateCACert(); err != nil {
@@ -231,8 +247,9 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 		CallbackUrl: param.Server_ExternalWebUrl.GetString() + "/api/v1.0/broker/callback",
 		OriginName:  originName,
 		Prefix:      prefix,
+		Tunnel:      tunnel,
 	}
-	logFields := log.Fields{"request_id": reqC.RequestId, "origin": originName, "prefix": prefix}
+	logFields := log.Fields{"request_id": reqC.RequestId, "origin": originName, "prefix": prefix, "tunnel": tunnel}
 	reqBytes, err := json.Marshal(&reqC)
 	if err != nil {
 		return
@@ -240,7 +257,7 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 
 	reqReader := strings.NewReader(string(reqBytes))
 
-	responseChannel := make(chan http.ResponseWriter)
+	responseChannel := make(chan reversalCallback)
 	defer close(responseChannel)
 	responseMapLock.Lock()
 	response[reqC.RequestId] = pendingReversals{channel: responseChannel, prefix: prefix}
@@ -255,6 +272,7 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, brokerUrl, reqReader)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "pelican-cache/"+config.GetVersion())
+	tracing.Inject(ctx, req.Header)
 
 	brokerAud, err := url.Parse(brokerUrl)
 	if err != nil {
@@ -305,7 +323,12 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 	if err != nil {
 		return
 	}
+	// The certificate must outlive a tunnel, as each stream performs a new TLS handshake
 	notBefore := time.Now()
+	lifetime := 10 * time.Minute
+	if tunnel {
+		lifetime += param.Transport_BrokerTunnelLifetime.GetDuration()
+	}
 	template := x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
@@ -313,7 +336,7 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 			CommonName:   originName,
 		},
 		NotBefore:             notBefore,
-		NotAfter:              notBefore.Add(10 * time.Minute),
+		NotAfter:              notBefore.Add(lifetime),
 		KeyUsage:              x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
@@ -350,7 +373,9 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 		log.WithFields(logFields).Warn("Request has timed out when waiting for callback from origin")
 		err = errors.Errorf("Timeout when waiting for callback from origin")
 		return
-	case writer := <-responseChannel:
+	case callback := <-responseChannel:
+		writer := callback.writer
+		tunneled = tunnel && callback.tunnel
 		hj, ok := writer.(http.Hijacker)
 		if !ok {
 			log.WithFields(logFields).Error("Not able to hijack underlying TCP connection from server")
@@ -444,7 +469,9 @@ func ConnectToService(ctx context.Context, brokerUrl, prefix, originName string)
 // Callback to a given cache based on the request we got from a broker.
 //
 // The TCP socket used for the callback will be converted to a one-shot listener
-// and reused with the origin as the "server".
+// and reused with the origin as the "server".  If the cache requested a tunnel,
+// the socket instead carries a multiplexed session and the listener accepts
+// each stream the cache opens until the session closes.
 func doCallback(ctx context.Context, sType server_structs.ServerType, brokerResp reversalRequest) (listener net.Listener, err error) {
 	logFields := log.Fields{"request_id": brokerResp.RequestId, "callback_url": brokerResp.CallbackUrl}
 	log.WithFields(logFields).Debug("Origin starting callback to cache")
@@ -453,7 +480,7 @@ func doCallback(ctx context.Context, sType server_structs.ServerType, brokerResp
 	if err != nil {
 		return
 	}
-	callbackReq := callbackRequest{RequestId: brokerResp.RequestId}
+	callbackReq := callbackRequest{RequestId: brokerResp.RequestId, Tunnel: brokerResp.Tunnel}
 	reqBytes, err := json.Marshal(&callbackReq)
 	if err != nil {
 		return
@@ -662,7 +689,12 @@ func doCallback(ctx context.Context, sType server_structs.ServerType, brokerResp
 	}
 
 	hj.realConn = nil
-	listener = tls.NewListener(newOneShotListener(revConn), &tlsConfig)
+	if brokerResp.Tunnel {
+		log.WithFields(logFields).Debug("Origin serving multiplexed tunnel to cache")
+		listener = tls.NewListener(newMuxSession(revConn, false), &tlsConfig)
+	} else {
+		listener = tls.NewListener(newOneShotListener(revConn), &tlsConfig)
+	}
 
 	return
 }
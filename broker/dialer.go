@@ -24,11 +24,14 @@ package broker
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 )
@@ -38,6 +41,7 @@ type (
 		ServerType server_structs.ServerType
 		BrokerUrl  string
 		Prefix     string
+		Tunnel     bool // Service supports persistent reverse tunnels
 	}
 
 	// BrokerDialer is a dialer that can use the broker
@@ -48,6 +52,10 @@ type (
 		// If the service name is not found in the cache, then the dialer
 		// will use a normal TCP connection to the service.
 		brokerEndpoints *ttlcache.Cache[string, brokerPrefixInfo]
+		// Persistent tunnels to services supporting them; nil if tunnels are disabled
+		tunnels *tunnelPool
+		// Idle, already-reversed connections; nil if the pool is disabled
+		pool *reversalPool
 	}
 )
 
@@ -63,27 +71,47 @@ func NewBrokerDialer(ctx context.Context, egrp *errgroup.Group) *BrokerDialer {
 		ttlcache.WithDisableTouchOnHit[string, brokerPrefixInfo](),
 	)
 
+	var tunnels *tunnelPool
+	if param.Transport_EnableBrokerTunnels.GetBool() {
+		tunnels = newTunnelPool()
+	}
+	var pool *reversalPool
+	if param.Transport_BrokerPoolSize.GetInt() > 0 {
+		pool = newReversalPool(ctx)
+		egrp.Go(func() error {
+			pool.maintain()
+			return nil
+		})
+	}
+
 	go brokerEndpoints.Start()
 	egrp.Go(func() error {
 		<-ctx.Done()
 		brokerEndpoints.DeleteAll()
 		brokerEndpoints.Stop()
+		if tunnels != nil {
+			tunnels.Close()
+		}
 		return nil
 	})
 
 	return &BrokerDialer{
 		dialerContext:   dialer.DialContext,
 		brokerEndpoints: brokerEndpoints,
+		tunnels:         tunnels,
+		pool:            pool,
 	}
 }
 
 // Set the dialer to use `brokerUrl` as the broker endpoint for
-// the service `name`.
-func (d *BrokerDialer) UseBroker(serverType server_structs.ServerType, name, brokerUrl, prefix string) {
+// the service `name`.  If `tunnel` is set, the service supports
+// persistent reverse tunnels.
+func (d *BrokerDialer) UseBroker(serverType server_structs.ServerType, name, brokerUrl, prefix string, tunnel bool) {
 	d.brokerEndpoints.Set(name, brokerPrefixInfo{
 		ServerType: serverType,
 		BrokerUrl:  brokerUrl,
 		Prefix:     prefix,
+		Tunnel:     tunnel,
 	}, ttlcache.DefaultTTL)
 }
 
@@ -101,13 +129,41 @@ func (d *BrokerDialer) DialContext(ctx context.Context, network, addr string) (n
 		return d.dialerContext(ctx, network, addr)
 	}
 
+	start := time.Now()
+	if d.tunnels != nil && info.Value().Tunnel {
+		conn, err := d.tunnels.dial(ctx, addr, info.Value())
+		if err == nil {
+			metrics.PelicanBrokerDialWaitTime.WithLabelValues("tunnel").Observe(time.Since(start).Seconds())
+			return conn, nil
+		} else if ctx.Err() != nil {
+			return nil, err
+		}
+		if errors.Is(err, errTunnelsBusy) {
+			log.Debugf("BrokerDialer: All tunnels to %s are busy; falling back to a connection reversal", addr)
+		} else {
+			log.Warningf("BrokerDialer: Failed to open a tunnel to %s via broker at %s; falling back to a connection reversal: %v", addr, info.Value().BrokerUrl, err)
+		}
+	}
+
+	if d.pool != nil {
+		if conn := d.pool.get(addr, info.Value()); conn != nil {
+			log.Debugf("BrokerDialer: Using an already-reversed connection to %s from the pool", addr)
+			metrics.PelicanBrokerDialWaitTime.WithLabelValues("pool").Observe(time.Since(start).Seconds())
+			return conn, nil
+		}
+	}
+
 	log.Debugf("BrokerDialer: Using broker to connect to %s via %s", addr, info.Value().BrokerUrl)
 	conn, err := ConnectToService(ctx, info.Value().BrokerUrl, info.Value().Prefix, addr)
 	if err != nil {
+		if ctx.Err() == nil {
+			metrics.PelicanBrokerReversalFailures.Inc()
+		}
 		log.Errorf("BrokerDialer: Failed to connect to %s via broker at %s: %v", addr, info.Value().BrokerUrl, err)
 		return nil, err
 	} else {
 		log.Debugf("BrokerDialer: Successfully connected to %s via broker at %s", addr, info.Value().BrokerUrl)
 	}
+	metrics.PelicanBrokerDialWaitTime.WithLabelValues("reversal").Observe(time.Since(start).Seconds())
 	return conn, err
 }
@@ -0,0 +1,209 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+// This file contains the pool of idle, already-reversed connections kept
+// by the broker dialer.  A connection reversal requires the service to
+// poll the broker, so a connection taken from the pool avoids waiting on
+// the poll interval.
+
+package broker
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	pooledConn struct {
+		conn    net.Conn
+		created time.Time
+	}
+
+	// The idle connections to a single service
+	servicePool struct {
+		info        brokerPrefixInfo
+		idle        []pooledConn
+		filling     int // Reversals in progress
+		lastUsed    time.Time
+		lastFailure time.Time
+	}
+
+	// A pool of idle, already-reversed connections to the services reached
+	// through the broker, refilled in the background
+	reversalPool struct {
+		ctx      context.Context
+		mutex    sync.Mutex
+		services map[string]*servicePool
+		total    int // Idle connections and reversals in progress across all services
+		closed   bool
+		connect  func(ctx context.Context, brokerUrl, prefix, originName string) (net.Conn, error)
+	}
+)
+
+// Services not connected to within this window are no longer kept warm
+const poolActivityWindow = 5 * time.Minute
+
+func newReversalPool(ctx context.Context) *reversalPool {
+	return &reversalPool{
+		ctx:      ctx,
+		services: make(map[string]*servicePool),
+		connect:  ConnectToService,
+	}
+}
+
+// Take an idle connection to the service from the pool, returning nil if
+// none is available.  Either way, the pool for the service is refilled.
+func (p *reversalPool) get(addr string, info brokerPrefixInfo) (conn net.Conn) {
+	idleTimeout := param.Transport_BrokerPoolIdleTimeout.GetDuration()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed {
+		return nil
+	}
+	sp := p.services[addr]
+	if sp == nil {
+		sp = &servicePool{}
+		p.services[addr] = sp
+	}
+	sp.info = info
+	sp.lastUsed = time.Now()
+	p.expire(sp, idleTimeout)
+	if len(sp.idle) > 0 {
+		// Use the most recently reversed connection
+		conn = sp.idle[len(sp.idle)-1].conn
+		sp.idle = sp.idle[:len(sp.idle)-1]
+		p.total--
+		metrics.PelicanBrokerPoolRequests.WithLabelValues("hit").Inc()
+	} else {
+		metrics.PelicanBrokerPoolRequests.WithLabelValues("miss").Inc()
+	}
+	p.refill(addr, sp)
+	return
+}
+
+// Close the idle connections to the service that are older than the idle
+// timeout.  Must be called with the pool's mutex held.
+func (p *reversalPool) expire(sp *servicePool, idleTimeout time.Duration) {
+	live := sp.idle[:0]
+	for _, pc := range sp.idle {
+		if time.Since(pc.created) > idleTimeout {
+			pc.conn.Close()
+			p.total--
+			continue
+		}
+		live = append(live, pc)
+	}
+	sp.idle = live
+}
+
+// Start reversals until the service's pool is full.  After a failed
+// reversal, the service is not refilled again until the idle timeout has
+// passed.  Must be called with the pool's mutex held.
+func (p *reversalPool) refill(addr string, sp *servicePool) {
+	if time.Since(sp.lastFailure) < param.Transport_BrokerPoolIdleTimeout.GetDuration() {
+		return
+	}
+	perService := param.Transport_BrokerPoolPerService.GetInt()
+	poolSize := param.Transport_BrokerPoolSize.GetInt()
+	for len(sp.idle)+sp.filling < perService && p.total < poolSize {
+		sp.filling++
+		p.total++
+		go p.fill(addr, sp)
+	}
+}
+
+// Perform a single connection reversal, adding the result to the pool
+func (p *reversalPool) fill(addr string, sp *servicePool) {
+	p.mutex.Lock()
+	info := sp.info
+	p.mutex.Unlock()
+
+	conn, err := p.connect(p.ctx, info.BrokerUrl, info.Prefix, addr)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	sp.filling--
+	if err != nil {
+		p.total--
+		sp.lastFailure = time.Now()
+		if p.ctx.Err() == nil {
+			metrics.PelicanBrokerReversalFailures.Inc()
+			log.Debugf("Failed to reverse a connection to %s for the broker connection pool: %v", addr, err)
+		}
+		return
+	}
+	if p.closed || p.services[addr] != sp {
+		p.total--
+		conn.Close()
+		return
+	}
+	sp.idle = append(sp.idle, pooledConn{conn: conn, created: time.Now()})
+}
+
+// Periodically discard stale connections, stop keeping idle services warm,
+// and refill the remaining services
+func (p *reversalPool) maintain() {
+	idleTimeout := param.Transport_BrokerPoolIdleTimeout.GetDuration()
+	ticker := time.NewTicker(max(idleTimeout/3, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			p.Close()
+			return
+		case <-ticker.C:
+		}
+		p.mutex.Lock()
+		for addr, sp := range p.services {
+			p.expire(sp, idleTimeout)
+			if time.Since(sp.lastUsed) > poolActivityWindow {
+				for _, pc := range sp.idle {
+					pc.conn.Close()
+				}
+				p.total -= len(sp.idle)
+				delete(p.services, addr)
+				continue
+			}
+			p.refill(addr, sp)
+		}
+		p.mutex.Unlock()
+	}
+}
+
+// Close all the idle connections in the pool
+func (p *reversalPool) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	for _, sp := range p.services {
+		for _, pc := range sp.idle {
+			pc.conn.Close()
+		}
+		p.total -= len(sp.idle)
+		sp.idle = nil
+	}
+	p.services = make(map[string]*servicePool)
+}
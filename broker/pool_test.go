@@ -0,0 +1,98 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package broker
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+func TestReversalPool(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.Transport_BrokerPoolSize.Set(3))
+	require.NoError(t, param.Transport_BrokerPoolPerService.Set(2))
+	require.NoError(t, param.Transport_BrokerPoolIdleTimeout.Set(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := newReversalPool(ctx)
+	var reversals atomic.Int32
+	var fail atomic.Bool
+	pool.connect = func(_ context.Context, brokerUrl, prefix, originName string) (net.Conn, error) {
+		reversals.Add(1)
+		if fail.Load() {
+			return nil, errors.New("reversal failed")
+		}
+		conn, _ := net.Pipe()
+		return conn, nil
+	}
+	idle := func(addr string) int {
+		pool.mutex.Lock()
+		defer pool.mutex.Unlock()
+		if sp := pool.services[addr]; sp != nil {
+			return len(sp.idle)
+		}
+		return 0
+	}
+	info := brokerPrefixInfo{BrokerUrl: "https://broker.example.com", Prefix: "/origins/origin.example.com"}
+
+	// The first connection misses, then the pool fills to the per-service limit
+	assert.Nil(t, pool.get("origin-a:8443", info))
+	assert.Eventually(t, func() bool { return idle("origin-a:8443") == 2 }, time.Second, time.Millisecond)
+	conn := pool.get("origin-a:8443", info)
+	require.NotNil(t, conn)
+	conn.Close()
+	assert.Eventually(t, func() bool { return idle("origin-a:8443") == 2 }, time.Second, time.Millisecond)
+
+	// The total pool size limits the connections kept for other services
+	assert.Nil(t, pool.get("origin-b:8443", info))
+	assert.Eventually(t, func() bool { return idle("origin-b:8443") == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(4), reversals.Load())
+	pool.mutex.Lock()
+	assert.Equal(t, 3, pool.total)
+	pool.mutex.Unlock()
+
+	// Stale connections are discarded rather than used, and a failed
+	// reversal pauses refilling the service
+	require.NoError(t, param.Transport_BrokerPoolIdleTimeout.Set(time.Nanosecond))
+	fail.Store(true)
+	assert.Nil(t, pool.get("origin-b:8443", info))
+	assert.Eventually(t, func() bool { return reversals.Load() == 5 }, time.Second, time.Millisecond)
+	require.NoError(t, param.Transport_BrokerPoolIdleTimeout.Set(time.Minute))
+	assert.Nil(t, pool.get("origin-b:8443", info))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(5), reversals.Load())
+
+	pool.Close()
+	assert.Nil(t, pool.get("origin-a:8443", info))
+	pool.mutex.Lock()
+	assert.Equal(t, 0, pool.total)
+	pool.mutex.Unlock()
+}
@@ -40,6 +40,7 @@ type (
 		RequestId   string `json:"request_id,omitempty"`
 		Prefix      string `json:"prefix,omitempty"`
 		OriginName  string `json:"origin,omitempty"` // Name of the service for the reversal request.  Originally, brokers were for origins-only (hence the inexact name of the parameter).
+		Tunnel      bool   `json:"tunnel,omitempty"` // Requester would like a persistent, multiplexed tunnel instead of a single connection
 	}
 
 	requestInfo struct {
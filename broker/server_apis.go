@@ -27,10 +27,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 type (
@@ -50,6 +52,7 @@ type (
 	// Structure for an origin calling back to the cache
 	callbackRequest struct {
 		RequestId string `json:"request_id"`
+		Tunnel    bool   `json:"tunnel,omitempty"` // Origin agrees to multiplex streams over the reversed connection
 	}
 )
 
@@ -154,6 +157,7 @@ func reverseRequest(ctx context.Context, ginCtx *gin.Context) {
 		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, newBrokerRespFail("Failed to parse the cache's reversal request"))
 		return
 	}
+	trace.SpanFromContext(ginCtx.Request.Context()).SetAttributes(tracing.RequestID(reversalReq.RequestId))
 	if reversalReq.OriginName == "" {
 		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, newBrokerRespFail("Missing 'origin' parameter in request"))
 		return
@@ -220,8 +224,8 @@ func reverseRequest(ctx context.Context, ginCtx *gin.Context) {
 //     to make a connection.
 func RegisterBroker(ctx context.Context, router *gin.RouterGroup) {
 	// Establish the routes used for cache/origin redirection
-	router.POST("/api/v1.0/broker/retrieve", func(ginCtx *gin.Context) { retrieveRequest(ctx, ginCtx) })
-	router.POST("/api/v1.0/broker/reverse", func(ginCtx *gin.Context) { reverseRequest(ctx, ginCtx) })
+	router.POST("/api/v1.0/broker/retrieve", tracing.Middleware("broker"), func(ginCtx *gin.Context) { retrieveRequest(ctx, ginCtx) })
+	router.POST("/api/v1.0/broker/reverse", tracing.Middleware("broker"), func(ginCtx *gin.Context) { reverseRequest(ctx, ginCtx) })
 }
 
 // Server's HTTP handler function for callbacks from a remote service behind a broker.
@@ -237,6 +241,7 @@ func handleCallback(ctx context.Context, ginCtx *gin.Context) {
 	}
 
 	logFields := log.Fields{"request_id": callbackReq.RequestId, "remote_addr": ginCtx.Request.RemoteAddr}
+	trace.SpanFromContext(ginCtx.Request.Context()).SetAttributes(tracing.RequestID(callbackReq.RequestId))
 
 	token := ginCtx.Request.Header.Get("Authorization")
 	token, hasPrefix := strings.CutPrefix(token, "Bearer ")
@@ -284,7 +289,7 @@ func handleCallback(ctx context.Context, ginCtx *gin.Context) {
 		log.WithFields(logFields).Debug("Cache callback gin context cancelled before passing to handler")
 		ginCtx.AbortWithStatus(http.StatusBadGateway)
 		return
-	case pendingRev.channel <- ginCtx.Writer:
+	case pendingRev.channel <- reversalCallback{writer: ginCtx.Writer, tunnel: callbackReq.Tunnel}:
 		break
 	}
 
@@ -305,5 +310,5 @@ func handleCallback(ctx context.Context, ginCtx *gin.Context) {
 
 // Register the HTTP handlers for the callback to a cache
 func RegisterBrokerCallback(ctx context.Context, router *gin.RouterGroup) {
-	router.POST("/api/v1.0/broker/callback", func(ginCtx *gin.Context) { handleCallback(ctx, ginCtx) })
+	router.POST("/api/v1.0/broker/callback", tracing.Middleware("broker"), func(ginCtx *gin.Context) { handleCallback(ctx, ginCtx) })
 }
@@ -0,0 +1,191 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+// This file contains the pool of persistent reverse tunnels kept by the
+// broker dialer.  Rather than a connection reversal per connection, a
+// tunnel is established once through the broker and each connection is
+// a stream within it.
+
+package broker
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	brokerTunnel struct {
+		session *muxSession
+		created time.Time
+	}
+
+	// The tunnels to each service reached through the broker
+	tunnelPool struct {
+		mutex   sync.Mutex
+		tunnels map[string][]*brokerTunnel
+		dialing map[string]chan struct{} // Closed once an in-progress tunnel establishment finishes
+		closed  bool
+	}
+)
+
+var (
+	errTunnelsBusy = errors.New("all tunnels to the service are busy")
+)
+
+func newTunnelPool() *tunnelPool {
+	return &tunnelPool{
+		tunnels: make(map[string][]*brokerTunnel),
+		dialing: make(map[string]chan struct{}),
+	}
+}
+
+// Close the tunnel once its remaining streams have finished
+func (tunnel *brokerTunnel) retire() {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for tunnel.session.NumStreams() > 0 && !tunnel.session.IsClosed() {
+			<-ticker.C
+		}
+		tunnel.session.Close()
+	}()
+}
+
+// Select the least-loaded tunnel to the service with room for another
+// stream, retiring closed and expired tunnels.  Must be called with the
+// pool's mutex held.
+func (tp *tunnelPool) pick(addr string) (best *brokerTunnel, count int) {
+	lifetime := param.Transport_BrokerTunnelLifetime.GetDuration()
+	maxStreams := param.Transport_BrokerTunnelMaxStreams.GetInt()
+	live := tp.tunnels[addr][:0]
+	bestStreams := 0
+	for _, tunnel := range tp.tunnels[addr] {
+		if tunnel.session.IsClosed() {
+			continue
+		}
+		if lifetime > 0 && time.Since(tunnel.created) > lifetime {
+			log.Debugf("Retiring broker tunnel to %s after %s", addr, lifetime)
+			tunnel.retire()
+			continue
+		}
+		live = append(live, tunnel)
+		streams := tunnel.session.NumStreams()
+		if (maxStreams <= 0 || streams < maxStreams) && (best == nil || streams < bestStreams) {
+			best = tunnel
+			bestStreams = streams
+		}
+	}
+	if len(live) == 0 {
+		delete(tp.tunnels, addr)
+	} else {
+		tp.tunnels[addr] = live
+	}
+	return best, len(live)
+}
+
+// Open a stream to the service, establishing a new tunnel through the broker
+// if needed.  Returns errTunnelsBusy if the service has its maximum number of
+// tunnels and all of them are full.
+//
+// If the service does not support tunnels, the connection from the reversal
+// is returned as-is.
+func (tp *tunnelPool) dial(ctx context.Context, addr string, info brokerPrefixInfo) (net.Conn, error) {
+	for {
+		tp.mutex.Lock()
+		if tp.closed {
+			tp.mutex.Unlock()
+			return nil, net.ErrClosed
+		}
+		tunnel, count := tp.pick(addr)
+		if tunnel != nil {
+			tp.mutex.Unlock()
+			stream, err := tunnel.session.Open()
+			if err != nil {
+				// The tunnel failed; the next pass will skip it
+				continue
+			}
+			return stream, nil
+		}
+		if wait, ok := tp.dialing[addr]; ok {
+			tp.mutex.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-wait:
+			}
+			continue
+		}
+		if count >= max(param.Transport_BrokerTunnelsPerService.GetInt(), 1) {
+			tp.mutex.Unlock()
+			return nil, errTunnelsBusy
+		}
+		done := make(chan struct{})
+		tp.dialing[addr] = done
+		tp.mutex.Unlock()
+
+		log.Debugf("Establishing broker tunnel to %s via %s", addr, info.BrokerUrl)
+		conn, tunneled, err := connectToService(ctx, info.BrokerUrl, info.Prefix, addr, true)
+
+		tp.mutex.Lock()
+		delete(tp.dialing, addr)
+		close(done)
+		if err != nil {
+			tp.mutex.Unlock()
+			return nil, err
+		}
+		if !tunneled {
+			tp.mutex.Unlock()
+			log.Debugf("Service %s declined the broker tunnel; using the reversed connection directly", addr)
+			return conn, nil
+		}
+		tunnel = &brokerTunnel{session: newMuxSession(conn, true), created: time.Now()}
+		if tp.closed {
+			tp.mutex.Unlock()
+			tunnel.session.Close()
+			return nil, net.ErrClosed
+		}
+		tp.tunnels[addr] = append(tp.tunnels[addr], tunnel)
+		tp.mutex.Unlock()
+		log.Debugf("Established broker tunnel to %s", addr)
+		stream, err := tunnel.session.Open()
+		if err != nil {
+			return nil, err
+		}
+		return stream, nil
+	}
+}
+
+// Close all the tunnels in the pool
+func (tp *tunnelPool) Close() {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+	tp.closed = true
+	for _, tunnels := range tp.tunnels {
+		for _, tunnel := range tunnels {
+			tunnel.session.Close()
+		}
+	}
+	tp.tunnels = make(map[string][]*brokerTunnel)
+}
@@ -0,0 +1,542 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+// This file contains a minimal stream multiplexer, used to carry many
+// logical connections over a single reversed TCP connection.
+//
+// Each frame has a 9-byte header (type, stream ID, payload length) followed
+// by the payload.  Streams opened by the dialing side have odd IDs and those
+// opened by the listening side have even IDs.  Each stream has a receive
+// window; the sender may not have more than a window of unacknowledged data
+// outstanding, so a slow reader never blocks the other streams.
+
+package broker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	muxFrameType uint8
+
+	// A multiplexed session over a single connection.  The session
+	// implements net.Listener, accepting the streams opened by the remote side.
+	muxSession struct {
+		conn        net.Conn
+		writeMutex  sync.Mutex
+		mutex       sync.Mutex
+		streams     map[uint32]*muxStream
+		nextID      uint32
+		accept      chan *muxStream
+		done        chan struct{}
+		closeOnce   sync.Once
+		lastRecv    atomic.Int64
+		idleTimeout time.Duration
+	}
+
+	// A logical connection within a session
+	muxStream struct {
+		session *muxSession
+		id      uint32
+
+		mutex         sync.Mutex
+		recvBuf       bytes.Buffer
+		recvWindow    uint32 // Bytes the remote side may still send
+		consumed      uint32 // Bytes read but not yet returned to the remote's window
+		sendWindow    uint32 // Bytes we may still send
+		recvClosed    bool   // The remote side will send no more data
+		closed        bool   // Closed locally
+		reset         bool   // Aborted by either side
+		readDeadline  time.Time
+		writeDeadline time.Time
+		readNotify    chan struct{}
+		writeNotify   chan struct{}
+	}
+)
+
+const (
+	muxFrameData   muxFrameType = iota // Payload for the stream
+	muxFrameOpen                       // Open a new stream
+	muxFrameClose                      // The sender will send no more data on the stream
+	muxFrameReset                      // Abort the stream
+	muxFrameWindow                     // Grow the receiver's send window by the 4-byte payload
+	muxFramePing                       // Keepalive; answered with a pong
+	muxFramePong
+
+	muxHeaderSize      = 9
+	muxMaxPayload      = 32 * 1024
+	muxWindowSize      = 256 * 1024
+	muxAcceptBacklog   = 128
+	muxKeepaliveTicker = 30 * time.Second
+)
+
+var (
+	errStreamReset = errors.New("tunnel stream was reset")
+)
+
+// Create a new session over the connection.  The side that dialed the
+// underlying connection is the client.
+func newMuxSession(conn net.Conn, client bool) *muxSession {
+	session := &muxSession{
+		conn:        conn,
+		streams:     make(map[uint32]*muxStream),
+		accept:      make(chan *muxStream, muxAcceptBacklog),
+		done:        make(chan struct{}),
+		idleTimeout: 3 * muxKeepaliveTicker,
+	}
+	if client {
+		session.nextID = 1
+	} else {
+		session.nextID = 2
+	}
+	session.lastRecv.Store(time.Now().UnixNano())
+	go session.recvLoop()
+	go session.keepalive(muxKeepaliveTicker)
+	return session
+}
+
+func (s *muxSession) writeFrame(frameType muxFrameType, id uint32, payload []byte) error {
+	buf := make([]byte, muxHeaderSize+len(payload))
+	buf[0] = byte(frameType)
+	binary.BigEndian.PutUint32(buf[1:5], id)
+	binary.BigEndian.PutUint32(buf[5:9], uint32(len(payload)))
+	copy(buf[muxHeaderSize:], payload)
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	if s.IsClosed() {
+		return net.ErrClosed
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		go s.Close()
+		return errors.Wrap(err, "failed to write to tunnel")
+	}
+	return nil
+}
+
+// Send a control frame without blocking the caller
+func (s *muxSession) writeFrameAsync(frameType muxFrameType, id uint32, payload []byte) {
+	go func() {
+		_ = s.writeFrame(frameType, id, payload)
+	}()
+}
+
+func (s *muxSession) newStream(id uint32) *muxStream {
+	return &muxStream{
+		session:     s,
+		id:          id,
+		recvWindow:  muxWindowSize,
+		sendWindow:  muxWindowSize,
+		readNotify:  make(chan struct{}, 1),
+		writeNotify: make(chan struct{}, 1),
+	}
+}
+
+// Open a new stream to the remote side
+func (s *muxSession) Open() (*muxStream, error) {
+	s.mutex.Lock()
+	if s.IsClosed() {
+		s.mutex.Unlock()
+		return nil, net.ErrClosed
+	}
+	stream := s.newStream(s.nextID)
+	s.nextID += 2
+	s.streams[stream.id] = stream
+	s.mutex.Unlock()
+
+	if err := s.writeFrame(muxFrameOpen, stream.id, nil); err != nil {
+		s.removeStream(stream.id)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Accept the next stream opened by the remote side
+func (s *muxSession) Accept() (net.Conn, error) {
+	select {
+	case stream := <-s.accept:
+		return stream, nil
+	case <-s.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (s *muxSession) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Close the session and the underlying connection, aborting all its streams
+func (s *muxSession) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.conn.Close()
+		s.mutex.Lock()
+		streams := s.streams
+		s.streams = make(map[uint32]*muxStream)
+		s.mutex.Unlock()
+		for _, stream := range streams {
+			stream.notify()
+		}
+	})
+	return err
+}
+
+func (s *muxSession) IsClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Return the number of open streams in the session
+func (s *muxSession) NumStreams() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.streams)
+}
+
+func (s *muxSession) getStream(id uint32) *muxStream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.streams[id]
+}
+
+func (s *muxSession) removeStream(id uint32) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.streams, id)
+}
+
+// Periodically ping the remote side, closing the session if the remote
+// side has gone silent
+func (s *muxSession) keepalive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, s.lastRecv.Load())) > s.idleTimeout {
+				log.Warningf("Closing broker tunnel to %s; no keepalive received in %s", s.conn.RemoteAddr(), s.idleTimeout)
+				s.Close()
+				return
+			}
+			_ = s.writeFrame(muxFramePing, 0, nil)
+		}
+	}
+}
+
+func (s *muxSession) recvLoop() {
+	defer s.Close()
+	header := make([]byte, muxHeaderSize)
+	for {
+		if _, err := io.ReadFull(s.conn, header); err != nil {
+			if !s.IsClosed() && !errors.Is(err, io.EOF) {
+				log.Debugf("Broker tunnel to %s failed: %v", s.conn.RemoteAddr(), err)
+			}
+			return
+		}
+		frameType := muxFrameType(header[0])
+		id := binary.BigEndian.Uint32(header[1:5])
+		length := binary.BigEndian.Uint32(header[5:9])
+		if length > muxMaxPayload {
+			log.Warningf("Closing broker tunnel to %s; frame of %d bytes exceeds the maximum", s.conn.RemoteAddr(), length)
+			return
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(s.conn, payload); err != nil {
+			return
+		}
+		s.lastRecv.Store(time.Now().UnixNano())
+
+		switch frameType {
+		case muxFrameOpen:
+			s.mutex.Lock()
+			if _, exists := s.streams[id]; exists || s.IsClosed() {
+				s.mutex.Unlock()
+				s.writeFrameAsync(muxFrameReset, id, nil)
+				continue
+			}
+			stream := s.newStream(id)
+			s.streams[id] = stream
+			s.mutex.Unlock()
+			select {
+			case s.accept <- stream:
+			default:
+				log.Warningf("Broker tunnel to %s has too many pending streams; rejecting stream", s.conn.RemoteAddr())
+				s.removeStream(id)
+				s.writeFrameAsync(muxFrameReset, id, nil)
+			}
+		case muxFrameData:
+			stream := s.getStream(id)
+			if stream == nil {
+				s.writeFrameAsync(muxFrameReset, id, nil)
+				continue
+			}
+			stream.receive(payload)
+		case muxFrameClose:
+			if stream := s.getStream(id); stream != nil {
+				stream.remoteClose()
+			}
+		case muxFrameReset:
+			if stream := s.getStream(id); stream != nil {
+				stream.abort()
+			}
+		case muxFrameWindow:
+			if stream := s.getStream(id); stream != nil && len(payload) == 4 {
+				stream.grow(binary.BigEndian.Uint32(payload))
+			}
+		case muxFramePing:
+			s.writeFrameAsync(muxFramePong, 0, nil)
+		case muxFramePong:
+		default:
+			log.Warningf("Closing broker tunnel to %s; unknown frame type %d", s.conn.RemoteAddr(), frameType)
+			return
+		}
+	}
+}
+
+// Wake any goroutines blocked on the stream
+func (st *muxStream) notify() {
+	select {
+	case st.readNotify <- struct{}{}:
+	default:
+	}
+	select {
+	case st.writeNotify <- struct{}{}:
+	default:
+	}
+}
+
+func (st *muxStream) receive(payload []byte) {
+	st.mutex.Lock()
+	if st.closed || st.reset || uint32(len(payload)) > st.recvWindow {
+		// Data for a closed stream or beyond the window aborts the stream
+		st.reset = true
+		st.mutex.Unlock()
+		st.session.removeStream(st.id)
+		st.session.writeFrameAsync(muxFrameReset, st.id, nil)
+		st.notify()
+		return
+	}
+	st.recvWindow -= uint32(len(payload))
+	st.recvBuf.Write(payload)
+	st.mutex.Unlock()
+	st.notify()
+}
+
+func (st *muxStream) remoteClose() {
+	st.mutex.Lock()
+	st.recvClosed = true
+	done := st.closed
+	st.mutex.Unlock()
+	if done {
+		st.session.removeStream(st.id)
+	}
+	st.notify()
+}
+
+func (st *muxStream) abort() {
+	st.mutex.Lock()
+	st.reset = true
+	st.mutex.Unlock()
+	st.session.removeStream(st.id)
+	st.notify()
+}
+
+func (st *muxStream) grow(increment uint32) {
+	st.mutex.Lock()
+	st.sendWindow += increment
+	st.mutex.Unlock()
+	st.notify()
+}
+
+// Wait for a notification on the channel, the session's closure, or the deadline
+func (st *muxStream) wait(notify chan struct{}, deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(remaining)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-notify:
+		return nil
+	case <-st.session.done:
+		// Drain any data that arrived before the session closed
+		st.mutex.Lock()
+		pending := st.recvBuf.Len() > 0
+		st.mutex.Unlock()
+		if pending && notify == st.readNotify {
+			return nil
+		}
+		return net.ErrClosed
+	case <-timeout:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+func (st *muxStream) Read(b []byte) (n int, err error) {
+	for {
+		st.mutex.Lock()
+		if st.closed {
+			st.mutex.Unlock()
+			return 0, net.ErrClosed
+		}
+		if st.recvBuf.Len() > 0 {
+			n, _ = st.recvBuf.Read(b)
+			st.consumed += uint32(n)
+			var increment uint32
+			if st.consumed >= muxWindowSize/2 {
+				increment = st.consumed
+				st.recvWindow += increment
+				st.consumed = 0
+			}
+			st.mutex.Unlock()
+			if increment > 0 {
+				payload := make([]byte, 4)
+				binary.BigEndian.PutUint32(payload, increment)
+				if err = st.session.writeFrame(muxFrameWindow, st.id, payload); err != nil {
+					return
+				}
+			}
+			return
+		}
+		if st.reset {
+			st.mutex.Unlock()
+			return 0, errStreamReset
+		}
+		if st.recvClosed {
+			st.mutex.Unlock()
+			return 0, io.EOF
+		}
+		deadline := st.readDeadline
+		st.mutex.Unlock()
+		if err = st.wait(st.readNotify, deadline); err != nil {
+			return
+		}
+	}
+}
+
+func (st *muxStream) Write(b []byte) (n int, err error) {
+	for len(b) > 0 {
+		st.mutex.Lock()
+		if st.reset {
+			st.mutex.Unlock()
+			return n, errStreamReset
+		}
+		if st.closed {
+			st.mutex.Unlock()
+			return n, net.ErrClosed
+		}
+		if st.sendWindow == 0 {
+			deadline := st.writeDeadline
+			st.mutex.Unlock()
+			if err = st.wait(st.writeNotify, deadline); err != nil {
+				return
+			}
+			continue
+		}
+		chunk := min(len(b), int(st.sendWindow), muxMaxPayload)
+		st.sendWindow -= uint32(chunk)
+		st.mutex.Unlock()
+
+		if err = st.session.writeFrame(muxFrameData, st.id, b[:chunk]); err != nil {
+			return
+		}
+		n += chunk
+		b = b[chunk:]
+	}
+	return
+}
+
+// Close the stream; the remote side reads an EOF once it has consumed the
+// data already sent
+func (st *muxStream) Close() error {
+	st.mutex.Lock()
+	if st.closed {
+		st.mutex.Unlock()
+		return nil
+	}
+	st.closed = true
+	sendClose := !st.reset
+	done := st.recvClosed || st.reset
+	st.mutex.Unlock()
+
+	if done {
+		st.session.removeStream(st.id)
+	}
+	st.notify()
+	if sendClose {
+		if err := st.session.writeFrame(muxFrameClose, st.id, nil); err != nil && !errors.Is(err, net.ErrClosed) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (st *muxStream) LocalAddr() net.Addr {
+	return st.session.conn.LocalAddr()
+}
+
+func (st *muxStream) RemoteAddr() net.Addr {
+	return st.session.conn.RemoteAddr()
+}
+
+func (st *muxStream) SetDeadline(t time.Time) error {
+	st.mutex.Lock()
+	st.readDeadline = t
+	st.writeDeadline = t
+	st.mutex.Unlock()
+	st.notify()
+	return nil
+}
+
+func (st *muxStream) SetReadDeadline(t time.Time) error {
+	st.mutex.Lock()
+	st.readDeadline = t
+	st.mutex.Unlock()
+	st.notify()
+	return nil
+}
+
+func (st *muxStream) SetWriteDeadline(t time.Time) error {
+	st.mutex.Lock()
+	st.writeDeadline = t
+	st.mutex.Unlock()
+	st.notify()
+	return nil
+}
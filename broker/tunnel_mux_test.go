@@ -0,0 +1,148 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package broker
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Return a client and server session over a loopback TCP connection
+func newMuxSessionPair(t *testing.T) (client, server *muxSession) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		assert.NoError(t, err)
+		accepted <- conn
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	client = newMuxSession(conn, true)
+	server = newMuxSession(<-accepted, false)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return
+}
+
+func TestMuxSession(t *testing.T) {
+	client, server := newMuxSessionPair(t)
+
+	// The server echoes each stream back to the client
+	go func() {
+		for {
+			conn, err := server.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	// Each stream carries several windows' worth of data concurrently
+	var wg sync.WaitGroup
+	for idx := 0; idx < 8; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream, err := client.Open()
+			if !assert.NoError(t, err) {
+				return
+			}
+			payload := make([]byte, 4*muxWindowSize+123)
+			_, err = rand.Read(payload)
+			assert.NoError(t, err)
+			go func() {
+				_, err := stream.Write(payload)
+				assert.NoError(t, err)
+			}()
+			echoed := make([]byte, len(payload))
+			_, err = io.ReadFull(stream, echoed)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(payload, echoed))
+			assert.NoError(t, stream.Close())
+		}()
+	}
+	wg.Wait()
+	assert.Eventually(t, func() bool { return client.NumStreams() == 0 && server.NumStreams() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// Client and server streams have distinct IDs
+	stream, err := client.Open()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), stream.id%2)
+
+	// Reads respect deadlines
+	require.NoError(t, stream.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err = stream.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Closing the session aborts its streams and the listener
+	require.NoError(t, stream.SetReadDeadline(time.Time{}))
+	require.NoError(t, client.Close())
+	_, err = stream.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, net.ErrClosed)
+	_, err = client.Open()
+	assert.ErrorIs(t, err, net.ErrClosed)
+	assert.Eventually(t, server.IsClosed, 5*time.Second, 10*time.Millisecond)
+	_, err = server.Accept()
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestMuxStreamClose(t *testing.T) {
+	client, server := newMuxSessionPair(t)
+
+	stream, err := client.Open()
+	require.NoError(t, err)
+	_, err = stream.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+
+	// Data sent before the close is delivered before the EOF
+	conn, err := server.Accept()
+	require.NoError(t, err)
+	contents, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(contents))
+
+	// Writing to a stream closed by the remote side resets it
+	_, err = conn.Write([]byte("ignored"))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err := conn.Write([]byte("ignored"))
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return client.NumStreams() == 0 && server.NumStreams() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
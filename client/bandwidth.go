@@ -0,0 +1,104 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"io"
+)
+
+type (
+	// A BandwidthLimiter throttles the data moved by a transfer job.
+	//
+	// WaitN blocks until n more bytes may be transferred, or returns an error
+	// if the context is cancelled first.  golang.org/x/time/rate.Limiter
+	// satisfies the interface.
+	BandwidthLimiter interface {
+		WaitN(ctx context.Context, n int) error
+	}
+
+	// A writer that charges every byte written to a bandwidth limiter
+	throttledWriter struct {
+		ctx     context.Context
+		dest    io.Writer
+		limiter BandwidthLimiter
+	}
+
+	// A reader that charges every byte read to a bandwidth limiter
+	throttledReader struct {
+		ctx     context.Context
+		src     io.Reader
+		limiter BandwidthLimiter
+	}
+)
+
+// The largest number of bytes charged to a limiter at once, so a single
+// large buffer cannot hold up other transfers sharing the limiter
+const throttleChunkSize = 32 * 1024
+
+// Return the limiter throttling the transfer, if any
+func (transfer *transferFile) bandwidthLimiter() BandwidthLimiter {
+	if transfer.job == nil {
+		return nil
+	}
+	return transfer.job.bandwidthLimiter
+}
+
+func newThrottledWriter(ctx context.Context, dest io.Writer, limiter BandwidthLimiter) *throttledWriter {
+	return &throttledWriter{ctx: ctx, dest: dest, limiter: limiter}
+}
+
+// Write waits for the limiter before writing each chunk of p
+func (tw *throttledWriter) Write(p []byte) (written int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > throttleChunkSize {
+			chunk = chunk[:throttleChunkSize]
+		}
+		if err = tw.limiter.WaitN(tw.ctx, len(chunk)); err != nil {
+			return
+		}
+		var n int
+		n, err = tw.dest.Write(chunk)
+		written += n
+		if err != nil {
+			return
+		}
+		p = p[n:]
+	}
+	return
+}
+
+func newThrottledReader(ctx context.Context, src io.Reader, limiter BandwidthLimiter) *throttledReader {
+	return &throttledReader{ctx: ctx, src: src, limiter: limiter}
+}
+
+// Read charges the bytes read after the fact so short reads are not over-billed
+func (tr *throttledReader) Read(p []byte) (n int, err error) {
+	if len(p) > throttleChunkSize {
+		p = p[:throttleChunkSize]
+	}
+	n, err = tr.src.Read(p)
+	if n > 0 {
+		if waitErr := tr.limiter.WaitN(tr.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return
+}
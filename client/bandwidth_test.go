@@ -0,0 +1,100 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLimiter records the requests made of it and fails once its budget
+// is exhausted
+type countingLimiter struct {
+	mu       sync.Mutex
+	requests []int
+	budget   int
+}
+
+func (l *countingLimiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n > l.budget {
+		return errors.New("over budget")
+	}
+	l.budget -= n
+	l.requests = append(l.requests, n)
+	return nil
+}
+
+func (l *countingLimiter) total() (total int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, n := range l.requests {
+		total += n
+	}
+	return
+}
+
+func TestThrottledWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 3*throttleChunkSize+100)
+
+	limiter := &countingLimiter{budget: len(data)}
+	var dest bytes.Buffer
+	n, err := newThrottledWriter(context.Background(), &dest, limiter).Write(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, data, dest.Bytes())
+
+	// Large writes are charged in chunks
+	assert.Equal(t, []int{throttleChunkSize, throttleChunkSize, throttleChunkSize, 100}, limiter.requests)
+
+	// Nothing is written once the limiter fails
+	limiter = &countingLimiter{budget: throttleChunkSize}
+	dest.Reset()
+	n, err = newThrottledWriter(context.Background(), &dest, limiter).Write(data)
+	assert.Error(t, err)
+	assert.Equal(t, throttleChunkSize, n)
+	assert.Equal(t, throttleChunkSize, dest.Len())
+}
+
+func TestThrottledReader(t *testing.T) {
+	data := bytes.Repeat([]byte("b"), 2*throttleChunkSize+100)
+
+	limiter := &countingLimiter{budget: len(data)}
+	read, err := io.ReadAll(newThrottledReader(context.Background(), bytes.NewReader(data), limiter))
+	require.NoError(t, err)
+	assert.Equal(t, data, read)
+
+	// Every byte read is charged, no more than a chunk at a time
+	assert.Equal(t, len(data), limiter.total())
+	for _, n := range limiter.requests {
+		assert.LessOrEqual(t, n, throttleChunkSize)
+	}
+
+	limiter = &countingLimiter{budget: throttleChunkSize}
+	_, err = io.ReadAll(newThrottledReader(context.Background(), bytes.NewReader(data), limiter))
+	assert.Error(t, err)
+}
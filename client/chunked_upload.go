@@ -0,0 +1,287 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/features"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+type (
+	// A file being uploaded to the origin in chunks
+	chunkedUpload struct {
+		request   *http.Request // The single-PUT request, used as a template and fallback
+		file      *os.File
+		size      int64
+		chunkSize int64
+		id        string
+		progress  *atomic.Int64    // Bytes sent, as reported to the progress callback
+		limiter   BandwidthLimiter // Shared by the chunks in flight; nil if unlimited
+		client    *http.Client
+
+		hashes    []io.Writer
+		hashTypes []ChecksumType
+	}
+
+	// Counts the bytes of a chunk as they are sent
+	chunkProgressReader struct {
+		reader   io.Reader
+		progress *atomic.Int64
+		sent     int64
+	}
+)
+
+// How many times each chunk is sent before the upload is abandoned
+const chunkedUploadTries = 3
+
+var errChunkedUploadUnsupported = errors.New("origin does not support chunked uploads")
+
+func (cr *chunkProgressReader) Read(p []byte) (n int, err error) {
+	n, err = cr.reader.Read(p)
+	cr.sent += int64(n)
+	cr.progress.Add(int64(n))
+	return
+}
+
+// Return the chunk size to use if the file should be uploaded in chunks, or
+// 0 if it should be sent in a single request.
+//
+// Encrypted uploads are always sent in a single request: every attempt
+// encrypts the file under a fresh random salt, so chunks staged at the
+// origin by an earlier attempt could not be combined with new ones.
+func chunkedUploadChunkSize(transfer *transferFile, size int64) int64 {
+	threshold := int64(param.Client_ChunkedUploadThreshold.GetInt())
+	chunkSize := int64(param.Client_ChunkedUploadChunkSize.GetInt())
+	if threshold <= 0 || chunkSize <= 0 || size < threshold || transfer.packOption != "" || transfer.reader != nil {
+		return 0
+	}
+	if transfer.encryptionKey() != nil {
+		log.Infof("Uploading %s in a single request: encrypted uploads cannot be sent in chunks", transfer.localPath)
+		return 0
+	}
+	// Stay within the number of chunks an origin accepts
+	if minChunkSize := (size + server_structs.ChunkedUploadMaxChunks - 1) / server_structs.ChunkedUploadMaxChunks; chunkSize < minChunkSize {
+		chunkSize = minChunkSize
+	}
+	return chunkSize
+}
+
+// Derive the upload ID from the destination and the state of the local file
+// so a later attempt to upload the same file picks up the staged chunks.
+func chunkedUploadID(destPath string, file *os.File, chunkSize int64) (string, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d\x00%d", destPath, info.Size(), info.ModTime().UnixNano(), chunkSize)))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// Return a request to the object's URL with the given query parameters set and
+// the headers of the single-PUT request
+func (cu *chunkedUpload) newRequest(ctx context.Context, method string, params map[string]string, body io.Reader) (*http.Request, error) {
+	reqUrl := *cu.request.URL
+	query := reqUrl.Query()
+	query.Del("oss.asize")
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	reqUrl.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = cu.request.Header.Clone()
+	return req, nil
+}
+
+// Open (or reopen) the upload at the origin, returning the chunks it already has
+func (cu *chunkedUpload) open(ctx context.Context) (*server_structs.ChunkedUploadSession, error) {
+	body, err := json.Marshal(server_structs.ChunkedUploadRequest{ID: cu.id, Size: cu.size, ChunkSize: cu.chunkSize})
+	if err != nil {
+		return nil, err
+	}
+	req, err := cu.newRequest(ctx, http.MethodPost, map[string]string{server_structs.ChunkedUploadQuery: server_structs.ChunkedUploadCreate}, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := cu.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	server := resp.Header.Get("Server")
+	if !strings.HasPrefix(server, "pelican/") || resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(errChunkedUploadUnsupported, "server %q responded with status %d", server, resp.StatusCode)
+	}
+	ad := server_structs.ServerAd{}
+	ad.Type = server_structs.OriginType.String()
+	ad.Version = strings.TrimPrefix(server, "pelican/")
+	if features.ServerSupportsFeature(features.ChunkedUpload, ad) == utils.Tern_False {
+		return nil, errors.Wrapf(errChunkedUploadUnsupported, "origin version %s", ad.Version)
+	}
+	session := &server_structs.ChunkedUploadSession{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(session); err != nil || session.ID != cu.id {
+		return nil, errors.Wrap(errChunkedUploadUnsupported, "unexpected response to chunked upload request")
+	}
+	return session, nil
+}
+
+// Send a single chunk, retrying a few times before giving up
+func (cu *chunkedUpload) sendChunk(ctx context.Context, idx int) (err error) {
+	start := int64(idx) * cu.chunkSize
+	length := min(cu.chunkSize, cu.size-start)
+	params := map[string]string{
+		server_structs.ChunkedUploadQuery:      cu.id,
+		server_structs.ChunkedUploadChunkQuery: strconv.Itoa(idx),
+	}
+	for try := 0; try < chunkedUploadTries; try++ {
+		var section io.Reader = io.NewSectionReader(cu.file, start, length)
+		if cu.limiter != nil {
+			section = newThrottledReader(ctx, section, cu.limiter)
+		}
+		reader := &chunkProgressReader{reader: section, progress: cu.progress}
+		var req *http.Request
+		if req, err = cu.newRequest(ctx, http.MethodPut, params, reader); err != nil {
+			return
+		}
+		req.ContentLength = length
+		var resp *http.Response
+		if resp, err = cu.client.Do(req); err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+				// Count whatever the transport didn't report through the reader
+				cu.progress.Add(length - reader.sent)
+				return nil
+			}
+			sce := StatusCodeError(resp.StatusCode)
+			err = &HttpErrResp{resp.StatusCode, fmt.Sprintf("chunk %d upload failed (HTTP status %d)", idx, resp.StatusCode), wrapStatusCodeError(&sce)}
+		}
+		cu.progress.Add(-reader.sent)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Debugf("Failed to upload chunk %d of %s (attempt %d of %d): %v", idx, cu.request.URL.Path, try+1, chunkedUploadTries, err)
+	}
+	return
+}
+
+// Build the Digest header for the commit request from the whole-file checksums
+func (cu *chunkedUpload) digestHeader() string {
+	digests := make([]string, 0, len(cu.hashTypes))
+	for idx, checksumType := range cu.hashTypes {
+		digests = append(digests, HttpDigestFromChecksum(checksumType)+"="+checksumValueToHttpDigest(checksumType, cu.hashes[idx].(hash.Hash).Sum(nil)))
+	}
+	return strings.Join(digests, ",")
+}
+
+// Upload the file in chunks, falling back to a single PUT if the origin
+// doesn't support chunked uploads.  Like runPut, the outcome is reported on
+// responseChan (the response to the final request) or errorChan.
+func runChunkedPut(cu *chunkedUpload, responseChan chan<- *http.Response, errorChan chan<- error, proxy bool) {
+	ctx := cu.request.Context()
+	cu.client = uploadClient(proxy)
+	session, err := cu.open(ctx)
+	if err != nil {
+		log.Debugf("Uploading %s in a single request: %v", cu.request.URL.Path, err)
+		runPut(cu.request, responseChan, errorChan, proxy)
+		return
+	}
+	sendErr := func(err error) {
+		errorChan <- err
+		close(errorChan)
+	}
+
+	received := make(map[int]bool, len(session.Received))
+	for _, idx := range session.Received {
+		received[idx] = true
+		cu.progress.Add(min(cu.chunkSize, cu.size-int64(idx)*cu.chunkSize))
+	}
+	chunks := server_structs.ChunkedUploadChunkCount(cu.size, cu.chunkSize)
+	log.Debugf("Uploading %s in %d chunks (%d already at the origin)", cu.request.URL.Path, chunks, len(received))
+
+	// The whole-file checksums are computed while the chunks are being sent
+	hashDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.MultiWriter(cu.hashes...), io.NewSectionReader(cu.file, 0, cu.size))
+		hashDone <- err
+	}()
+
+	egrp, egrpCtx := errgroup.WithContext(ctx)
+	egrp.SetLimit(max(param.Client_ChunkedUploadStreams.GetInt(), 1))
+	for idx := 0; idx < chunks; idx++ {
+		if received[idx] {
+			continue
+		}
+		egrp.Go(func() error {
+			return cu.sendChunk(egrpCtx, idx)
+		})
+	}
+	err = egrp.Wait()
+	if hashErr := <-hashDone; err == nil && hashErr != nil {
+		err = errors.Wrap(hashErr, "failed to compute checksum of file")
+	}
+	if err != nil {
+		sendErr(err)
+		return
+	}
+
+	req, err := cu.newRequest(ctx, http.MethodPost, map[string]string{
+		server_structs.ChunkedUploadQuery:       cu.id,
+		server_structs.ChunkedUploadCommitQuery: "true",
+	}, http.NoBody)
+	if err != nil {
+		sendErr(err)
+		return
+	}
+	req.Header.Set("Digest", cu.digestHeader())
+	resp, err := cu.client.Do(req)
+	if err != nil {
+		sendErr(err)
+		return
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		textResponse, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		log.Errorf("Failed to complete chunked upload of %s: %s %s", cu.request.URL.Path, resp.Status, string(textResponse))
+	}
+	responseChan <- resp
+}
@@ -0,0 +1,224 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// A minimal origin implementing the chunked upload protocol
+type chunkedTestOrigin struct {
+	mu         sync.Mutex
+	chunked    bool
+	session    *server_structs.ChunkedUploadRequest
+	chunks     map[int][]byte
+	failChunks map[int]int // Number of times to fail each chunk
+	puts       []string    // Query of each PUT received
+	object     []byte
+}
+
+func (o *chunkedTestOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	query := r.URL.Query()
+	if o.chunked {
+		w.Header().Set("Server", "pelican/7.25.0")
+	}
+	switch {
+	case r.Method == http.MethodHead || r.Method == "PROPFIND":
+		if o.object == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.object)))
+		w.Header().Set("Digest", fmt.Sprintf("crc32c=%08x", crc32.Checksum(o.object, crc32cTable)))
+	case r.Method == http.MethodPost && o.chunked && query.Get(server_structs.ChunkedUploadQuery) == server_structs.ChunkedUploadCreate:
+		req := server_structs.ChunkedUploadRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if o.session == nil || *o.session != req {
+			o.session = &req
+			o.chunks = map[int][]byte{}
+		}
+		session := server_structs.ChunkedUploadSession{ID: req.ID, Size: req.Size, ChunkSize: req.ChunkSize,
+			Chunks: server_structs.ChunkedUploadChunkCount(req.Size, req.ChunkSize), Received: []int{}}
+		for idx := range o.chunks {
+			session.Received = append(session.Received, idx)
+		}
+		_ = json.NewEncoder(w).Encode(session)
+	case r.Method == http.MethodPut && query.Has(server_structs.ChunkedUploadChunkQuery):
+		o.puts = append(o.puts, r.URL.RawQuery)
+		idx, _ := strconv.Atoi(query.Get(server_structs.ChunkedUploadChunkQuery))
+		body, _ := io.ReadAll(r.Body)
+		if o.failChunks[idx] > 0 {
+			o.failChunks[idx]--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		o.chunks[idx] = body
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && query.Get(server_structs.ChunkedUploadCommitQuery) == "true":
+		var assembled []byte
+		for idx := 0; idx < server_structs.ChunkedUploadChunkCount(o.session.Size, o.session.ChunkSize); idx++ {
+			assembled = append(assembled, o.chunks[idx]...)
+		}
+		if !strings.Contains(r.Header.Get("Digest"), fmt.Sprintf("crc32c=%08x", crc32.Checksum(assembled, crc32cTable))) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		o.object = assembled
+		o.session = nil
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut:
+		o.puts = append(o.puts, r.URL.RawQuery)
+		o.object, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestChunkedUpload(t *testing.T) {
+	test_utils.InitClient(t, map[param.Param]any{
+		param.Client_ChunkedUploadThreshold: 1024,
+		param.Client_ChunkedUploadChunkSize: 4096,
+		param.Client_ChunkedUploadStreams:   2,
+		param.Client_EnableOverwrites:       true,
+	})
+
+	content := make([]byte, 5*4096+100)
+	for idx := range content {
+		content[idx] = byte(idx * 13)
+	}
+	localPath := filepath.Join(t.TempDir(), "obj")
+	require.NoError(t, os.WriteFile(localPath, content, 0644))
+
+	upload := func(t *testing.T, origin *chunkedTestOrigin, limiter BandwidthLimiter) (TransferResults, error) {
+		server := httptest.NewServer(origin)
+		t.Cleanup(server.Close)
+		serverURL, err := url.Parse(server.URL + "/test/obj")
+		require.NoError(t, err)
+		transfer := &transferFile{
+			ctx: context.Background(),
+			job: &TransferJob{
+				requireChecksum:  true,
+				bandwidthLimiter: limiter,
+				remoteURL:        &pelican_url.PelicanURL{Scheme: "pelican://", Host: serverURL.Host, Path: "/test/obj"},
+			},
+			localPath:       localPath,
+			remoteURL:       serverURL,
+			requireChecksum: true,
+			attempts:        []transferAttemptDetails{{Url: serverURL}},
+		}
+		results, err := uploadObject(transfer)
+		if err == nil {
+			err = results.Error
+		}
+		return results, err
+	}
+
+	t.Run("resume-missing-chunks", func(t *testing.T) {
+		origin := &chunkedTestOrigin{chunked: true, failChunks: map[int]int{3: chunkedUploadTries}}
+		_, err := upload(t, origin, nil)
+		require.Error(t, err)
+		origin.mu.Lock()
+		assert.Nil(t, origin.object)
+		assert.Len(t, origin.chunks, 5)
+		origin.puts = nil
+		origin.mu.Unlock()
+
+		// Only the chunk that failed is sent again
+		results, err := upload(t, origin, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), results.TransferredBytes)
+		origin.mu.Lock()
+		defer origin.mu.Unlock()
+		assert.Equal(t, content, origin.object)
+		require.Len(t, origin.puts, 1)
+		assert.Contains(t, origin.puts[0], server_structs.ChunkedUploadChunkQuery+"=3")
+	})
+
+	t.Run("retry-chunk", func(t *testing.T) {
+		origin := &chunkedTestOrigin{chunked: true, failChunks: map[int]int{0: 1, 5: 1}}
+		_, err := upload(t, origin, nil)
+		require.NoError(t, err)
+		origin.mu.Lock()
+		defer origin.mu.Unlock()
+		assert.Equal(t, content, origin.object)
+		assert.Len(t, origin.puts, 8)
+	})
+
+	t.Run("bandwidth-limited", func(t *testing.T) {
+		// The chunks in flight share the job's limiter
+		limiter := &countingLimiter{budget: len(content)}
+		origin := &chunkedTestOrigin{chunked: true}
+		_, err := upload(t, origin, limiter)
+		require.NoError(t, err)
+		assert.Equal(t, len(content), limiter.total())
+		origin.mu.Lock()
+		defer origin.mu.Unlock()
+		assert.Equal(t, content, origin.object)
+		assert.Len(t, origin.puts, 6)
+	})
+
+	t.Run("fallback-single-put", func(t *testing.T) {
+		origin := &chunkedTestOrigin{}
+		_, err := upload(t, origin, nil)
+		require.NoError(t, err)
+		origin.mu.Lock()
+		defer origin.mu.Unlock()
+		assert.Equal(t, content, origin.object)
+		require.Len(t, origin.puts, 1)
+		assert.NotContains(t, origin.puts[0], server_structs.ChunkedUploadQuery)
+	})
+
+	t.Run("chunk-size", func(t *testing.T) {
+		assert.Zero(t, chunkedUploadChunkSize(&transferFile{}, 5))
+		assert.Equal(t, int64(4096), chunkedUploadChunkSize(&transferFile{}, int64(len(content))))
+		assert.Zero(t, chunkedUploadChunkSize(&transferFile{packOption: "tar"}, int64(len(content))))
+		// Encrypted uploads are never split
+		encrypted := &transferFile{job: &TransferJob{encryptionKey: &EncryptionKey{symmetric: make([]byte, 32)}}}
+		assert.Zero(t, chunkedUploadChunkSize(encrypted, int64(len(content))))
+		// Very large files use bigger chunks to stay within the origin's limit
+		assert.Equal(t, int64(10000), chunkedUploadChunkSize(&transferFile{}, 10000*server_structs.ChunkedUploadMaxChunks))
+	})
+}
@@ -34,12 +34,14 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/error_codes"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
@@ -77,6 +79,19 @@ func fromPelican(resp *http.Response) bool {
 // redirects to origins instead of caches.  This is the correct behaviour
 // when the caller is itself an embedded cache.
 func queryDirector(ctx context.Context, verb string, pUrl *pelican_url.PelicanURL, token string, cacheMode bool) (resp *http.Response, redirectBody string, err error) {
+	ctx, span := tracing.StartClient(ctx, "client.queryDirector",
+		attribute.String("http.request.method", verb), attribute.String("pelican.object", pUrl.Path))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			// The director reports the request ID it assigned
+			if jobId := resp.Header.Get("X-Pelican-JobId"); jobId != "" {
+				span.SetAttributes(tracing.RequestID(jobId))
+			}
+		}
+		tracing.End(span, err)
+	}()
+
 	resourceUrl, err := url.Parse(pUrl.FedInfo.DirectorEndpoint)
 	if err != nil {
 		log.Errorln("Failed to parse the director URL:", err)
@@ -123,6 +138,7 @@ func queryDirector(ctx context.Context, verb string, pUrl *pelican_url.PelicanUR
 		if token != "" {
 			req.Header.Set("Authorization", "Bearer "+token)
 		}
+		tracing.Inject(ctx, req.Header)
 
 		forceDebug, _ := ctx.Value(directorDebugCtxKey{}).(bool)
 		if log.IsLevelEnabled(log.DebugLevel) || forceDebug {
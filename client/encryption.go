@@ -0,0 +1,384 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// Client-side encryption of objects
+//
+// An encrypted object starts with a header:
+//
+//	magic (8 bytes) | mode (1 byte) | salt (32 bytes) [| ephemeral X25519 public key (32 bytes)]
+//
+// followed by the data split into segments of encryptionSegmentSize bytes,
+// each sealed with NaCl secretbox.  The secretbox key and base nonce are
+// derived with HKDF-SHA256 from the salt and either the shared symmetric key
+// or the X25519 shared secret with the recipient's key.  Each segment's nonce
+// is the base nonce XOR'd with the segment number, and the final segment is
+// marked by flipping the last nonce byte, so truncating, reordering or
+// extending the object causes decryption to fail.
+
+type (
+	// A key used to encrypt objects on upload and decrypt them on download
+	//
+	// A key is either a shared symmetric key, used for both directions, or an
+	// X25519 key pair.  Objects are encrypted to a recipient's public key and
+	// decrypted with the matching private key.
+	EncryptionKey struct {
+		symmetric []byte
+		private   *ecdh.PrivateKey
+		public    *ecdh.PublicKey
+	}
+
+	// Encrypts the data read from the underlying reader
+	encryptingReader struct {
+		src     io.Reader
+		key     [32]byte
+		nonce   [24]byte
+		counter uint64
+		plain   []byte // One segment plus a byte of lookahead to detect the final segment
+		filled  int
+		sealed  []byte
+		out     []byte // The part of the header or sealed segment not yet read
+		done    bool
+	}
+
+	// Decrypts the data written to it, passing through data that isn't encrypted
+	decryptingWriter struct {
+		dest        io.Writer
+		key         *EncryptionKey
+		header      bool
+		passthrough bool
+		closed      bool
+		secret      [32]byte
+		nonce       [24]byte
+		counter     uint64
+		buf         []byte
+		plain       []byte
+	}
+)
+
+const (
+	encryptionSegmentSize = 64 * 1024
+	encryptionModeKey     = byte(1)
+	encryptionModeX25519  = byte(2)
+	encryptionSaltSize    = 32
+)
+
+var encryptionMagic = []byte("PLCNENC1")
+
+// Load an encryption key from a file
+//
+// The file may contain a symmetric key as 32 base64-encoded random bytes
+// (e.g., the output of `openssl rand -base64 32`), or a PEM-encoded X25519
+// private key (`openssl genpkey -algorithm X25519`) or public key
+// (`openssl pkey -pubout`).  A public key can only be used to encrypt.
+func LoadEncryptionKey(keyFile string) (*EncryptionKey, error) {
+	contents, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read encryption key file")
+	}
+	if block, _ := pem.Decode(contents); block != nil {
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse private key in %s", keyFile)
+			}
+			private, ok := parsed.(*ecdh.PrivateKey)
+			if !ok || private.Curve() != ecdh.X25519() {
+				return nil, errors.Errorf("private key in %s is not an X25519 key", keyFile)
+			}
+			return &EncryptionKey{private: private, public: private.PublicKey()}, nil
+		case "PUBLIC KEY":
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse public key in %s", keyFile)
+			}
+			public, ok := parsed.(*ecdh.PublicKey)
+			if !ok || public.Curve() != ecdh.X25519() {
+				return nil, errors.Errorf("public key in %s is not an X25519 key", keyFile)
+			}
+			return &EncryptionKey{public: public}, nil
+		default:
+			return nil, errors.Errorf("unsupported PEM block %q in %s", block.Type, keyFile)
+		}
+	}
+	symmetric, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil || len(symmetric) != 32 {
+		return nil, errors.Errorf("%s does not contain a PEM-encoded X25519 key or a base64-encoded 32-byte key", keyFile)
+	}
+	return &EncryptionKey{symmetric: symmetric}, nil
+}
+
+// Return the key used to encrypt uploads and decrypt downloads, if any
+func (transfer *transferFile) encryptionKey() *EncryptionKey {
+	if transfer.job == nil {
+		return nil
+	}
+	return transfer.job.encryptionKey
+}
+
+// Derive the secretbox key and base nonce for an object
+func deriveObjectKey(ikm, salt []byte, mode byte) (key [32]byte, nonce [24]byte, err error) {
+	reader := hkdf.New(sha256.New, ikm, salt, append([]byte("pelican-e2e"), mode))
+	if _, err = io.ReadFull(reader, key[:]); err != nil {
+		return
+	}
+	_, err = io.ReadFull(reader, nonce[:])
+	return
+}
+
+// Return the nonce for the given segment
+func segmentNonce(base [24]byte, counter uint64, last bool) *[24]byte {
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], counter)
+	for idx := range ctr {
+		base[idx] ^= ctr[idx]
+	}
+	if last {
+		base[23] ^= 1
+	}
+	return &base
+}
+
+// Return the size of an object after encrypting size bytes with the key
+func (key *EncryptionKey) encryptedSize(size int64) int64 {
+	header := int64(len(encryptionMagic) + 1 + encryptionSaltSize)
+	if key.symmetric == nil {
+		header += 32
+	}
+	segments := max((size+encryptionSegmentSize-1)/encryptionSegmentSize, 1)
+	return header + size + segments*secretbox.Overhead
+}
+
+// Return a reader encrypting the contents of src, starting with the header
+func newEncryptingReader(src io.Reader, key *EncryptionKey) (*encryptingReader, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	header := append(append([]byte{}, encryptionMagic...), encryptionModeKey)
+	ikm := key.symmetric
+	if key.symmetric == nil {
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate ephemeral key")
+		}
+		shared, err := ephemeral.ECDH(key.public)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to compute shared secret")
+		}
+		header[len(encryptionMagic)] = encryptionModeX25519
+		salt = append(salt, ephemeral.PublicKey().Bytes()...)
+		ikm = append(append(shared, ephemeral.PublicKey().Bytes()...), key.public.Bytes()...)
+	}
+	er := &encryptingReader{
+		src:   src,
+		plain: make([]byte, encryptionSegmentSize+1),
+		out:   append(header, salt...),
+	}
+	var err error
+	if er.key, er.nonce, err = deriveObjectKey(ikm, salt[:encryptionSaltSize], header[len(encryptionMagic)]); err != nil {
+		return nil, errors.Wrap(err, "failed to derive object key")
+	}
+	return er, nil
+}
+
+func (er *encryptingReader) Read(p []byte) (n int, err error) {
+	for len(er.out) == 0 {
+		if er.done {
+			return 0, io.EOF
+		}
+		if err = er.seal(); err != nil {
+			return
+		}
+	}
+	n = copy(p, er.out)
+	er.out = er.out[n:]
+	return
+}
+
+// Encrypt the next segment of the source
+func (er *encryptingReader) seal() error {
+	n, err := io.ReadFull(er.src, er.plain[er.filled:])
+	n += er.filled
+	last := false
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		last = true
+	} else if err != nil {
+		return err
+	}
+	er.sealed = secretbox.Seal(er.sealed[:0], er.plain[:min(n, encryptionSegmentSize)], segmentNonce(er.nonce, er.counter, last), &er.key)
+	er.out = er.sealed
+	er.counter++
+	if last {
+		er.done = true
+	} else {
+		er.plain[0] = er.plain[encryptionSegmentSize]
+		er.filled = 1
+	}
+	return nil
+}
+
+// Return a writer decrypting encrypted objects into dest
+//
+// Objects without the encryption header are written to dest unchanged.  The
+// final segment is only decrypted, and its authenticity checked, on Close.
+func newDecryptingWriter(dest io.Writer, key *EncryptionKey) *decryptingWriter {
+	return &decryptingWriter{dest: dest, key: key}
+}
+
+// Parse the header once enough of it has been buffered, returning false if
+// more data is needed
+func (dw *decryptingWriter) parseHeader() (bool, error) {
+	if !bytes.HasPrefix(dw.buf, encryptionMagic) {
+		if len(dw.buf) < len(encryptionMagic) && bytes.HasPrefix(encryptionMagic, dw.buf) {
+			return false, nil
+		}
+		log.Debugln("Downloaded object is not encrypted; writing it unchanged")
+		dw.passthrough = true
+		return true, nil
+	}
+	headerLen := len(encryptionMagic) + 1 + encryptionSaltSize
+	if len(dw.buf) < headerLen {
+		return false, nil
+	}
+	mode := dw.buf[len(encryptionMagic)]
+	salt := dw.buf[len(encryptionMagic)+1 : headerLen]
+	var ikm []byte
+	switch mode {
+	case encryptionModeKey:
+		if dw.key.symmetric == nil {
+			return false, errors.New("object was encrypted with a symmetric key but an X25519 key was provided")
+		}
+		ikm = dw.key.symmetric
+	case encryptionModeX25519:
+		headerLen += 32
+		if len(dw.buf) < headerLen {
+			return false, nil
+		}
+		if dw.key.private == nil {
+			return false, errors.New("object was encrypted to an X25519 public key; the matching private key is required to decrypt it")
+		}
+		ephemeral, err := ecdh.X25519().NewPublicKey(dw.buf[headerLen-32 : headerLen])
+		if err != nil {
+			return false, errors.Wrap(err, "invalid ephemeral key in encrypted object")
+		}
+		shared, err := dw.key.private.ECDH(ephemeral)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to compute shared secret")
+		}
+		ikm = append(append(shared, ephemeral.Bytes()...), dw.key.public.Bytes()...)
+	default:
+		return false, errors.Errorf("unsupported encryption mode %d", mode)
+	}
+	var err error
+	if dw.secret, dw.nonce, err = deriveObjectKey(ikm, salt, mode); err != nil {
+		return false, errors.Wrap(err, "failed to derive object key")
+	}
+	dw.buf = append([]byte(nil), dw.buf[headerLen:]...)
+	return true, nil
+}
+
+// Decrypt one segment and write it to the destination
+func (dw *decryptingWriter) open(segment []byte, last bool) error {
+	var ok bool
+	dw.plain, ok = secretbox.Open(dw.plain[:0], segment, segmentNonce(dw.nonce, dw.counter, last), &dw.secret)
+	if !ok {
+		return errors.Errorf("failed to decrypt segment %d of object: wrong key or corrupted data", dw.counter)
+	}
+	dw.counter++
+	_, err := dw.dest.Write(dw.plain)
+	return err
+}
+
+func (dw *decryptingWriter) Write(p []byte) (n int, err error) {
+	if dw.passthrough {
+		return dw.dest.Write(p)
+	}
+	dw.buf = append(dw.buf, p...)
+	if !dw.header {
+		if dw.header, err = dw.parseHeader(); err != nil {
+			return 0, err
+		} else if !dw.header {
+			return len(p), nil
+		}
+		if dw.passthrough {
+			buffered := dw.buf
+			dw.buf = nil
+			if _, err = dw.dest.Write(buffered); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	// A full segment can only be decrypted once the next byte arrives, which
+	// shows it isn't the final one
+	const sealedSize = encryptionSegmentSize + secretbox.Overhead
+	consumed := 0
+	for len(dw.buf)-consumed > sealedSize {
+		if err = dw.open(dw.buf[consumed:consumed+sealedSize], false); err != nil {
+			return 0, err
+		}
+		consumed += sealedSize
+	}
+	if consumed > 0 {
+		dw.buf = append(dw.buf[:0], dw.buf[consumed:]...)
+	}
+	return len(p), nil
+}
+
+// Decrypt the final segment, failing if the object was truncated
+func (dw *decryptingWriter) Close() error {
+	if dw.passthrough || dw.closed {
+		return nil
+	}
+	dw.closed = true
+	if !dw.header {
+		if bytes.HasPrefix(dw.buf, encryptionMagic) {
+			return errors.New("encrypted object is truncated")
+		}
+		// Too short to be encrypted
+		_, err := dw.dest.Write(dw.buf)
+		return err
+	}
+	if err := dw.open(dw.buf, true); err != nil {
+		return errors.Wrap(err, "encrypted object is incomplete or corrupted")
+	}
+	dw.buf = nil
+	return nil
+}
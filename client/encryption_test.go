@@ -0,0 +1,263 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// Write the keys used by the tests, returning the paths of the symmetric key,
+// the X25519 private key and its public key
+func writeTestEncryptionKeys(t *testing.T) (symmetric, private, public string) {
+	dir := t.TempDir()
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	require.NoError(t, err)
+	symmetric = filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(symmetric, []byte(base64.StdEncoding.EncodeToString(raw)+"\n"), 0600))
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	private = filepath.Join(dir, "private.pem")
+	require.NoError(t, os.WriteFile(private, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	der, err = x509.MarshalPKIXPublicKey(key.PublicKey())
+	require.NoError(t, err)
+	public = filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(public, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return
+}
+
+// Encrypt the data with the key
+func encryptTestData(t *testing.T, key *EncryptionKey, data []byte) []byte {
+	reader, err := newEncryptingReader(bytes.NewReader(data), key)
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return encrypted
+}
+
+// Decrypt the data with the key, writing it in pieces of the given size
+func decryptTestData(key *EncryptionKey, data []byte, writeSize int) ([]byte, error) {
+	out := &bytes.Buffer{}
+	writer := newDecryptingWriter(out, key)
+	for len(data) > 0 {
+		n := min(writeSize, len(data))
+		if _, err := writer.Write(data[:n]); err != nil {
+			return nil, err
+		}
+		data = data[n:]
+	}
+	err := writer.Close()
+	return append([]byte{}, out.Bytes()...), err
+}
+
+func TestEncryption(t *testing.T) {
+	symmetricPath, privatePath, publicPath := writeTestEncryptionKeys(t)
+	symmetric, err := LoadEncryptionKey(symmetricPath)
+	require.NoError(t, err)
+	private, err := LoadEncryptionKey(privatePath)
+	require.NoError(t, err)
+	public, err := LoadEncryptionKey(publicPath)
+	require.NoError(t, err)
+
+	t.Run("round-trip", func(t *testing.T) {
+		for _, size := range []int{0, 1, encryptionSegmentSize - 1, encryptionSegmentSize, encryptionSegmentSize + 1, 3*encryptionSegmentSize + 5} {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+			for _, keys := range [][2]*EncryptionKey{{symmetric, symmetric}, {public, private}} {
+				encrypted := encryptTestData(t, keys[0], data)
+				assert.Equal(t, keys[0].encryptedSize(int64(size)), int64(len(encrypted)))
+				if size > 16 {
+					assert.NotContains(t, string(encrypted), string(data))
+				}
+				for _, writeSize := range []int{7, 32 * 1024, len(encrypted) + 1} {
+					decrypted, err := decryptTestData(keys[1], encrypted, writeSize)
+					require.NoError(t, err, "size %d, write size %d", size, writeSize)
+					assert.Equal(t, data, decrypted)
+				}
+			}
+		}
+	})
+
+	t.Run("plain-object-unchanged", func(t *testing.T) {
+		for _, data := range [][]byte{[]byte("hello, world"), encryptionMagic[:3], {}} {
+			decrypted, err := decryptTestData(symmetric, data, 2)
+			require.NoError(t, err)
+			assert.Equal(t, data, decrypted)
+		}
+	})
+
+	t.Run("tampering-detected", func(t *testing.T) {
+		data := make([]byte, 2*encryptionSegmentSize+10)
+		encrypted := encryptTestData(t, symmetric, data)
+
+		// Truncated at a segment boundary
+		headerLen := int(symmetric.encryptedSize(0)) - 16
+		_, err := decryptTestData(symmetric, encrypted[:headerLen+encryptionSegmentSize+16], 4096)
+		assert.Error(t, err)
+
+		flipped := bytes.Clone(encrypted)
+		flipped[len(flipped)/2] ^= 1
+		_, err = decryptTestData(symmetric, flipped, 4096)
+		assert.Error(t, err)
+
+		_, err = decryptTestData(symmetric, encrypted[:len(encryptionMagic)+4], 4096)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong-key", func(t *testing.T) {
+		other, err := LoadEncryptionKey(symmetricPath)
+		require.NoError(t, err)
+		other.symmetric = bytes.Repeat([]byte{1}, 32)
+		_, err = decryptTestData(other, encryptTestData(t, symmetric, []byte("secret")), 4096)
+		assert.ErrorContains(t, err, "wrong key")
+
+		// Only the private key can decrypt objects encrypted to a public key
+		_, err = decryptTestData(public, encryptTestData(t, public, []byte("secret")), 4096)
+		assert.ErrorContains(t, err, "private key is required")
+		_, err = decryptTestData(private, encryptTestData(t, symmetric, []byte("secret")), 4096)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid-key-file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(path, []byte("not a key"), 0600))
+		_, err := LoadEncryptionKey(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("encrypted-upload", func(t *testing.T) {
+		test_utils.InitClient(t, map[param.Param]any{
+			param.Client_ChunkedUploadThreshold: 1024,
+			param.Client_ChunkedUploadChunkSize: 4096,
+			param.Client_EnableOverwrites:       true,
+		})
+		content := bytes.Repeat([]byte("pelican"), 3000)
+		localPath := filepath.Join(t.TempDir(), "obj")
+		require.NoError(t, os.WriteFile(localPath, content, 0644))
+
+		origin := &chunkedTestOrigin{chunked: true}
+		server := httptest.NewServer(origin)
+		t.Cleanup(server.Close)
+		serverURL, err := url.Parse(server.URL + "/test/obj")
+		require.NoError(t, err)
+		limiter := &countingLimiter{budget: len(content)}
+		transfer := &transferFile{
+			ctx: context.Background(),
+			job: &TransferJob{
+				requireChecksum:  true,
+				remoteURL:        &pelican_url.PelicanURL{Scheme: "pelican://", Host: serverURL.Host, Path: "/test/obj"},
+				encryptionKey:    public,
+				bandwidthLimiter: limiter,
+			},
+			localPath:       localPath,
+			remoteURL:       serverURL,
+			requireChecksum: true,
+			attempts:        []transferAttemptDetails{{Url: serverURL}},
+		}
+		results, err := uploadObject(transfer)
+		require.NoError(t, err)
+		require.NoError(t, results.Error)
+
+		origin.mu.Lock()
+		defer origin.mu.Unlock()
+		// Encrypted uploads are sent in a single request
+		require.Len(t, origin.puts, 1)
+		assert.Contains(t, origin.puts[0], "oss.asize="+strconv.FormatInt(public.encryptedSize(int64(len(content))), 10))
+		assert.Equal(t, public.encryptedSize(int64(len(content))), int64(len(origin.object)))
+		decrypted, err := decryptTestData(private, origin.object, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, content, decrypted)
+
+		// Encryption does not bypass the bandwidth limit
+		assert.Equal(t, len(content), limiter.total())
+	})
+	t.Run("encrypted-download", func(t *testing.T) {
+		test_utils.InitClient(t, nil)
+		require.NoError(t, param.Client_ParallelDownloadThreshold.Set(1024))
+		content := bytes.Repeat([]byte("federation"), 20000)
+		download := func(t *testing.T, object []byte, key *EncryptionKey) ([]byte, error) {
+			server := httptest.NewServer(&parallelTestServer{content: object, etag: `"v1"`})
+			t.Cleanup(server.Close)
+			serverURL, err := url.Parse(server.URL + "/test/obj")
+			require.NoError(t, err)
+			localPath := filepath.Join(t.TempDir(), "obj")
+			transfer := &transferFile{
+				xferType: transferTypeDownload,
+				ctx:      context.Background(),
+				job: &TransferJob{
+					remoteURL:       &pelican_url.PelicanURL{Scheme: "pelican://", Host: serverURL.Host, Path: "/test/obj"},
+					parallelStreams: 4,
+					encryptionKey:   key,
+				},
+				localPath:       localPath,
+				remoteURL:       serverURL,
+				requireChecksum: true,
+				attempts:        []transferAttemptDetails{{Url: serverURL}},
+			}
+			results, err := downloadObject(transfer)
+			if err == nil {
+				err = results.Error
+			}
+			if err != nil {
+				return nil, err
+			}
+			return os.ReadFile(localPath)
+		}
+
+		encrypted := encryptTestData(t, symmetric, content)
+		got, err := download(t, encrypted, symmetric)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+
+		// Objects that aren't encrypted are downloaded unchanged
+		got, err = download(t, content, symmetric)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+
+		// A corrupted object fails the download, even though its checksum matches
+		corrupted := bytes.Clone(encrypted)
+		corrupted[len(corrupted)-1] ^= 1
+		_, err = download(t, corrupted, symmetric)
+		assert.Error(t, err)
+	})
+}
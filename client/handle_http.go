@@ -55,6 +55,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/studio-b12/gowebdav"
 	"github.com/vbauerster/mpb/v8"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/time/rate"
@@ -64,6 +65,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 var (
@@ -233,6 +235,20 @@ type (
 		// the server must not be sorted after any non-preferred (director-provided)
 		// server, even if the origin/cache service responds more quickly.
 		Preferred bool
+
+		// ETag of the object whose partial download is being resumed.  When set, a
+		// ranged request is sent with If-Range and is abandoned, before any data is
+		// written, if the server no longer has the same version of the object.
+		ResumeETag string
+
+		// Set when the object is decrypted as it is downloaded, so the size of
+		// the local file differs from the size of the object
+		Decrypting bool
+
+		// Set for the byte ranges of a parallel download, which are written at
+		// their offsets: unless the server answers with exactly the requested
+		// range, the response is abandoned before any data is written.
+		ExactRange bool
 	}
 
 	// A structure representing a single file to transfer.
@@ -282,8 +298,10 @@ type (
 		requireChecksum    bool
 		recursive          bool
 		skipAcquire        bool
-		dryRun             bool       // Enable dry-run mode to display what would be transferred without actually doing it
-		syncLevel          SyncLevel  // Policy for handling synchronization when the destination exists
+		dryRun             bool      // Enable dry-run mode to display what would be transferred without actually doing it
+		syncLevel          SyncLevel // Policy for handling synchronization when the destination exists
+		deleteExtraneous   bool      // Remove destination objects not present at the source after a recursive transfer
+		syncSource         syncSourceSet
 		prefObjServers     []*url.URL // holds any client-requested caches/origins
 		dirResp            server_structs.DirectorResponse
 		directorUrl        string
@@ -294,6 +312,10 @@ type (
 		writer             io.WriteCloser          // Optional writer for downloads - if set, write to this instead of localPath
 		reader             io.ReadCloser           // Optional reader for uploads - if set, read from this instead of localPath
 		inPlace            bool                    // If true, write directly to final destination; if false, use temporary file
+		resume             bool                    // If true, keep failed downloads in a partial file that later runs can resume
+		parallelStreams    int                     // Number of concurrent range requests used for large downloads
+		encryptionKey      *EncryptionKey          // If set, encrypt uploads and decrypt downloads with this key
+		bandwidthLimiter   BandwidthLimiter        // If set, throttle the data moved by the job's transfers
 		forcePrestageAPI   bool                    // If true, force use of prestage API and error if not supported (no fallback)
 		byteRange          *ByteRange              // Optional byte range for partial downloads
 		metadataChan       chan<- TransferMetadata // Optional channel to receive early transfer metadata
@@ -357,6 +379,7 @@ type (
 		fedToken       TokenProvider // Federation token; sent as access_token query param to origins (not to the director)
 		cacheMode      bool          // When true, the client queries the director's origin endpoint (/api/v1.0/director/origin/)
 		dryRun         bool          // Enable dry-run mode to display what would be transferred without actually doing it
+		deleteExtra    bool          // Remove destination objects not present at the source after recursive transfers
 		work           chan *TransferJob
 		closed         bool
 		closeOnce      sync.Once
@@ -381,12 +404,18 @@ type (
 	identTransferOptionWriter                  struct{}
 	identTransferOptionReader                  struct{}
 	identTransferOptionInPlace                 struct{}
+	identTransferOptionResume                  struct{}
+	identTransferOptionParallelStreams         struct{}
+	identTransferOptionEncryptionKey           struct{}
+	identTransferOptionThirdPartyCopy          struct{}
 	identTransferOptionDryRun                  struct{}
+	identTransferOptionDeleteExtraneous        struct{}
 	identTransferOptionForcePrestageAPI        struct{}
 	identTransferOptionByteRange               struct{}
 	identTransferOptionMetadataChannel         struct{}
 	identTransferOptionFedToken                struct{}
 	identTransferOptionCacheEmbeddedClientMode struct{}
+	identTransferOptionBandwidthLimiter        struct{}
 
 	// ByteRange specifies a byte range for partial object transfers
 	// Start and End are inclusive byte offsets (0-indexed)
@@ -439,9 +468,11 @@ const (
 )
 
 const (
-	SyncNone  SyncLevel = iota // When synchronizing, always re-transfer, regardless of existence at destination.
-	SyncExist                  // Skip synchronization transfer if the destination exists
-	SyncSize                   // Skip synchronization transfer if the destination exists and matches the current source size
+	SyncNone     SyncLevel = iota // When synchronizing, always re-transfer, regardless of existence at destination.
+	SyncExist                     // Skip synchronization transfer if the destination exists
+	SyncSize                      // Skip synchronization transfer if the destination exists and matches the current source size
+	SyncModTime                   // Skip synchronization transfer if the destination matches the source size and is at least as new as the source
+	SyncChecksum                  // Skip synchronization transfer if the destination exists and its checksum matches the source's
 )
 
 const (
@@ -907,6 +938,58 @@ func WithInPlace(inPlace bool) TransferOption {
 	return option.New(identTransferOptionInPlace{}, inPlace)
 }
 
+// Create an option to specify whether interrupted downloads may be resumed
+//
+// When enabled, a download is written to a partial file next to its destination
+// along with a sidecar recording the object's ETag and the progress made.  If
+// the download fails, the partial file is kept, and a later download of the
+// same object to the same destination continues where it left off as long as
+// the object's ETag is unchanged.  Defaults to the Client.ResumeDownloads
+// parameter.
+func WithResume(resume bool) TransferOption {
+	return option.New(identTransferOptionResume{}, resume)
+}
+
+// Create an option to set the number of concurrent streams used to download
+// a single large object
+//
+// Objects of at least Client.ParallelDownloadThreshold bytes are split into
+// byte ranges fetched concurrently over up to `streams` HTTP requests.  A value
+// of 1 disables parallel downloads.  Defaults to the
+// Client.ParallelDownloadStreams parameter.
+func WithParallelStreams(streams int) TransferOption {
+	return option.New(identTransferOptionParallelStreams{}, streams)
+}
+
+// Create an option to encrypt uploads and decrypt downloads on the client
+//
+// Uploaded objects are encrypted with the key (or, for an X25519 public key, to
+// its owner) before they leave the client.  Downloaded objects carrying the
+// encryption header are decrypted as they are written; objects without the
+// header are written unchanged.  See LoadEncryptionKey for the supported keys.
+func WithEncryptionKey(key *EncryptionKey) TransferOption {
+	return option.New(identTransferOptionEncryptionKey{}, key)
+}
+
+// Create an option to throttle the data moved by the job's transfers
+//
+// Downloads wait for the limiter before writing each buffer and uploads
+// charge it for each buffer read from the source.  Throttled downloads use
+// a single stream and throttled uploads are sent in a single request.
+func WithBandwidthLimiter(limiter BandwidthLimiter) TransferOption {
+	return option.New(identTransferOptionBandwidthLimiter{}, limiter)
+}
+
+// Create an option to control third-party copies between remote URLs
+//
+// When enabled (the default), copying an object from one federation URL to
+// another asks the destination origin to pull the object from the source.  When
+// disabled, or if the destination doesn't support it, the object is streamed
+// through the client instead.
+func WithThirdPartyCopy(enable bool) TransferOption {
+	return option.New(identTransferOptionThirdPartyCopy{}, enable)
+}
+
 // Create an option to enable dry-run mode
 //
 // When enabled, the transfer will display what would be copied without actually
@@ -916,6 +999,16 @@ func WithDryRun(enable bool) TransferOption {
 	return option.New(identTransferOptionDryRun{}, enable)
 }
 
+// Create an option to delete extraneous objects at the destination
+//
+// When enabled for a recursive transfer, objects and collections at the
+// destination that are not present at the source are removed once every
+// transfer in the job has succeeded.  Combined with WithDryRun, the objects
+// that would be removed are reported instead.
+func WithDeleteExtraneous(enable bool) TransferOption {
+	return option.New(identTransferOptionDeleteExtraneous{}, enable)
+}
+
 // Create an option to force use of the Pelican prestage API
 //
 // When enabled for prestage transfers, the client will return an error if the cache
@@ -999,6 +1092,8 @@ func (te *TransferEngine) NewClient(options ...TransferOption) (client *Transfer
 			client.syncLevel = option.Value().(SyncLevel)
 		case identTransferOptionDryRun{}:
 			client.dryRun = option.Value().(bool)
+		case identTransferOptionDeleteExtraneous{}:
+			client.deleteExtra = option.Value().(bool)
 		case identTransferOptionCacheEmbeddedClientMode{}:
 			client.cacheMode = option.Value().(bool)
 		case identTransferOptionForcePrestageAPI{}:
@@ -1360,19 +1455,22 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 		operation = config.TokenWrite
 	}
 	tj = &TransferJob{
-		prefObjServers: tc.prefObjServers,
-		recursive:      recursive,
-		localPath:      localPath,
-		remoteURL:      &copyUrl,
-		callback:       tc.callback,
-		skipAcquire:    tc.skipAcquire,
-		dryRun:         tc.dryRun,
-		syncLevel:      tc.syncLevel,
-		xferType:       transferTypeDownload,
-		uuid:           id,
-		project:        project,
-		token:          newTokenGenerator(&copyUrl, nil, operation, !tc.skipAcquire),
-		inPlace:        false, // Default to using temporary files (rsync-style)
+		prefObjServers:   tc.prefObjServers,
+		recursive:        recursive,
+		localPath:        localPath,
+		remoteURL:        &copyUrl,
+		callback:         tc.callback,
+		skipAcquire:      tc.skipAcquire,
+		dryRun:           tc.dryRun,
+		syncLevel:        tc.syncLevel,
+		deleteExtraneous: tc.deleteExtra,
+		xferType:         transferTypeDownload,
+		uuid:             id,
+		project:          project,
+		token:            newTokenGenerator(&copyUrl, nil, operation, !tc.skipAcquire),
+		inPlace:          false, // Default to using temporary files (rsync-style)
+		resume:           param.Client_ResumeDownloads.GetBool(),
+		parallelStreams:  param.Client_ParallelDownloadStreams.GetInt(),
 	}
 	if upload {
 		tj.xferType = transferTypeUpload
@@ -1414,8 +1512,18 @@ func (tc *TransferClient) NewTransferJob(ctx context.Context, remoteUrl *url.URL
 			tj.reader = option.Value().(io.ReadCloser)
 		case identTransferOptionInPlace{}:
 			tj.inPlace = option.Value().(bool)
+		case identTransferOptionResume{}:
+			tj.resume = option.Value().(bool)
+		case identTransferOptionParallelStreams{}:
+			tj.parallelStreams = option.Value().(int)
+		case identTransferOptionEncryptionKey{}:
+			tj.encryptionKey = option.Value().(*EncryptionKey)
+		case identTransferOptionBandwidthLimiter{}:
+			tj.bandwidthLimiter = option.Value().(BandwidthLimiter)
 		case identTransferOptionDryRun{}:
 			tj.dryRun = option.Value().(bool)
+		case identTransferOptionDeleteExtraneous{}:
+			tj.deleteExtraneous = option.Value().(bool)
 		case identTransferOptionByteRange{}:
 			br := option.Value().(ByteRange)
 			tj.byteRange = &br
@@ -2336,6 +2444,13 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	localPath := transfer.localPath
 	transferResults.job = transfer.job
 
+	// Encrypted objects are decrypted as they are written out
+	decrypt := transfer.xferType == transferTypeDownload && transfer.encryptionKey() != nil
+	if decrypt && transfer.byteRange != nil {
+		err = errors.New("byte ranges of encrypted objects cannot be downloaded")
+		return
+	}
+
 	// Create hash instances for all known checksum types so we can verify against
 	// whatever algorithm the server actually supports. This handles cases like a multiuser
 	// origin returning MD5 when CRC32C was requested.
@@ -2350,6 +2465,7 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	var writeDestination string // Path to write to (may be temporary file or final destination)
 	var fileCloser io.Closer
 	var fp *os.File // File pointer for temp file that needs to be closed before rename on Windows
+	var resume *resumableDownload
 
 	// Check if we have a custom writer provided (e.g., for io.FS implementation)
 	if transfer.writer != nil {
@@ -2417,7 +2533,15 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 			// Determine write destination - use temporary file unless inPlace is true
 			// Special case: os.DevNull should always use inPlace mode (no temp files)
 			writeDestination = localPath
-			if !transfer.job.inPlace && localPath != os.DevNull && localPath != "" {
+			if !transfer.job.inPlace && localPath != os.DevNull && localPath != "" && transfer.job.resume && transfer.byteRange == nil && !decrypt {
+				// Resumable downloads use a well-known partial file so a later run can find it
+				if resume, err = openResumableDownload(localPath, transfer.remoteURL.Path, allHashes, allHashTypes); err != nil {
+					return
+				}
+				fp = resume.fp
+				writeDestination = fp.Name()
+				fileWriter = fp
+			} else if !transfer.job.inPlace && localPath != os.DevNull && localPath != "" {
 				// Use os.CreateTemp for secure atomic temp file creation in the
 				// destination directory, using an rsync-style .basename.* pattern.
 				dir := filepath.Dir(localPath)
@@ -2438,6 +2562,10 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 						fp.Close()
 						fp = nil
 					}
+					// A partial download kept for a later resume must not be removed
+					if resume != nil && resume.retained {
+						return
+					}
 					// Only clean up if the temporary file still exists and wasn't renamed
 					if _, statErr := os.Stat(writeDestination); statErr == nil {
 						if removeErr := os.Remove(writeDestination); removeErr != nil {
@@ -2560,7 +2688,20 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 		}()
 	}
 
+	// The checksums cover the object as stored, so they are computed before decryption
+	var decrypter *decryptingWriter
+	if decrypt {
+		decrypter = newDecryptingWriter(fileWriter, transfer.encryptionKey())
+		fileWriter = decrypter
+	}
 	fileWriter = io.MultiWriter(fileWriter, hashesWriter)
+	if limiter := transfer.bandwidthLimiter(); limiter != nil {
+		fileWriter = newThrottledWriter(transfer.ctx, fileWriter, limiter)
+	}
+	if resume != nil {
+		resume.writer = fileWriter
+		fileWriter = resume
+	}
 
 	var size int64 = -1
 	attempts := transfer.attempts
@@ -2573,6 +2714,11 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	if transferResults.job == nil {
 		transferResults = newTransferResults(transfer.job)
 	}
+	if resume != nil {
+		// Data from a previous run is only valid for the version of the object it came from
+		transferResults.ETag = resume.state.ETag
+		resume.userMetaCh = transfer.metadataChan
+	}
 	xferErrors := NewTransferErrors()
 	success := false
 	// transferStartTime is the start time of the last transfer attempt
@@ -2580,7 +2726,43 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 	var transferStartTime time.Time
 	transferUrls := make([]*url.URL, len(attempts))
 	downloadAttemptCount := 0
-	for idx, transferEndpoint := range attempts { // For each transfer attempt (usually 3), try to download via HTTP
+
+	// Large objects may be fetched as concurrent byte ranges instead of
+	// the sequential attempts below
+	serialAttempts := attempts
+	if streams := parallelStreamsFor(transfer, fp, localPath, attempts); streams > 1 {
+		headSize, headETag := headObject(transfer.ctx, transfer, prepareDownloadAttempt(transfer, attempts[0]))
+		if size < 0 {
+			size = headSize
+		}
+		if size > 0 && size >= int64(param.Client_ParallelDownloadThreshold.GetInt()) {
+			serialAttempts = nil
+			transferStartTime = time.Now()
+			var parallelResults []TransferResult
+			var parallelErr error
+			downloaded, parallelResults, transferUrls, transferResults.ETag, parallelErr = downloadParallel(
+				transfer, fp, writeDestination, size, headETag, streams, attempts, hashesWriter, xferErrors,
+			)
+			downloadAttemptCount = len(transferUrls)
+			transferResults.Attempts = append(transferResults.Attempts, parallelResults...)
+			if parallelErr == nil {
+				success = true
+			} else if errors.Is(parallelErr, errParallelRangeIgnored) {
+				// The server sends whole objects; start over with a regular download
+				log.WithFields(log.Fields{"url": transfer.remoteURL.String(), "job": transfer.job.ID()}).Infoln("Falling back to a single-stream download:", parallelErr)
+				if err = resetDownload(fp, allHashes); err != nil {
+					return
+				}
+				downloaded = 0
+				serialAttempts = attempts
+				transferUrls = make([]*url.URL, len(attempts))
+				downloadAttemptCount = 0
+			} else {
+				log.WithFields(log.Fields{"url": transfer.remoteURL.String(), "job": transfer.job.ID()}).Debugln("Parallel download failed:", parallelErr)
+			}
+		}
+	}
+	for idx, transferEndpoint := range serialAttempts { // For each transfer attempt (usually 3), try to download via HTTP
 		downloadAttemptCount++
 		var attempt TransferResult
 		attempt.CacheAge = -1
@@ -2589,24 +2771,11 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 		if transferEndpoint.CacheQuery {
 			attempt.CacheAge = transferEndpoint.CacheAge
 		}
-		// Make a copy of the transfer endpoint URL; otherwise, when we mutate the pointer, other parallel
+		// Work on a copy of the transfer endpoint URL; otherwise, when we mutate the pointer, other parallel
 		// workers might download from the wrong path.
-		transferEndpointUrl := *transferEndpoint.Url
-		transferEndpoint.Url = &transferEndpointUrl
-		if transferEndpointUrl.Scheme == "unix" {
-			transferEndpointUrl.Path = transfer.remoteURL.Path
-		}
-		// If a federation token is set, add it as an access_token query
-		// parameter.  The transfer URL already points at the origin (post
-		// director redirect), so this goes directly to the origin and is
-		// NOT sent to the director.
-		if transfer.fedToken != nil {
-			if ft, ftErr := transfer.fedToken.Get(); ftErr == nil && ft != "" {
-				q := transferEndpointUrl.Query()
-				q.Set("access_token", ft)
-				transferEndpointUrl.RawQuery = q.Encode()
-			}
-		}
+		transferEndpoint = prepareDownloadAttempt(transfer, transferEndpoint)
+		transferEndpoint.Decrypting = decrypter != nil
+		transferEndpointUrl := transferEndpoint.Url
 		transferUrls[idx] = transferEndpoint.Url
 		fields := log.Fields{
 			"url": transferEndpoint.Url.String(),
@@ -2623,9 +2792,30 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 		if transfer.byteRange != nil {
 			byteRangeEnd = transfer.byteRange.End
 		}
+		bytesSoFar := rangeStart + downloaded
+		metadataChan := transfer.metadataChan
+		if resume != nil {
+			bytesSoFar = resume.offset
+			metadataChan = resume.metaCh
+			transferEndpoint.ResumeETag = transferResults.ETag
+		}
 		attemptDownloaded, timeToFirstByte, cacheAge, serverVersion, attemptETag, err := downloadHTTP(
-			ctx, transfer.engine, transfer.callback, transferEndpoint, writeDestination, fileWriter, rangeStart+downloaded, byteRangeEnd, size, tokenContents, transfer.project, transfer.metadataChan,
+			ctx, transfer.engine, transfer.callback, transferEndpoint, writeDestination, fileWriter, bytesSoFar, byteRangeEnd, size, tokenContents, transfer.project, metadataChan,
 		)
+		if resume != nil && errors.Is(err, errResumeInvalidated) {
+			// The partial data belongs to an older version of the object; start over from this endpoint
+			resume.pullMetadata()
+			if err = resume.restart(); err == nil {
+				transferResults.ETag = ""
+				transferEndpoint.ResumeETag = ""
+				attemptDownloaded, timeToFirstByte, cacheAge, serverVersion, attemptETag, err = downloadHTTP(
+					ctx, transfer.engine, transfer.callback, transferEndpoint, writeDestination, fileWriter, 0, byteRangeEnd, size, tokenContents, transfer.project, metadataChan,
+				)
+			}
+		}
+		if resume != nil {
+			resume.pullMetadata()
+		}
 		// Clear metadata channel after first attempt - we only want to send metadata once
 		transfer.metadataChan = nil
 
@@ -2682,7 +2872,7 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 			log.WithFields(fields).Debugln("Downloaded bytes:", downloaded)
 			success = true
 			break
-		} else if size > 0 && downloaded == size {
+		} else if size > 0 && (downloaded == size || (resume != nil && resume.offset == size)) {
 			// We have downloaded all the data but we still have an error.  If we retry again,
 			// we will read past the end of the file and generate yet another error.  So, break
 			// and cause a permanent failure.
@@ -2693,6 +2883,16 @@ func downloadObject(transfer *transferFile) (transferResults TransferResults, er
 
 	transferResults.TransferStartTime = transferStartTime
 	transferResults.TransferredBytes = downloaded
+	if resume != nil {
+		resume.finish(success)
+	}
+	if success && decrypter != nil {
+		if closeErr := decrypter.Close(); closeErr != nil {
+			log.WithFields(log.Fields{"url": transfer.remoteURL.String(), "job": transfer.job.ID()}).Debugln("Failed to decrypt object:", closeErr)
+			xferErrors.AddError(closeErr)
+			success = false
+		}
+	}
 	if success {
 		// Clear any previous errors (e.g., from failed prestage attempts)
 		transferResults.Error = nil
@@ -3013,6 +3213,12 @@ func verifyFileSize(dest string, expectedSize int64, fields log.Fields) error {
 //
 // Returns the downloaded size, time to 1st byte downloaded, serverVersion and an error if there is one
 func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCallbackFunc, transfer transferAttemptDetails, dest string, writer io.Writer, bytesSoFar int64, byteRangeEnd int64, totalSize int64, token string, project string, metadataChan chan<- TransferMetadata) (downloaded int64, timeToFirstByte time.Duration, cacheAge time.Duration, serverVersion string, etag string, err error) {
+	ctx, span := tracing.StartClient(ctx, "client.downloadHTTP",
+		attribute.String("server.address", transfer.Url.Host), attribute.Int64("pelican.offset", bytesSoFar))
+	defer func() {
+		span.SetAttributes(attribute.Int64("pelican.bytes_downloaded", downloaded))
+		tracing.End(span, err)
+	}()
 	fields, ok := ctx.Value(logFields("fields")).(log.Fields)
 	if !ok {
 		fields = log.Fields{}
@@ -3116,6 +3322,7 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 	jobId, found := searchJobAd(attrJobId)
 	if found {
 		req.Header.Set("X-Pelican-JobId", jobId)
+		span.SetAttributes(tracing.RequestID(jobId))
 	}
 	req.Header.Set("X-Transfer-Status", "true")
 	req.Header.Set("X-Pelican-Timeout", headerTimeout.Round(time.Millisecond).String())
@@ -3129,9 +3336,14 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 			log.Debugln("Resuming transfer starting at offset", bytesSoFar)
 		}
 		req.Header.Set("Range", rangeHeader)
+		// If-Range requires a strong validator
+		if (bytesSoFar > 0 || transfer.ExactRange) && transfer.ResumeETag != "" && !strings.HasPrefix(transfer.ResumeETag, "W/") {
+			req.Header.Set("If-Range", transfer.ResumeETag)
+		}
 	}
 	req.Header.Set("TE", "trailers")
 	req.Header.Set("User-Agent", userAgent)
+	tracing.Inject(ctx, req.Header)
 
 	req = req.WithContext(ctx)
 
@@ -3167,6 +3379,7 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 		return
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
 		log.WithFields(fields).Debugln("Got failure status code:", resp.StatusCode)
@@ -3192,6 +3405,25 @@ func downloadHTTP(ctx context.Context, te *TransferEngine, callback TransferCall
 	serverVersion = resp.Header.Get("Server")
 	etag = resp.Header.Get("ETag")
 
+	// A range of a parallel download must be exactly the bytes requested; a full (200)
+	// response would overwrite the neighbouring ranges.  A different version of the
+	// object is reported as such below.
+	if transfer.ExactRange && (etag == "" || transfer.ResumeETag == "" || etag == transfer.ResumeETag) &&
+		!contentRangeMatches(resp, bytesSoFar, byteRangeEnd) {
+		log.WithFields(fields).Infof("Server returned status %d (Content-Range %q) for bytes %d-%d; ranges are not supported",
+			resp.StatusCode, resp.Header.Get("Content-Range"), bytesSoFar, byteRangeEnd)
+		return 0, 0, -1, serverVersion, etag, errParallelRangeIgnored
+	}
+
+	// When resuming a partial download, the data already on disk is only valid if the server
+	// is still serving the same version of the object and honored the range request; a full
+	// (200) response means the If-Range precondition failed or ranges aren't supported.
+	if (bytesSoFar > 0 || transfer.ExactRange) && transfer.ResumeETag != "" &&
+		(resp.StatusCode != http.StatusPartialContent || (etag != "" && etag != transfer.ResumeETag)) {
+		log.WithFields(fields).Infof("Object changed since the partial download was started (ETag was %q, now %q); cannot resume", transfer.ResumeETag, etag)
+		return 0, 0, -1, serverVersion, etag, errResumeInvalidated
+	}
+
 	if ageStr := resp.Header.Get("Age"); ageStr != "" {
 		if ageSec, err := strconv.Atoi(ageStr); err == nil {
 			cacheAge = time.Duration(ageSec) * time.Second
@@ -3548,7 +3780,11 @@ Loop:
 		}
 
 		// Second sanity check to verify the file size as it appears on disk.
-		if err = verifyFileSize(dest, totalSize, fields); err != nil {
+		// Decrypted files are smaller than the object; the decryption itself
+		// verifies the object is complete.
+		if transfer.Decrypting {
+			log.WithFields(fields).Debugln("Skipping size check of decrypted file")
+		} else if err = verifyFileSize(dest, totalSize, fields); err != nil {
 			err = errors.Wrapf(err, "failed to verify size of downloaded file (%s) on disk", dest)
 			return
 		}
@@ -3785,6 +4021,18 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 		transfer.callback(transfer.localPath, 0, sizer.Size(), false)
 	}
 
+	// Encryption adds a header and an authentication tag per segment, so even an
+	// empty file results in a non-empty object
+	encryptionKey := transfer.encryptionKey()
+	uploadSizeHint := sizer.Size()
+	if encryptionKey != nil {
+		nonZeroSize = true
+		if hasFileSize {
+			fileSizeHint = encryptionKey.encryptedSize(fileSizeHint)
+			uploadSizeHint = fileSizeHint
+		}
+	}
+
 	// Parse the writeback host as a URL
 	writebackhostUrl := transfer.attempts[0].Url
 
@@ -3808,7 +4056,7 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 	dest := &destCopy
 	// Add the oss.asize query parameter for PUT requests
 	query := dest.Query()
-	query.Set("oss.asize", fmt.Sprintf("%d", sizer.Size()))
+	query.Set("oss.asize", fmt.Sprintf("%d", uploadSizeHint))
 	dest.RawQuery = query.Encode()
 	attempt.Endpoint = dest.Host
 	// Create the wrapped reader and send it to the request
@@ -3816,8 +4064,19 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 	errorChan := make(chan error, 1)
 	responseChan := make(chan *http.Response)
 	reader := &progressReader{ioreader, sizer, closed}
-	// This will write to the checksum hashes as we read from the file
-	tee := io.TeeReader(reader, hashesWriter)
+	var body io.Reader = reader
+	if limiter := transfer.bandwidthLimiter(); limiter != nil {
+		body = newThrottledReader(transfer.ctx, body, limiter)
+	}
+	if encryptionKey != nil {
+		if body, err = newEncryptingReader(body, encryptionKey); err != nil {
+			transferResult.Error = err
+			return transferResult, err
+		}
+	}
+	// This will write to the checksum hashes as we read from the file; when
+	// encrypting, the checksums cover the encrypted object
+	tee := io.TeeReader(body, hashesWriter)
 	putContext, cancel := context.WithCancel(transfer.ctx)
 	transferStartTime := time.Now()
 	defer cancel()
@@ -3860,7 +4119,30 @@ func uploadObject(transfer *transferFile) (transferResult TransferResults, err e
 
 	useProxy := transfer.attempts[0].Proxy
 
-	go runPut(request, responseChan, errorChan, useProxy)
+	// Large files may be sent in chunks that can be retried individually
+	var chunked *chunkedUpload
+	if file, isFile := ioreader.(*os.File); isFile && hasFileSize {
+		if chunkSize := chunkedUploadChunkSize(transfer, fileSizeHint); chunkSize > 0 {
+			if id, idErr := chunkedUploadID(dest.Path, file, chunkSize); idErr == nil {
+				chunked = &chunkedUpload{
+					request:   request,
+					file:      file,
+					size:      fileSizeHint,
+					chunkSize: chunkSize,
+					id:        id,
+					progress:  &sizer.(*ConstantSizer).read,
+					limiter:   transfer.bandwidthLimiter(),
+					hashes:    allHashes,
+					hashTypes: allHashTypes,
+				}
+			}
+		}
+	}
+	if chunked != nil {
+		go runChunkedPut(chunked, responseChan, errorChan, useProxy)
+	} else {
+		go runPut(request, responseChan, errorChan, useProxy)
+	}
 	var lastError error = nil
 
 	tickerDuration := 100 * time.Millisecond
@@ -4064,12 +4346,21 @@ Loop:
 	return transferResult, nil
 }
 
+// Return the HTTP client for uploads, going through the configured proxy if
+// the attempt uses one
+func uploadClient(proxy bool) *http.Client {
+	if proxy {
+		return config.GetClient()
+	}
+	return config.GetClientNoProxy()
+}
+
 // Actually perform the HTTP PUT request to the server.
 //
 // This is executed in a separate goroutine to allow periodic progress callbacks
 // to be created within the main goroutine.
 func runPut(request *http.Request, responseChan chan<- *http.Response, errorChan chan<- error, proxy bool) {
-	client := config.GetClientNoProxy()
+	client := uploadClient(proxy)
 	dump, _ := httputil.DumpRequestOut(request, false)
 	log.Debugf("Dumping request: %s", dump)
 	response, err := client.Do(request)
@@ -4139,25 +4430,33 @@ func createWebDavClient(collectionsUrl *url.URL, token *tokenGenerator, project
 }
 
 // Depending on the synchronization policy, decide if a object download should be skipped
-func skipDownload(syncLevel SyncLevel, remoteInfo fs.FileInfo, localPath string) bool {
-	if syncLevel == SyncNone {
+func skipDownload(job *TransferJob, transfers []transferAttemptDetails, remoteInfo fs.FileInfo, remotePath, localPath string) bool {
+	if job.syncLevel == SyncNone {
 		return false
 	}
 	localInfo, err := os.Stat(localPath)
 	if err != nil {
 		return false
 	}
-	switch syncLevel {
+	switch job.syncLevel {
 	case SyncExist:
 		return true
 	case SyncSize:
 		return localInfo.Size() == remoteInfo.Size()
+	case SyncModTime:
+		return destinationUpToDate(remoteInfo.Size(), remoteInfo.ModTime(), localInfo.Size(), localInfo.ModTime())
+	case SyncChecksum:
+		if localInfo.Size() != remoteInfo.Size() {
+			return false
+		}
+		urls := downloadAttemptURLs(transfers, job.remoteURL.Path, remotePath)
+		return localChecksumMatches(job.ctx, localPath, urls, job.syncToken(), job.project)
 	}
 	return false
 }
 
 // Depending on the synchronization policy, decide if the upload should be skipped
-func skipUpload(job *TransferJob, localPath string, remoteUrl *pelican_url.PelicanURL) bool {
+func skipUpload(job *TransferJob, transfers []transferAttemptDetails, localPath string, remoteUrl *pelican_url.PelicanURL) bool {
 	if job.syncLevel == SyncNone {
 		return false
 	}
@@ -4176,11 +4475,25 @@ func skipUpload(job *TransferJob, localPath string, remoteUrl *pelican_url.Pelic
 		return false
 	}
 
+	// An encrypted upload is larger than the local file
+	localSize := localInfo.Size()
+	if job.encryptionKey != nil {
+		localSize = job.encryptionKey.encryptedSize(localSize)
+	}
 	switch job.syncLevel {
 	case SyncExist:
 		return true
 	case SyncSize:
-		return localInfo.Size() == remoteInfo.Size
+		return localSize == remoteInfo.Size
+	case SyncModTime:
+		return destinationUpToDate(localSize, localInfo.ModTime(), remoteInfo.Size, remoteInfo.ModTime)
+	case SyncChecksum:
+		// Each encryption of a file differs, so its checksum can't be compared
+		if localSize != remoteInfo.Size || job.encryptionKey != nil {
+			return false
+		}
+		urls := []*url.URL{uploadAttemptURL(transfers, remoteUrl.Path)}
+		return localChecksumMatches(job.ctx, localPath, urls, job.syncToken(), job.project)
 	}
 	return false
 }
@@ -4233,7 +4546,7 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 			}
 			// If the path leads to a file and not a collection, create a job to download the file and return
 			if !info.IsDir() {
-				if skipDownload(job.job.syncLevel, info, job.job.localPath) {
+				if skipDownload(job.job, transfers, info, remotePath, job.job.localPath) {
 					log.Infoln("Skipping download of object", remotePath, "as it already exists at", job.job.localPath)
 				} else {
 					// Construct URL using the transfer URL's base, _not the collections URL base_
@@ -4297,9 +4610,11 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 		// Otherwise, a different error occurred and we should return it
 		return errors.Wrap(err, "failed to read remote collection")
 	}
+	job.job.syncSource.markWalked()
 	localBase := strings.TrimPrefix(remotePath, job.job.remoteURL.Path)
 	for _, info := range infos {
 		newPath := path.Join(remotePath, info.Name())
+		job.job.recordSyncSource(path.Join(localBase, info.Name()), info.IsDir())
 		if info.IsDir() {
 			err := te.walkDirDownloadHelper(job, transfers, files, newPath, client)
 			if err != nil {
@@ -4317,7 +4632,7 @@ func (te *TransferEngine) walkDirDownloadHelper(job *clientTransferJob, transfer
 				targetPath = path.Join(job.job.localPath, localBase, info.Name())
 			}
 
-			if job.job.xferType == transferTypeDownload && skipDownload(job.job.syncLevel, info, targetPath) {
+			if job.job.xferType == transferTypeDownload && skipDownload(job.job, transfers, info, newPath, targetPath) {
 				log.Infoln("Skipping download of object", newPath, "as it already exists at", targetPath)
 				continue
 			}
@@ -4396,7 +4711,7 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 		}
 		// If the path leads to a file and not a directory, create a job to upload the file and return
 		if !info.IsDir() {
-			if remotePath := path.Join(job.job.remoteURL.Path, strings.TrimPrefix(localPath, job.job.localPath)); skipUpload(job.job, localPath, job.job.remoteURL) {
+			if remotePath := path.Join(job.job.remoteURL.Path, strings.TrimPrefix(localPath, job.job.localPath)); skipUpload(job.job, transfers, localPath, job.job.remoteURL) {
 				log.Infoln("Skipping upload of object", remotePath, "as it already exists at the destination")
 			} else if info.Mode().Type().IsRegular() {
 				job.job.activeXfer.Add(1)
@@ -4428,6 +4743,7 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 		return error_codes.NewParameterError(errors.Wrap(err, "failed to upload local collection"))
 	}
 
+	job.job.syncSource.markWalked()
 	for _, info := range infos {
 		newPath := localPath + "/" + info.Name()
 		remoteUrl, err := pelican_url.Parse(job.job.remoteURL.String(), nil, nil)
@@ -4435,6 +4751,7 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 			return err
 		}
 		remoteUrl.Path = path.Join(remoteUrl.Path, strings.TrimPrefix(newPath, job.job.localPath))
+		job.job.recordSyncSource(strings.TrimPrefix(newPath, job.job.localPath), info.IsDir())
 
 		if info.IsDir() {
 			// Recursively call this function to create any nested dir's as well as list their files
@@ -4442,7 +4759,7 @@ func (te *TransferEngine) walkDirUpload(job *clientTransferJob, transfers []tran
 			if err != nil {
 				return err
 			}
-		} else if skipUpload(job.job, newPath, remoteUrl) {
+		} else if skipUpload(job.job, transfers, newPath, remoteUrl) {
 			log.Infoln("Skipping upload of object", remoteUrl.Path, "as it already exists at the destination")
 		} else if info.Type().IsRegular() {
 			job.job.activeXfer.Add(1)
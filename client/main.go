@@ -659,6 +659,9 @@ func DoPut(ctx context.Context, localObject string, remoteDestination string, re
 			err = result.Error
 		}
 	}
+	if err == nil {
+		err = tj.removeExtraneous(ctx)
+	}
 	return
 }
 
@@ -741,7 +744,7 @@ func DoGet(ctx context.Context, remoteObject string, localDestination string, re
 	if err != nil {
 		return
 	}
-	tj, err := tc.NewTransferJob(context.Background(), pUrl.GetRawUrl(), localDestination, false, recursive)
+	tj, err := tc.NewTransferJob(context.Background(), pUrl.GetRawUrl(), localDestination, false, recursive, options...)
 	if err != nil {
 		return
 	}
@@ -766,6 +769,11 @@ func DoGet(ctx context.Context, remoteObject string, localDestination string, re
 			err = result.Error
 		}
 	}
+	if success {
+		if err = tj.removeExtraneous(ctx); err != nil {
+			success = false
+		}
+	}
 
 	if success {
 		// Get the final size of the download file
@@ -834,6 +842,9 @@ func DoCopy(ctx context.Context, sourceFile string, destination string, recursiv
 		log.Debugf("Detected a GET from %s to %s", parsedSrc.String(), parsedDest.Path)
 		localPath = parsedDest.Path
 		remotePath = parsedSrc.String()
+	} else if parsedDest.Scheme != "" && parsedDest.Scheme != "file" && parsedSrc.Scheme != "" && parsedSrc.Scheme != "file" {
+		log.Debugf("Detected a remote copy from %s to %s", parsedSrc.String(), parsedDest.String())
+		return doRemoteCopy(ctx, sourceFile, destination, recursive, options...)
 	} else {
 		return nil, errors.New("unable to determine direction of transfer.  Both source and destination are either local or remote")
 	}
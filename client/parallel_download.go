@@ -0,0 +1,439 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	// A byte range of an object fetched by one stream of a parallel download
+	downloadChunk struct {
+		start    int64 // Offset of the first byte
+		end      int64 // Offset of the last byte (inclusive)
+		progress atomic.Int64
+		done     chan struct{} // Closed once every byte of the chunk is written
+	}
+
+	// Per-endpoint statistics of a parallel download, reported as one attempt each
+	parallelEndpointStats struct {
+		attempt TransferResult
+		url     *url.URL
+		used    bool
+	}
+
+	// The shared state of a parallel download
+	parallelDownload struct {
+		transfer  *transferFile
+		fp        *os.File
+		dest      string
+		size      int64
+		attempts  []transferAttemptDetails
+		spread    int
+		chunks    []*downloadChunk
+		xferErrs  *TransferErrors
+		endpoints []parallelEndpointStats
+
+		mu           sync.Mutex // Protects etag, metadataChan, xferErrs and endpoints
+		etag         string
+		metadataChan chan<- TransferMetadata // Handed to the first request only
+	}
+)
+
+// Returned when the ranges of a parallel download come from different
+// versions of the object
+var errParallelETagMismatch = errors.New("object was modified during the download (ETag mismatch between ranges)")
+
+// Returned when a server answers a range of a parallel download with anything
+// other than exactly the requested bytes; the download falls back to a
+// single stream
+var errParallelRangeIgnored = errors.New("server did not honor the byte range request")
+
+// The smallest byte range fetched by a stream of a parallel download.  A
+// variable so tests can exercise chunking with small objects.
+var parallelMinChunkSize int64 = 8 * 1024 * 1024
+
+// Return a copy of the attempt with its own URL, adjusted for local-cache
+// sockets and federation tokens, so concurrent downloads can't interfere.
+func prepareDownloadAttempt(transfer *transferFile, attempt transferAttemptDetails) transferAttemptDetails {
+	attemptUrl := *attempt.Url
+	attempt.Url = &attemptUrl
+	if attemptUrl.Scheme == "unix" {
+		attemptUrl.Path = transfer.remoteURL.Path
+	}
+	// If a federation token is set, add it as an access_token query
+	// parameter.  The transfer URL already points at the origin (post
+	// director redirect), so this goes directly to the origin and is
+	// NOT sent to the director.
+	if transfer.fedToken != nil {
+		if ft, ftErr := transfer.fedToken.Get(); ftErr == nil && ft != "" {
+			q := attemptUrl.Query()
+			q.Set("access_token", ft)
+			attemptUrl.RawQuery = q.Encode()
+		}
+	}
+	return attempt
+}
+
+// Split an object of the given size into byte ranges so that each stream
+// handles a few of them, letting fast streams pick up the slack of slow ones.
+func splitDownloadChunks(size int64, streams int) []*downloadChunk {
+	chunkSize := (size + int64(streams)*4 - 1) / (int64(streams) * 4)
+	if chunkSize < parallelMinChunkSize {
+		chunkSize = parallelMinChunkSize
+	}
+	chunks := make([]*downloadChunk, 0, (size+chunkSize-1)/chunkSize)
+	for start := int64(0); start < size; start += chunkSize {
+		end := start + chunkSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		chunks = append(chunks, &downloadChunk{start: start, end: end, done: make(chan struct{})})
+	}
+	return chunks
+}
+
+// Determine the size and ETag of the object via a HEAD request to the first
+// endpoint; the size is -1 if it can't be determined.
+func headObject(ctx context.Context, transfer *transferFile, attempt transferAttemptDetails) (size int64, etag string) {
+	client := config.GetClientNoProxy()
+	if attempt.Proxy {
+		client = config.GetClient()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, attempt.Url.String(), nil)
+	if err != nil {
+		return -1, ""
+	}
+	if transfer.token != nil {
+		if tokenContents, err := transfer.token.Get(); err == nil && tokenContents != "" {
+			req.Header.Set("Authorization", "Bearer "+tokenContents)
+		}
+	}
+	req.Header.Set("User-Agent", getUserAgent(transfer.project))
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Debugln("Failed to determine object size for parallel download:", err)
+		return -1, ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return -1, ""
+	}
+	etag = resp.Header.Get("ETag")
+	if size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err != nil {
+		return -1, etag
+	}
+	return size, etag
+}
+
+// Report whether the response carries exactly the bytes start-end of the object
+func contentRangeMatches(resp *http.Response, start, end int64) bool {
+	if resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+	var gotStart, gotEnd int64
+	var total string
+	if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes %d-%d/%s", &gotStart, &gotEnd, &total); err != nil {
+		return false
+	}
+	return gotStart == start && gotEnd == end
+}
+
+// Discard the ranges written by an abandoned parallel download so the
+// object can be fetched again from the start
+func resetDownload(fp *os.File, hashes []io.Writer) error {
+	if err := fp.Truncate(0); err != nil {
+		return errors.Wrap(err, "failed to truncate destination file")
+	}
+	if _, err := fp.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "failed to rewind destination file")
+	}
+	for _, w := range hashes {
+		if h, ok := w.(hash.Hash); ok {
+			h.Reset()
+		}
+	}
+	return nil
+}
+
+// Decide how many streams to use for downloading the object into fp; a
+// return value of 1 or less means a regular, single-stream download.
+// Resumable downloads always use a single stream, as their checksum state
+// picks up where the partial file left off.
+func parallelStreamsFor(transfer *transferFile, fp *os.File, localPath string, attempts []transferAttemptDetails) int {
+	if transfer.job == nil || transfer.job.parallelStreams <= 1 || transfer.job.resume || fp == nil || localPath == os.DevNull ||
+		transfer.byteRange != nil || transfer.packOption != "" || transfer.encryptionKey() != nil || transfer.bandwidthLimiter() != nil || len(attempts) == 0 || attempts[0].Url.Scheme == "unix" {
+		return 1
+	}
+	// Ranges are written at their offsets, which requires a regular file
+	if info, err := fp.Stat(); err != nil || !info.Mode().IsRegular() {
+		return 1
+	}
+	return transfer.job.parallelStreams
+}
+
+// Download the object into the (pre-opened) destination file using
+// concurrent range requests.  The data is fed, in order, to hashesWriter as
+// the leading ranges complete so checksums can be verified as usual.  Every
+// range is requested with If-Range on the given ETag (if known) so that all
+// of them come from the same version of the object.
+// Returns the bytes transferred and the URLs used, most recently used last.
+func downloadParallel(transfer *transferFile, fp *os.File, dest string, size int64, etag string, streams int,
+	attempts []transferAttemptDetails, hashesWriter io.Writer, xferErrors *TransferErrors,
+) (downloaded int64, results []TransferResult, usedUrls []*url.URL, objectETag string, err error) {
+	pd := &parallelDownload{
+		transfer: transfer,
+		fp:       fp,
+		dest:     dest,
+		size:     size,
+		etag:     etag,
+		attempts: attempts,
+		spread:   param.Client_ParallelDownloadEndpoints.GetInt(),
+		chunks:   splitDownloadChunks(size, streams),
+		xferErrs: xferErrors,
+
+		metadataChan: transfer.metadataChan,
+	}
+	if pd.spread < 1 {
+		pd.spread = 1
+	}
+	if pd.spread > len(attempts) {
+		pd.spread = len(attempts)
+	}
+	if streams > len(pd.chunks) {
+		streams = len(pd.chunks)
+	}
+	pd.endpoints = make([]parallelEndpointStats, len(attempts))
+	for idx, attempt := range attempts {
+		pd.endpoints[idx] = parallelEndpointStats{
+			attempt: TransferResult{Number: idx, Endpoint: attempt.Url.Host, CacheAge: -1},
+		}
+	}
+	log.Debugf("Downloading %s (%d bytes) with %d streams in %d ranges across %d endpoint(s)",
+		transfer.remoteURL.Path, size, streams, len(pd.chunks), pd.spread)
+
+	if err = fp.Truncate(size); err != nil {
+		err = errors.Wrap(err, "failed to allocate destination file")
+		xferErrors.AddError(err)
+		return
+	}
+
+	egrp, ctx := errgroup.WithContext(transfer.ctx)
+	chunkCh := make(chan *downloadChunk)
+	egrp.Go(func() error {
+		defer close(chunkCh)
+		for _, chunk := range pd.chunks {
+			select {
+			case chunkCh <- chunk:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+	for stream := 0; stream < streams; stream++ {
+		egrp.Go(func() error {
+			for chunk := range chunkCh {
+				if err := pd.fetchChunk(ctx, stream, chunk); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	egrp.Go(func() error {
+		return pd.hashChunks(ctx, hashesWriter)
+	})
+
+	progressDone := make(chan struct{})
+	go pd.reportProgress(progressDone)
+	err = egrp.Wait()
+	close(progressDone)
+	if err != nil && len(xferErrors.errors) == 0 {
+		xferErrors.AddError(err)
+	}
+
+	for _, chunk := range pd.chunks {
+		downloaded += chunk.progress.Load()
+	}
+	if transfer.callback != nil {
+		transfer.callback(dest, downloaded, size, true)
+	}
+	for _, endpoint := range pd.endpoints {
+		if endpoint.used {
+			results = append(results, endpoint.attempt)
+			usedUrls = append(usedUrls, endpoint.url)
+		}
+	}
+	objectETag = pd.etag
+	if err == nil {
+		err = verifyFileSize(dest, size, log.Fields{"url": transfer.remoteURL.String()})
+	}
+	return
+}
+
+// Fetch a single chunk, moving on to the next endpoint in the sorted list
+// each time the current one fails.  Every endpoint is tried once, and the
+// stream's starting endpoint twice if it's the only one.
+func (pd *parallelDownload) fetchChunk(ctx context.Context, stream int, chunk *downloadChunk) error {
+	tries := len(pd.attempts)
+	if tries < 2 {
+		tries = 2
+	}
+	var lastErr error
+	for try := 0; try < tries; try++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		idx := (stream%pd.spread + try) % len(pd.attempts)
+		attempt := prepareDownloadAttempt(pd.transfer, pd.attempts[idx])
+		attempt.ExactRange = true
+		pd.mu.Lock()
+		attempt.ResumeETag = pd.etag
+		metadataChan := pd.metadataChan
+		pd.metadataChan = nil
+		pd.mu.Unlock()
+
+		offset := chunk.start + chunk.progress.Load()
+		callback := func(_ string, transferred int64, _ int64, _ bool) {
+			if written := transferred - chunk.start; written > chunk.progress.Load() {
+				chunk.progress.Store(written)
+			}
+		}
+		tokenContents := ""
+		if pd.transfer.token != nil {
+			tokenContents, _ = pd.transfer.token.Get()
+		}
+		fields := log.Fields{"url": attempt.Url.String(), "job": pd.transfer.job.ID()}
+		attemptCtx := context.WithValue(ctx, logFields("fields"), fields)
+
+		startTime := time.Now()
+		n, timeToFirstByte, cacheAge, serverVersion, etag, err := downloadHTTP(
+			attemptCtx, pd.transfer.engine, callback, attempt, "", io.NewOffsetWriter(pd.fp, offset),
+			offset, chunk.end, pd.size, tokenContents, pd.transfer.project, metadataChan,
+		)
+		endTime := time.Now()
+		chunk.progress.Store(offset - chunk.start + n)
+
+		pd.mu.Lock()
+		if pd.etag == "" {
+			pd.etag = etag
+		} else if err == nil && etag != "" && etag != pd.etag {
+			err = errParallelETagMismatch
+		}
+		if errors.Is(err, errResumeInvalidated) {
+			// The server ignored If-Range because its copy is a different version
+			err = errParallelETagMismatch
+		}
+		stats := &pd.endpoints[idx]
+		stats.used = true
+		stats.url = attempt.Url
+		stats.attempt.TransferFileBytes += n
+		stats.attempt.TransferTime += endTime.Sub(startTime)
+		stats.attempt.TransferEndTime = endTime
+		if stats.attempt.TimeToFirstByte == 0 || (timeToFirstByte > 0 && timeToFirstByte < stats.attempt.TimeToFirstByte) {
+			stats.attempt.TimeToFirstByte = timeToFirstByte
+		}
+		if cacheAge >= 0 {
+			stats.attempt.CacheAge = cacheAge
+		}
+		if serverVersion != "" {
+			stats.attempt.ServerVersion = serverVersion
+		}
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.WithFields(fields).Debugf("Failed to download bytes %d-%d: %v", offset, chunk.end, err)
+			proxyStr, _ := os.LookupEnv("http_proxy")
+			if !attempt.Proxy {
+				proxyStr = ""
+			}
+			wrappedErr, isProxyErr, modifiedProxyStr := wrapDownloadError(err, attempt.Url.String(), tokenContents)
+			if isProxyErr {
+				proxyStr += modifiedProxyStr
+			}
+			stats.attempt.Error = newTransferAttemptError(stats.attempt.Endpoint, proxyStr, isProxyErr, false, wrappedErr)
+			pd.xferErrs.AddPastError(stats.attempt.Error, endTime)
+		}
+		pd.mu.Unlock()
+
+		if err == nil {
+			close(chunk.done)
+			return nil
+		}
+		if errors.Is(err, errParallelETagMismatch) || errors.Is(err, errParallelRangeIgnored) {
+			return err
+		}
+		lastErr = err
+	}
+	return errors.Wrapf(lastErr, "failed to download bytes %d-%d", chunk.start, chunk.end)
+}
+
+// Feed the completed chunks, in order, to the checksum writer
+func (pd *parallelDownload) hashChunks(ctx context.Context, hashesWriter io.Writer) error {
+	for _, chunk := range pd.chunks {
+		select {
+		case <-chunk.done:
+		case <-ctx.Done():
+			return nil
+		}
+		section := io.NewSectionReader(pd.fp, chunk.start, chunk.end-chunk.start+1)
+		if _, err := io.Copy(hashesWriter, section); err != nil {
+			return errors.Wrap(err, "failed to compute checksum of downloaded data")
+		}
+	}
+	return nil
+}
+
+// Periodically report the combined progress of all streams
+func (pd *parallelDownload) reportProgress(done <-chan struct{}) {
+	if pd.transfer.callback == nil {
+		return
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	pd.transfer.callback(pd.dest, 0, pd.size, false)
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			var transferred int64
+			for _, chunk := range pd.chunks {
+				transferred += chunk.progress.Load()
+			}
+			pd.transfer.callback(pd.dest, transferred, pd.size, false)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// Serves a single object with range support, optionally failing the first
+// GET or ignoring Range headers altogether
+type parallelTestServer struct {
+	content     []byte
+	etag        string
+	failFirst   bool
+	ignoreRange bool
+	failed      atomic.Bool
+
+	mu       sync.Mutex
+	ranges   []string
+	ifRanges []string
+}
+
+func (s *parallelTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		s.ifRanges = append(s.ifRanges, r.Header.Get("If-Range"))
+		s.mu.Unlock()
+		if s.failFirst && s.failed.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.Header().Set("ETag", s.etag)
+	w.Header().Set("Digest", fmt.Sprintf("crc32c=%08x", crc32.Checksum(s.content, crc32cTable)))
+	if s.ignoreRange {
+		r.Header.Del("Range")
+	}
+	http.ServeContent(w, r, "obj", time.Time{}, bytes.NewReader(s.content))
+}
+
+func TestParallelDownload(t *testing.T) {
+	test_utils.InitClient(t, nil)
+	require.NoError(t, param.Client_ParallelDownloadThreshold.Set(1024))
+	require.NoError(t, param.Client_ParallelDownloadEndpoints.Set(2))
+	oldMinChunk := parallelMinChunkSize
+	parallelMinChunkSize = 4096
+	t.Cleanup(func() { parallelMinChunkSize = oldMinChunk })
+
+	content := make([]byte, 64*1024+123)
+	for idx := range content {
+		content[idx] = byte(idx * 7)
+	}
+
+	download := func(t *testing.T, streams int, resume bool, servers ...*httptest.Server) (string, TransferResults, error) {
+		attempts := make([]transferAttemptDetails, len(servers))
+		for idx, server := range servers {
+			serverURL, err := url.Parse(server.URL + "/test/obj")
+			require.NoError(t, err)
+			attempts[idx] = transferAttemptDetails{Url: serverURL}
+		}
+		localPath := filepath.Join(t.TempDir(), "obj")
+		var lastProgress atomic.Int64
+		transfer := &transferFile{
+			xferType: transferTypeDownload,
+			ctx:      context.Background(),
+			job: &TransferJob{
+				remoteURL:       &pelican_url.PelicanURL{Scheme: "pelican://", Host: attempts[0].Url.Host, Path: "/test/obj"},
+				parallelStreams: streams,
+				resume:          resume,
+			},
+			callback: func(_ string, transferred int64, _ int64, _ bool) {
+				lastProgress.Store(transferred)
+			},
+			localPath:       localPath,
+			remoteURL:       attempts[0].Url,
+			requireChecksum: true,
+			attempts:        attempts,
+		}
+		results, err := downloadObject(transfer)
+		if err == nil {
+			err = results.Error
+		}
+		if err == nil {
+			assert.Equal(t, int64(len(content)), lastProgress.Load())
+		}
+		return localPath, results, err
+	}
+
+	t.Run("multiple-endpoints-with-retry", func(t *testing.T) {
+		srv1 := &parallelTestServer{content: content, etag: `"v1"`, failFirst: true}
+		srv2 := &parallelTestServer{content: content, etag: `"v1"`}
+		server1 := httptest.NewServer(srv1)
+		defer server1.Close()
+		server2 := httptest.NewServer(srv2)
+		defer server2.Close()
+
+		localPath, results, err := download(t, 4, false, server1, server2)
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.Equal(t, int64(len(content)), results.TransferredBytes)
+		assert.Equal(t, `"v1"`, results.ETag)
+		require.NotEmpty(t, results.ServerChecksums)
+		assert.Len(t, results.Attempts, 2)
+
+		// Both endpoints served ranges, and the failed range was retried
+		srv1.mu.Lock()
+		srv2.mu.Lock()
+		defer srv1.mu.Unlock()
+		defer srv2.mu.Unlock()
+		assert.NotEmpty(t, srv1.ranges)
+		assert.NotEmpty(t, srv2.ranges)
+		assert.True(t, srv1.failed.Load())
+		assert.Greater(t, len(srv1.ranges)+len(srv2.ranges), 4)
+
+		// Every range, including the first batch, is tied to the same version
+		for _, ifRange := range append(srv1.ifRanges, srv2.ifRanges...) {
+			assert.Equal(t, `"v1"`, ifRange)
+		}
+	})
+
+	t.Run("range-ignored", func(t *testing.T) {
+		srv := &parallelTestServer{content: content, etag: `"v1"`, ignoreRange: true}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		// Full responses to range requests are abandoned, and the object is
+		// fetched again over a single stream
+		localPath, results, err := download(t, 4, false, server)
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.Equal(t, int64(len(content)), results.TransferredBytes)
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.NotEmpty(t, srv.ranges)
+		assert.Empty(t, srv.ranges[len(srv.ranges)-1])
+	})
+
+	t.Run("etag-mismatch", func(t *testing.T) {
+		server1 := httptest.NewServer(&parallelTestServer{content: content, etag: `"v1"`})
+		defer server1.Close()
+		server2 := httptest.NewServer(&parallelTestServer{content: content, etag: `"v2"`})
+		defer server2.Close()
+
+		localPath, _, err := download(t, 4, false, server1, server2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ETag mismatch")
+		assert.NoFileExists(t, localPath)
+	})
+
+	t.Run("single-stream", func(t *testing.T) {
+		srv := &parallelTestServer{content: content, etag: `"v1"`}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		localPath, _, err := download(t, 1, false, server)
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		assert.Len(t, srv.ranges, 1)
+	})
+
+	t.Run("resumable", func(t *testing.T) {
+		srv := &parallelTestServer{content: content, etag: `"v1"`}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		// The partial file's checksum state only covers a prefix of the
+		// object, so resumable downloads are never split into ranges
+		localPath, _, err := download(t, 4, true, server)
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		assert.Len(t, srv.ranges, 1)
+	})
+}
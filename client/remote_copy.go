@@ -0,0 +1,469 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/error_codes"
+	"github.com/pelicanplatform/pelican/features"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+type (
+	// A writer whose Close does nothing, letting the remote copy decide how
+	// the pipe between the download and the upload is closed
+	nopWriteCloser struct {
+		io.Writer
+	}
+)
+
+var errThirdPartyCopyUnsupported = errors.New("destination does not support third-party copy")
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// Copy the object (or, if recursive, the collection) at the source federation
+// URL to the destination federation URL
+//
+// Each object is first copied with an HTTP third-party copy: the destination
+// is asked to pull the object from the source itself.  If the destination
+// doesn't support that, the object is streamed through the client instead,
+// from a download job into an upload job without touching the local disk.
+func doRemoteCopy(ctx context.Context, source string, destination string, recursive bool, options ...TransferOption) (transferResults []TransferResults, err error) {
+	srcUrl, err := ParseRemoteAsPUrl(ctx, source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse source: %s", source)
+	}
+	dstUrl, err := ParseRemoteAsPUrl(ctx, destination)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse destination: %s", destination)
+	}
+	if _, exists := srcUrl.Query()[pelican_url.QueryRecursive]; exists {
+		recursive = true
+	}
+
+	useTpc := true
+	var callback TransferCallbackFunc
+	for _, option := range options {
+		switch option.Ident() {
+		case identTransferOptionThirdPartyCopy{}:
+			useTpc = option.Value().(bool)
+		case identTransferOptionCallback{}:
+			callback = option.Value().(TransferCallbackFunc)
+		}
+	}
+
+	// Map each source object to its destination
+	type copyPair struct {
+		src, dst *url.URL
+		size     int64
+	}
+	var pairs []copyPair
+	if recursive {
+		infos, listErr := DoList(ctx, source, append(options, WithRecursive(true))...)
+		if listErr != nil {
+			return nil, errors.Wrapf(listErr, "failed to list source collection %s", source)
+		}
+		for _, info := range infos {
+			if info.IsCollection {
+				continue
+			}
+			src := srcUrl.GetRawUrl()
+			src.Path = info.Name
+			dst := dstUrl.GetRawUrl()
+			dst.Path = path.Join(dstUrl.Path, strings.TrimPrefix(info.Name, path.Clean(srcUrl.Path)))
+			pairs = append(pairs, copyPair{src, dst, info.Size})
+		}
+	} else {
+		dst := dstUrl.GetRawUrl()
+		if strings.HasSuffix(dstUrl.Path, "/") || strings.HasSuffix(destination, "/") {
+			dst.Path = path.Join(dstUrl.Path, path.Base(srcUrl.Path))
+		}
+		pairs = append(pairs, copyPair{srcUrl.GetRawUrl(), dst, -1})
+	}
+
+	te, err := NewTransferEngine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := te.Shutdown(); err != nil {
+			log.Errorln("Failure when shutting down transfer engine:", err)
+		}
+	}()
+
+	for _, pair := range pairs {
+		var result TransferResults
+		result, err = te.copyRemoteObject(ctx, pair.src, pair.dst, pair.size, useTpc, callback, options)
+		if err == nil {
+			err = result.Error
+		}
+		transferResults = append(transferResults, result)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Copy a single object between two federation URLs
+func (te *TransferEngine) copyRemoteObject(ctx context.Context, src, dst *url.URL, size int64, useTpc bool, callback TransferCallbackFunc, options []TransferOption) (results TransferResults, err error) {
+	// The download and upload are run by separate clients so that each can
+	// be shut down as soon as its side of the copy finishes
+	dlClient, err := te.NewClient(options...)
+	if err != nil {
+		return
+	}
+	defer dlClient.Close()
+	ulClient, err := te.NewClient(options...)
+	if err != nil {
+		return
+	}
+	defer ulClient.Close()
+
+	srcJob, err := dlClient.NewTransferJob(ctx, src, "", false, false, options...)
+	if err != nil {
+		return
+	}
+	dstJob, err := ulClient.NewTransferJob(ctx, dst, "", true, false, options...)
+	if err != nil {
+		return
+	}
+
+	if useTpc {
+		// Like an upload, don't replace an existing object unless overwrites are enabled
+		if !param.Client_EnableOverwrites.GetBool() {
+			if _, statErr := statHttp(dstJob.remoteURL, dstJob.dirResp, dstJob.token, nil); statErr == nil {
+				results = newTransferResults(dstJob)
+				results.Error = error_codes.NewSpecification_FileAlreadyExistsError(errors.New("remote object already exists, copy aborted"))
+				return
+			}
+		}
+		results, err = dstJob.thirdPartyCopy(ctx, srcJob, size, callback)
+		if !errors.Is(err, errThirdPartyCopyUnsupported) {
+			return
+		}
+		log.Infof("Streaming %s to %s through the client: %v", src.Path, dst.Path, err)
+	}
+	return streamRemoteObject(dlClient, ulClient, srcJob, dstJob, callback)
+}
+
+// Copy an object by piping a download job into an upload job
+func streamRemoteObject(dlClient, ulClient *TransferClient, srcJob, dstJob *TransferJob, callback TransferCallbackFunc) (results TransferResults, err error) {
+	if param.Client_WorkerCount.GetInt() < 2 {
+		return newTransferResults(dstJob), errors.New("streaming an object between remote URLs requires Client.WorkerCount to be at least 2")
+	}
+	pr, pw := io.Pipe()
+	srcJob.writer = nopWriteCloser{pw}
+	dstJob.reader = pr
+
+	// Progress is reported by the download, which knows the object's size
+	name := dstJob.remoteURL.Path
+	srcJob.callback = nil
+	if callback != nil {
+		srcJob.callback = func(_ string, transferred int64, total int64, done bool) {
+			callback(name, transferred, total, done)
+		}
+	}
+	dstJob.callback = nil
+
+	if err = dlClient.Submit(srcJob); err != nil {
+		return
+	}
+	if err = ulClient.Submit(dstJob); err != nil {
+		pw.CloseWithError(err)
+		return
+	}
+
+	// Wait for one side of the copy, returning its result or the error that
+	// should abort the other side
+	wait := func(client *TransferClient, job *TransferJob) (result TransferResults, err error) {
+		jobResults, err := client.Shutdown()
+		if err == nil {
+			err = job.lookupErr
+		}
+		if len(jobResults) > 0 {
+			result = jobResults[0]
+			if err == nil {
+				err = result.Error
+			}
+		} else {
+			result = newTransferResults(job)
+			if err == nil {
+				err = errors.New("transfer did not complete")
+			}
+		}
+		return
+	}
+	dlDone := make(chan error, 1)
+	go func() {
+		_, dlErr := wait(dlClient, srcJob)
+		// A failed download, including one whose checksum doesn't match the
+		// source, must fail the upload rather than end it cleanly
+		pw.CloseWithError(dlErr)
+		dlDone <- dlErr
+	}()
+	results, ulErr := wait(ulClient, dstJob)
+	pr.CloseWithError(errors.New("upload has finished"))
+	if dlErr := <-dlDone; dlErr != nil {
+		results.Error = errors.Wrap(dlErr, "failed to download source object")
+	} else if ulErr != nil {
+		results.Error = ulErr
+	}
+	results.Source = srcJob.remoteURL.String()
+	return
+}
+
+// Ask the job's destination to pull the object from the source job's URL
+// with an HTTP third-party copy, then check the checksums of the two copies
+// match.  Returns errThirdPartyCopyUnsupported if the destination's version
+// doesn't support third-party copies or it doesn't accept the request.
+func (tj *TransferJob) thirdPartyCopy(ctx context.Context, srcJob *TransferJob, size int64, callback TransferCallbackFunc) (results TransferResults, err error) {
+	results = newTransferResults(tj)
+	results.Source = srcJob.remoteURL.String()
+	results.TransferStartTime = time.Now()
+
+	sources, _, err := generateSortedObjServers(srcJob.dirResp, srcJob.prefObjServers)
+	if err != nil {
+		return
+	}
+	var srcUrl *url.URL
+	for _, server := range sources {
+		// The destination can't reach a local cache's socket
+		if server.Scheme == "http" || server.Scheme == "https" {
+			srcCopy := *server
+			srcUrl = &srcCopy
+			break
+		}
+	}
+	if srcUrl == nil || len(tj.dirResp.ObjectServers) == 0 {
+		err = errors.Wrap(errThirdPartyCopyUnsupported, "no HTTP source or destination endpoints available")
+		return
+	}
+	if srcUrl.Path == "" || srcUrl.Path == "/" {
+		srcUrl.Path = path.Clean(srcJob.remoteURL.Path)
+	}
+	dstCopy := *tj.dirResp.ObjectServers[0]
+	dstUrl := &dstCopy
+	dstUrl.Path = computeUploadDestPath(tj.remoteURL.Path, dstUrl.Path)
+
+	srcToken, dstToken := "", ""
+	if srcJob.token != nil {
+		if srcToken, err = srcJob.token.Get(); err != nil {
+			return
+		}
+	}
+	if tj.token != nil {
+		if dstToken, err = tj.token.Get(); err != nil {
+			return
+		}
+	}
+	if size < 0 {
+		if info, statErr := statHttp(srcJob.remoteURL, srcJob.dirResp, srcJob.token, srcJob.fedToken); statErr == nil {
+			size = info.Size
+		} else {
+			size = 0
+		}
+	}
+
+	if err = checkThirdPartyCopySupport(ctx, dstUrl, dstToken, tj.project); err != nil {
+		return
+	}
+
+	progress := func(transferred int64, done bool) {
+		if callback != nil {
+			callback(tj.remoteURL.Path, transferred, size, done)
+		}
+	}
+	progress(0, false)
+	attempt := TransferResult{Endpoint: dstUrl.Host}
+	transferred, serverVersion, err := sendThirdPartyCopy(ctx, srcUrl, srcToken, dstUrl, dstToken, tj.project, func(transferred int64) {
+		progress(transferred, false)
+	})
+	attempt.ServerVersion = serverVersion
+	attempt.TransferEndTime = time.Now()
+	attempt.TransferTime = attempt.TransferEndTime.Sub(results.TransferStartTime)
+	if err != nil {
+		if !errors.Is(err, errThirdPartyCopyUnsupported) {
+			attempt.Error = newTransferAttemptError(dstUrl.Host, "", false, false, err)
+			results.Attempts = append(results.Attempts, attempt)
+			results.Error = err
+		}
+		return
+	}
+	if transferred <= 0 {
+		transferred = size
+	}
+	attempt.TransferFileBytes = transferred
+	results.Attempts = append(results.Attempts, attempt)
+	results.TransferredBytes = transferred
+	results.Scheme = dstUrl.Scheme
+	progress(transferred, true)
+	log.Debugf("Third-party copy of %s to %s completed (%d bytes)", srcUrl, dstUrl, transferred)
+
+	// The destination computed its checksum from the data it received; compare
+	// it with the source's checksum of the same algorithm
+	srcChecksums, srcErr := fetchChecksum(ctx, KnownChecksumTypes(), srcUrl, srcToken, srcJob.project)
+	dstChecksums, dstErr := fetchChecksum(ctx, KnownChecksumTypes(), dstUrl, dstToken, tj.project)
+	results.ClientChecksums = srcChecksums
+	results.ServerChecksums = dstChecksums
+	if srcErr != nil || dstErr != nil {
+		log.Debugln("Unable to fetch checksums after third-party copy:", srcErr, dstErr)
+	}
+	matched := false
+	for _, srcChecksum := range srcChecksums {
+		for _, dstChecksum := range dstChecksums {
+			if srcChecksum.Algorithm != dstChecksum.Algorithm {
+				continue
+			}
+			if !bytes.Equal(srcChecksum.Value, dstChecksum.Value) {
+				results.Error = errors.Errorf("checksum mismatch after third-party copy: source %s %x, destination %x",
+					HttpDigestFromChecksum(srcChecksum.Algorithm), srcChecksum.Value, dstChecksum.Value)
+				return
+			}
+			matched = true
+		}
+	}
+	if !matched && (tj.requireChecksum || srcJob.requireChecksum) {
+		results.Error = errors.New("checksum is required but the source and destination did not provide a common checksum")
+	}
+	return
+}
+
+// Check the destination supports third-party copies from the version in its
+// Server header.  Pelican servers must have the ThirdPartyCopy feature;
+// XRootD servers implement HTTP third-party copies natively.  Returns
+// errThirdPartyCopyUnsupported for any other server.
+func checkThirdPartyCopySupport(ctx context.Context, dstUrl *url.URL, dstToken string, project string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, dstUrl.String(), nil)
+	if err != nil {
+		return err
+	}
+	if dstToken != "" {
+		req.Header.Set("Authorization", "Bearer "+dstToken)
+	}
+	req.Header.Set("User-Agent", getUserAgent(project))
+	resp, err := config.GetClient().Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+
+	server := resp.Header.Get("Server")
+	if strings.HasPrefix(strings.ToLower(server), "xrootd") {
+		return nil
+	}
+	if !strings.HasPrefix(server, "pelican/") {
+		return errors.Wrapf(errThirdPartyCopyUnsupported, "unrecognized server %q", server)
+	}
+	ad := server_structs.ServerAd{}
+	ad.Type = server_structs.OriginType.String()
+	ad.Version = strings.TrimPrefix(server, "pelican/")
+	if features.ServerSupportsFeature(features.ThirdPartyCopy, ad) == utils.Tern_False {
+		return errors.Wrapf(errThirdPartyCopyUnsupported, "origin version %s", ad.Version)
+	}
+	return nil
+}
+
+// Send the HTTP third-party copy (pull mode) request to the destination and
+// follow its performance markers until it reports the outcome.  Returns the
+// number of bytes the destination reported receiving.
+func sendThirdPartyCopy(ctx context.Context, srcUrl *url.URL, srcToken string, dstUrl *url.URL, dstToken string, project string, progress func(int64)) (transferred int64, serverVersion string, err error) {
+	req, err := http.NewRequestWithContext(ctx, "COPY", dstUrl.String(), nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Source", srcUrl.String())
+	if srcToken != "" {
+		req.Header.Set("TransferHeaderAuthorization", "Bearer "+srcToken)
+	}
+	if dstToken != "" {
+		req.Header.Set("Authorization", "Bearer "+dstToken)
+	}
+	req.Header.Set("User-Agent", getUserAgent(project))
+	if val, found := searchJobAd(attrJobId); found {
+		req.Header.Set("X-Pelican-JobId", val)
+	}
+	resp, err := config.GetClient().Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	serverVersion = resp.Header.Get("Server")
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		err = errors.Wrapf(errThirdPartyCopyUnsupported, "COPY request failed with status %d", resp.StatusCode)
+		return
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		sce := StatusCodeError(resp.StatusCode)
+		err = &HttpErrResp{resp.StatusCode, fmt.Sprintf("third-party copy request failed (HTTP status %d): %s",
+			resp.StatusCode, strings.TrimSpace(string(body))), wrapStatusCodeError(&sce)}
+		return
+	}
+
+	// A completed copy may be reported without any markers
+	sawMarker := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, found := strings.CutPrefix(line, "Stripe Bytes Transferred:"); found {
+			sawMarker = true
+			if bytes, parseErr := strconv.ParseInt(strings.TrimSpace(value), 10, 64); parseErr == nil {
+				transferred = bytes
+				progress(transferred)
+			}
+		} else if _, found := strings.CutPrefix(line, "success:"); found {
+			return
+		} else if message, found := strings.CutPrefix(line, "failure:"); found {
+			err = errors.Errorf("destination failed to copy the object: %s", strings.TrimSpace(message))
+			return
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		err = errors.Wrap(err, "failed to read third-party copy progress")
+		return
+	}
+	if sawMarker || resp.StatusCode == http.StatusAccepted {
+		err = errors.New("destination closed the connection before the third-party copy finished")
+	}
+	return
+}
@@ -0,0 +1,205 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// An origin serving a single object that, if tpc is set, accepts third-party
+// copies by pulling from the Source URL
+type tpcTestOrigin struct {
+	mu      sync.Mutex
+	server  string // Value of the Server header, if any
+	tpc     bool
+	corrupt bool // Store a different object than the one pulled
+	object  []byte
+	copyReq *http.Request
+}
+
+func (o *tpcTestOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.server != "" {
+		w.Header().Set("Server", o.server)
+	}
+	switch {
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		if o.object == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.object)))
+		w.Header().Set("Digest", fmt.Sprintf("crc32c=%08x", crc32.Checksum(o.object, crc32cTable)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(o.object)
+		}
+	case r.Method == "COPY" && o.tpc:
+		o.copyReq = r
+		req, _ := http.NewRequest(http.MethodGet, r.Header.Get("Source"), nil)
+		req.Header.Set("Authorization", r.Header.Get("TransferHeaderAuthorization"))
+		resp, err := http.DefaultClient.Do(req)
+		w.WriteHeader(http.StatusAccepted)
+		if err != nil {
+			fmt.Fprintf(w, "failure: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+		if o.object, err = io.ReadAll(resp.Body); err != nil {
+			fmt.Fprintf(w, "failure: %v\n", err)
+			return
+		}
+		if o.corrupt {
+			o.object = append(o.object, '!')
+		}
+		fmt.Fprintf(w, "Perf Marker\nStripe Bytes Transferred: %d\nEnd\nsuccess: Created\n", len(o.object))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestThirdPartyCopy(t *testing.T) {
+	test_utils.InitClient(t, nil)
+	content := []byte("an object copied between origins")
+
+	// Return jobs for copying between the two servers
+	newJobs := func(t *testing.T, src, dst *httptest.Server) (srcJob, dstJob *TransferJob) {
+		srcUrl, err := url.Parse(src.URL + "/first/obj")
+		require.NoError(t, err)
+		dstUrl, err := url.Parse(dst.URL + "/second/obj")
+		require.NoError(t, err)
+		srcJob = &TransferJob{
+			remoteURL: &pelican_url.PelicanURL{Scheme: "pelican://", Host: srcUrl.Host, Path: "/first/obj"},
+			dirResp:   server_structs.DirectorResponse{ObjectServers: []*url.URL{srcUrl}},
+		}
+		dstJob = &TransferJob{
+			remoteURL:       &pelican_url.PelicanURL{Scheme: "pelican://", Host: dstUrl.Host, Path: "/second/obj"},
+			dirResp:         server_structs.DirectorResponse{ObjectServers: []*url.URL{dstUrl}},
+			requireChecksum: true,
+		}
+		return
+	}
+
+	t.Run("copy", func(t *testing.T) {
+		source := &tpcTestOrigin{object: content}
+		srcServer := httptest.NewServer(source)
+		t.Cleanup(srcServer.Close)
+		dest := &tpcTestOrigin{server: "pelican/v7.25.0", tpc: true}
+		dstServer := httptest.NewServer(dest)
+		t.Cleanup(dstServer.Close)
+
+		var lastProgress int64
+		srcJob, dstJob := newJobs(t, srcServer, dstServer)
+		results, err := dstJob.thirdPartyCopy(context.Background(), srcJob, int64(len(content)), func(_ string, transferred int64, total int64, done bool) {
+			lastProgress = transferred
+			assert.Equal(t, int64(len(content)), total)
+		})
+		require.NoError(t, err)
+		require.NoError(t, results.Error)
+		assert.Equal(t, int64(len(content)), results.TransferredBytes)
+		assert.Equal(t, int64(len(content)), lastProgress)
+
+		dest.mu.Lock()
+		defer dest.mu.Unlock()
+		assert.Equal(t, content, dest.object)
+		assert.Equal(t, srcServer.URL+"/first/obj", dest.copyReq.Header.Get("Source"))
+		assert.Equal(t, "/second/obj", dest.copyReq.URL.Path)
+	})
+
+	t.Run("checksum-mismatch", func(t *testing.T) {
+		srcServer := httptest.NewServer(&tpcTestOrigin{object: content})
+		t.Cleanup(srcServer.Close)
+		dstServer := httptest.NewServer(&tpcTestOrigin{server: "pelican/v7.25.0", tpc: true, corrupt: true})
+		t.Cleanup(dstServer.Close)
+
+		srcJob, dstJob := newJobs(t, srcServer, dstServer)
+		results, err := dstJob.thirdPartyCopy(context.Background(), srcJob, int64(len(content)), nil)
+		require.NoError(t, err)
+		assert.ErrorContains(t, results.Error, "checksum mismatch")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		srcServer := httptest.NewServer(&tpcTestOrigin{object: content})
+		t.Cleanup(srcServer.Close)
+
+		for _, server := range []string{"", "nginx", "pelican/v7.24.0"} {
+			// Servers without the feature are never sent a COPY request
+			dest := &tpcTestOrigin{server: server, tpc: true}
+			dstServer := httptest.NewServer(dest)
+			t.Cleanup(dstServer.Close)
+
+			srcJob, dstJob := newJobs(t, srcServer, dstServer)
+			_, err := dstJob.thirdPartyCopy(context.Background(), srcJob, int64(len(content)), nil)
+			assert.ErrorIs(t, err, errThirdPartyCopyUnsupported, "server %q", server)
+			dest.mu.Lock()
+			assert.Nil(t, dest.copyReq, "server %q", server)
+			dest.mu.Unlock()
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		srcServer := httptest.NewServer(&tpcTestOrigin{object: content})
+		t.Cleanup(srcServer.Close)
+		dstServer := httptest.NewServer(&tpcTestOrigin{server: "pelican/v7.25.0"})
+		t.Cleanup(dstServer.Close)
+
+		// A recent origin with third-party copies turned off rejects the request
+		srcJob, dstJob := newJobs(t, srcServer, dstServer)
+		_, err := dstJob.thirdPartyCopy(context.Background(), srcJob, int64(len(content)), nil)
+		assert.ErrorIs(t, err, errThirdPartyCopyUnsupported)
+	})
+
+	t.Run("failure-marker", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, "Perf Marker\nStripe Bytes Transferred: 10\nEnd\nfailure: source returned 403\n")
+		}))
+		t.Cleanup(server.Close)
+		serverUrl, err := url.Parse(server.URL + "/obj")
+		require.NoError(t, err)
+		transferred, _, err := sendThirdPartyCopy(context.Background(), serverUrl, "", serverUrl, "", "", func(int64) {})
+		assert.ErrorContains(t, err, "source returned 403")
+		assert.NotErrorIs(t, err, errThirdPartyCopyUnsupported)
+		assert.Equal(t, int64(10), transferred)
+	})
+
+	t.Run("stream-requires-workers", func(t *testing.T) {
+		require.NoError(t, param.Client_WorkerCount.Set(1))
+		t.Cleanup(func() { require.NoError(t, param.Client_WorkerCount.Set(5)) })
+		_, err := streamRemoteObject(nil, nil, &TransferJob{remoteURL: &pelican_url.PelicanURL{}}, &TransferJob{remoteURL: &pelican_url.PelicanURL{}}, nil)
+		assert.ErrorContains(t, err, "Client.WorkerCount")
+	})
+}
@@ -0,0 +1,301 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"encoding"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Suffixes of the partial data file and its sidecar state file; both are
+	// hidden files in the destination directory, e.g. ".data.bin.pelican-partial"
+	resumePartialSuffix = ".pelican-partial"
+	resumeStateSuffix   = ".pelican-partial.json"
+
+	resumeStateVersion = 1
+
+	// How often the progress of a resumable download is persisted
+	resumeCheckpointInterval = 5 * time.Second
+)
+
+// Returned by downloadHTTP when the server no longer has the version of the
+// object that a partial download was started from.
+var errResumeInvalidated = errors.New("object changed since the partial download was started")
+
+type (
+	// The contents of the sidecar file kept next to a partial download
+	resumeState struct {
+		Version int    `json:"version"`
+		Object  string `json:"object"` // Remote path of the object being downloaded
+		ETag    string `json:"etag"`
+		Size    int64  `json:"size"` // Full size of the object; -1 if unknown
+		// Number of bytes at the start of the partial file known to be on disk
+		// and included in ChecksumState
+		BytesVerified int64 `json:"bytesVerified"`
+		// Serialized state of each running checksum, keyed by digest name
+		ChecksumState map[string][]byte `json:"checksumState"`
+		Updated       time.Time         `json:"updated"`
+	}
+
+	// A download whose progress is persisted so a later client invocation can
+	// resume it.  The writer side is driven by the single goroutine copying the
+	// response body, and the remaining methods are only invoked between
+	// attempts, so no locking is needed.
+	resumableDownload struct {
+		partialPath string
+		statePath   string
+		fp          *os.File
+		hashes      []io.Writer
+		hashTypes   []ChecksumType
+
+		writer         io.Writer // Writes to both fp and the hashes
+		offset         int64     // Bytes written to fp
+		state          resumeState
+		lastCheckpoint time.Time
+		retained       bool // Set once the partial file is kept for a later resume
+
+		// Transfer metadata is routed through the download so the ETag is known
+		// before the first checkpoint; it is forwarded to the caller's channel.
+		metaCh     chan TransferMetadata
+		userMetaCh chan<- TransferMetadata
+	}
+)
+
+// Return the paths of the partial file and sidecar for a download to localPath
+func resumePaths(localPath string) (partialPath, statePath string) {
+	dir, base := filepath.Split(localPath)
+	return filepath.Join(dir, "."+base+resumePartialSuffix), filepath.Join(dir, "."+base+resumeStateSuffix)
+}
+
+// Open the partial file for a download of object to localPath.  If a valid
+// partial download of the same object exists, the file is positioned at the
+// end of its verified data and the checksums are restored to match;
+// otherwise a new, empty partial file is created.
+func openResumableDownload(localPath, object string, hashes []io.Writer, hashTypes []ChecksumType) (rd *resumableDownload, err error) {
+	rd = &resumableDownload{
+		hashes:    hashes,
+		hashTypes: hashTypes,
+		metaCh:    make(chan TransferMetadata, 1),
+	}
+	rd.partialPath, rd.statePath = resumePaths(localPath)
+	rd.state = resumeState{Version: resumeStateVersion, Object: object, Size: -1}
+
+	if prev, loadErr := rd.loadState(); loadErr == nil && prev.Object == object {
+		if rd.fp, err = rd.reopenPartial(prev); err == nil {
+			rd.state = *prev
+			rd.offset = prev.BytesVerified
+			log.Infof("Resuming download of %s at byte %d", object, rd.offset)
+			return
+		}
+		log.Debugf("Unable to resume download of %s from %s: %v", object, rd.partialPath, err)
+		rd.resetHashes()
+	} else if loadErr != nil && !errors.Is(loadErr, os.ErrNotExist) {
+		log.Debugf("Ignoring unusable download state file %s: %v", rd.statePath, loadErr)
+	}
+
+	// Start from scratch
+	if err = os.Remove(rd.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrap(err, "failed to remove stale download state file")
+	}
+	if rd.fp, err = os.OpenFile(rd.partialPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+func (rd *resumableDownload) loadState() (*resumeState, error) {
+	contents, err := os.ReadFile(rd.statePath)
+	if err != nil {
+		return nil, err
+	}
+	state := &resumeState{}
+	if err := json.Unmarshal(contents, state); err != nil {
+		return nil, err
+	}
+	if state.Version != resumeStateVersion {
+		return nil, errors.Errorf("unsupported state version %d", state.Version)
+	}
+	return state, nil
+}
+
+// Open the partial file described by state, discarding anything past the
+// verified data, and restore the checksums computed over that data.
+func (rd *resumableDownload) reopenPartial(state *resumeState) (*os.File, error) {
+	if state.ETag == "" || state.BytesVerified <= 0 {
+		return nil, errors.New("state does not describe a resumable download")
+	}
+	for idx, t := range rd.hashTypes {
+		saved, ok := state.ChecksumState[HttpDigestFromChecksum(t)]
+		unmarshaler, canUnmarshal := rd.hashes[idx].(encoding.BinaryUnmarshaler)
+		if !ok || !canUnmarshal {
+			return nil, errors.Errorf("no saved state for %s checksum", HttpDigestFromChecksum(t))
+		}
+		if err := unmarshaler.UnmarshalBinary(saved); err != nil {
+			return nil, errors.Wrapf(err, "failed to restore %s checksum", HttpDigestFromChecksum(t))
+		}
+	}
+
+	fp, err := os.OpenFile(rd.partialPath, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fp.Stat()
+	if err == nil && info.Size() < state.BytesVerified {
+		err = errors.Errorf("partial file has %d bytes but %d were recorded", info.Size(), state.BytesVerified)
+	}
+	if err == nil {
+		err = fp.Truncate(state.BytesVerified)
+	}
+	if err == nil {
+		_, err = fp.Seek(state.BytesVerified, io.SeekStart)
+	}
+	if err != nil {
+		fp.Close()
+		return nil, err
+	}
+	return fp, nil
+}
+
+func (rd *resumableDownload) resetHashes() {
+	for _, h := range rd.hashes {
+		if hasher, ok := h.(hash.Hash); ok {
+			hasher.Reset()
+		}
+	}
+}
+
+// Write implements io.Writer, persisting progress periodically so it survives
+// the client being killed.
+func (rd *resumableDownload) Write(p []byte) (n int, err error) {
+	rd.pullMetadata()
+	n, err = rd.writer.Write(p)
+	rd.offset += int64(n)
+	if err == nil && time.Since(rd.lastCheckpoint) >= resumeCheckpointInterval {
+		if ckptErr := rd.checkpoint(); ckptErr != nil {
+			log.Warningln("Failed to save progress of resumable download:", ckptErr)
+		}
+	}
+	return
+}
+
+// Collect the metadata sent by downloadHTTP, if any, and forward it to the
+// caller's metadata channel the first time it is seen.
+func (rd *resumableDownload) pullMetadata() {
+	select {
+	case md := <-rd.metaCh:
+		if rd.state.ETag == "" {
+			rd.state.ETag = md.ETag
+		}
+		if md.ObjectSize > 0 {
+			rd.state.Size = md.ObjectSize
+		}
+		if rd.userMetaCh != nil {
+			select {
+			case rd.userMetaCh <- md:
+			default:
+			}
+			rd.userMetaCh = nil
+		}
+	default:
+	}
+}
+
+// Flush the partial file to disk and record the current progress in the sidecar
+func (rd *resumableDownload) checkpoint() error {
+	rd.lastCheckpoint = time.Now()
+	// Without an ETag there is no way to validate the data on a later run
+	if rd.state.ETag == "" || rd.offset == 0 {
+		return nil
+	}
+	checksums := make(map[string][]byte, len(rd.hashes))
+	for idx, t := range rd.hashTypes {
+		marshaler, ok := rd.hashes[idx].(encoding.BinaryMarshaler)
+		if !ok {
+			return errors.Errorf("%s checksum state cannot be saved", HttpDigestFromChecksum(t))
+		}
+		saved, err := marshaler.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		checksums[HttpDigestFromChecksum(t)] = saved
+	}
+	if err := rd.fp.Sync(); err != nil {
+		return err
+	}
+
+	rd.state.BytesVerified = rd.offset
+	rd.state.ChecksumState = checksums
+	rd.state.Updated = rd.lastCheckpoint
+	contents, err := json.Marshal(rd.state)
+	if err != nil {
+		return err
+	}
+	tmpPath := rd.statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, contents, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, rd.statePath)
+}
+
+// Discard the partial data so the object is downloaded from the beginning
+func (rd *resumableDownload) restart() error {
+	if err := rd.fp.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := rd.fp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	rd.resetHashes()
+	rd.offset = 0
+	rd.state.ETag = ""
+	rd.state.Size = -1
+	rd.state.BytesVerified = 0
+	if err := os.Remove(rd.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Record the outcome of the download.  On success the sidecar is removed (the
+// caller renames the partial file into place); on failure the progress is
+// saved so the partial file can be kept for a later resume, if possible.
+func (rd *resumableDownload) finish(success bool) {
+	rd.pullMetadata()
+	if !success {
+		if err := rd.checkpoint(); err != nil {
+			log.Warningln("Failed to save progress of resumable download; it will not be resumed:", err)
+		} else if rd.state.BytesVerified > 0 {
+			log.Infof("Keeping partial download %s (%d bytes) to resume later", rd.partialPath, rd.state.BytesVerified)
+			rd.retained = true
+			return
+		}
+	}
+	if err := os.Remove(rd.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warningln("Failed to remove download state file:", err)
+	}
+}
@@ -0,0 +1,168 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+// A server for a single object that drops the connection partway through the
+// first GET and otherwise serves the object with range support
+type resumeTestServer struct {
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	truncate bool
+	ranges   []string
+	ifRanges []string
+}
+
+func (s *resumeTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	content, etag, truncate := s.content, s.etag, s.truncate
+	if r.Method == http.MethodGet {
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		s.ifRanges = append(s.ifRanges, r.Header.Get("If-Range"))
+		s.truncate = false
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Digest", fmt.Sprintf("crc32c=%08x", crc32.Checksum(content, crc32cTable)))
+	if truncate {
+		// Claim the full object but only send the first half
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content[:len(content)/2])
+		return
+	}
+	http.ServeContent(w, r, "obj", time.Time{}, bytes.NewReader(content))
+}
+
+func TestResumableDownload(t *testing.T) {
+	test_utils.InitClient(t, nil)
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	srv := &resumeTestServer{content: content, etag: `"v1"`, truncate: true}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL + "/test/obj")
+	require.NoError(t, err)
+
+	localPath := filepath.Join(t.TempDir(), "obj")
+	partialPath, statePath := resumePaths(localPath)
+	download := func() (TransferResults, error) {
+		transfer := &transferFile{
+			xferType: transferTypeDownload,
+			ctx:      context.Background(),
+			job: &TransferJob{
+				remoteURL: &pelican_url.PelicanURL{Scheme: "pelican://", Host: serverURL.Host, Path: "/test/obj"},
+				resume:    true,
+			},
+			localPath:       localPath,
+			remoteURL:       serverURL,
+			requireChecksum: true,
+			attempts:        []transferAttemptDetails{{Url: serverURL}},
+		}
+		results, err := downloadObject(transfer)
+		if err == nil {
+			err = results.Error
+		}
+		return results, err
+	}
+
+	t.Run("resume-after-failure", func(t *testing.T) {
+		_, err := download()
+		require.Error(t, err)
+		assert.NoFileExists(t, localPath)
+		info, err := os.Stat(partialPath)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)/2), info.Size())
+		require.FileExists(t, statePath)
+
+		results, err := download()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)-len(content)/2), results.TransferredBytes)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.NoFileExists(t, partialPath)
+		assert.NoFileExists(t, statePath)
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Len(t, srv.ranges, 2)
+		assert.Equal(t, fmt.Sprintf("bytes=%d-", len(content)/2), srv.ranges[1])
+		assert.Equal(t, `"v1"`, srv.ifRanges[1])
+	})
+
+	t.Run("object-changed", func(t *testing.T) {
+		require.NoError(t, os.Remove(localPath))
+		srv.mu.Lock()
+		srv.truncate = true
+		srv.ranges = nil
+		srv.mu.Unlock()
+		_, err := download()
+		require.Error(t, err)
+		require.FileExists(t, statePath)
+
+		// A new version of the object must be downloaded from the beginning
+		newContent := bytes.Repeat([]byte("fedcba9876543210"), 4096)
+		srv.mu.Lock()
+		srv.content = newContent
+		srv.etag = `"v2"`
+		srv.mu.Unlock()
+		_, err = download()
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Equal(t, newContent, got)
+		assert.NoFileExists(t, statePath)
+	})
+
+	t.Run("corrupt-state", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(partialPath, []byte("garbage"), 0644))
+		require.NoError(t, os.WriteFile(statePath, []byte("{not json"), 0644))
+		_, err := download()
+		require.NoError(t, err)
+		got, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		assert.Len(t, got, len(content))
+		assert.NoFileExists(t, partialPath)
+		assert.NoFileExists(t, statePath)
+	})
+}
@@ -0,0 +1,325 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/studio-b12/gowebdav"
+
+	"github.com/pelicanplatform/pelican/pelican_url"
+)
+
+// syncSourceSet records the objects found at the source of a recursive
+// transfer, keyed by their slash-separated path relative to the transfer
+// root, so that extraneous destination objects can be identified once the
+// transfer completes.
+type syncSourceSet struct {
+	mu      sync.Mutex
+	walked  bool            // Set once the source was listed as a collection
+	entries map[string]bool // Relative path -> whether it is a collection
+}
+
+// markWalked notes that the source was successfully listed as a collection
+func (s *syncSourceSet) markWalked() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.walked = true
+}
+
+func (s *syncSourceSet) add(relPath string, isDir bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]bool)
+	}
+	s.entries[relPath] = isDir
+}
+
+func (s *syncSourceSet) lookup(relPath string) (isDir bool, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	isDir, found = s.entries[relPath]
+	return
+}
+
+func (s *syncSourceSet) isWalked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.walked
+}
+
+// normalizeSyncPath converts a path relative to the transfer root into the
+// form used as a syncSourceSet key.
+func normalizeSyncPath(relPath string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(relPath)), "/")
+}
+
+// Record an object seen at the source of the job; only tracked when the job
+// will later delete extraneous destination objects.
+func (tj *TransferJob) recordSyncSource(relPath string, isDir bool) {
+	if !tj.deleteExtraneous {
+		return
+	}
+	tj.syncSource.add(normalizeSyncPath(relPath), isDir)
+}
+
+// Returns the token to use for synchronization metadata requests.  Public
+// downloads don't need one, so avoid triggering a token acquisition for them.
+func (tj *TransferJob) syncToken() string {
+	if tj.token == nil || (tj.xferType == transferTypeDownload && !tj.dirResp.XPelNsHdr.RequireToken) {
+		return ""
+	}
+	contents, err := tj.token.Get()
+	if err != nil {
+		log.Debugln("Unable to get token for synchronization check:", err)
+		return ""
+	}
+	return contents
+}
+
+// Decide whether a destination is current with respect to its source using
+// size and modification time: the sizes must match and the destination must
+// not be older than the source.  WebDAV reports times with one-second
+// resolution, so the source time is truncated before comparison.  An
+// unknown time on either side is treated as out of date.
+func destinationUpToDate(srcSize int64, srcModTime time.Time, dstSize int64, dstModTime time.Time) bool {
+	if srcSize != dstSize || srcModTime.IsZero() || dstModTime.IsZero() {
+		return false
+	}
+	return !dstModTime.Before(srcModTime.Truncate(time.Second))
+}
+
+// Construct the per-object download URLs for objectPath from the job's
+// transfer attempts, mirroring the URLs the walk emits for each object.
+func downloadAttemptURLs(transfers []transferAttemptDetails, federationPath, objectPath string) []*url.URL {
+	if federationPath != "" && !strings.HasSuffix(federationPath, "/") {
+		federationPath += "/"
+	}
+	urls := make([]*url.URL, 0, len(transfers))
+	for _, attempt := range transfers {
+		if attempt.Url == nil {
+			continue
+		}
+		attemptPath := attempt.Url.Path
+		if attemptPath != "" && !strings.HasSuffix(attemptPath, "/") {
+			attemptPath += "/"
+		}
+		transferBase := strings.TrimSuffix(attemptPath, federationPath)
+		urls = append(urls, &url.URL{
+			Scheme:   attempt.Url.Scheme,
+			Host:     attempt.Url.Host,
+			Path:     path.Join(transferBase, objectPath),
+			RawQuery: attempt.Url.RawQuery,
+		})
+	}
+	return urls
+}
+
+// Construct the URL an upload of objectPath will be written to, mirroring
+// the destination computed by uploadObject.
+func uploadAttemptURL(transfers []transferAttemptDetails, objectPath string) *url.URL {
+	if len(transfers) == 0 || transfers[0].Url == nil {
+		return &url.URL{Path: objectPath}
+	}
+	dest := *transfers[0].Url
+	dest.Path = computeUploadDestPath(objectPath, dest.Path)
+	if dest.Scheme == "" {
+		dest.Scheme = "https"
+	}
+	return &dest
+}
+
+// Compute every known checksum of a local file in a single pass
+func computeLocalChecksums(localPath string) (map[ChecksumType][]byte, error) {
+	fp, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	writers, types := createAllChecksumHashes()
+	if _, err := io.Copy(io.MultiWriter(writers...), fp); err != nil {
+		return nil, err
+	}
+	result := make(map[ChecksumType][]byte, len(types))
+	for idx, t := range types {
+		result[t] = writers[idx].(hash.Hash).Sum(nil)
+	}
+	return result, nil
+}
+
+// Determine whether the local file matches the remote object by comparing
+// the local checksum against the Digest reported by the first of urls able
+// to provide one.  If no server can provide a checksum, the file is assumed
+// to differ so it will be transferred.
+func localChecksumMatches(ctx context.Context, localPath string, urls []*url.URL, token string, project string) bool {
+	var serverChecksums []ChecksumInfo
+	for _, u := range urls {
+		checksums, err := fetchChecksum(ctx, KnownChecksumTypes(), u, token, project)
+		if err != nil {
+			log.Debugf("Failed to fetch checksum from %s for synchronization: %v", u.String(), err)
+			continue
+		}
+		if len(checksums) > 0 {
+			serverChecksums = checksums
+			break
+		}
+	}
+	if len(serverChecksums) == 0 {
+		log.Debugln("No remote checksum available to compare with", localPath, "; it will be transferred")
+		return false
+	}
+
+	computed, err := computeLocalChecksums(localPath)
+	if err != nil {
+		log.Warningln("Failed to compute checksum of", localPath, "for synchronization:", err)
+		return false
+	}
+	for _, cksum := range serverChecksums {
+		if val, ok := computed[cksum.Algorithm]; ok {
+			return bytes.Equal(val, cksum.Value)
+		}
+	}
+	return false
+}
+
+// Remove (or, in dry-run mode, report) local files and directories below
+// the job's destination that were not present at the source.
+func deleteExtraneousLocal(tj *TransferJob) error {
+	root := tj.localPath
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil
+	}
+	return filepath.WalkDir(root, func(localPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if localPath == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, localPath)
+		if err != nil {
+			return err
+		}
+		if _, found := tj.syncSource.lookup(normalizeSyncPath(rel)); found {
+			return nil
+		}
+		if tj.dryRun {
+			fmt.Printf("DELETE: %s\n", localPath)
+		} else {
+			if err := os.RemoveAll(localPath); err != nil {
+				return errors.Wrapf(err, "failed to delete extraneous local path %s", localPath)
+			}
+			log.Infoln("Deleted extraneous local path", localPath)
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// Remove (or, in dry-run mode, report) remote objects and collections below
+// the job's destination that were not present at the source.
+func deleteExtraneousRemote(ctx context.Context, tj *TransferJob) error {
+	collectionsUrl := tj.dirResp.XPelNsHdr.CollectionsUrl
+	if collectionsUrl == nil {
+		return errors.New("namespace does not provide a collections URL; cannot determine extraneous remote objects")
+	}
+	client := createWebDavClient(collectionsUrl, tj.token, tj.project)
+	root := path.Clean(tj.remoteURL.Path)
+
+	var walk func(remoteDir string) error
+	walk = func(remoteDir string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var infos []fs.FileInfo
+		err := retryWebDavOperation("ReadDir", func() error {
+			var err error
+			infos, err = client.ReadDir(remoteDir)
+			return err
+		})
+		if err != nil {
+			if gowebdav.IsErrNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(err, "failed to list remote collection %s", remoteDir)
+		}
+		for _, info := range infos {
+			remotePath := path.Join(remoteDir, info.Name())
+			if isDir, found := tj.syncSource.lookup(normalizeSyncPath(strings.TrimPrefix(remotePath, root))); found {
+				if isDir && info.IsDir() {
+					if err := walk(remotePath); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if tj.dryRun {
+				fmt.Printf("DELETE: %s\n", remotePath)
+				continue
+			}
+			if err := deleteHttp(ctx, &pelican_url.PelicanURL{Path: remotePath}, true, tj.dirResp, tj.token); err != nil {
+				return errors.Wrapf(err, "failed to delete extraneous remote object %s", remotePath)
+			}
+			log.Infoln("Deleted extraneous remote object", remotePath)
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+// Delete objects at the destination of a completed recursive job that are
+// absent from its source.  This is a no-op unless the job was created with
+// WithDeleteExtraneous and its source was a collection that was fully listed.
+func (tj *TransferJob) removeExtraneous(ctx context.Context) error {
+	if !tj.deleteExtraneous || !tj.recursive {
+		return nil
+	}
+	if tj.lookupErr != nil || !tj.syncSource.isWalked() {
+		log.Debugln("Source was not fully listed as a collection; not deleting extraneous destination objects")
+		return nil
+	}
+	switch tj.xferType {
+	case transferTypeDownload:
+		if tj.writer != nil || tj.localPath == os.DevNull {
+			return nil
+		}
+		return deleteExtraneousLocal(tj)
+	case transferTypeUpload:
+		return deleteExtraneousRemote(ctx, tj)
+	}
+	return nil
+}
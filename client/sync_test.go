@@ -0,0 +1,144 @@
+//go:build !windows
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/pelican_url"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestDestinationUpToDate(t *testing.T) {
+	now := time.Now()
+	assert.True(t, destinationUpToDate(10, now, 10, now))
+	assert.True(t, destinationUpToDate(10, now, 10, now.Add(time.Hour)))
+	// Sub-second source precision is ignored
+	assert.True(t, destinationUpToDate(10, now, 10, now.Truncate(time.Second)))
+	assert.False(t, destinationUpToDate(10, now, 10, now.Add(-time.Hour)))
+	assert.False(t, destinationUpToDate(10, now, 11, now))
+	assert.False(t, destinationUpToDate(10, time.Time{}, 10, now))
+	assert.False(t, destinationUpToDate(10, now, 10, time.Time{}))
+}
+
+func TestSkipDownloadSyncLevels(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	test_utils.InitClient(t, map[param.Param]any{})
+
+	content := []byte("analysis output v2")
+	digest := fmt.Sprintf("crc32c=%08x", crc32.Checksum(content, crc32cTable))
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/test/dir/obj" {
+			w.Header().Set("Digest", digest)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer svr.Close()
+	svrURL, err := url.Parse(svr.URL)
+	require.NoError(t, err)
+
+	localDir := t.TempDir()
+	localPath := filepath.Join(localDir, "obj")
+	require.NoError(t, os.WriteFile(localPath, content, 0644))
+	localInfo, err := os.Stat(localPath)
+	require.NoError(t, err)
+
+	job := &TransferJob{
+		ctx:       context.Background(),
+		xferType:  transferTypeDownload,
+		remoteURL: &pelican_url.PelicanURL{Path: "/test/dir"},
+		dirResp:   server_structs.DirectorResponse{},
+	}
+	transfers := []transferAttemptDetails{{Url: &url.URL{Scheme: "http", Host: svrURL.Host, Path: "/test/dir"}}}
+	remoteInfo := &pelicanFileInfo{name: "obj", size: int64(len(content)), modTime: localInfo.ModTime().Add(-time.Minute)}
+
+	t.Run("modtime", func(t *testing.T) {
+		job.syncLevel = SyncModTime
+		assert.True(t, skipDownload(job, transfers, remoteInfo, "/test/dir/obj", localPath))
+
+		newer := *remoteInfo
+		newer.modTime = localInfo.ModTime().Add(time.Minute)
+		assert.False(t, skipDownload(job, transfers, &newer, "/test/dir/obj", localPath))
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		job.syncLevel = SyncChecksum
+		assert.True(t, skipDownload(job, transfers, remoteInfo, "/test/dir/obj", localPath))
+
+		// Same size, different content must be transferred
+		require.NoError(t, os.WriteFile(localPath, []byte("analysis output v1"), 0644))
+		assert.False(t, skipDownload(job, transfers, remoteInfo, "/test/dir/obj", localPath))
+
+		// No digest available from the server
+		assert.False(t, skipDownload(job, transfers, remoteInfo, "/test/dir/missing", localPath))
+	})
+}
+
+func TestDeleteExtraneousLocal(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "keep", "stale"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "keep", "a"), []byte("a"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "keep", "stale", "b"), []byte("b"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "extra"), []byte("c"), 0644))
+
+	job := &TransferJob{
+		xferType:         transferTypeDownload,
+		recursive:        true,
+		deleteExtraneous: true,
+		localPath:        root,
+	}
+	job.syncSource.markWalked()
+	job.recordSyncSource("/keep", true)
+	job.recordSyncSource("/keep/a", false)
+
+	job.dryRun = true
+	require.NoError(t, job.removeExtraneous(context.Background()))
+	assert.FileExists(t, filepath.Join(root, "extra"))
+	assert.DirExists(t, filepath.Join(root, "keep", "stale"))
+
+	job.dryRun = false
+	require.NoError(t, job.removeExtraneous(context.Background()))
+	assert.NoFileExists(t, filepath.Join(root, "extra"))
+	assert.NoDirExists(t, filepath.Join(root, "keep", "stale"))
+	assert.FileExists(t, filepath.Join(root, "keep", "a"))
+
+	// Nothing is deleted if the source was never listed
+	require.NoError(t, os.WriteFile(filepath.Join(root, "extra"), []byte("c"), 0644))
+	unwalked := &TransferJob{xferType: transferTypeDownload, recursive: true, deleteExtraneous: true, localPath: root}
+	require.NoError(t, unwalked.removeExtraneous(context.Background()))
+	assert.FileExists(t, filepath.Join(root, "extra"))
+}
@@ -19,6 +19,7 @@
 package apiclient
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -26,6 +27,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -34,6 +36,13 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 )
 
+const (
+	// Consecutive attempts to reconnect to a job's event stream without
+	// receiving an event before WatchJob gives up
+	watchMaxRetries = 5
+	watchRetryDelay = 500 * time.Millisecond
+)
+
 // APIClient provides a client interface to the Pelican Client API Server
 type APIClient struct {
 	socketPath string
@@ -96,11 +105,15 @@ func (c *APIClient) IsServerRunning(ctx context.Context) bool {
 
 // CreateJob creates a new transfer job and returns the job ID
 func (c *APIClient) CreateJob(ctx context.Context, transfers []client_agent.TransferRequest, options client_agent.TransferOptions) (string, error) {
-	jobReq := client_agent.JobRequest{
+	return c.SubmitJob(ctx, client_agent.JobRequest{
 		Transfers: transfers,
 		Options:   options,
-	}
+	})
+}
 
+// SubmitJob creates a new transfer job from a full job request, which may
+// delay the start of the job or make it depend on other jobs
+func (c *APIClient) SubmitJob(ctx context.Context, jobReq client_agent.JobRequest) (string, error) {
 	body, err := json.Marshal(jobReq)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to marshal job request")
@@ -187,6 +200,122 @@ func (c *APIClient) WaitForJob(ctx context.Context, jobID string, timeout time.D
 	}
 }
 
+// WatchJob follows the job's event stream, calling handler for each event
+// until the job finishes, the handler returns an error, or the context is
+// cancelled.  If the stream is interrupted, it is resumed from the last
+// event received so no job or transfer state transitions are missed.
+func (c *APIClient) WatchJob(ctx context.Context, jobID string, handler func(client_agent.JobEvent) error) error {
+	// The stream lasts as long as the job, so the client's request timeout does not apply
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+
+	lastEventID := ""
+	failures := 0
+	for {
+		finished, received, err := c.readJobEvents(ctx, &streamClient, jobID, &lastEventID, handler)
+		if finished {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			failures = 0
+		} else if failures++; failures >= watchMaxRetries {
+			return errors.Wrap(err, "failed to follow job events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// readJobEvents reads the job's event stream once, starting after lastEventID
+// and updating it as events arrive.  finished is true if the stream should
+// not be resumed: the job finished, the handler failed, or the server
+// rejected the request.
+func (c *APIClient) readJobEvents(ctx context.Context, httpClient *http.Client, jobID string, lastEventID *string, handler func(client_agent.JobEvent) error) (finished, received bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/jobs/"+jobID+"/events", nil)
+	if err != nil {
+		return true, false, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, false, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode < http.StatusInternalServerError, false, errors.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	// Snapshots of jobs with many transfers can exceed the default line limit
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var id, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line dispatches the event
+			if data != "" {
+				var event client_agent.JobEvent
+				if err := json.Unmarshal([]byte(data), &event); err != nil {
+					return true, received, errors.Wrap(err, "failed to decode job event")
+				}
+				if id != "" {
+					*lastEventID = id
+				}
+				received = true
+				if err := handler(event); err != nil {
+					return true, received, err
+				}
+				if (event.Type == client_agent.EventJobStatus || event.Type == client_agent.EventSnapshot) && isFinished(event.Status) {
+					return true, received, nil
+				}
+			}
+			id, data = "", ""
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comments keep the stream alive
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			if data != "" {
+				data += "\n"
+			}
+			data += value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, received, errors.Wrap(err, "failed to read job events")
+	}
+	return false, received, errors.New("job event stream ended before the job finished")
+}
+
+// isFinished returns true if a job in the status will not change again
+func isFinished(status string) bool {
+	switch status {
+	case client_agent.StatusCompleted, client_agent.StatusFailed, client_agent.StatusCancelled:
+		return true
+	}
+	return false
+}
+
 // CancelJob cancels a running job
 func (c *APIClient) CancelJob(ctx context.Context, jobID string) error {
 	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/jobs/"+jobID, nil)
@@ -208,6 +337,85 @@ func (c *APIClient) CancelJob(ctx context.Context, jobID string) error {
 	return nil
 }
 
+// CreateSchedule creates a recurring transfer job
+func (c *APIClient) CreateSchedule(ctx context.Context, scheduleReq client_agent.ScheduleRequest) (*client_agent.ScheduleStatus, error) {
+	body, err := json.Marshal(scheduleReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal schedule request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/schedules", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var schedule client_agent.ScheduleStatus
+	if err := json.NewDecoder(resp.Body).Decode(&schedule); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &schedule, nil
+}
+
+// ListSchedules lists all recurring transfer jobs
+func (c *APIClient) ListSchedules(ctx context.Context) (*client_agent.ScheduleListResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/schedules", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, errors.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var listResp client_agent.ScheduleListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &listResp, nil
+}
+
+// DeleteSchedule deletes a recurring transfer job; jobs it already started are not cancelled
+func (c *APIClient) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/schedules/"+scheduleID, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 // ListJobs lists all jobs with optional filtering
 func (c *APIClient) ListJobs(ctx context.Context, status string, limit, offset int) (*client_agent.JobListResponse, error) {
 	url := fmt.Sprintf("%s/jobs?limit=%d&offset=%d", c.baseURL, limit, offset)
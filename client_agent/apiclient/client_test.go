@@ -23,12 +23,16 @@ package apiclient_test
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
@@ -574,3 +578,69 @@ func TestAPIClientEndToEnd(t *testing.T) {
 
 	t.Log("End-to-end test completed successfully!")
 }
+
+// TestAPIClientWatchJob tests following a job's event stream, including
+// resuming the stream after it is interrupted
+func TestAPIClientWatchJob(t *testing.T) {
+	socketDir, err := os.MkdirTemp("", "watch")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(socketDir) })
+	socketPath := filepath.Join(socketDir, "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	// The first stream is interrupted after two events; the second resumes
+	// after the last event received and finishes the job
+	var requests atomic.Int32
+	var resumedFrom atomic.Value
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1.0/transfer-agent/jobs/job-1/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if requests.Add(1) == 1 {
+			fmt.Fprint(w, "id: 1\nevent: snapshot\ndata: {\"id\":1,\"type\":\"snapshot\",\"job_id\":\"job-1\",\"status\":\"running\"}\n\n")
+			fmt.Fprint(w, ": keepalive\n\n")
+			fmt.Fprint(w, "id: 2\nevent: transfer_status\ndata: {\"id\":2,\"type\":\"transfer_status\",\"job_id\":\"job-1\",\n")
+			fmt.Fprint(w, "data: \"transfer_id\":\"transfer-1\",\"status\":\"completed\"}\n\n")
+			return
+		}
+		resumedFrom.Store(r.Header.Get("Last-Event-ID"))
+		fmt.Fprint(w, "id: 3\nevent: job_status\ndata: {\"id\":3,\"type\":\"job_status\",\"job_id\":\"job-1\",\"status\":\"completed\"}\n\n")
+	})
+	mux.HandleFunc("/api/v1.0/transfer-agent/jobs/unknown/events", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() { server.Close() })
+
+	apiClient, err := apiclient.NewAPIClient(socketPath)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var events []client_agent.JobEvent
+	err = apiClient.WatchJob(ctx, "job-1", func(event client_agent.JobEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, client_agent.EventSnapshot, events[0].Type)
+	assert.Equal(t, "transfer-1", events[1].TransferID)
+	assert.Equal(t, client_agent.StatusCompleted, events[2].Status)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, "2", resumedFrom.Load())
+
+	// Errors from the handler and the server end the watch
+	requests.Store(0)
+	err = apiClient.WatchJob(ctx, "job-1", func(event client_agent.JobEvent) error {
+		return errors.New("stop")
+	})
+	assert.EqualError(t, err, "stop")
+	err = apiClient.WatchJob(ctx, "unknown", func(event client_agent.JobEvent) error {
+		return nil
+	})
+	assert.ErrorContains(t, err, "404")
+}
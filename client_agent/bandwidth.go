@@ -0,0 +1,90 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"context"
+
+	"github.com/pelicanplatform/pelican/byte_rate"
+	"github.com/pelicanplatform/pelican/htb"
+)
+
+type (
+	// jobBandwidthLimiter throttles the transfers of a single job.  Bytes are
+	// charged to the job's bucket in each limiter: the agent-wide hierarchical
+	// token bucket, whose children are the running jobs so that each gets a
+	// fair share of the cap, and the job's own cap, if it has one.
+	jobBandwidthLimiter struct {
+		jobID    string
+		limiters []bandwidthBucket
+	}
+
+	bandwidthBucket struct {
+		bucket  *htb.HTB
+		stepMax int64 // Largest single request, so one transfer cannot starve the others
+	}
+)
+
+// newBandwidthBucket creates a token bucket, denominated in bytes, that
+// holds one second's worth of tokens.  Returns nil if the limit is not set.
+func newBandwidthBucket(limit byte_rate.ByteRate) *bandwidthBucket {
+	if limit <= 0 {
+		return nil
+	}
+	stepMax := int64(limit) / 10
+	if stepMax <= 0 {
+		stepMax = 1
+	}
+	return &bandwidthBucket{
+		bucket:  htb.New(float64(limit), int64(limit)),
+		stepMax: stepMax,
+	}
+}
+
+// newJobBandwidthLimiter returns the limiter for the job's transfers, or nil
+// if neither the agent nor the job caps bandwidth
+func (tm *TransferManager) newJobBandwidthLimiter(job *TransferJob) *jobBandwidthLimiter {
+	limiter := &jobBandwidthLimiter{jobID: job.ID}
+	if jobBucket := newBandwidthBucket(job.BandwidthLimit); jobBucket != nil {
+		limiter.limiters = append(limiter.limiters, *jobBucket)
+	}
+	if tm.bandwidth != nil {
+		limiter.limiters = append(limiter.limiters, *tm.bandwidth)
+	}
+	if len(limiter.limiters) == 0 {
+		return nil
+	}
+	return limiter
+}
+
+// WaitN implements client.BandwidthLimiter, blocking until n bytes may be
+// transferred under every limit
+func (l *jobBandwidthLimiter) WaitN(ctx context.Context, n int) error {
+	for _, limiter := range l.limiters {
+		remaining := int64(n)
+		for remaining > 0 {
+			step := min(remaining, limiter.stepMax)
+			if _, err := limiter.bucket.Wait(ctx, l.jobID, step); err != nil {
+				return err
+			}
+			remaining -= step
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronSchedule is a parsed cron expression.  Each field is a bitset of the
+// values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+	every                         time.Duration // Set for "@every <duration>" expressions
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronFields = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		}},
+		// Both 0 and 7 are Sunday
+		{name: "day of week", min: 0, max: 7, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		}},
+	}
+
+	cronMacros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// How far ahead to search for the next run of a schedule; expressions such
+// as "0 0 30 2 *" never match
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// parseCron parses a standard five-field cron expression (minute, hour, day
+// of month, month, day of week), one of the @yearly, @monthly, @weekly,
+// @daily, @midnight or @hourly macros, or "@every <duration>".  Fields
+// support lists, ranges, steps and three-letter month and day names.
+func parseCron(spec string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid interval in cron expression %q", spec)
+		}
+		if every < time.Minute {
+			return nil, errors.Errorf("interval in cron expression %q must be at least one minute", spec)
+		}
+		return &cronSchedule{every: every}, nil
+	}
+	if expanded, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, errors.Errorf("cron expression %q must have %d fields", spec, len(cronFields))
+	}
+	bits := make([]uint64, len(cronFields))
+	for idx, part := range parts {
+		value, err := parseCronField(part, cronFields[idx])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression %q", spec)
+		}
+		bits[idx] = value
+	}
+
+	schedule := &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	return schedule, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps
+func parseCronField(spec string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(spec, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepSpec); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q in %s field", stepSpec, field.name)
+			}
+		}
+
+		low, high := field.min, field.max
+		if rangeSpec != "*" {
+			lowSpec, highSpec, isRange := strings.Cut(rangeSpec, "-")
+			var err error
+			if low, err = parseCronValue(lowSpec, field); err != nil {
+				return 0, err
+			}
+			if isRange {
+				if high, err = parseCronValue(highSpec, field); err != nil {
+					return 0, err
+				}
+				if high < low {
+					return 0, errors.Errorf("invalid range %q in %s field", rangeSpec, field.name)
+				}
+			} else if !hasStep {
+				// A single value; "5/15" means every 15 starting at 5
+				high = low
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(spec string, field cronField) (int, error) {
+	if value, ok := field.names[strings.ToLower(spec)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(spec)
+	if err != nil || value < field.min || value > field.max {
+		return 0, errors.Errorf("invalid value %q in %s field (must be %d-%d)", spec, field.name, field.min, field.max)
+	}
+	return value, nil
+}
+
+// next returns the first time after the given one that the schedule runs,
+// in the given time's location, or the zero time if it never runs
+func (cs *cronSchedule) next(after time.Time) time.Time {
+	if cs.every > 0 {
+		return after.Add(cs.every).Truncate(time.Second)
+	}
+
+	loc := after.Location()
+	current := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(cronSearchLimit)
+	for current.Before(limit) {
+		year, month, day := current.Date()
+		if cs.month&(1<<uint(month)) == 0 {
+			current = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !cs.dayMatches(current) {
+			current = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if cs.hour&(1<<uint(current.Hour())) == 0 {
+			current = time.Date(year, month, day, current.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if cs.minute&(1<<uint(current.Minute())) == 0 {
+			current = current.Add(time.Minute)
+			continue
+		}
+		return current
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron's rule that when both the day of month and day of
+// week are restricted, a day matching either one matches
+func (cs *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := cs.dom&(1<<uint(t.Day())) != 0
+	dowMatch := cs.dow&(1<<uint(t.Weekday())) != 0
+	if cs.domAny || cs.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,85 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday, 15 January 2025
+	start := time.Date(2025, time.January, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 15, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, time.January, 16, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", time.Date(2025, time.January, 15, 13, 0, 0, 0, time.UTC)},
+		{"30 1 * * 0", time.Date(2025, time.January, 19, 1, 30, 0, 0, time.UTC)},
+		{"30 1 * * 7", time.Date(2025, time.January, 19, 1, 30, 0, 0, time.UTC)},
+		{"0 0 1 feb,mar *", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		// Restricting both the day of month and day of week matches either
+		{"0 0 20 * fri", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2025, time.January, 15, 12, 0, 45, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := parseCron(test.spec)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, schedule.next(start))
+		})
+	}
+
+	// Expressions that can never match have no next run
+	schedule, err := parseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.next(start).IsZero())
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@every 10s",
+		"@every soon",
+		"@fortnightly",
+	} {
+		_, err := parseCron(spec)
+		assert.Error(t, err, "expected %q to be rejected", spec)
+	}
+}
@@ -0,0 +1,235 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+// maxRetainedJobEvents is the number of job and transfer state transitions
+// kept in memory for clients resuming an event stream
+const maxRetainedJobEvents = 10000
+
+// jobEventLog records the events published by the transfer manager so
+// clients following a job can resume from the last event they saw.
+//
+// State transitions are retained (up to maxRetainedJobEvents across all
+// jobs) while only the most recent progress event for each job is kept;
+// a client that reconnects needs the latest byte counts, not every update.
+//
+// Event IDs restart whenever the agent does, so the IDs sent to clients are
+// prefixed with the epoch of the log; an ID from another epoch refers to
+// events this log never saw.
+type jobEventLog struct {
+	epoch          string
+	mu             sync.Mutex
+	lastID         uint64
+	events         []JobEvent
+	droppedThrough uint64 // ID of the newest transition no longer retained
+	progress       map[string]JobEvent
+	notify         chan struct{} // Closed and replaced whenever an event is published
+}
+
+func newJobEventLog() *jobEventLog {
+	return &jobEventLog{
+		epoch:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		progress: make(map[string]JobEvent),
+		notify:   make(chan struct{}),
+	}
+}
+
+// publish assigns the event the next ID and wakes up any waiting streams
+func (el *jobEventLog) publish(event JobEvent) {
+	if el == nil {
+		return
+	}
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	el.lastID++
+	event.ID = el.lastID
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	if event.Type == EventProgress {
+		el.progress[event.JobID] = event
+	} else {
+		el.events = append(el.events, event)
+		if len(el.events) > maxRetainedJobEvents {
+			// Drop a tenth of the log at a time so trimming is amortized
+			drop := len(el.events) - maxRetainedJobEvents + maxRetainedJobEvents/10
+			el.droppedThrough = el.events[drop-1].ID
+			el.events = append([]JobEvent(nil), el.events[drop:]...)
+		}
+		// The final status of a job supersedes its progress
+		if event.Type == EventJobStatus && isTerminalStatus(event.Status) {
+			delete(el.progress, event.JobID)
+		}
+	}
+
+	close(el.notify)
+	el.notify = make(chan struct{})
+}
+
+// streamID returns the ID sent to clients for the event with the given ID
+func (el *jobEventLog) streamID(id uint64) string {
+	return el.epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// parseStreamID returns the event ID encoded in an ID previously sent to a
+// client.  sameEpoch is false if the ID was issued before the agent last
+// restarted, in which case the returned event ID is meaningless.
+func (el *jobEventLog) parseStreamID(streamID string) (id uint64, sameEpoch bool, err error) {
+	epoch, idStr, found := strings.Cut(streamID, "-")
+	if !found {
+		// IDs without an epoch come from an agent predating them
+		epoch, idStr = "", streamID
+	}
+	if id, err = strconv.ParseUint(idStr, 10, 64); err != nil {
+		return 0, false, errors.Errorf("invalid event ID %q", streamID)
+	}
+	return id, epoch == el.epoch, nil
+}
+
+// changed returns a channel closed when the next event is published
+func (el *jobEventLog) changed() <-chan struct{} {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	return el.notify
+}
+
+// since returns the job's events published after the given ID, ordered by ID,
+// along with the ID of the latest event published for any job and a channel
+// closed when the next event is published.
+//
+// gap is true if events the client has not seen may no longer be available
+// (or the ID is from before the agent restarted), in which case the client
+// should be sent a fresh snapshot of the job.
+func (el *jobEventLog) since(jobID string, after uint64) (events []JobEvent, gap bool, current uint64, wait <-chan struct{}) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	current = el.lastID
+	wait = el.notify
+	if after > el.lastID || after < el.droppedThrough {
+		return nil, true, current, wait
+	}
+
+	start := sort.Search(len(el.events), func(idx int) bool { return el.events[idx].ID > after })
+	for _, event := range el.events[start:] {
+		if event.JobID == jobID {
+			events = append(events, event)
+		}
+	}
+	if event, ok := el.progress[jobID]; ok && event.ID > after {
+		events = append(events, event)
+		sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	}
+	return events, false, current, wait
+}
+
+// publishJobStatus publishes the job's current status.  Must be called with
+// the transfer manager's mutex held, or before the job has started.
+func (tm *TransferManager) publishJobStatus(job *TransferJob) {
+	event := JobEvent{
+		Type:   EventJobStatus,
+		JobID:  job.ID,
+		Status: job.Status,
+	}
+	if job.Error != nil {
+		event.Error = job.Error.Error()
+	}
+	tm.events.publish(event)
+}
+
+// publishTransferStatus publishes the transfer's current status.  Must be
+// called with the transfer manager's mutex held.
+func (tm *TransferManager) publishTransferStatus(transfer *Transfer) {
+	event := JobEvent{
+		Type:       EventTransferStatus,
+		JobID:      transfer.JobID,
+		TransferID: transfer.ID,
+		Status:     transfer.Status,
+	}
+	if transfer.Error != nil {
+		event.Error = transfer.Error.Error()
+	}
+	tm.events.publish(event)
+}
+
+// publishProgressEvents periodically publishes the progress of each running
+// job whose byte count has changed since its last progress event
+func (tm *TransferManager) publishProgressEvents() {
+	interval := param.ClientAgent_ProgressEventInterval.GetDuration()
+	if interval <= 0 {
+		interval = time.Second // Default if not configured
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastBytes := make(map[string]int64)
+	for {
+		select {
+		case <-tm.ctx.Done():
+			log.Debug("Stopping progress event task")
+			return
+		case <-ticker.C:
+		}
+
+		tm.mu.RLock()
+		var running []*TransferJob
+		for _, job := range tm.jobs {
+			if job.Status == StatusRunning {
+				running = append(running, job)
+			}
+		}
+		tm.mu.RUnlock()
+
+		active := make(map[string]int64, len(running))
+		for _, job := range running {
+			progress := tm.GetJobProgress(job)
+			active[job.ID] = progress.BytesTransferred
+			if last, ok := lastBytes[job.ID]; ok && last == progress.BytesTransferred {
+				continue
+			}
+			tm.events.publish(JobEvent{
+				Type:     EventProgress,
+				JobID:    job.ID,
+				Progress: progress,
+			})
+		}
+		lastBytes = active
+	}
+}
+
+// isTerminalStatus returns true if a job or transfer in the status will not change again
+func isTerminalStatus(status string) bool {
+	return status == StatusCompleted || status == StatusFailed || status == StatusCancelled
+}
@@ -0,0 +1,242 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEventLog(t *testing.T) {
+	el := newJobEventLog()
+
+	el.publish(JobEvent{Type: EventJobStatus, JobID: "job-a", Status: StatusPending})
+	el.publish(JobEvent{Type: EventJobStatus, JobID: "job-b", Status: StatusPending})
+	el.publish(JobEvent{Type: EventJobStatus, JobID: "job-a", Status: StatusRunning})
+	el.publish(JobEvent{Type: EventProgress, JobID: "job-a", Progress: &JobProgress{BytesTransferred: 10}})
+	el.publish(JobEvent{Type: EventTransferStatus, JobID: "job-a", TransferID: "transfer-1", Status: StatusRunning})
+	el.publish(JobEvent{Type: EventProgress, JobID: "job-a", Progress: &JobProgress{BytesTransferred: 20}})
+
+	// Only the job's events are returned, with only the latest progress
+	events, gap, current, wait := el.since("job-a", 0)
+	assert.False(t, gap)
+	assert.Equal(t, uint64(6), current)
+	require.Len(t, events, 4)
+	assert.Equal(t, []uint64{1, 3, 5, 6}, []uint64{events[0].ID, events[1].ID, events[2].ID, events[3].ID})
+	assert.Equal(t, int64(20), events[3].Progress.BytesTransferred)
+	assert.False(t, events[0].Timestamp.IsZero())
+
+	// Resuming returns only the events after the given ID
+	events, gap, _, _ = el.since("job-a", 5)
+	assert.False(t, gap)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventProgress, events[0].Type)
+
+	// Publishing wakes up waiting streams
+	select {
+	case <-wait:
+		t.Fatal("wait channel closed before an event was published")
+	default:
+	}
+	el.publish(JobEvent{Type: EventJobStatus, JobID: "job-a", Status: StatusCompleted})
+	select {
+	case <-wait:
+	case <-time.After(time.Second):
+		t.Fatal("wait channel not closed after an event was published")
+	}
+
+	// The final status supersedes the job's progress
+	events, _, _, _ = el.since("job-a", 6)
+	require.Len(t, events, 1)
+	assert.Equal(t, StatusCompleted, events[0].Status)
+
+	// An ID from the future (e.g. before the agent restarted) is a gap
+	_, gap, _, _ = el.since("job-a", 100)
+	assert.True(t, gap)
+
+	// Once old transitions are trimmed, resuming from before them is a gap
+	for idx := 0; idx < maxRetainedJobEvents; idx++ {
+		el.publish(JobEvent{Type: EventTransferStatus, JobID: "job-b", Status: StatusRunning})
+	}
+	assert.LessOrEqual(t, len(el.events), maxRetainedJobEvents)
+	_, gap, _, _ = el.since("job-a", 5)
+	assert.True(t, gap)
+	_, gap, current, _ = el.since("job-b", el.lastID-1)
+	assert.False(t, gap)
+	assert.Equal(t, el.lastID, current)
+
+	// Stream IDs carry the log's epoch
+	id, sameEpoch, err := el.parseStreamID(el.streamID(42))
+	require.NoError(t, err)
+	assert.True(t, sameEpoch)
+	assert.Equal(t, uint64(42), id)
+	_, sameEpoch, err = (&jobEventLog{epoch: "other"}).parseStreamID(el.streamID(42))
+	require.NoError(t, err)
+	assert.False(t, sameEpoch, "IDs from another agent process are from another epoch")
+	_, sameEpoch, err = el.parseStreamID("42")
+	require.NoError(t, err)
+	assert.False(t, sameEpoch)
+	_, _, err = el.parseStreamID(el.epoch + "-abc")
+	assert.Error(t, err)
+}
+
+// Read server-sent events from the stream until the job finishes, skipping
+// the progress events published in the background
+func readSSEEvents(t *testing.T, el *jobEventLog, scanner *bufio.Scanner, events chan<- JobEvent) {
+	defer close(events)
+	var id string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			var event JobEvent
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+			assert.Equal(t, el.streamID(event.ID), id)
+			if event.Type != EventProgress {
+				events <- event
+			}
+		}
+	}
+}
+
+func TestGetJobEventsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tm := NewTransferManager(context.Background(), 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+	server := &Server{
+		transferManager: tm,
+		router:          gin.New(),
+	}
+	server.setupRoutes()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	// Add a job directly so no transfers are attempted
+	transfer := &Transfer{ID: "transfer-1", JobID: "job-1", Operation: "get", Source: "pelican://example.com/foo", Status: StatusPending}
+	job := &TransferJob{ID: "job-1", Status: StatusPending, CreatedAt: time.Now(), Transfers: []*Transfer{transfer}}
+	tm.mu.Lock()
+	tm.jobs[job.ID] = job
+	tm.transfers[transfer.ID] = transfer
+	tm.publishJobStatus(job)
+	tm.mu.Unlock()
+
+	resp, err := http.Get(ts.URL + "/api/v1.0/transfer-agent/jobs/job-1/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	events := make(chan JobEvent, 10)
+	go readSSEEvents(t, tm.events, bufio.NewScanner(resp.Body), events)
+
+	// A new client starts with a snapshot of the job
+	snapshot := <-events
+	assert.Equal(t, EventSnapshot, snapshot.Type)
+	assert.Equal(t, StatusPending, snapshot.Status)
+	require.NotNil(t, snapshot.Job)
+	require.Len(t, snapshot.Job.Transfers, 1)
+	assert.Equal(t, "transfer-1", snapshot.Job.Transfers[0].TransferID)
+
+	// Transitions are pushed as they happen
+	tm.mu.Lock()
+	job.Status = StatusRunning
+	tm.publishJobStatus(job)
+	transfer.Status = StatusRunning
+	tm.publishTransferStatus(transfer)
+	tm.mu.Unlock()
+	event := <-events
+	assert.Equal(t, EventJobStatus, event.Type)
+	assert.Equal(t, StatusRunning, event.Status)
+	event = <-events
+	assert.Equal(t, EventTransferStatus, event.Type)
+	assert.Equal(t, "transfer-1", event.TransferID)
+	resumeFrom := tm.events.streamID(event.ID)
+
+	// The stream ends after the job finishes
+	tm.mu.Lock()
+	transfer.Status = StatusCompleted
+	tm.publishTransferStatus(transfer)
+	job.Status = StatusCompleted
+	tm.publishJobStatus(job)
+	tm.mu.Unlock()
+	event = <-events
+	assert.Equal(t, StatusCompleted, event.Status)
+	event = <-events
+	assert.Equal(t, EventJobStatus, event.Type)
+	assert.Equal(t, StatusCompleted, event.Status)
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// A reconnecting client receives only the events it missed
+	req, err := http.NewRequest("GET", ts.URL+"/api/v1.0/transfer-agent/jobs/job-1/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", resumeFrom)
+	resumed, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resumed.Body.Close()
+	events = make(chan JobEvent, 10)
+	go readSSEEvents(t, tm.events, bufio.NewScanner(resumed.Body), events)
+	var missed []JobEvent
+	for event := range events {
+		missed = append(missed, event)
+	}
+	require.Len(t, missed, 2)
+	assert.Equal(t, EventTransferStatus, missed[0].Type)
+	assert.Equal(t, EventJobStatus, missed[1].Type)
+
+	// A client resuming from before the agent restarted is told to reset,
+	// even though its ID is one the new process has also issued
+	resp, err = http.Get(ts.URL + "/api/v1.0/transfer-agent/jobs/job-1/events?last_event_id=" + (&jobEventLog{epoch: "other"}).streamID(1))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	events = make(chan JobEvent, 10)
+	go readSSEEvents(t, tm.events, bufio.NewScanner(resp.Body), events)
+	var reset []JobEvent
+	for event := range events {
+		reset = append(reset, event)
+	}
+	require.Len(t, reset, 2)
+	assert.Equal(t, EventReset, reset[0].Type)
+	assert.Equal(t, EventSnapshot, reset[1].Type)
+	assert.Equal(t, StatusCompleted, reset[1].Status)
+
+	// Invalid event IDs and unknown jobs are rejected
+	resp, err = http.Get(ts.URL + "/api/v1.0/transfer-agent/jobs/job-1/events?last_event_id=abc")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, err = http.Get(ts.URL + "/api/v1.0/transfer-agent/jobs/unknown/events")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
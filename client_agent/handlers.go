@@ -19,12 +19,16 @@
 package client_agent
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/pelicanplatform/pelican/client"
@@ -33,6 +37,10 @@ import (
 
 var serverStartTime = time.Now()
 
+// How often a comment is written to an idle job event stream so clients and
+// proxies do not consider it dead
+const jobEventHeartbeatInterval = 15 * time.Second
+
 // CreateJobHandler handles POST /api/v1.0/transfer-agent/jobs
 func (s *Server) CreateJobHandler(c *gin.Context) {
 	var req JobRequest
@@ -44,32 +52,24 @@ func (s *Server) CreateJobHandler(c *gin.Context) {
 		return
 	}
 
-	// Validate transfers
-	if len(req.Transfers) == 0 {
+	if msg := validateTransferRequests(req.Transfers); msg != "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Code:  ErrCodeInvalidRequest,
-			Error: "At least one transfer is required",
+			Error: msg,
 		})
 		return
 	}
 
-	// Additional validation for specific operations
-	for _, transfer := range req.Transfers {
-		if transfer.Operation != "prestage" && transfer.Destination == "" {
+	// Create job
+	job, err := s.transferManager.SubmitJob(req)
+	if err != nil {
+		if errors.Is(err, errInvalidDependency) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Code:  ErrCodeInvalidRequest,
-				Error: "Destination is required for " + transfer.Operation + " operations",
+				Error: "Invalid dependency: " + err.Error(),
 			})
 			return
 		}
-	}
-
-	// Build transfer options
-	options := buildTransferOptions(req.Options)
-
-	// Create job
-	job, err := s.transferManager.CreateJob(req.Transfers, options)
-	if err != nil {
 		log.Errorf("Failed to create job: %v", err)
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Code:  ErrCodeInternal,
@@ -100,6 +100,22 @@ func (s *Server) CreateJobHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, resp)
 }
 
+// validateTransferRequests returns a description of the first problem with
+// the requested transfers, or an empty string if they are valid
+func validateTransferRequests(transfers []TransferRequest) string {
+	if len(transfers) == 0 {
+		return "At least one transfer is required"
+	}
+
+	// Additional validation for specific operations
+	for _, transfer := range transfers {
+		if transfer.Operation != "prestage" && transfer.Destination == "" {
+			return "Destination is required for " + transfer.Operation + " operations"
+		}
+	}
+	return ""
+}
+
 // GetJobStatusHandler handles GET /api/v1.0/transfer-agent/jobs/:job_id
 func (s *Server) GetJobStatusHandler(c *gin.Context) {
 	jobID := c.Param("job_id")
@@ -113,46 +129,121 @@ func (s *Server) GetJobStatusHandler(c *gin.Context) {
 		return
 	}
 
-	// Build transfer statuses
-	transfers := make([]TransferStatus, len(job.Transfers))
-	for i, transfer := range job.Transfers {
-		status := TransferStatus{
-			TransferID:       transfer.ID,
-			JobID:            transfer.JobID,
-			Operation:        transfer.Operation,
-			Source:           transfer.Source,
-			Destination:      transfer.Destination,
-			Status:           transfer.Status,
-			CreatedAt:        transfer.CreatedAt,
-			StartedAt:        transfer.StartedAt,
-			CompletedAt:      transfer.CompletedAt,
-			BytesTransferred: transfer.BytesTransferred.Load(),
-			TotalBytes:       transfer.TotalBytes.Load(),
-		}
-		if transfer.Error != nil {
-			status.Error = transfer.Error.Error()
+	c.JSON(http.StatusOK, s.transferManager.GetJobStatus(job))
+}
+
+// GetJobEventsHandler handles GET /api/v1.0/transfer-agent/jobs/:job_id/events
+//
+// The job's events are streamed as server-sent events until the job finishes
+// or the client disconnects.  A client that reconnects with the Last-Event-ID
+// header (or the last_event_id query parameter) receives the events it missed;
+// a new client first receives a snapshot of the job's full status.  A client
+// whose missed events are no longer available, including one resuming from
+// before the agent restarted, receives a reset event followed by a snapshot.
+func (s *Server) GetJobEventsHandler(c *gin.Context) {
+	jobID := c.Param("job_id")
+
+	job, err := s.transferManager.GetJob(jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Code:  ErrCodeNotFound,
+			Error: "Job not found",
+		})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	eventLog := s.transferManager.events
+	resume := lastEventID != ""
+	var after uint64
+	sameEpoch := true
+	if resume {
+		if after, sameEpoch, err = eventLog.parseStreamID(lastEventID); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:  ErrCodeInvalidRequest,
+				Error: "Invalid last event ID: " + lastEventID,
+			})
+			return
 		}
-		transfers[i] = status
 	}
 
-	// Get progress
-	progress := s.transferManager.GetJobProgress(job)
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debugf("Failed to clear the write deadline for the event stream of job %s: %v", jobID, err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	heartbeat := time.NewTicker(jobEventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		events, gap, current, wait := eventLog.since(jobID, after)
+		if !resume || gap || !sameEpoch {
+			events = nil
+			if resume {
+				// Tell the client that the events it has seen are stale
+				events = append(events, JobEvent{
+					ID:        current,
+					Type:      EventReset,
+					JobID:     jobID,
+					Timestamp: time.Now(),
+				})
+			}
+			status := s.transferManager.GetJobStatus(job)
+			events = append(events, JobEvent{
+				ID:        current,
+				Type:      EventSnapshot,
+				JobID:     jobID,
+				Status:    status.Status,
+				Error:     status.Error,
+				Job:       status,
+				Timestamp: time.Now(),
+			})
+			resume, sameEpoch = true, true
+		}
 
-	// Build job status
-	jobStatus := JobStatus{
-		JobID:       job.ID,
-		Status:      job.Status,
-		CreatedAt:   job.CreatedAt,
-		StartedAt:   job.StartedAt,
-		CompletedAt: job.CompletedAt,
-		Progress:    progress,
-		Transfers:   transfers,
-	}
-	if job.Error != nil {
-		jobStatus.Error = job.Error.Error()
+		for _, event := range events {
+			if err := writeJobEvent(c.Writer, eventLog.streamID(event.ID), event); err != nil {
+				log.Debugf("Failed to write event to the event stream of job %s: %v", jobID, err)
+				return
+			}
+			after = event.ID
+			if (event.Type == EventJobStatus || event.Type == EventSnapshot) && isTerminalStatus(event.Status) {
+				c.Writer.Flush()
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-s.transferManager.ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-wait:
+		}
 	}
+}
 
-	c.JSON(http.StatusOK, jobStatus)
+// writeJobEvent writes the event in the server-sent events format
+func writeJobEvent(w io.Writer, id string, event JobEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal job event")
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, event.Type, data)
+	return err
 }
 
 // CancelJobHandler handles DELETE /api/v1.0/transfer-agent/jobs/:job_id
@@ -482,6 +573,79 @@ func (s *Server) DeleteJobHistoryHandler(c *gin.Context) {
 	})
 }
 
+// CreateScheduleHandler handles POST /api/v1.0/transfer-agent/schedules
+func (s *Server) CreateScheduleHandler(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:  ErrCodeInvalidRequest,
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateTransferRequests(req.Transfers); msg != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Code:  ErrCodeInvalidRequest,
+			Error: msg,
+		})
+		return
+	}
+
+	schedule, err := s.transferManager.CreateSchedule(req)
+	if err != nil {
+		if errors.Is(err, errInvalidSchedule) || errors.Is(err, errInvalidDependency) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Code:  ErrCodeInvalidRequest,
+				Error: "Invalid schedule: " + err.Error(),
+			})
+			return
+		}
+		log.Errorf("Failed to create schedule: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:  ErrCodeInternal,
+			Error: "Failed to create schedule: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListSchedulesHandler handles GET /api/v1.0/transfer-agent/schedules
+func (s *Server) ListSchedulesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, ScheduleListResponse{
+		Schedules: s.transferManager.ListSchedules(),
+	})
+}
+
+// DeleteScheduleHandler handles DELETE /api/v1.0/transfer-agent/schedules/:schedule_id
+// Jobs already started by the schedule are not cancelled
+func (s *Server) DeleteScheduleHandler(c *gin.Context) {
+	scheduleID := c.Param("schedule_id")
+
+	if err := s.transferManager.DeleteSchedule(scheduleID); err != nil {
+		if errors.Is(err, errScheduleNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Code:  ErrCodeNotFound,
+				Error: "Schedule not found",
+			})
+			return
+		}
+		log.Errorf("Failed to delete schedule %s: %v", scheduleID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Code:  ErrCodeInternal,
+			Error: "Failed to delete schedule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Schedule deleted",
+		"schedule_id": scheduleID,
+	})
+}
+
 // ShutdownHandler handles POST /shutdown
 // Initiates a graceful shutdown of the server
 func (s *Server) ShutdownHandler(c *gin.Context) {
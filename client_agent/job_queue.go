@@ -0,0 +1,124 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// jobQueue hands out the transfer manager's job slots.  Jobs waiting for a
+// slot are started highest priority first, and in the order they started
+// waiting within a priority, so a large background job queued earlier does
+// not hold up an interactive one.  Running jobs are never preempted.
+type jobQueue struct {
+	mu      sync.Mutex
+	slots   int
+	running int
+	waiting []*queuedJob // Ordered by priority, then by arrival
+	seq     uint64
+}
+
+type queuedJob struct {
+	jobID    string
+	priority int
+	seq      uint64
+	ready    chan struct{} // Closed when the job is granted a slot
+	granted  bool
+}
+
+func newJobQueue(slots int) *jobQueue {
+	return &jobQueue{slots: slots}
+}
+
+// acquire blocks until the job is granted a slot or the context is cancelled
+func (q *jobQueue) acquire(ctx context.Context, jobID string, priority int) error {
+	q.mu.Lock()
+	if q.running < q.slots && len(q.waiting) == 0 {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+
+	q.seq++
+	entry := &queuedJob{jobID: jobID, priority: priority, seq: q.seq, ready: make(chan struct{})}
+	idx := sort.Search(len(q.waiting), func(i int) bool {
+		return q.waiting[i].priority < priority
+	})
+	q.waiting = append(q.waiting, nil)
+	copy(q.waiting[idx+1:], q.waiting[idx:])
+	q.waiting[idx] = entry
+	q.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if entry.granted {
+		// The slot was granted as the job was cancelled; pass it on
+		q.running--
+		q.dispatchLocked()
+	} else {
+		for i, waiting := range q.waiting {
+			if waiting == entry {
+				q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+				break
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// release returns a slot and starts the next waiting job, if any
+func (q *jobQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	q.dispatchLocked()
+}
+
+// dispatchLocked grants free slots to the jobs at the head of the queue.
+// Must be called with the queue's mutex held.
+func (q *jobQueue) dispatchLocked() {
+	for q.running < q.slots && len(q.waiting) > 0 {
+		entry := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		entry.granted = true
+		q.running++
+		close(entry.ready)
+	}
+}
+
+// position returns the job's 1-based position among the jobs waiting for a
+// slot, or 0 if it is not waiting
+func (q *jobQueue) position(jobID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, entry := range q.waiting {
+		if entry.jobID == jobID {
+			return i + 1
+		}
+	}
+	return 0
+}
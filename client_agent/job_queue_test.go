@@ -0,0 +1,162 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/byte_rate"
+)
+
+func TestJobQueuePriority(t *testing.T) {
+	queue := newJobQueue(1)
+	require.NoError(t, queue.acquire(context.Background(), "running", 0))
+
+	// Queue jobs one at a time so their arrival order is known
+	started := make(chan string, 4)
+	enqueue := func(ctx context.Context, jobID string, priority int) {
+		go func() {
+			if err := queue.acquire(ctx, jobID, priority); err == nil {
+				started <- jobID
+			}
+		}()
+		require.Eventually(t, func() bool {
+			return queue.position(jobID) > 0
+		}, time.Second, time.Millisecond)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	enqueue(context.Background(), "background", -10)
+	enqueue(context.Background(), "first", 0)
+	enqueue(ctx, "cancelled", 5)
+	enqueue(context.Background(), "interactive", 5)
+	enqueue(context.Background(), "second", 0)
+
+	// Higher priority jobs are ahead; equal priorities keep their order
+	assert.Equal(t, 1, queue.position("cancelled"))
+	assert.Equal(t, 2, queue.position("interactive"))
+	assert.Equal(t, 3, queue.position("first"))
+	assert.Equal(t, 4, queue.position("second"))
+	assert.Equal(t, 5, queue.position("background"))
+	assert.Equal(t, 0, queue.position("running"))
+
+	// A cancelled job leaves the queue
+	cancel()
+	require.Eventually(t, func() bool {
+		return queue.position("cancelled") == 0
+	}, time.Second, time.Millisecond)
+
+	for _, expected := range []string{"interactive", "first", "second", "background"} {
+		queue.release()
+		select {
+		case jobID := <-started:
+			assert.Equal(t, expected, jobID)
+		case <-time.After(time.Second):
+			t.Fatalf("job %s was not started", expected)
+		}
+	}
+}
+
+func TestJobBandwidthLimiter(t *testing.T) {
+	tm := NewTransferManager(context.Background(), 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	// Jobs are only throttled if they or the agent have a cap
+	assert.Nil(t, tm.newJobBandwidthLimiter(&TransferJob{ID: "unlimited"}))
+
+	limit := byte_rate.ByteRate(100 * 1024)
+	limiter := tm.newJobBandwidthLimiter(&TransferJob{ID: "limited", BandwidthLimit: limit})
+	require.NotNil(t, limiter)
+
+	// The bucket holds one second's worth of bytes, so the first second's
+	// worth is immediate and the rest arrives at the capped rate
+	start := time.Now()
+	require.NoError(t, limiter.WaitN(context.Background(), int(limit)))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	require.NoError(t, limiter.WaitN(context.Background(), int(limit)/2))
+	assert.Greater(t, time.Since(start), 300*time.Millisecond)
+
+	// A cancelled transfer stops waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, limiter.WaitN(ctx, int(limit)))
+}
+
+func TestJobPriorityAndBandwidthLimitStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tm := NewTransferManager(context.Background(), 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+	server := &Server{
+		transferManager: tm,
+		router:          gin.New(),
+	}
+	server.setupRoutes()
+
+	// Defer the job so it is still pending when its status is read
+	body := []byte(`{
+		"transfers": [{"operation": "get", "source": "osdf:///test/file.txt", "destination": "/tmp/test.txt"}],
+		"options": {"priority": 7, "bandwidth_limit": "10MB/s"},
+		"start_after": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"
+	}`)
+	req, _ := http.NewRequest("POST", "/api/v1.0/transfer-agent/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp JobResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	req, _ = http.NewRequest("GET", "/api/v1.0/transfer-agent/jobs/"+resp.JobID, nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var status JobStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 7, status.Priority)
+	assert.Equal(t, byte_rate.ByteRate(10*byte_rate.MiB), status.BandwidthLimit)
+
+	jobs, _ := tm.ListJobs("", 10, 0)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, 7, jobs[0].Priority)
+
+	// Invalid limits are rejected
+	body = []byte(`{
+		"transfers": [{"operation": "get", "source": "osdf:///test/file.txt", "destination": "/tmp/test.txt"}],
+		"options": {"bandwidth_limit": "fast"}
+	}`)
+	req, _ = http.NewRequest("POST", "/api/v1.0/transfer-agent/jobs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -20,6 +20,8 @@ package client_agent
 
 import (
 	"time"
+
+	"github.com/pelicanplatform/pelican/byte_rate"
 )
 
 // TransferRequest represents a single transfer operation within a job
@@ -34,6 +36,11 @@ type TransferRequest struct {
 type JobRequest struct {
 	Transfers []TransferRequest `json:"transfers" binding:"required,min=1,dive"`
 	Options   TransferOptions   `json:"options"`
+	// The job waits until this time before starting
+	StartAfter *time.Time `json:"start_after,omitempty"`
+	// The job waits for these jobs to complete before starting, and fails
+	// if any of them fails or is cancelled
+	DependsOn []string `json:"depends_on,omitempty"`
 }
 
 // TransferOptions contains options that apply to all transfers in a job
@@ -42,6 +49,10 @@ type TransferOptions struct {
 	Caches     []string `json:"caches,omitempty"`
 	Methods    []string `json:"methods,omitempty"`
 	PackOption string   `json:"pack_option,omitempty"`
+	// Jobs waiting to start are started highest priority first; the default is 0
+	Priority int `json:"priority,omitempty"`
+	// Cap on the job's transfer rate, such as "10MB/s"; 0 means no cap
+	BandwidthLimit byte_rate.ByteRate `json:"bandwidth_limit,omitempty"`
 }
 
 // JobResponse is returned when a job is created
@@ -71,6 +82,14 @@ type JobStatus struct {
 	Progress    *JobProgress     `json:"progress,omitempty"`
 	Transfers   []TransferStatus `json:"transfers"`
 	Error       string           `json:"error,omitempty"`
+	StartAfter  *time.Time       `json:"start_after,omitempty"`
+	DependsOn   []string         `json:"depends_on,omitempty"`
+	ScheduleID  string           `json:"schedule_id,omitempty"`
+	Priority    int              `json:"priority"`
+	// Position among the jobs waiting for a slot, if the job is waiting
+	QueuePosition int `json:"queue_position,omitempty"`
+	// The job's own cap; the agent-wide ClientAgent.BandwidthLimit also applies
+	BandwidthLimit byte_rate.ByteRate `json:"bandwidth_limit,omitempty"`
 }
 
 // JobProgress tracks overall job progress
@@ -101,11 +120,27 @@ type TransferStatus struct {
 	Error            string     `json:"error,omitempty"`
 }
 
+// JobEvent is a single event in a job's event stream. Events are numbered
+// with a server-wide, increasing ID; the stream's event IDs, which clients
+// use to resume it, also identify the agent process that issued them.
+type JobEvent struct {
+	ID         uint64       `json:"id"`
+	Type       string       `json:"type"`
+	JobID      string       `json:"job_id"`
+	TransferID string       `json:"transfer_id,omitempty"`
+	Status     string       `json:"status,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Progress   *JobProgress `json:"progress,omitempty"`
+	Job        *JobStatus   `json:"job,omitempty"`
+	Timestamp  time.Time    `json:"timestamp"`
+}
+
 // JobListItem represents a job in a list response
 type JobListItem struct {
 	JobID              string    `json:"job_id"`
 	Status             string    `json:"status"`
 	CreatedAt          time.Time `json:"created_at"`
+	Priority           int       `json:"priority"`
 	TransfersCompleted int       `json:"transfers_completed"`
 	TransfersTotal     int       `json:"transfers_total"`
 	BytesTransferred   int64     `json:"bytes_transferred"`
@@ -189,6 +224,33 @@ type DeleteResponse struct {
 	URL     string `json:"url"`
 }
 
+// ScheduleRequest represents a request to create a recurring transfer job
+type ScheduleRequest struct {
+	JobRequest
+	// A five-field cron expression, a macro such as "@daily", or "@every <duration>"
+	Cron string `json:"cron" binding:"required"`
+}
+
+// ScheduleStatus represents a recurring transfer job.  Runs begin no earlier
+// than the start time, and the dependencies apply to every job it creates.
+type ScheduleStatus struct {
+	ScheduleID string            `json:"schedule_id"`
+	Cron       string            `json:"cron"`
+	Transfers  []TransferRequest `json:"transfers"`
+	Options    TransferOptions   `json:"options"`
+	StartAfter *time.Time        `json:"start_after,omitempty"`
+	DependsOn  []string          `json:"depends_on,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	NextRun    time.Time         `json:"next_run"`
+	LastRun    *time.Time        `json:"last_run,omitempty"`
+	LastJobID  string            `json:"last_job_id,omitempty"`
+}
+
+// ScheduleListResponse represents a list of recurring transfer jobs
+type ScheduleListResponse struct {
+	Schedules []ScheduleStatus `json:"schedules"`
+}
+
 // Error codes
 const (
 	ErrCodeInvalidRequest = "INVALID_REQUEST"
@@ -209,3 +271,16 @@ const (
 	StatusFailed    = "failed"
 	StatusCancelled = "cancelled"
 )
+
+// Job event types
+const (
+	// The full job status, sent when a client starts following a job or
+	// when the events it missed are no longer available
+	EventSnapshot = "snapshot"
+	// Sent before the snapshot when a resuming client's missed events are
+	// no longer available, such as after the agent restarted
+	EventReset          = "reset"
+	EventJobStatus      = "job_status"
+	EventTransferStatus = "transfer_status"
+	EventProgress       = "progress"
+)
@@ -0,0 +1,327 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/client_agent/types"
+)
+
+// jobSchedule is a recurring job; each time the schedule comes due, a new
+// job is created from the request
+type jobSchedule struct {
+	ID        string
+	Cron      string
+	cron      *cronSchedule
+	Request   JobRequest
+	CreatedAt time.Time
+	NextRun   time.Time
+	LastRun   *time.Time
+	LastJobID string
+}
+
+var (
+	errInvalidSchedule  = errors.New("invalid schedule")
+	errScheduleNotFound = errors.New("schedule not found")
+)
+
+// How long the scheduler sleeps when there are no schedules
+const idleScheduleInterval = time.Hour
+
+// CreateSchedule creates a recurring job that runs the request's transfers
+// each time the cron expression matches
+func (tm *TransferManager) CreateSchedule(req ScheduleRequest) (*ScheduleStatus, error) {
+	cron, err := parseCron(req.Cron)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidSchedule, err.Error())
+	}
+	if err := tm.validateDependencies(req.DependsOn); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from := now
+	if req.StartAfter != nil && req.StartAfter.After(now) {
+		from = *req.StartAfter
+	}
+	nextRun := cron.next(from)
+	if nextRun.IsZero() {
+		return nil, errors.Wrapf(errInvalidSchedule, "cron expression %q never runs", req.Cron)
+	}
+
+	schedule := &jobSchedule{
+		ID:        uuid.New().String(),
+		Cron:      req.Cron,
+		cron:      cron,
+		Request:   req.JobRequest,
+		CreatedAt: now,
+		NextRun:   nextRun,
+	}
+
+	// Persist the schedule so it survives a restart
+	if tm.store != nil {
+		requestJSON, err := json.Marshal(schedule.Request)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode schedule request")
+		}
+		if err := tm.store.CreateSchedule(&types.StoredSchedule{
+			ID:        schedule.ID,
+			Cron:      schedule.Cron,
+			Request:   string(requestJSON),
+			CreatedAt: now.Unix(),
+			NextRun:   nextRun,
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to persist schedule")
+		}
+	}
+
+	tm.mu.Lock()
+	tm.schedules[schedule.ID] = schedule
+	status := schedule.status()
+	tm.mu.Unlock()
+
+	tm.signalSchedulesChanged()
+	log.Infof("Created schedule %s (%s), next run at %s", schedule.ID, schedule.Cron, nextRun.Format(time.RFC3339))
+	return status, nil
+}
+
+// ListSchedules returns all recurring jobs, oldest first
+func (tm *TransferManager) ListSchedules() []ScheduleStatus {
+	tm.mu.RLock()
+	schedules := make([]ScheduleStatus, 0, len(tm.schedules))
+	for _, schedule := range tm.schedules {
+		schedules = append(schedules, *schedule.status())
+	}
+	tm.mu.RUnlock()
+
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].CreatedAt.Equal(schedules[j].CreatedAt) {
+			return schedules[i].ScheduleID < schedules[j].ScheduleID
+		}
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+	return schedules
+}
+
+// DeleteSchedule removes a recurring job; jobs it already created are not affected
+func (tm *TransferManager) DeleteSchedule(scheduleID string) error {
+	tm.mu.Lock()
+	_, exists := tm.schedules[scheduleID]
+	delete(tm.schedules, scheduleID)
+	tm.mu.Unlock()
+
+	if !exists {
+		return errScheduleNotFound
+	}
+
+	if tm.store != nil {
+		if err := tm.store.DeleteSchedule(scheduleID); err != nil {
+			return errors.Wrap(err, "failed to delete schedule from database")
+		}
+	}
+
+	tm.signalSchedulesChanged()
+	log.Infof("Deleted schedule %s", scheduleID)
+	return nil
+}
+
+// signalSchedulesChanged wakes up the scheduler to recompute its next wakeup
+func (tm *TransferManager) signalSchedulesChanged() {
+	select {
+	case tm.schedulesChanged <- struct{}{}:
+	default:
+	}
+}
+
+// runSchedules starts jobs for schedules as they come due until the
+// transfer manager shuts down
+func (tm *TransferManager) runSchedules() {
+	timer := time.NewTimer(idleScheduleInterval)
+	defer timer.Stop()
+
+	for {
+		tm.runDueSchedules(time.Now())
+
+		timer.Reset(tm.nextScheduleDelay())
+		select {
+		case <-tm.ctx.Done():
+			log.Debug("Stopping scheduler")
+			return
+		case <-timer.C:
+		case <-tm.schedulesChanged:
+		}
+	}
+}
+
+// nextScheduleDelay returns how long until the earliest schedule comes due
+func (tm *TransferManager) nextScheduleDelay() time.Duration {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	delay := idleScheduleInterval
+	for _, schedule := range tm.schedules {
+		if until := time.Until(schedule.NextRun); until < delay {
+			delay = until
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// runDueSchedules starts a job for each schedule whose next run has passed.
+// A schedule that came due several times while the agent was stopped runs
+// only once.
+func (tm *TransferManager) runDueSchedules(now time.Time) {
+	tm.mu.RLock()
+	var due []*jobSchedule
+	for _, schedule := range tm.schedules {
+		if !schedule.NextRun.After(now) {
+			due = append(due, schedule)
+		}
+	}
+	tm.mu.RUnlock()
+
+	for _, schedule := range due {
+		tm.runSchedule(schedule, now)
+	}
+}
+
+// runSchedule starts a job for the schedule, unless the job from its
+// previous run is still pending or running, and advances its next run
+func (tm *TransferManager) runSchedule(schedule *jobSchedule, now time.Time) {
+	tm.mu.RLock()
+	var previousActive bool
+	if previous, exists := tm.jobs[schedule.LastJobID]; exists {
+		previousActive = previous.Status == StatusPending || previous.Status == StatusRunning
+	}
+	tm.mu.RUnlock()
+
+	lastRun, lastJobID := schedule.LastRun, schedule.LastJobID
+	if previousActive {
+		log.Warnf("Skipping run of schedule %s: job %s from its previous run has not finished", schedule.ID, schedule.LastJobID)
+	} else {
+		req := schedule.Request
+		job, err := tm.createJob(req.Transfers, buildTransferOptions(req.Options), storedJobOptions{
+			Options:    req.Options,
+			DependsOn:  req.DependsOn,
+			ScheduleID: schedule.ID,
+		})
+		if err != nil {
+			log.Warnf("Failed to create job for schedule %s: %v", schedule.ID, err)
+		} else {
+			log.Infof("Schedule %s started job %s", schedule.ID, job.ID)
+			lastRun, lastJobID = &now, job.ID
+		}
+	}
+
+	nextRun := schedule.cron.next(now)
+
+	tm.mu.Lock()
+	_, exists := tm.schedules[schedule.ID]
+	if exists {
+		schedule.LastRun, schedule.LastJobID, schedule.NextRun = lastRun, lastJobID, nextRun
+		if nextRun.IsZero() {
+			delete(tm.schedules, schedule.ID)
+		}
+	}
+	tm.mu.Unlock()
+
+	// The schedule was deleted while the job was being created
+	if !exists || tm.store == nil {
+		return
+	}
+	if nextRun.IsZero() {
+		log.Infof("Schedule %s will not run again; removing it", schedule.ID)
+		if err := tm.store.DeleteSchedule(schedule.ID); err != nil {
+			log.Warnf("Failed to delete schedule %s from database: %v", schedule.ID, err)
+		}
+		return
+	}
+	if err := tm.store.UpdateScheduleRun(schedule.ID, lastRun, lastJobID, nextRun); err != nil {
+		log.Warnf("Failed to update schedule %s in database: %v", schedule.ID, err)
+	}
+}
+
+// recoverSchedules loads the recurring jobs from the database
+func (tm *TransferManager) recoverSchedules() {
+	storedSchedules, err := tm.store.ListSchedules()
+	if err != nil {
+		log.Warnf("Failed to load schedules: %v", err)
+		return
+	}
+
+	var recovered int
+	for _, stored := range storedSchedules {
+		cron, err := parseCron(stored.Cron)
+		if err != nil {
+			log.Warnf("Skipping schedule %s: %v", stored.ID, err)
+			continue
+		}
+		var req JobRequest
+		if err := json.Unmarshal([]byte(stored.Request), &req); err != nil {
+			log.Warnf("Skipping schedule %s: failed to decode request: %v", stored.ID, err)
+			continue
+		}
+
+		tm.mu.Lock()
+		tm.schedules[stored.ID] = &jobSchedule{
+			ID:        stored.ID,
+			Cron:      stored.Cron,
+			cron:      cron,
+			Request:   req,
+			CreatedAt: time.Unix(stored.CreatedAt, 0),
+			NextRun:   stored.NextRun,
+			LastRun:   stored.LastRun,
+			LastJobID: stored.LastJobID,
+		}
+		tm.mu.Unlock()
+		recovered++
+	}
+
+	if recovered > 0 {
+		log.Infof("Recovered %d job schedules", recovered)
+		tm.signalSchedulesChanged()
+	}
+}
+
+// status returns the API representation of the schedule.  Must be called
+// with the transfer manager's mutex held.
+func (s *jobSchedule) status() *ScheduleStatus {
+	return &ScheduleStatus{
+		ScheduleID: s.ID,
+		Cron:       s.Cron,
+		Transfers:  s.Request.Transfers,
+		Options:    s.Request.Options,
+		StartAfter: s.Request.StartAfter,
+		DependsOn:  s.Request.DependsOn,
+		CreatedAt:  s.CreatedAt,
+		NextRun:    s.NextRun,
+		LastRun:    s.LastRun,
+		LastJobID:  s.LastJobID,
+	}
+}
@@ -0,0 +1,222 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package client_agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/client_agent/store"
+	"github.com/pelicanplatform/pelican/client_agent/types"
+)
+
+// An operation the transfer manager rejects immediately, so jobs finish
+// without attempting a transfer
+var unknownTransfer = []TransferRequest{{Operation: "unknown", Source: "pelican://example.com/foo", Destination: "/tmp/foo"}}
+
+func jobStatusOf(tm *TransferManager, job *TransferJob) string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return job.Status
+}
+
+func TestJobDependencies(t *testing.T) {
+	tm := NewTransferManager(context.Background(), 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	// Add the jobs being depended on directly so they do not run
+	upstream := &TransferJob{ID: "upstream", Status: StatusPending, CreatedAt: time.Now()}
+	failing := &TransferJob{ID: "failing", Status: StatusPending, CreatedAt: time.Now()}
+	tm.mu.Lock()
+	tm.jobs[upstream.ID] = upstream
+	tm.jobs[failing.ID] = failing
+	tm.mu.Unlock()
+
+	// Unknown dependencies are rejected up front
+	_, err := tm.SubmitJob(JobRequest{Transfers: unknownTransfer, DependsOn: []string{"missing"}})
+	assert.True(t, errors.Is(err, errInvalidDependency))
+
+	job, err := tm.SubmitJob(JobRequest{Transfers: unknownTransfer, DependsOn: []string{upstream.ID}})
+	require.NoError(t, err)
+	dependent, err := tm.SubmitJob(JobRequest{Transfers: unknownTransfer, DependsOn: []string{failing.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{upstream.ID}, tm.GetJobStatus(job).DependsOn)
+
+	// Jobs wait for their dependencies
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, StatusPending, jobStatusOf(tm, job))
+	assert.Equal(t, StatusPending, jobStatusOf(tm, dependent))
+
+	// A completed dependency lets the job run; a failed one fails it
+	tm.updateJobStatus(upstream.ID, StatusCompleted, nil)
+	tm.updateJobStatus(failing.ID, StatusFailed, errors.New("transfer failed"))
+	require.Eventually(t, func() bool {
+		return isTerminalStatus(jobStatusOf(tm, job)) && isTerminalStatus(jobStatusOf(tm, dependent))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	require.Len(t, job.Transfers, 1)
+	assert.Contains(t, job.Transfers[0].Error.Error(), "unknown operation")
+	assert.Equal(t, StatusFailed, dependent.Status)
+	assert.Contains(t, dependent.Error.Error(), "dependency failing failed")
+	assert.Equal(t, StatusCancelled, dependent.Transfers[0].Status)
+}
+
+func TestJobStartAfter(t *testing.T) {
+	tm := NewTransferManager(context.Background(), 5, nil)
+	defer func() {
+		_ = tm.Shutdown()
+	}()
+
+	startAfter := time.Now().Add(time.Hour)
+	job, err := tm.SubmitJob(JobRequest{Transfers: unknownTransfer, StartAfter: &startAfter})
+	require.NoError(t, err)
+	assert.Equal(t, &startAfter, tm.GetJobStatus(job).StartAfter)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, StatusPending, jobStatusOf(tm, job))
+
+	// A deferred job can be cancelled before it starts
+	_, _, err = tm.CancelJob(job.ID)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return jobStatusOf(tm, job) == StatusCancelled
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestJobSchedules(t *testing.T) {
+	testStore, dbPath := setupTestStore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tm := NewTransferManager(ctx, 5, testStore)
+
+	// The database only accepts known operations
+	requests := []TransferRequest{{Operation: "get", Source: "pelican://example.com/test/file1.txt", Destination: "/tmp/file1.txt"}}
+
+	// Invalid cron expressions are rejected
+	_, err := tm.CreateSchedule(ScheduleRequest{JobRequest: JobRequest{Transfers: unknownTransfer}, Cron: "61 * * * *"})
+	assert.True(t, errors.Is(err, errInvalidSchedule))
+
+	created, err := tm.CreateSchedule(ScheduleRequest{JobRequest: JobRequest{Transfers: requests}, Cron: "@every 1h"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), created.NextRun, time.Minute)
+	assert.True(t, tm.HasActiveJobs())
+
+	// Make the schedule due; a job is started and the next run advanced
+	tm.mu.Lock()
+	tm.schedules[created.ScheduleID].NextRun = time.Now().Add(-time.Minute)
+	tm.mu.Unlock()
+	tm.signalSchedulesChanged()
+
+	var schedule ScheduleStatus
+	require.Eventually(t, func() bool {
+		schedules := tm.ListSchedules()
+		require.Len(t, schedules, 1)
+		schedule = schedules[0]
+		return schedule.LastJobID != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(t, schedule.LastRun)
+	assert.True(t, schedule.NextRun.After(time.Now()))
+
+	job, err := tm.GetJob(schedule.LastJobID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ScheduleID, tm.GetJobStatus(job).ScheduleID)
+
+	// The run is persisted so the schedule survives a restart
+	cancel()
+	require.NoError(t, tm.Shutdown())
+	testStore.Close()
+
+	testStore, err = store.NewStore(dbPath)
+	require.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	tm = NewTransferManager(ctx, 5, testStore)
+	t.Cleanup(func() {
+		cancel()
+		_ = tm.Shutdown()
+		testStore.Close()
+	})
+
+	schedules := tm.ListSchedules()
+	require.Len(t, schedules, 1)
+	assert.Equal(t, created.ScheduleID, schedules[0].ScheduleID)
+	assert.Equal(t, schedule.LastJobID, schedules[0].LastJobID)
+	assert.Equal(t, schedule.NextRun.Unix(), schedules[0].NextRun.Unix())
+	assert.Equal(t, requests, schedules[0].Transfers)
+
+	// Deleting the schedule removes it from the database too
+	require.NoError(t, tm.DeleteSchedule(created.ScheduleID))
+	assert.True(t, errors.Is(tm.DeleteSchedule(created.ScheduleID), errScheduleNotFound))
+	stored, err := testStore.ListSchedules()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestDeferredJobRecovery(t *testing.T) {
+	testStore, _ := setupTestStore(t)
+
+	// A job interrupted while waiting for its start time and dependency
+	startAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	options, err := json.Marshal(storedJobOptions{
+		Options:    TransferOptions{Token: "/path/to/token"},
+		StartAfter: &startAfter,
+		DependsOn:  []string{"upstream"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, testStore.CreateJob("deferred", StatusPending, time.Now(), string(options), 0))
+	require.NoError(t, testStore.CreateTransfer(&types.StoredTransfer{
+		ID:          "deferred-transfer",
+		JobID:       "deferred",
+		Operation:   "get",
+		Source:      "pelican://example.com/test/file1.txt",
+		Destination: "/tmp/file1.txt",
+		Status:      StatusPending,
+		CreatedAt:   time.Now().Unix(),
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tm := NewTransferManager(ctx, 5, testStore)
+	t.Cleanup(func() {
+		cancel()
+		_ = tm.Shutdown()
+		testStore.Close()
+	})
+
+	job, err := tm.GetJob("deferred")
+	require.NoError(t, err)
+	status := tm.GetJobStatus(job)
+	assert.Equal(t, StatusPending, status.Status)
+	require.NotNil(t, status.StartAfter)
+	assert.True(t, startAfter.Equal(*status.StartAfter))
+	assert.Equal(t, []string{"upstream"}, status.DependsOn)
+
+	// The options are persisted again for the next restart
+	storedJob, err := testStore.GetJob("deferred")
+	require.NoError(t, err)
+	assert.Equal(t, "/path/to/token", storedJob.Options["options"].(map[string]interface{})["token"])
+}
@@ -201,8 +201,14 @@ func (s *Server) setupRoutes() {
 		api.POST("/jobs", s.CreateJobHandler)
 		api.GET("/jobs", s.ListJobsHandler)
 		api.GET("/jobs/:job_id", s.GetJobStatusHandler)
+		api.GET("/jobs/:job_id/events", s.GetJobEventsHandler)
 		api.DELETE("/jobs/:job_id", s.CancelJobHandler)
 
+		// Recurring jobs
+		api.POST("/schedules", s.CreateScheduleHandler)
+		api.GET("/schedules", s.ListSchedulesHandler)
+		api.DELETE("/schedules/:schedule_id", s.DeleteScheduleHandler)
+
 		// History management
 		api.GET("/history", s.GetJobHistoryHandler)
 		api.DELETE("/history/:job_id", s.DeleteJobHistoryHandler)
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Create schedules table for recurring transfer jobs
+CREATE TABLE IF NOT EXISTS schedules (
+    id TEXT PRIMARY KEY,
+    cron TEXT NOT NULL,
+    request TEXT NOT NULL,  -- JSON-encoded job request used for each run
+    created_at INTEGER NOT NULL,  -- Unix timestamp in seconds
+    next_run INTEGER NOT NULL,
+    last_run INTEGER,
+    last_job_id TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedules_next_run ON schedules(next_run);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_schedules_next_run;
+DROP TABLE IF EXISTS schedules;
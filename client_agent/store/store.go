@@ -857,3 +857,147 @@ func (s *Store) DeleteJobHistory(jobID string) error {
 	log.Infof("Deleted historical job %s", jobID)
 	return nil
 }
+
+// GetHistoricalJob retrieves a job archived to history by ID
+func (s *Store) GetHistoricalJob(jobID string) (*types.HistoricalJob, error) {
+	query := `SELECT id, status, created_at, started_at, completed_at, error_message,
+	          transfers_completed, transfers_failed, transfers_total, bytes_transferred, total_bytes, retry_count
+	          FROM job_history WHERE id = ?`
+
+	var job types.HistoricalJob
+	var startedAt, completedAt sql.NullInt64
+	var errorMsg sql.NullString
+
+	err := s.db.QueryRow(query, jobID).Scan(
+		&job.ID, &job.Status, &job.CreatedAt,
+		&startedAt, &completedAt, &errorMsg,
+		&job.TransfersCompleted, &job.TransfersFailed, &job.TransfersTotal,
+		&job.BytesTransferred, &job.TotalBytes, &job.RetryCount,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.Errorf("historical job %s not found", jobID)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query historical job")
+	}
+
+	// Convert nullable fields
+	if startedAt.Valid {
+		t := time.Unix(startedAt.Int64, 0)
+		job.StartedAt = &t
+	}
+	if completedAt.Valid {
+		t := time.Unix(completedAt.Int64, 0)
+		job.CompletedAt = &t
+	}
+	if errorMsg.Valid {
+		job.ErrorMessage = errorMsg.String
+	}
+
+	return &job, nil
+}
+
+// CreateSchedule inserts a new recurring job schedule into the database
+func (s *Store) CreateSchedule(schedule *types.StoredSchedule) error {
+	query := `INSERT INTO schedules (id, cron, request, created_at, next_run) VALUES (?, ?, ?, ?, ?)`
+	_, err := s.db.Exec(query, schedule.ID, schedule.Cron, schedule.Request, schedule.CreatedAt, schedule.NextRun.Unix())
+	if err != nil {
+		return errors.Wrap(err, "failed to insert schedule")
+	}
+
+	log.Debugf("Created schedule %s in database", schedule.ID)
+	return nil
+}
+
+// ListSchedules retrieves all recurring job schedules, oldest first
+func (s *Store) ListSchedules() ([]*types.StoredSchedule, error) {
+	query := `SELECT id, cron, request, created_at, next_run, last_run, last_job_id
+	          FROM schedules ORDER BY created_at ASC`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query schedules")
+	}
+	defer rows.Close()
+
+	var schedules []*types.StoredSchedule
+	for rows.Next() {
+		var schedule types.StoredSchedule
+		var nextRun int64
+		var lastRun sql.NullInt64
+		var lastJobID sql.NullString
+
+		err := rows.Scan(
+			&schedule.ID, &schedule.Cron, &schedule.Request, &schedule.CreatedAt,
+			&nextRun, &lastRun, &lastJobID,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan schedule row")
+		}
+
+		// Convert nullable fields
+		schedule.NextRun = time.Unix(nextRun, 0)
+		if lastRun.Valid {
+			t := time.Unix(lastRun.Int64, 0)
+			schedule.LastRun = &t
+		}
+		if lastJobID.Valid {
+			schedule.LastJobID = lastJobID.String
+		}
+
+		schedules = append(schedules, &schedule)
+	}
+
+	return schedules, nil
+}
+
+// UpdateScheduleRun records a run of a schedule and the time of its next run
+func (s *Store) UpdateScheduleRun(scheduleID string, lastRun *time.Time, lastJobID string, nextRun time.Time) error {
+	var lastRunUnix sql.NullInt64
+	if lastRun != nil {
+		lastRunUnix = sql.NullInt64{Int64: lastRun.Unix(), Valid: true}
+	}
+	var lastJob sql.NullString
+	if lastJobID != "" {
+		lastJob = sql.NullString{String: lastJobID, Valid: true}
+	}
+
+	query := `UPDATE schedules SET last_run = ?, last_job_id = ?, next_run = ? WHERE id = ?`
+	result, err := s.db.Exec(query, lastRunUnix, lastJob, nextRun.Unix(), scheduleID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update schedule run")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rows == 0 {
+		return errors.Errorf("schedule %s not found", scheduleID)
+	}
+
+	return nil
+}
+
+// DeleteSchedule removes a recurring job schedule from the database
+func (s *Store) DeleteSchedule(scheduleID string) error {
+	query := `DELETE FROM schedules WHERE id = ?`
+	result, err := s.db.Exec(query, scheduleID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete schedule")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rows == 0 {
+		return errors.Errorf("schedule %s not found", scheduleID)
+	}
+
+	log.Infof("Deleted schedule %s", scheduleID)
+	return nil
+}
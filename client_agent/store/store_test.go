@@ -372,3 +372,65 @@ func TestPruneHistory(t *testing.T) {
 	assert.Equal(t, 2, total)
 	assert.Len(t, jobs, 2)
 }
+
+func TestGetHistoricalJob(t *testing.T) {
+	store, _ := setupTestDB(t)
+
+	createdAt := time.Now()
+	completedAt := createdAt.Add(time.Minute)
+	require.NoError(t, store.CreateJob("job-1", "completed", createdAt, "{}", 0))
+	require.NoError(t, store.UpdateJobTimes("job-1", nil, &completedAt))
+	require.NoError(t, store.ArchiveJob("job-1"))
+
+	job, err := store.GetHistoricalJob("job-1")
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", job.ID)
+	assert.Equal(t, "completed", job.Status)
+
+	_, err = store.GetHistoricalJob("missing")
+	assert.Error(t, err)
+}
+
+func TestSchedules(t *testing.T) {
+	store, _ := setupTestDB(t)
+
+	now := time.Now()
+	for i, id := range []string{"schedule-1", "schedule-2"} {
+		err := store.CreateSchedule(&types.StoredSchedule{
+			ID:        id,
+			Cron:      "@hourly",
+			Request:   `{"transfers":[]}`,
+			CreatedAt: now.Add(time.Duration(i) * time.Second).Unix(),
+			NextRun:   now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	schedules, err := store.ListSchedules()
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "schedule-1", schedules[0].ID)
+	assert.Equal(t, `{"transfers":[]}`, schedules[0].Request)
+	assert.Equal(t, now.Add(time.Hour).Unix(), schedules[0].NextRun.Unix())
+	assert.Nil(t, schedules[0].LastRun)
+	assert.Empty(t, schedules[0].LastJobID)
+
+	// Record a run
+	nextRun := now.Add(2 * time.Hour)
+	require.NoError(t, store.UpdateScheduleRun("schedule-1", &now, "job-1", nextRun))
+	schedules, err = store.ListSchedules()
+	require.NoError(t, err)
+	require.NotNil(t, schedules[0].LastRun)
+	assert.Equal(t, now.Unix(), schedules[0].LastRun.Unix())
+	assert.Equal(t, "job-1", schedules[0].LastJobID)
+	assert.Equal(t, nextRun.Unix(), schedules[0].NextRun.Unix())
+	assert.Error(t, store.UpdateScheduleRun("missing", &now, "job-1", nextRun))
+
+	// Delete a schedule
+	require.NoError(t, store.DeleteSchedule("schedule-1"))
+	assert.Error(t, store.DeleteSchedule("schedule-1"))
+	schedules, err = store.ListSchedules()
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "schedule-2", schedules[0].ID)
+}
@@ -53,9 +53,16 @@ type StoreInterface interface {
 	// History operations
 	ArchiveJob(jobID string) error
 	GetJobHistory(status string, from, to time.Time, limit, offset int) ([]*types.HistoricalJob, int, error)
+	GetHistoricalJob(jobID string) (*types.HistoricalJob, error)
 	DeleteJobHistory(jobID string) error
 	PruneHistory(olderThan time.Time) (int, error)
 
+	// Schedule operations
+	CreateSchedule(schedule *types.StoredSchedule) error
+	ListSchedules() ([]*types.StoredSchedule, error)
+	UpdateScheduleRun(scheduleID string, lastRun *time.Time, lastJobID string, nextRun time.Time) error
+	DeleteSchedule(scheduleID string) error
+
 	// Lifecycle
 	Close() error
 }
@@ -20,6 +20,8 @@ package client_agent
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +31,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/pelicanplatform/pelican/byte_rate"
 	"github.com/pelicanplatform/pelican/client"
 	pelican_config "github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/param"
@@ -64,10 +67,29 @@ type TransferJob struct {
 	Options     []client.TransferOption
 	Error       error
 	CancelFunc  context.CancelFunc
-	ctx         context.Context
-	wg          sync.WaitGroup
+	StartAfter  *time.Time
+	DependsOn   []string
+	ScheduleID  string // Set for jobs created by a recurring schedule
+	Priority    int
+	// The job's own cap on its transfer rate; 0 means no cap
+	BandwidthLimit byte_rate.ByteRate
+	ctx            context.Context
+	wg             sync.WaitGroup
 }
 
+// storedJobOptions is persisted as a job's options so the job can be
+// recovered as submitted after a restart
+type storedJobOptions struct {
+	Options    TransferOptions `json:"options"`
+	StartAfter *time.Time      `json:"start_after,omitempty"`
+	DependsOn  []string        `json:"depends_on,omitempty"`
+	ScheduleID string          `json:"schedule_id,omitempty"`
+}
+
+var (
+	errInvalidDependency = errors.New("invalid job dependency")
+)
+
 // TransferManager manages all transfer jobs and their execution
 type TransferManager struct {
 	jobs                   map[string]*TransferJob
@@ -75,11 +97,15 @@ type TransferManager struct {
 	store                  StoreInterface
 	mu                     sync.RWMutex
 	maxJobs                int
-	semaphore              chan struct{}
+	queue                  *jobQueue
+	bandwidth              *bandwidthBucket // Agent-wide cap shared fairly between jobs; nil if unlimited
 	ctx                    context.Context
 	cancel                 context.CancelFunc
 	eg                     *errgroup.Group
 	backgroundTasksStarted bool
+	events                 *jobEventLog
+	schedules              map[string]*jobSchedule
+	schedulesChanged       chan struct{}
 }
 
 // NewTransferManager creates a new transfer manager
@@ -110,12 +136,29 @@ func NewTransferManager(ctx context.Context, maxConcurrentJobs int, store StoreI
 		transfers: make(map[string]*Transfer),
 		store:     store,
 		maxJobs:   maxConcurrentJobs,
-		semaphore: make(chan struct{}, maxConcurrentJobs),
+		queue:     newJobQueue(maxConcurrentJobs),
+		bandwidth: newBandwidthBucket(param.ClientAgent_BandwidthLimit.GetByteRate()),
 		ctx:       managerCtx,
 		cancel:    cancel,
 		eg:        eg,
+		events:    newJobEventLog(),
+
+		schedules:        make(map[string]*jobSchedule),
+		schedulesChanged: make(chan struct{}, 1),
 	}
 
+	// Publish byte-progress events for clients following running jobs
+	tm.eg.Go(func() error {
+		tm.publishProgressEvents()
+		return nil
+	})
+
+	// Start jobs for recurring schedules as they come due
+	tm.eg.Go(func() error {
+		tm.runSchedules()
+		return nil
+	})
+
 	// Attempt to recover incomplete jobs from database
 	if store != nil {
 		tm.recoverJobs()
@@ -155,6 +198,8 @@ func (tm *TransferManager) recoverJobs() {
 	} else {
 		log.Infof("Job recovery complete: restarted %d incomplete jobs", recoveredCount)
 	}
+
+	tm.recoverSchedules()
 }
 
 // recoverSingleJob restarts a single interrupted job
@@ -191,19 +236,33 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 		return
 	}
 
+	// Restore the options, start time and dependencies the job was submitted with
+	var spec storedJobOptions
+	if optionsJSON, err := json.Marshal(storedJob.Options); err == nil {
+		if err := json.Unmarshal(optionsJSON, &spec); err != nil {
+			log.Warnf("Failed to decode options of recovered job %s: %v", jobID, err)
+			spec = storedJobOptions{}
+		}
+	}
+
 	// Create in-memory job structure
 	newRetryCount := storedJob.RetryCount + 1
 	jobCtx, jobCancel := context.WithCancel(tm.ctx)
 	createdAt := time.Now()
 
 	job := &TransferJob{
-		ID:         jobID, // PRESERVE the original job ID
-		Status:     StatusPending,
-		CreatedAt:  createdAt,
-		Transfers:  make([]*Transfer, 0, len(requests)),
-		Options:    nil, // Options are not persisted, so we can't recover them
-		CancelFunc: jobCancel,
-		ctx:        jobCtx,
+		ID:             jobID, // PRESERVE the original job ID
+		Status:         StatusPending,
+		CreatedAt:      createdAt,
+		Transfers:      make([]*Transfer, 0, len(requests)),
+		Options:        buildTransferOptions(spec.Options),
+		CancelFunc:     jobCancel,
+		StartAfter:     spec.StartAfter,
+		DependsOn:      spec.DependsOn,
+		ScheduleID:     spec.ScheduleID,
+		Priority:       spec.Options.Priority,
+		BandwidthLimit: spec.Options.BandwidthLimit,
+		ctx:            jobCtx,
 	}
 
 	// Prepare transfer data for atomic recovery
@@ -246,8 +305,12 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 
 	// Use atomic RecoverJob transaction - deletes old job and creates new one with transfers
 	// All operations succeed or all fail (atomic)
-	optionsJSON := "{}"
-	if err := tm.store.RecoverJob(jobID, newRetryCount, createdAt, optionsJSON, transferData); err != nil {
+	optionsJSON, err := json.Marshal(spec)
+	if err != nil {
+		log.Errorf("Failed to encode options of recovered job %s: %v", jobID, err)
+		optionsJSON = []byte("{}")
+	}
+	if err := tm.store.RecoverJob(jobID, newRetryCount, createdAt, string(optionsJSON), transferData); err != nil {
 		log.Errorf("Failed to atomically recover job %s in database: %v", jobID, err)
 		// Clean up in-memory structures on failure
 		tm.mu.Lock()
@@ -260,6 +323,7 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 	}
 
 	log.Infof("Job %s recovered and restarted with %d transfers (retry attempt %d)", jobID, len(requests), newRetryCount)
+	tm.publishJobStatus(job)
 
 	// Start the job asynchronously
 	job.wg.Add(1)
@@ -271,6 +335,30 @@ func (tm *TransferManager) recoverSingleJob(jobID string) {
 
 // CreateJob creates a new transfer job
 func (tm *TransferManager) CreateJob(requests []TransferRequest, options []client.TransferOption) (*TransferJob, error) {
+	return tm.createJob(requests, options, storedJobOptions{})
+}
+
+// SubmitJob creates a new transfer job from an API request, which may delay
+// the start of the job or make it depend on other jobs
+func (tm *TransferManager) SubmitJob(req JobRequest) (*TransferJob, error) {
+	if err := tm.validateDependencies(req.DependsOn); err != nil {
+		return nil, err
+	}
+	return tm.createJob(req.Transfers, buildTransferOptions(req.Options), storedJobOptions{
+		Options:    req.Options,
+		StartAfter: req.StartAfter,
+		DependsOn:  req.DependsOn,
+	})
+}
+
+// createJob creates a job and starts it asynchronously; spec is persisted
+// so the job can be recovered after a restart
+func (tm *TransferManager) createJob(requests []TransferRequest, options []client.TransferOption, spec storedJobOptions) (*TransferJob, error) {
+	optionsJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode job options")
+	}
+
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
@@ -278,13 +366,18 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 	jobCtx, jobCancel := context.WithCancel(tm.ctx)
 
 	job := &TransferJob{
-		ID:         jobID,
-		Status:     StatusPending,
-		CreatedAt:  time.Now(),
-		Transfers:  make([]*Transfer, 0, len(requests)),
-		Options:    options,
-		CancelFunc: jobCancel,
-		ctx:        jobCtx,
+		ID:             jobID,
+		Status:         StatusPending,
+		CreatedAt:      time.Now(),
+		Transfers:      make([]*Transfer, 0, len(requests)),
+		Options:        options,
+		CancelFunc:     jobCancel,
+		StartAfter:     spec.StartAfter,
+		DependsOn:      spec.DependsOn,
+		ScheduleID:     spec.ScheduleID,
+		Priority:       spec.Options.Priority,
+		BandwidthLimit: spec.Options.BandwidthLimit,
+		ctx:            jobCtx,
 	}
 
 	tm.jobs[jobID] = job
@@ -326,8 +419,7 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 
 	// Atomically persist job and all transfers to database in a single transaction
 	if tm.store != nil {
-		optionsJSON := "{}"
-		if err := tm.store.CreateJobWithTransfers(jobID, StatusPending, job.CreatedAt, optionsJSON, 0, transferData); err != nil {
+		if err := tm.store.CreateJobWithTransfers(jobID, StatusPending, job.CreatedAt, string(optionsJSON), 0, transferData); err != nil {
 			log.Errorf("Failed to persist job %s to database: %v", jobID, err)
 			// Clean up in-memory structures on database failure
 			delete(tm.jobs, jobID)
@@ -338,6 +430,7 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 			return nil, errors.Wrap(err, "failed to persist job to database")
 		}
 	}
+	tm.publishJobStatus(job)
 
 	// Start the job asynchronously
 	job.wg.Add(1)
@@ -353,20 +446,38 @@ func (tm *TransferManager) CreateJob(requests []TransferRequest, options []clien
 func (tm *TransferManager) executeJob(job *TransferJob) {
 	defer job.wg.Done() // Signal job completion
 
-	// Acquire semaphore slot
-	select {
-	case tm.semaphore <- struct{}{}:
-		defer func() { <-tm.semaphore }()
-	case <-job.ctx.Done():
+	// Wait for the job's start time and dependencies
+	if err := tm.waitUntilReady(job); err != nil {
+		if job.ctx.Err() != nil {
+			tm.updateJobStatus(job.ID, StatusCancelled, errors.New("job cancelled before execution"))
+			return
+		}
+		log.Warnf("Job %s cannot run: %v", job.ID, err)
+		tm.cancelRemainingTransfers(job)
+		tm.updateJobStatus(job.ID, StatusFailed, err)
+		tm.persistJobResult(job)
+		return
+	}
+
+	// Wait for a job slot; higher priority jobs are started first
+	if err := tm.queue.acquire(job.ctx, job.ID, job.Priority); err != nil {
 		tm.updateJobStatus(job.ID, StatusCancelled, errors.New("job cancelled before execution"))
 		return
 	}
+	defer tm.queue.release()
+
+	// Throttle the job's transfers if the agent or the job caps bandwidth
+	options := job.Options
+	if limiter := tm.newJobBandwidthLimiter(job); limiter != nil {
+		options = append(slices.Clip(options), client.WithBandwidthLimiter(limiter))
+	}
 
 	// Update job status
 	now := time.Now()
 	tm.mu.Lock()
 	job.Status = StatusRunning
 	job.StartedAt = &now
+	tm.publishJobStatus(job)
 	tm.mu.Unlock()
 
 	// Persist status update to database
@@ -393,7 +504,7 @@ func (tm *TransferManager) executeJob(job *TransferJob) {
 			tm.updateJobStatus(job.ID, StatusCancelled, nil)
 			return
 		default:
-			if err := tm.executeTransfer(transfer, job.Options); err != nil {
+			if err := tm.executeTransfer(transfer, options); err != nil {
 				log.Errorf("Transfer %s failed: %v", transfer.ID, err)
 				allSucceeded = false
 				anyFailed = true
@@ -411,24 +522,113 @@ func (tm *TransferManager) executeJob(job *TransferJob) {
 	} else if allSucceeded {
 		job.Status = StatusCompleted
 	}
+	tm.publishJobStatus(job)
 	tm.mu.Unlock()
 
 	// Persist final status to database
-	if tm.store != nil {
-		if err := tm.store.UpdateJobStatus(job.ID, job.Status); err != nil {
-			log.Warnf("Failed to update job %s final status in database: %v", job.ID, err)
+	tm.persistJobResult(job)
+
+	log.Infof("Job %s completed with status %s", job.ID, job.Status)
+}
+
+// persistJobResult persists the final status, completion time and error of
+// a finished job to the database
+func (tm *TransferManager) persistJobResult(job *TransferJob) {
+	if tm.store == nil {
+		return
+	}
+
+	tm.mu.RLock()
+	status, completedAt, jobErr := job.Status, job.CompletedAt, job.Error
+	tm.mu.RUnlock()
+
+	if err := tm.store.UpdateJobStatus(job.ID, status); err != nil {
+		log.Warnf("Failed to update job %s final status in database: %v", job.ID, err)
+	}
+	if err := tm.store.UpdateJobTimes(job.ID, nil, completedAt); err != nil {
+		log.Warnf("Failed to update job %s completion time in database: %v", job.ID, err)
+	}
+	if jobErr != nil {
+		if err := tm.store.UpdateJobError(job.ID, jobErr.Error()); err != nil {
+			log.Warnf("Failed to update job %s error in database: %v", job.ID, err)
 		}
-		if err := tm.store.UpdateJobTimes(job.ID, nil, &completedAt); err != nil {
-			log.Warnf("Failed to update job %s completion time in database: %v", job.ID, err)
+	}
+}
+
+// waitUntilReady blocks until the job's start time has passed and all of
+// its dependencies have completed.  It returns an error if a dependency
+// failed, was cancelled or is unknown, or if the job was cancelled.
+func (tm *TransferManager) waitUntilReady(job *TransferJob) error {
+	if job.StartAfter != nil {
+		if delay := time.Until(*job.StartAfter); delay > 0 {
+			log.Infof("Job %s will start after %s", job.ID, job.StartAfter.Format(time.RFC3339))
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-job.ctx.Done():
+				return job.ctx.Err()
+			case <-timer.C:
+			}
 		}
-		if job.Error != nil {
-			if err := tm.store.UpdateJobError(job.ID, job.Error.Error()); err != nil {
-				log.Warnf("Failed to update job %s error in database: %v", job.ID, err)
+	}
+
+	for _, dependency := range job.DependsOn {
+		for {
+			// Take the wait channel before checking so a change in between is not missed
+			changed := tm.events.changed()
+			status, err := tm.dependencyStatus(dependency)
+			if err != nil {
+				return err
+			}
+			if status == StatusCompleted {
+				break
+			}
+			if status == StatusFailed || status == StatusCancelled {
+				return errors.Errorf("dependency %s %s", dependency, status)
+			}
+			select {
+			case <-job.ctx.Done():
+				return job.ctx.Err()
+			case <-changed:
 			}
 		}
 	}
+	return nil
+}
 
-	log.Infof("Job %s completed with status %s", job.ID, job.Status)
+// dependencyStatus returns the status of a job another job depends on,
+// looking in the database for jobs that are no longer in memory
+func (tm *TransferManager) dependencyStatus(jobID string) (string, error) {
+	tm.mu.RLock()
+	job, exists := tm.jobs[jobID]
+	var status string
+	if exists {
+		status = job.Status
+	}
+	tm.mu.RUnlock()
+	if exists {
+		return status, nil
+	}
+
+	if tm.store != nil {
+		if storedJob, err := tm.store.GetJob(jobID); err == nil {
+			return storedJob.Status, nil
+		}
+		if historicalJob, err := tm.store.GetHistoricalJob(jobID); err == nil {
+			return historicalJob.Status, nil
+		}
+	}
+	return "", errors.Wrapf(errInvalidDependency, "job %s not found", jobID)
+}
+
+// validateDependencies checks that each job a new job depends on exists
+func (tm *TransferManager) validateDependencies(dependencies []string) error {
+	for _, dependency := range dependencies {
+		if _, err := tm.dependencyStatus(dependency); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // executeTransfer executes a single transfer
@@ -437,6 +637,7 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 	tm.mu.Lock()
 	transfer.Status = StatusRunning
 	transfer.StartedAt = &now
+	tm.publishTransferStatus(transfer)
 	tm.mu.Unlock()
 
 	// Persist transfer status update to database
@@ -490,6 +691,7 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 	if err != nil {
 		transfer.Status = StatusFailed
 		transfer.Error = err
+		tm.publishTransferStatus(transfer)
 
 		// Persist failure to database
 		if tm.store != nil {
@@ -517,6 +719,7 @@ func (tm *TransferManager) executeTransfer(transfer *Transfer, options []client.
 	transfer.BytesTransferred.Store(totalBytes)
 	transfer.TotalBytes.Store(totalBytes)
 	transfer.Status = StatusCompleted
+	tm.publishTransferStatus(transfer)
 
 	// Persist success to database
 	if tm.store != nil {
@@ -547,6 +750,7 @@ func (tm *TransferManager) cancelRemainingTransfers(job *TransferJob) {
 				now := time.Now()
 				transfer.CompletedAt = &now
 			}
+			tm.publishTransferStatus(transfer)
 		}
 	}
 }
@@ -557,6 +761,7 @@ func (tm *TransferManager) updateJobStatus(jobID, status string, err error) {
 	defer tm.mu.Unlock()
 
 	if job, exists := tm.jobs[jobID]; exists {
+		changed := job.Status != status
 		job.Status = status
 		if err != nil {
 			job.Error = err
@@ -565,6 +770,9 @@ func (tm *TransferManager) updateJobStatus(jobID, status string, err error) {
 			now := time.Now()
 			job.CompletedAt = &now
 		}
+		if changed {
+			tm.publishJobStatus(job)
+		}
 	}
 }
 
@@ -645,6 +853,7 @@ func (tm *TransferManager) CancelJob(jobID string) (int, int, error) {
 					now := time.Now()
 					transfer.CompletedAt = &now
 				}
+				tm.publishTransferStatus(transfer)
 
 				// Persist cancellation to database
 				if tm.store != nil {
@@ -659,11 +868,15 @@ func (tm *TransferManager) CancelJob(jobID string) (int, int, error) {
 		}
 	}
 
+	alreadyCancelled := job.Status == StatusCancelled
 	job.Status = StatusCancelled
 	if job.CompletedAt == nil {
 		now := time.Now()
 		job.CompletedAt = &now
 	}
+	if !alreadyCancelled {
+		tm.publishJobStatus(job)
+	}
 
 	// Persist job cancellation to database
 	if tm.store != nil {
@@ -726,6 +939,7 @@ func (tm *TransferManager) buildJobListItem(job *TransferJob) JobListItem {
 		JobID:              job.ID,
 		Status:             job.Status,
 		CreatedAt:          job.CreatedAt,
+		Priority:           job.Priority,
 		TransfersCompleted: completed,
 		TransfersTotal:     total,
 		BytesTransferred:   bytesTransferred,
@@ -733,6 +947,54 @@ func (tm *TransferManager) buildJobListItem(job *TransferJob) JobListItem {
 	}
 }
 
+// GetJobStatus builds the full status of a job, including its transfers
+func (tm *TransferManager) GetJobStatus(job *TransferJob) *JobStatus {
+	tm.mu.RLock()
+	transfers := make([]TransferStatus, len(job.Transfers))
+	for i, transfer := range job.Transfers {
+		status := TransferStatus{
+			TransferID:       transfer.ID,
+			JobID:            transfer.JobID,
+			Operation:        transfer.Operation,
+			Source:           transfer.Source,
+			Destination:      transfer.Destination,
+			Status:           transfer.Status,
+			CreatedAt:        transfer.CreatedAt,
+			StartedAt:        transfer.StartedAt,
+			CompletedAt:      transfer.CompletedAt,
+			BytesTransferred: transfer.BytesTransferred.Load(),
+			TotalBytes:       transfer.TotalBytes.Load(),
+		}
+		if transfer.Error != nil {
+			status.Error = transfer.Error.Error()
+		}
+		transfers[i] = status
+	}
+
+	jobStatus := &JobStatus{
+		JobID:          job.ID,
+		Status:         job.Status,
+		CreatedAt:      job.CreatedAt,
+		StartedAt:      job.StartedAt,
+		CompletedAt:    job.CompletedAt,
+		Transfers:      transfers,
+		StartAfter:     job.StartAfter,
+		DependsOn:      job.DependsOn,
+		ScheduleID:     job.ScheduleID,
+		Priority:       job.Priority,
+		BandwidthLimit: job.BandwidthLimit,
+	}
+	if job.Error != nil {
+		jobStatus.Error = job.Error.Error()
+	}
+	tm.mu.RUnlock()
+
+	// GetJobProgress takes the read lock itself
+	jobStatus.Progress = tm.GetJobProgress(job)
+	jobStatus.QueuePosition = tm.queue.position(job.ID)
+	return jobStatus
+}
+
 // GetJobProgress calculates current progress for a job
 func (tm *TransferManager) GetJobProgress(job *TransferJob) *JobProgress {
 	tm.mu.RLock()
@@ -978,11 +1240,16 @@ func (tm *TransferManager) Shutdown() error {
 	return nil
 }
 
-// HasActiveJobs returns true if there are any jobs in pending or running status
+// HasActiveJobs returns true if there are any jobs in pending or running status,
+// or any recurring schedules that will start jobs in the future
 func (tm *TransferManager) HasActiveJobs() bool {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
 
+	if len(tm.schedules) > 0 {
+		return true
+	}
+
 	for _, job := range tm.jobs {
 		if job.Status == StatusPending || job.Status == StatusRunning {
 			return true
@@ -67,3 +67,14 @@ type HistoricalJob struct {
 	BytesTransferred   int64
 	TotalBytes         int64
 }
+
+// StoredSchedule represents a recurring job schedule stored in the database
+type StoredSchedule struct {
+	ID        string
+	Cron      string
+	Request   string // JSON-encoded job request used for each run
+	CreatedAt int64
+	NextRun   time.Time
+	LastRun   *time.Time
+	LastJobID string
+}
@@ -150,6 +150,13 @@ func constructApiKeyApiURL(serverURLStr string) (*url.URL, error) {
 
 // Helper function to load or generate token that could access server's web API with admin privileges
 func fetchOrGenerateWebAPIAdminToken(serverURLStr, tokenLocation string) (string, error) {
+	return fetchOrGenerateWebAPIToken(serverURLStr, tokenLocation, token_scopes.WebUi_Access)
+}
+
+// fetchOrGenerateWebAPIToken reads the token at tokenLocation if one is provided;
+// otherwise, it generates a short-lived token with the given scopes, signed with
+// the current issuer key.
+func fetchOrGenerateWebAPIToken(serverURLStr, tokenLocation string, scopes ...token_scopes.TokenScope) (string, error) {
 	var tok string
 	var err error
 	// Prioritize using a token from a file if one is provided.
@@ -171,7 +178,7 @@ func fetchOrGenerateWebAPIAdminToken(serverURLStr, tokenLocation string) (string
 		tc.Subject = "admin"
 		tc.Issuer = serverURLStr
 		tc.AddAudienceAny()
-		tc.AddScopes(token_scopes.WebUi_Access)
+		tc.AddScopes(scopes...)
 		tok, err := tc.CreateToken()
 		if err != nil {
 			log.Debugln("Token Configuration (partial):")
@@ -96,8 +96,8 @@ func jobListMain(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Total jobs: %d\n\n", resp.Total)
-	fmt.Printf("%-40s %-12s %-20s %s\n", "Job ID", "Status", "Created", "Progress")
-	fmt.Println("─────────────────────────────────────────────────────────────────────────────────────────────")
+	fmt.Printf("%-40s %-12s %-8s %-20s %s\n", "Job ID", "Status", "Priority", "Created", "Progress")
+	fmt.Println("──────────────────────────────────────────────────────────────────────────────────────────────────────")
 
 	for _, job := range resp.Jobs {
 		progress := ""
@@ -108,9 +108,10 @@ func jobListMain(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		fmt.Printf("%-40s %-12s %-20s %s\n",
+		fmt.Printf("%-40s %-12s %-8d %-20s %s\n",
 			job.JobID,
 			job.Status,
+			job.Priority,
 			job.CreatedAt.Format(time.RFC3339),
 			progress)
 	}
@@ -0,0 +1,304 @@
+//go:build client
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/byte_rate"
+	"github.com/pelicanplatform/pelican/client_agent"
+	"github.com/pelicanplatform/pelican/config"
+)
+
+var (
+	jobScheduleCmd = &cobra.Command{
+		Use:   "schedule <operation> <source> [destination]",
+		Short: "Schedule a deferred, dependent or recurring transfer job",
+		Long: `Schedule a transfer job to run later, after other jobs, or repeatedly.
+The operation is one of get, put, copy or prestage; prestage takes no
+destination.
+
+With --start-after, the job does not start before the given time, which is
+either an RFC3339 timestamp or a duration from now (e.g. 2h). With
+--depends-on, the job waits for the given jobs to complete and fails if any
+of them fails or is cancelled.
+
+With --cron, a recurring schedule is created instead of a single job. A new
+job is started each time the cron expression matches, unless the job from
+the previous run is still running. The expression is either five fields
+(minute, hour, day of month, month, day of week), a macro such as @daily or
+@hourly, or "@every <duration>". The start time delays the first run and
+the dependencies apply to every run.
+
+Jobs waiting for a free slot in the client agent are started highest
+--priority first (the default is 0; use a negative priority for background
+work). --bandwidth-limit caps the job's transfer rate, e.g. 10MB/s, in
+addition to any agent-wide ClientAgent.BandwidthLimit.`,
+		Example: `  pelican job schedule get pelican://example.org/data/file.txt /tmp/ --start-after 2h
+  pelican job schedule put /tmp/results.txt pelican://example.org/data/ --depends-on <job-id>
+  pelican job schedule get pelican://example.org/data/ /tmp/data -r --cron "0 2 * * *"
+  pelican job schedule get pelican://example.org/data/ /tmp/data -r --priority -10 --bandwidth-limit 20MB/s`,
+		Args:         cobra.RangeArgs(2, 3),
+		SilenceUsage: true,
+		RunE:         jobScheduleMain,
+	}
+
+	jobScheduleListCmd = &cobra.Command{
+		Use:          "list",
+		Short:        "List recurring transfer job schedules",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         jobScheduleListMain,
+	}
+
+	jobScheduleDeleteCmd = &cobra.Command{
+		Use:   "delete <schedule-id>",
+		Short: "Delete a recurring transfer job schedule",
+		Long: `Delete a recurring transfer job schedule so it starts no more jobs.
+Jobs the schedule already started are not cancelled.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         jobScheduleDeleteMain,
+	}
+
+	jobScheduleCron       string
+	jobScheduleStartAfter string
+	jobScheduleDependsOn  []string
+	jobScheduleRecursive  bool
+	jobScheduleToken      string
+	jobSchedulePriority   int
+	jobScheduleBandwidth  string
+)
+
+func init() {
+	flagSet := jobScheduleCmd.Flags()
+	flagSet.StringVar(&jobScheduleCron, "cron", "", "Create a recurring schedule from a cron expression, e.g. \"0 2 * * *\", \"@daily\" or \"@every 6h\"")
+	flagSet.StringVar(&jobScheduleStartAfter, "start-after", "", "Do not start before this time (RFC3339 timestamp or duration from now)")
+	flagSet.StringArrayVar(&jobScheduleDependsOn, "depends-on", nil, "Wait for this job to complete first; may be repeated")
+	flagSet.BoolVarP(&jobScheduleRecursive, "recursive", "r", false, "Transfer a collection recursively")
+	flagSet.StringVarP(&jobScheduleToken, "token", "t", "", "Token file to use for the transfer")
+	flagSet.IntVar(&jobSchedulePriority, "priority", 0, "Start before queued jobs with a lower priority")
+	flagSet.StringVar(&jobScheduleBandwidth, "bandwidth-limit", "", "Cap the job's transfer rate, e.g. 10MB/s or 100Mbps")
+
+	jobScheduleCmd.AddCommand(jobScheduleListCmd)
+	jobScheduleCmd.AddCommand(jobScheduleDeleteCmd)
+	jobCmd.AddCommand(jobScheduleCmd)
+}
+
+// parseStartAfter parses an RFC3339 timestamp or a duration from now
+func parseStartAfter(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if startAfter, err := time.Parse(time.RFC3339, value); err == nil {
+		return &startAfter, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, errors.Errorf("invalid start time %q (must be an RFC3339 timestamp or a duration)", value)
+	}
+	startAfter := time.Now().Add(delay)
+	return &startAfter, nil
+}
+
+func jobScheduleMain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	operation := strings.ToLower(args[0])
+	transfer := client_agent.TransferRequest{
+		Operation: operation,
+		Source:    args[1],
+		Recursive: jobScheduleRecursive,
+	}
+	switch operation {
+	case "get", "put", "copy":
+		if len(args) != 3 {
+			return errors.Errorf("a destination is required for %s operations", operation)
+		}
+		transfer.Destination = args[2]
+	case "prestage":
+		if len(args) != 2 {
+			return errors.New("prestage operations do not take a destination")
+		}
+	default:
+		return errors.Errorf("unknown operation %q (must be get, put, copy or prestage)", args[0])
+	}
+
+	startAfter, err := parseStartAfter(jobScheduleStartAfter)
+	if err != nil {
+		return err
+	}
+
+	var bandwidthLimit byte_rate.ByteRate
+	if jobScheduleBandwidth != "" {
+		if bandwidthLimit, err = byte_rate.ParseRate(jobScheduleBandwidth); err != nil {
+			return errors.Wrapf(err, "invalid bandwidth limit %q", jobScheduleBandwidth)
+		}
+	}
+
+	// Initialize config to read parameters
+	if err := config.InitClient(); err != nil {
+		return errors.Wrap(err, "failed to initialize config")
+	}
+
+	// Ensure server is running (auto-start if needed)
+	apiClient, err := ensureClientAgentRunning(ctx, 5)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to client agent server")
+	}
+
+	jobReq := client_agent.JobRequest{
+		Transfers: []client_agent.TransferRequest{transfer},
+		Options: client_agent.TransferOptions{
+			Token:          jobScheduleToken,
+			Priority:       jobSchedulePriority,
+			BandwidthLimit: bandwidthLimit,
+		},
+		StartAfter: startAfter,
+		DependsOn:  jobScheduleDependsOn,
+	}
+
+	if jobScheduleCron == "" {
+		jobID, err := apiClient.SubmitJob(ctx, jobReq)
+		if err != nil {
+			return errors.Wrap(err, "failed to create job")
+		}
+		if outputJSON {
+			jsonBytes, err := json.MarshalIndent(map[string]interface{}{
+				"job_id": jobID,
+				"status": "created",
+			}, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal JSON")
+			}
+			fmt.Println(string(jsonBytes))
+		} else {
+			fmt.Printf("Job created: %s\n", jobID)
+		}
+		return nil
+	}
+
+	schedule, err := apiClient.CreateSchedule(ctx, client_agent.ScheduleRequest{
+		JobRequest: jobReq,
+		Cron:       jobScheduleCron,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create schedule")
+	}
+	if outputJSON {
+		jsonBytes, err := json.MarshalIndent(schedule, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON")
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Printf("Schedule created: %s\n", schedule.ScheduleID)
+		fmt.Printf("Next run: %s\n", schedule.NextRun.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func jobScheduleListMain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	// Initialize config to read parameters
+	if err := config.InitClient(); err != nil {
+		return errors.Wrap(err, "failed to initialize config")
+	}
+
+	// Ensure server is running (auto-start if needed)
+	apiClient, err := ensureClientAgentRunning(ctx, 5)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to client agent server")
+	}
+
+	resp, err := apiClient.ListSchedules(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list schedules")
+	}
+
+	if outputJSON {
+		jsonBytes, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON")
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(resp.Schedules) == 0 {
+		fmt.Println("No schedules found")
+		return nil
+	}
+
+	fmt.Printf("%-40s %-16s %-26s %s\n", "Schedule ID", "Cron", "Next Run", "Last Job")
+	fmt.Println("─────────────────────────────────────────────────────────────────────────────────────────────")
+	for _, schedule := range resp.Schedules {
+		fmt.Printf("%-40s %-16s %-26s %s\n",
+			schedule.ScheduleID,
+			schedule.Cron,
+			schedule.NextRun.Format(time.RFC3339),
+			schedule.LastJobID)
+	}
+
+	return nil
+}
+
+func jobScheduleDeleteMain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	scheduleID := args[0]
+
+	// Initialize config to read parameters
+	if err := config.InitClient(); err != nil {
+		return errors.Wrap(err, "failed to initialize config")
+	}
+
+	// Ensure server is running (auto-start if needed)
+	apiClient, err := ensureClientAgentRunning(ctx, 5)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to client agent server")
+	}
+
+	if err := apiClient.DeleteSchedule(ctx, scheduleID); err != nil {
+		return errors.Wrap(err, "failed to delete schedule")
+	}
+
+	if outputJSON {
+		jsonBytes, err := json.MarshalIndent(map[string]interface{}{
+			"schedule_id": scheduleID,
+			"status":      "deleted",
+		}, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal JSON")
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Printf("Schedule %s deleted\n", scheduleID)
+	}
+
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -134,6 +135,30 @@ func printJobStatus(status *client_agent.JobStatus) {
 		fmt.Printf("Completed: %s\n", status.CompletedAt.Format(time.RFC3339))
 	}
 
+	if status.StartAfter != nil {
+		fmt.Printf("Start after: %s\n", status.StartAfter.Format(time.RFC3339))
+	}
+
+	if len(status.DependsOn) > 0 {
+		fmt.Printf("Depends on: %s\n", strings.Join(status.DependsOn, ", "))
+	}
+
+	if status.ScheduleID != "" {
+		fmt.Printf("Schedule: %s\n", status.ScheduleID)
+	}
+
+	if status.Priority != 0 {
+		fmt.Printf("Priority: %d\n", status.Priority)
+	}
+
+	if status.QueuePosition > 0 {
+		fmt.Printf("Queue position: %d\n", status.QueuePosition)
+	}
+
+	if status.BandwidthLimit > 0 {
+		fmt.Printf("Bandwidth limit: %s\n", status.BandwidthLimit)
+	}
+
 	if status.Progress != nil {
 		fmt.Printf("\nProgress:\n")
 		fmt.Printf("  Transfers: %d/%d completed", status.Progress.TransfersCompleted, status.Progress.TransfersTotal)
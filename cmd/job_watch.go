@@ -0,0 +1,153 @@
+//go:build client
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/client_agent"
+	"github.com/pelicanplatform/pelican/config"
+)
+
+var (
+	jobWatchCmd = &cobra.Command{
+		Use:   "watch <job-id>",
+		Short: "Follow the progress of a transfer job",
+		Long: `Follow a transfer job as it runs, printing each job and transfer state
+change and periodic progress updates until the job finishes.
+
+Unlike 'pelican job status --watch', events are pushed by the client agent
+as they happen rather than polled. With --json, each event is printed as a
+single-line JSON object.
+
+The command exits with an error if the job fails or is cancelled.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         jobWatchMain,
+	}
+)
+
+func init() {
+	jobCmd.AddCommand(jobWatchCmd)
+}
+
+func jobWatchMain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	jobID := args[0]
+
+	// Initialize config to read parameters
+	if err := config.InitClient(); err != nil {
+		return errors.Wrap(err, "failed to initialize config")
+	}
+
+	// Ensure server is running (auto-start if needed)
+	apiClient, err := ensureClientAgentRunning(ctx, 5)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to client agent server")
+	}
+
+	// Transfer events only carry the transfer ID; remember the sources
+	// from the job snapshot to print something readable
+	sources := make(map[string]string)
+	var final client_agent.JobEvent
+	err = apiClient.WatchJob(ctx, jobID, func(event client_agent.JobEvent) error {
+		if event.Type == client_agent.EventSnapshot || event.Type == client_agent.EventJobStatus {
+			final = event
+		}
+		if outputJSON {
+			jsonBytes, err := json.Marshal(event)
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal JSON")
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+		printJobEvent(event, sources)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to watch job")
+	}
+
+	switch final.Status {
+	case client_agent.StatusFailed:
+		if final.Error != "" {
+			return errors.Errorf("job failed: %s", final.Error)
+		}
+		return errors.New("job failed")
+	case client_agent.StatusCancelled:
+		return errors.New("job was cancelled")
+	}
+	return nil
+}
+
+func printJobEvent(event client_agent.JobEvent, sources map[string]string) {
+	timestamp := event.Timestamp.Local().Format(time.TimeOnly)
+	switch event.Type {
+	case client_agent.EventSnapshot:
+		for _, transfer := range event.Job.Transfers {
+			sources[transfer.TransferID] = transfer.Source
+		}
+		fmt.Printf("%s Job %s is %s\n", timestamp, event.JobID, event.Status)
+		for _, transfer := range event.Job.Transfers {
+			fmt.Printf("%s   [%s] %s -> %s (%s)\n", timestamp, transfer.Status, transfer.Source, transfer.Destination, transfer.Operation)
+		}
+		if event.Job.Progress != nil {
+			printProgressLine(timestamp, event.Job.Progress)
+		}
+	case client_agent.EventReset:
+		fmt.Printf("%s Some events were missed; resynchronizing\n", timestamp)
+	case client_agent.EventJobStatus:
+		fmt.Printf("%s Job %s\n", timestamp, event.Status)
+	case client_agent.EventTransferStatus:
+		source := sources[event.TransferID]
+		if source == "" {
+			source = event.TransferID
+		}
+		fmt.Printf("%s   [%s] %s\n", timestamp, event.Status, source)
+	case client_agent.EventProgress:
+		printProgressLine(timestamp, event.Progress)
+		return
+	}
+	if event.Error != "" {
+		fmt.Printf("%s     Error: %s\n", timestamp, event.Error)
+	}
+}
+
+func printProgressLine(timestamp string, progress *client_agent.JobProgress) {
+	if progress == nil {
+		return
+	}
+	fmt.Printf("%s Progress: %d/%d transfers", timestamp, progress.TransfersCompleted, progress.TransfersTotal)
+	if progress.TotalBytes > 0 {
+		fmt.Printf(", %s / %s (%.1f%%) at %.2f Mbps",
+			formatBytes(progress.BytesTransferred),
+			formatBytes(progress.TotalBytes),
+			progress.Percentage,
+			progress.TransferRateMbps)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,41 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	localCacheCmd = &cobra.Command{
+		Use:   "local-cache",
+		Short: "Manage a Pelican local cache",
+		Long: `Provide commands to manage a running Pelican local cache through its
+administrative API endpoint.`,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(localCacheCmd)
+
+	localCacheCmd.PersistentFlags().StringVarP(&serverURLStr, "server", "s", "", "Web URL of the local cache (defaults to Server.ExternalWebUrl)")
+	localCacheCmd.PersistentFlags().StringVarP(&tokenLocation, "token", "t", "", "Path to a token with the localcache.purge scope")
+}
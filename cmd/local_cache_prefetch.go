@@ -0,0 +1,221 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/local_cache"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+var (
+	prefetchObjectTokenLocation string
+	prefetchPinLifetime         time.Duration
+	prefetchWait                bool
+
+	localCachePrefetchCmd = &cobra.Command{
+		Use:   "prefetch <manifest>",
+		Short: "Pre-populate the local cache with the objects in a manifest",
+		Long: `Download the objects listed in a manifest into a running local cache before they are used.
+
+The manifest lists one object path per line; a path ending in '/' is a prefix, which is
+listed recursively and every object found is prefetched.  Blank lines and lines starting
+with '#' are ignored.  Use '-' to read the manifest from standard input.
+
+Examples:
+  pelican local-cache prefetch manifest.txt --wait
+  pelican local-cache prefetch manifest.txt --object-token /path/to/read.tkn --pin 12h`,
+		Args: cobra.ExactArgs(1),
+		RunE: prefetchLocalCache,
+	}
+)
+
+func init() {
+	localCacheCmd.AddCommand(localCachePrefetchCmd)
+	localCachePrefetchCmd.Flags().StringVar(&prefetchObjectTokenLocation, "object-token", "", "Path to the token used to read the objects from the federation")
+	localCachePrefetchCmd.Flags().DurationVar(&prefetchPinLifetime, "pin", 0, "Pin each prefetched object against purge for this duration")
+	localCachePrefetchCmd.Flags().BoolVarP(&prefetchWait, "wait", "w", false, "Wait for the prefetch to complete, reporting its progress")
+}
+
+// Parse a prefetch manifest into its objects and prefixes
+func parsePrefetchManifest(reader io.Reader) (request local_cache.PrefetchRequest, err error) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(line, "/") {
+			request.Prefixes = append(request.Prefixes, line)
+		} else {
+			request.Objects = append(request.Objects, line)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		err = errors.Wrap(err, "failed to read prefetch manifest")
+		return
+	}
+	if len(request.Objects) == 0 && len(request.Prefixes) == 0 {
+		err = errors.New("prefetch manifest does not list any objects")
+	}
+	return
+}
+
+func doLocalCacheRequest(ctx context.Context, method string, targetURL *url.URL, tok string, body any) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to marshal request payload")
+		}
+		reqBody = bytes.NewBuffer(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, targetURL.String(), reqBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create HTTP request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	req.Header.Set("User-Agent", "pelican-client/"+config.GetVersion())
+
+	httpClient := &http.Client{Transport: config.GetTransport()}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "HTTP request failed")
+	}
+	defer resp.Body.Close()
+	return handleAdminApiResponse(resp)
+}
+
+func printPrefetchStatus(status local_cache.PrefetchStatus) {
+	fmt.Printf("Prefetch %s (%s): %d of %d objects cached (%d bytes), %d failed\n",
+		status.ID, status.State, status.Succeeded, status.Total, status.Bytes, status.Failed)
+}
+
+func prefetchLocalCache(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := config.InitClient(); err != nil {
+		log.Errorln("Failed to initialize client:", err)
+	}
+
+	manifest := os.Stdin
+	if args[0] != "-" {
+		fp, err := os.Open(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to open prefetch manifest")
+		}
+		defer fp.Close()
+		manifest = fp
+	}
+	request, err := parsePrefetchManifest(manifest)
+	if err != nil {
+		return err
+	}
+	if prefetchObjectTokenLocation != "" {
+		if request.Token, err = utils.GetTokenFromFile(prefetchObjectTokenLocation); err != nil {
+			return errors.Wrapf(err, "Failed to read token file: %s", prefetchObjectTokenLocation)
+		}
+	}
+	if prefetchPinLifetime > 0 {
+		request.PinLifetime = prefetchPinLifetime.String()
+	}
+
+	srvURL := serverURLStr
+	if srvURL == "" {
+		if srvURL = param.Server_ExternalWebUrl.GetString(); srvURL == "" {
+			return errors.New("Server URL must be provided via --server flag or Server.ExternalWebUrl config")
+		}
+	}
+	baseURL, err := url.Parse(strings.TrimSuffix(srvURL, "/"))
+	if err != nil {
+		return errors.Wrapf(err, "Invalid server URL format: %s", srvURL)
+	}
+	tok, err := fetchOrGenerateWebAPIToken(srvURL, tokenLocation, token_scopes.Localcache_Purge)
+	if err != nil {
+		return err
+	}
+
+	body, err := doLocalCacheRequest(ctx, http.MethodPost, baseURL.JoinPath("/api/v1.0/localcache/prefetch"), tok, request)
+	if err != nil {
+		return errors.Wrap(err, "Server request failed")
+	}
+	var status local_cache.PrefetchStatus
+	if err = json.Unmarshal(body, &status); err != nil {
+		return errors.Wrap(err, "Failed to parse server response")
+	}
+	fmt.Printf("Started prefetch %s of %d objects and %d prefixes\n", status.ID, len(request.Objects), len(request.Prefixes))
+	if !prefetchWait {
+		return nil
+	}
+
+	statusURL := baseURL.JoinPath("/api/v1.0/localcache/prefetch", status.ID)
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for status.State != local_cache.PrefetchCompleted {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		// Generated tokens are short-lived; refresh the token for each poll
+		if tok, err = fetchOrGenerateWebAPIToken(srvURL, tokenLocation, token_scopes.Localcache_Purge); err != nil {
+			return err
+		}
+		if body, err = doLocalCacheRequest(ctx, http.MethodGet, statusURL, tok, nil); err != nil {
+			return errors.Wrap(err, "Server request failed")
+		}
+		if err = json.Unmarshal(body, &status); err != nil {
+			return errors.Wrap(err, "Failed to parse server response")
+		}
+		printPrefetchStatus(status)
+	}
+	for _, failure := range status.Failures {
+		fmt.Printf("Failed to prefetch %s: %s\n", failure.Path, failure.Error)
+	}
+	if status.Failed > 0 {
+		return errors.Errorf("%d objects failed to prefetch", status.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build server
+
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrefetchManifest(t *testing.T) {
+	request, err := parsePrefetchManifest(strings.NewReader(`
+# Inputs for the workflow
+/ns/inputs/a.dat
+  /ns/inputs/b.dat
+
+/ns/reference/
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/ns/inputs/a.dat", "/ns/inputs/b.dat"}, request.Objects)
+	assert.Equal(t, []string{"/ns/reference/"}, request.Prefixes)
+
+	_, err = parsePrefetchManifest(strings.NewReader("# nothing\n\n"))
+	assert.Error(t, err)
+}
@@ -23,6 +23,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -62,6 +63,7 @@ func init() {
 the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.StringP("token", "t", "", "Token file to use for transfer")
 	flagSet.BoolP("recursive", "r", false, "Recursively copy a collection.  Forces methods to only be http to get the freshest collection contents")
+	flagSet.Bool("tpc", true, "When both source and destination are remote, ask the destination to pull the object from the source; if disabled or unsupported, the object is streamed through the client")
 	flagSet.StringP("cache-list-name", "n", "xroot", "(Deprecated) Cache list to use, currently either xroot or xroots; may be ignored")
 	flagSet.Lookup("cache-list-name").Hidden = true
 
@@ -281,7 +283,12 @@ func copyMain(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if len(source) > 1 {
+	// A remote destination for several sources is treated as a collection
+	if destUrl, err := url.Parse(dest); err == nil && destUrl.Scheme != "" && destUrl.Scheme != "file" {
+		if len(source) > 1 && !strings.HasSuffix(dest, "/") {
+			dest += "/"
+		}
+	} else if len(source) > 1 {
 		if destStat, err := os.Stat(dest); err != nil {
 			log.Errorln("Destination does not exist")
 			os.Exit(1)
@@ -294,9 +301,11 @@ func copyMain(cmd *cobra.Command, args []string) {
 	var result error
 	lastSrc := ""
 
+	useTpc, _ := cmd.Flags().GetBool("tpc")
 	for _, src := range source {
 		isRecursive, _ := cmd.Flags().GetBool("recursive")
-		_, result = client.DoCopy(ctx, src, dest, isRecursive, client.WithCallback(pb.callback), client.WithTokenLocation(tokenLocation), client.WithCaches(caches...))
+		_, result = client.DoCopy(ctx, src, dest, isRecursive, client.WithCallback(pb.callback), client.WithTokenLocation(tokenLocation), client.WithCaches(caches...),
+			client.WithThirdPartyCopy(useTpc))
 		if result != nil {
 			lastSrc = src
 			break
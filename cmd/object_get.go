@@ -56,6 +56,8 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.StringP("token", "t", "", "Token file to use for transfer")
 	flagSet.BoolP("recursive", "r", false, "Recursively download a collection.  Forces methods to only be http to get the freshest collection contents")
 	flagSet.Bool("inplace", false, "Write files directly to destination (default: use temporary files)")
+	flagSet.Bool("resume", false, "Keep partial downloads on failure and resume them on the next run (default: the Client.ResumeDownloads setting)")
+	flagSet.Int("streams", 1, "Number of concurrent range requests used to download large objects (default: the Client.ParallelDownloadStreams setting)")
 	flagSet.Bool("dry-run", false, "Show what would be downloaded without actually downloading")
 	flagSet.StringP("cache-list-name", "n", "xroot", "(Deprecated) Cache list to use, currently either xroot or xroots; may be ignored")
 	flagSet.Lookup("cache-list-name").Hidden = true
@@ -63,6 +65,7 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.String("transfer-stats", "", "A path to a file to write transfer statistics to")
 	flagSet.String("pack", "", "Package transfer using remote packing functionality (same as '?pack=' query). Options: auto, tar, tar.gz, tar.xz, zip. Default: auto when flag is provided without an explicit value")
 	flagSet.Bool("direct", false, "Download directly from an origin, bypassing any caches (same as '?directread' query)")
+	flagSet.String("decrypt", "", "Decrypt encrypted objects with the key in this file: a base64-encoded 32-byte key or a PEM-encoded X25519 private key. Objects that are not encrypted are downloaded unchanged")
 	flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 	flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
 	objectCmd.AddCommand(getCmd)
@@ -85,6 +88,10 @@ func getMain(cmd *cobra.Command, args []string) {
 
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
+	if isAsync && cmd.Flags().Changed("decrypt") {
+		log.Errorln("The --decrypt flag cannot be used with --async")
+		os.Exit(1)
+	}
 	if isAsync {
 		// Validate arguments
 		if len(args) < 2 {
@@ -287,6 +294,14 @@ func getMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	var encryptionKey *client.EncryptionKey
+	if keyFile, _ := cmd.Flags().GetString("decrypt"); keyFile != "" {
+		if encryptionKey, err = client.LoadEncryptionKey(keyFile); err != nil {
+			log.Errorln("Failed to load decryption key:", err)
+			os.Exit(1)
+		}
+	}
+
 	var attemptErr error
 	lastSrc := ""
 
@@ -302,6 +317,17 @@ func getMain(cmd *cobra.Command, args []string) {
 			client.WithInPlace(inPlace),
 			client.WithDryRun(dryRun),
 		}
+		if cmd.Flags().Changed("resume") {
+			resume, _ := cmd.Flags().GetBool("resume")
+			options = append(options, client.WithResume(resume))
+		}
+		if cmd.Flags().Changed("streams") {
+			streams, _ := cmd.Flags().GetInt("streams")
+			options = append(options, client.WithParallelStreams(streams))
+		}
+		if encryptionKey != nil {
+			options = append(options, client.WithEncryptionKey(encryptionKey))
+		}
 		transferResults, err := client.DoGet(ctx, src, dest, isRecursive, options...)
 		if err != nil {
 			attemptErr = err
@@ -62,6 +62,7 @@ func init() {
 	flagSet.String("checksums", "", "Verify files against a checksums manifest. The format is ALGORITHM:FILENAME")
 	flagSet.String("transfer-stats", "", "File to write transfer stats to")
 	flagSet.String("pack", "", "Package transfer using remote packing functionality (same as '?pack=' query). Options: auto, tar, tar.gz, tar.xz, zip. Default: auto when flag is provided without an explicit value")
+	flagSet.String("encrypt", "", "Encrypt objects before uploading with the key in this file: a base64-encoded 32-byte key, or a PEM-encoded X25519 public or private key of the recipient")
 	flagSet.Bool("async", false, "Run the transfer asynchronously through the client API server and return a job ID")
 	flagSet.Bool("wait", false, "When used with --async, wait for the job to complete before returning")
 	objectCmd.AddCommand(putCmd)
@@ -147,6 +148,10 @@ func putMain(cmd *cobra.Command, args []string) {
 
 	// Check for async mode
 	isAsync, _ := cmd.Flags().GetBool("async")
+	if isAsync && cmd.Flags().Changed("encrypt") {
+		log.Errorln("The --encrypt flag cannot be used with --async")
+		os.Exit(1)
+	}
 	if isAsync {
 		// Validate arguments
 		if len(args) < 2 {
@@ -367,6 +372,14 @@ func putMain(cmd *cobra.Command, args []string) {
 
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	options = append(options, client.WithCallback(pb.callback), client.WithTokenLocation(tokenLocation), client.WithDryRun(dryRun))
+	if keyFile, _ := cmd.Flags().GetString("encrypt"); keyFile != "" {
+		key, err := client.LoadEncryptionKey(keyFile)
+		if err != nil {
+			log.Errorln("Failed to load encryption key:", err)
+			os.Exit(1)
+		}
+		options = append(options, client.WithEncryptionKey(key))
+	}
 
 	finalResults := make([][]client.TransferResults, 0)
 
@@ -53,6 +53,9 @@ the client should fallback to discovered caches if all preferred caches fail.`)
 	flagSet.StringP("token", "t", "", "Token file to use for transfer")
 	flagSet.Bool("inplace", false, "Write files directly to destination (default: use temporary files)")
 	flagSet.Bool("dry-run", false, "Show what would be synchronized without actually modifying the destination")
+	flagSet.String("compare", "size", `How to decide whether an object already at the destination is up to date: "exist", "size",
+"modtime" (same size and destination not older than source) or "checksum" (compare against the server-reported digest)`)
+	flagSet.Bool("delete", false, "Delete objects at the destination that are not present at the source")
 	objectCmd.AddCommand(syncCmd)
 }
 
@@ -88,6 +91,21 @@ func isPelicanUrl(input string) bool {
 	return true
 }
 
+// Map the value of the --compare flag to a client synchronization level
+func parseSyncLevel(compare string) (client.SyncLevel, error) {
+	switch strings.ToLower(compare) {
+	case "exist", "exists":
+		return client.SyncExist, nil
+	case "size":
+		return client.SyncSize, nil
+	case "modtime", "mtime":
+		return client.SyncModTime, nil
+	case "checksum":
+		return client.SyncChecksum, nil
+	}
+	return client.SyncNone, errors.Errorf("unknown comparison mode %q; must be one of exist, size, modtime or checksum", compare)
+}
+
 func syncMain(cmd *cobra.Command, args []string) {
 	ctx := cmd.Context()
 
@@ -173,16 +191,28 @@ func syncMain(cmd *cobra.Command, args []string) {
 	lastSrc := ""
 
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	deleteExtra, _ := cmd.Flags().GetBool("delete")
+	compare, _ := cmd.Flags().GetString("compare")
+	syncLevel, err := parseSyncLevel(compare)
+	if err != nil {
+		log.Errorln(err)
+		os.Exit(1)
+	}
+	if deleteExtra && len(sources) > 1 {
+		log.Errorln("The --delete flag may only be used with a single source")
+		os.Exit(1)
+	}
 
 	if doDownload {
 		for _, src := range sources {
 			options := []client.TransferOption{
 				client.WithCallback(pb.callback),
 				client.WithTokenLocation(tokenLocation),
-				client.WithSynchronize(client.SyncSize),
+				client.WithSynchronize(syncLevel),
 				client.WithCaches(caches...),
 				client.WithInPlace(inPlace),
 				client.WithDryRun(dryRun),
+				client.WithDeleteExtraneous(deleteExtra),
 			}
 			if _, err = client.DoGet(ctx, src, dest, true, options...); err != nil {
 				lastSrc = src
@@ -201,9 +231,10 @@ func syncMain(cmd *cobra.Command, args []string) {
 			options := []client.TransferOption{
 				client.WithCallback(pb.callback),
 				client.WithTokenLocation(tokenLocation),
-				client.WithSynchronize(client.SyncSize),
+				client.WithSynchronize(syncLevel),
 				client.WithCaches(caches...),
 				client.WithDryRun(dryRun),
+				client.WithDeleteExtraneous(deleteExtra),
 			}
 			if _, err = client.DoPut(ctx, src, dest, true, options...); err != nil {
 				lastSrc = src
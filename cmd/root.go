@@ -27,6 +27,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -39,6 +40,7 @@ import (
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/logging"
 	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 type uint16Value uint16
@@ -116,6 +118,13 @@ func Execute() error {
 	// Wait until all goroutines in errgroup finish their clean up
 	egrpErr := egrp.Wait()
 
+	// Send any trace spans still buffered by the exporter
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := tracing.Shutdown(shutdownCtx); err != nil {
+		log.Debugln("Failed to flush trace spans:", err)
+	}
+	cancel()
+
 	// Flush logs if necessary, after all logging and cleanup is complete
 	logging.FlushLogs(false)
 	if out, ok := log.StandardLogger().Out.(*os.File); ok {
@@ -53,6 +53,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 	"github.com/pelicanplatform/pelican/version"
 )
@@ -1941,13 +1942,17 @@ func InitServer(ctx context.Context, currentServers server_structs.ServerType) e
 		switch s := (server_structs.SortType)(param.Director_CacheSortMethod.GetString()); s {
 		case server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType:
 			break
+		case server_structs.PolicyType:
+			if param.Director_SortPolicyFile.GetString() == "" {
+				return errors.Errorf("Director.CacheSortMethod is %q but no policy file is configured in %s", s, param.Director_SortPolicyFile.GetName())
+			}
 		case server_structs.SortType(""):
 			if err := param.Director_CacheSortMethod.Set(string(server_structs.DistanceType)); err != nil {
 				return err
 			}
 		default:
-			return errors.New(fmt.Sprintf("invalid Director.CacheSortMethod. Must be one of %q, %q, %q, or %q, but you configured %q.",
-				server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType, s))
+			return errors.New(fmt.Sprintf("invalid Director.CacheSortMethod. Must be one of %q, %q, %q, %q, or %q, but you configured %q.",
+				server_structs.DistanceType, server_structs.DistanceAndLoadType, server_structs.RandomType, server_structs.AdaptiveType, server_structs.PolicyType, s))
 		}
 	} else {
 		viper.SetDefault("Federation.DirectorUrl", "")
@@ -2212,6 +2217,10 @@ func InitClient() error {
 	// Set up the log filter mechanisms, e.g., for sensitive secrets
 	initFilterLogging()
 
+	if err := tracing.Setup(context.Background(), "pelican-client"); err != nil {
+		return errors.Wrap(err, "failed to configure tracing")
+	}
+
 	clientInitialized = true
 
 	var printClientConfigErr error
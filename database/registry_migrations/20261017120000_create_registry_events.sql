@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Namespace registration events, kept for the events API and as the outbox for notifications
+CREATE TABLE IF NOT EXISTS registry_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    registration_id INTEGER NOT NULL,
+    prefix TEXT NOT NULL,
+    site_name TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The delivery state of each event to each notification target
+CREATE TABLE IF NOT EXISTS registry_event_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL,
+    target TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NOT NULL,
+    delivered_at DATETIME,
+    failed BOOLEAN NOT NULL DEFAULT FALSE,
+    last_error TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (event_id) REFERENCES registry_events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_registry_events_created_at ON registry_events(created_at);
+CREATE INDEX IF NOT EXISTS idx_registry_event_deliveries_pending ON registry_event_deliveries(delivered_at, failed, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_registry_event_deliveries_event_id ON registry_event_deliveries(event_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_registry_event_deliveries_event_id;
+DROP INDEX IF EXISTS idx_registry_event_deliveries_pending;
+DROP INDEX IF EXISTS idx_registry_events_created_at;
+DROP TABLE IF EXISTS registry_event_deliveries;
+DROP TABLE IF EXISTS registry_events;
+
+-- +goose StatementEnd
@@ -37,6 +37,7 @@ import (
 
 	"github.com/pelicanplatform/pelican/broker"
 	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/features"
 	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
@@ -301,11 +302,12 @@ func recordAd(ctx context.Context, sAd server_structs.ServerAd, namespaceAds *[]
 	if sAd.BrokerURL.Host != "" && brokerDialer != nil {
 		sType := server_structs.NewServerType()
 		sType.SetString(sAd.Type)
-		log.Debugf("Director registering broker endpoint for %s WebURL=%s BrokerURL=%s", sAd.Type, sAd.WebURL.Host, sAd.BrokerURL.String())
-		brokerDialer.UseBroker(sType, sAd.WebURL.Host, sAd.BrokerURL.String(), sAd.RegistryPrefix)
+		tunnel := features.ServerSupportsFeature(features.BrokerTunnel, sAd) == utils.Tern_True
+		log.Debugf("Director registering broker endpoint for %s WebURL=%s BrokerURL=%s (tunnels supported: %t)", sAd.Type, sAd.WebURL.Host, sAd.BrokerURL.String(), tunnel)
+		brokerDialer.UseBroker(sType, sAd.WebURL.Host, sAd.BrokerURL.String(), sAd.RegistryPrefix, tunnel)
 		if sAd.Type == server_structs.OriginType.String() {
 			log.Debugf("Director registering broker endpoint for Origin DataURL=%s", sAd.URL.Host)
-			brokerDialer.UseBroker(sType, sAd.URL.Host, sAd.BrokerURL.String(), sAd.RegistryPrefix)
+			brokerDialer.UseBroker(sType, sAd.URL.Host, sAd.BrokerURL.String(), sAd.RegistryPrefix, tunnel)
 		}
 	}
 
@@ -50,6 +50,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/token"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 	"github.com/pelicanplatform/pelican/web_ui"
 )
@@ -844,6 +845,54 @@ func processSortedAdsErr(ginCtx *gin.Context, err error, requestId uuid.UUID) {
 	}
 }
 
+// Choose the servers the cache endpoint redirects to: the sorted caches or, if the
+// namespace has no supporting caches, the first origin willing to serve direct reads.
+// The returned bool reports whether that origin fallback was used; an empty result
+// means neither was available.
+func selectCacheRedirectServers(oAds, cAds []copyAd) (chosen []server_structs.ServerAd, fellBackToOrigin bool) {
+	if len(cAds) == 0 {
+		for _, oAd := range oAds {
+			// Find the first origin that enables direct reads as the fallback
+			if oAd.ServerAd.Caps.DirectReads && oAd.NamespaceAd.Caps.DirectReads {
+				return []server_structs.ServerAd{oAd.ServerAd}, true
+			}
+		}
+		return nil, false
+	}
+
+	chosen = make([]server_structs.ServerAd, 0, len(cAds))
+	for _, ad := range cAds {
+		chosen = append(chosen, ad.ServerAd)
+	}
+	return chosen, false
+}
+
+// Choose the servers the origin endpoint redirects to: up to serverResLimit origins,
+// preceded by caches when the request requires cache chaining.
+func selectOriginRedirectServers(ginCtx *gin.Context, oAds, cAds []copyAd) []server_structs.ServerAd {
+	chosenServers := make([]server_structs.ServerAd, 0, serverResLimit)
+	for idx, ad := range oAds {
+		if idx >= serverResLimit {
+			break
+		}
+		chosenServers = append(chosenServers, ad.ServerAd)
+	}
+
+	if requiresCacheChaining(ginCtx, chosenServers) {
+		chosenCaches := make([]server_structs.ServerAd, 0, serverResLimit-len(oAds))
+		for i, ad := range cAds {
+			if i+len(oAds) < serverResLimit {
+				chosenCaches = append(chosenCaches, ad.ServerAd)
+			} else {
+				break
+			}
+		}
+
+		chosenServers = append(chosenCaches, chosenServers...)
+	}
+	return chosenServers
+}
+
 func redirectToCache(ginCtx *gin.Context) {
 	// Later we'll collect metrics for which service we sent the user to. For now, assume
 	// we're sending them to a cache.
@@ -856,6 +905,8 @@ func redirectToCache(ginCtx *gin.Context) {
 	// Assign this request an ID (which may come from the client) so we can use it to track
 	// the request through the Director.
 	requestId := getRequestID(ginCtx)
+	span := tracing.StartRequest(ginCtx, "director.redirectToCache", tracing.RequestID(requestId.String()))
+	defer tracing.EndRequest(ginCtx, span)
 
 	// Make sure the user hasn't asked us to do anything too goofy
 	if err := validateIncomingRequest(ginCtx); err != nil {
@@ -875,38 +926,23 @@ func redirectToCache(ginCtx *gin.Context) {
 		return
 	}
 
-	// If the namespace prefix is exported by at least one functioning origin but there are no supporting
-	// caches, fallback to the origin if able.
-	if len(cAds) == 0 {
-		for _, oAd := range oAds {
-			// Find the first origin that enables direct reads as the fallback
-			if oAd.ServerAd.Caps.DirectReads && oAd.NamespaceAd.Caps.DirectReads {
-				cAds = append(cAds, oAd)
-				break
-			}
-		}
-		if len(cAds) == 0 {
-			msg := "No caches can fulfill this request and no fallback origins with the 'DirectReads' capability found for this object. Request ID: " + requestId.String()
-			log.Debugln(msg)
-			ginCtx.JSON(http.StatusNotFound, server_structs.SimpleApiResp{
-				Status: server_structs.RespFailed,
-				Msg:    msg,
-			})
-			return
-		}
-
-		// At this point, the cacheAds is full of originAds
+	chosenServers, fellBackToOrigin := selectCacheRedirectServers(oAds, cAds)
+	if len(chosenServers) == 0 {
+		msg := "No caches can fulfill this request and no fallback origins with the 'DirectReads' capability found for this object. Request ID: " + requestId.String()
+		log.Debugln(msg)
+		ginCtx.JSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    msg,
+		})
+		return
+	}
+	if fellBackToOrigin {
 		// We need to indicate that we are redirecting to an origin and not a cache
 		// This is for the purpose of metrics
 		// See collectDirectorRedirectionMetric
 		chosenService = "origin"
 	}
 
-	chosenServers := make([]server_structs.ServerAd, 0, len(cAds))
-	for _, ad := range cAds {
-		chosenServers = append(chosenServers, ad.ServerAd)
-	}
-
 	oServers := make([]server_structs.ServerAd, 0, len(oAds))
 	for _, ad := range oAds {
 		oServers = append(oServers, ad.ServerAd)
@@ -935,6 +971,8 @@ func redirectToOrigin(ginCtx *gin.Context) {
 	// Assign this request an ID (which may come from the client) so we can use it to track
 	// the request through the Director.
 	requestId := getRequestID(ginCtx)
+	span := tracing.StartRequest(ginCtx, "director.redirectToOrigin", tracing.RequestID(requestId.String()))
+	defer tracing.EndRequest(ginCtx, span)
 
 	// Make sure the user hasn't asked us to do anything too goofy
 	if err := validateIncomingRequest(ginCtx); err != nil {
@@ -963,26 +1001,7 @@ func redirectToOrigin(ginCtx *gin.Context) {
 		return
 	}
 
-	chosenServers := make([]server_structs.ServerAd, 0, serverResLimit)
-	for idx, ad := range oAds {
-		if idx >= serverResLimit {
-			break
-		}
-		chosenServers = append(chosenServers, ad.ServerAd)
-	}
-
-	if requiresCacheChaining(ginCtx, chosenServers) {
-		chosenCaches := make([]server_structs.ServerAd, 0, serverResLimit-len(oAds))
-		for i, ad := range cAds {
-			if i+len(oAds) < serverResLimit {
-				chosenCaches = append(chosenCaches, ad.ServerAd)
-			} else {
-				break
-			}
-		}
-
-		chosenServers = append(chosenCaches, chosenServers...)
-	}
+	chosenServers := selectOriginRedirectServers(ginCtx, oAds, cAds)
 
 	oServers := make([]server_structs.ServerAd, 0, len(oAds))
 	for _, ad := range oAds {
@@ -1829,6 +1848,9 @@ func RegisterDirectorAPI(ctx context.Context, router *gin.RouterGroup) {
 		// Rename the endpoint to reflect such plan.
 		directorAPIV1.GET("/discoverServers", discoverOriginCache)
 
+		// Admin-only report of how the director would handle a request for an object
+		directorAPIV1.GET("/explain/*any", web_ui.AuthHandler, web_ui.AdminAuthHandler, explainRedirect)
+
 	}
 
 	directorAPIV2 := router.Group("/api/v2.0/director", web_ui.ServerHeaderMiddleware)
@@ -0,0 +1,356 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jellydator/ttlcache/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/utils"
+)
+
+type (
+	// A single server ad considered by the director while explaining a redirect
+	explainCandidate struct {
+		Name      string `json:"name"`
+		URL       string `json:"url"`
+		Type      string `json:"type"`
+		Namespace string `json:"namespace,omitempty"`
+		// Whether the ad survived filtering and was handed to the sort
+		Eligible bool `json:"eligible"`
+		// The filters that removed the ad from consideration, e.g. "cacheNotInErrorState"
+		RemovedBy []string `json:"removedBy,omitempty"`
+		// Set for caches that may or may not support the features required by the origins;
+		// these are placed after all other caches
+		FeatureSupportUnknown bool `json:"featureSupportUnknown,omitempty"`
+		// 1-based position in the final redirect order; 0 if the server is not redirected to
+		Rank       int                             `json:"rank"`
+		Coordinate *server_structs.Coordinate      `json:"coordinate,omitempty"`
+		Weights    *server_structs.RedirectWeights `json:"weights,omitempty"`
+	}
+
+	explainOrderEntry struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	}
+
+	explainTokenCheck struct {
+		Present bool   `json:"present"`
+		Status  int    `json:"status"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	// The response of the explain API
+	explainResponse struct {
+		RequestId  string                            `json:"requestId"`
+		Path       string                            `json:"path"`
+		Service    string                            `json:"service"`
+		Verb       string                            `json:"verb"`
+		Query      string                            `json:"query,omitempty"`
+		SortMethod string                            `json:"sortMethod,omitempty"`
+		Client     server_structs.ClientRedirectInfo `json:"client"`
+		Candidates []explainCandidate                `json:"candidates"`
+		Order      []explainOrderEntry               `json:"order"`
+		TokenCheck explainTokenCheck                 `json:"tokenCheck"`
+		// Set if the real request would fail; the message mirrors what the client would see
+		Error string `json:"error,omitempty"`
+	}
+)
+
+const (
+	// Header carrying the bearer token of the request being explained.  A separate header is
+	// used because the Authorization header authenticates the admin calling the API.
+	explainTokenHeader = "X-Pelican-Explain-Token"
+
+	// Reasons reported for ads removed by steps other than the named predicates
+	explainNoNamespace  = "noMatchingNamespace"
+	explainSuperseded   = "supersededByNamespace"
+	explainNotAvailable = "objectNotAvailable"
+	explainNotSorted    = "notSelectedBySort"
+)
+
+// Build the request the director would have received from the client being explained.
+// The request is attached to a standalone gin context so that the redirect pipeline can
+// run without touching the admin's request or response.
+func buildExplainContext(ginCtx *gin.Context, service, verb, objectPath string, query url.Values, clientIP netip.Addr) *gin.Context {
+	endpoint := "/api/v1.0/director/object"
+	if service == "origin" {
+		endpoint = "/api/v1.0/director/origin"
+	}
+
+	req := ginCtx.Request.Clone(ginCtx.Request.Context())
+	req.Method = verb
+	req.URL = &url.URL{Path: endpoint + objectPath, RawQuery: query.Encode()}
+	req.RequestURI = req.URL.RequestURI()
+	req.Body = http.NoBody
+	req.ContentLength = 0
+	req.RemoteAddr = net.JoinHostPort(clientIP.String(), "0")
+	req.Header = ginCtx.Request.Header.Clone()
+	for _, hdr := range []string{"Authorization", "Cookie", "X-Forwarded-For", "X-Real-Ip", explainTokenHeader} {
+		req.Header.Del(hdr)
+	}
+	if tok := ginCtx.GetHeader(explainTokenHeader); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimPrefix(tok, "Bearer "))
+	}
+	req.Header.Set("X-Pelican-Debug", "true")
+
+	synth, _ := gin.CreateTestContext(httptest.NewRecorder())
+	synth.Request = req
+	return synth
+}
+
+// Collect every known server ad, explaining why those not matched to the request
+// path by getAdsForPath were discarded.
+func explainPathCandidates(reqPath string, oAds, cAds []copyAd) (candidates []explainCandidate, index map[string]int) {
+	selected := make(map[string]bool, len(oAds)+len(cAds))
+	for _, ad := range append(slices.Clone(oAds), cAds...) {
+		selected[ad.ServerAd.URL.String()] = true
+	}
+
+	ads := make([]*server_structs.Advertisement, 0, serverAds.Len())
+	serverAds.Range(func(item *ttlcache.Item[string, *server_structs.Advertisement]) bool {
+		ads = append(ads, item.Value())
+		return true
+	})
+	slices.SortFunc(ads, func(a, b *server_structs.Advertisement) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	index = make(map[string]int, len(ads))
+	for _, ad := range ads {
+		candidate := explainCandidate{Name: ad.Name, URL: ad.URL.String(), Type: ad.Type}
+		if nsAd := getLongestNSMatch(path.Clean(reqPath)+"/", ad.NamespaceAds); nsAd != nil {
+			candidate.Namespace = strings.TrimSuffix(nsAd.Path, "/")
+		}
+		if filtered, fType := checkFilter(ad.Name); filtered {
+			candidate.RemovedBy = append(candidate.RemovedBy, string(fType))
+		} else if candidate.Namespace == "" {
+			candidate.RemovedBy = append(candidate.RemovedBy, explainNoNamespace)
+		} else if !selected[candidate.URL] {
+			// A server with a more specific namespace (or a Pelican server replacing
+			// a topology one) was preferred
+			candidate.RemovedBy = append(candidate.RemovedBy, explainSuperseded)
+		}
+		index[candidate.URL] = len(candidates)
+		candidates = append(candidates, candidate)
+	}
+	return
+}
+
+// Record the names of the predicates that reject the ad
+func failedPredicates(ctx *gin.Context, ad copyAd, preds []namedAdPredicate) (failed []string) {
+	for _, pred := range preds {
+		if !pred.pred(ctx, ad) {
+			failed = append(failed, pred.name)
+		}
+	}
+	return
+}
+
+// Report, for a request the director would receive, which servers were considered, which
+// filters removed them, the sort weights of the remaining servers and the final redirect
+// order.  The simulated request is described by the query parameters:
+//
+//   - service: "cache" (default) or "origin", selecting the object or origin endpoint
+//   - verb: the HTTP method of the request, defaults to GET
+//   - clientIP: the client address to resolve with GeoIP, defaults to the caller's address
+//   - query: the raw query string of the request, e.g. "directread"
+//
+// The client's token may be supplied via the X-Pelican-Explain-Token header.
+func explainRedirect(ginCtx *gin.Context) {
+	objectPath := path.Clean("/" + ginCtx.Param("any"))
+	service := ginCtx.DefaultQuery("service", "cache")
+	verb := strings.ToUpper(ginCtx.DefaultQuery("verb", http.MethodGet))
+
+	badRequest := func(msg string) {
+		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    msg,
+		})
+	}
+	switch service {
+	case "cache":
+		if verb != http.MethodGet && verb != http.MethodHead {
+			badRequest(fmt.Sprintf("Verb %s is not supported by the cache endpoint", verb))
+			return
+		}
+	case "origin":
+		if !slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, "PROPFIND"}, verb) {
+			badRequest(fmt.Sprintf("Verb %s is not supported by the origin endpoint", verb))
+			return
+		}
+	default:
+		badRequest(fmt.Sprintf("Unknown service %q; must be 'cache' or 'origin'", service))
+		return
+	}
+
+	clientIP := utils.ClientIPAddr(ginCtx)
+	if ipStr := ginCtx.Query("clientIP"); ipStr != "" {
+		addr, err := netip.ParseAddr(ipStr)
+		if err != nil {
+			badRequest(fmt.Sprintf("Invalid clientIP %q: %v", ipStr, err))
+			return
+		}
+		clientIP = addr
+	}
+	query, err := url.ParseQuery(ginCtx.Query("query"))
+	if err != nil {
+		badRequest(fmt.Sprintf("Invalid query %q: %v", ginCtx.Query("query"), err))
+		return
+	}
+
+	synth := buildExplainContext(ginCtx, service, verb, objectPath, query, clientIP)
+	requestId := uuid.New()
+	resp := explainResponse{
+		RequestId:  requestId.String(),
+		Path:       objectPath,
+		Service:    service,
+		Verb:       verb,
+		Query:      synth.Request.URL.RawQuery,
+		Client:     server_structs.ClientRedirectInfo{IpAddr: clientIP.String()},
+		Candidates: []explainCandidate{},
+		Order:      []explainOrderEntry{},
+	}
+	log.Debugf("Explaining %s request for %s from %s to the %s endpoint (Request ID: %s)", verb, objectPath, clientIP, service, requestId)
+
+	if err := validateIncomingRequest(synth); err != nil {
+		resp.Error = fmt.Sprintf("Failed to validate incoming request: %v", err)
+		ginCtx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	// Walk the same filtering steps as getSortedAds, but evaluate every predicate so that
+	// all the reasons an ad was rejected are reported.
+	oAds, cAds := getAdsForPath(objectPath)
+	var candidateIdx map[string]int
+	resp.Candidates, candidateIdx = explainPathCandidates(objectPath, oAds, cAds)
+	var eligibleOrigins []copyAd
+	for _, ad := range oAds {
+		failed := failedPredicates(synth, ad, originPredicates(verb))
+		if len(failed) == 0 {
+			eligibleOrigins = append(eligibleOrigins, ad)
+		}
+		if idx, ok := candidateIdx[ad.ServerAd.URL.String()]; ok {
+			resp.Candidates[idx].RemovedBy = failed
+			resp.Candidates[idx].Eligible = len(failed) == 0
+		}
+	}
+	common, supported, unknown := cachePredicates(computeFeaturesUnion(eligibleOrigins))
+	for _, ad := range cAds {
+		idx, ok := candidateIdx[ad.ServerAd.URL.String()]
+		if !ok {
+			continue
+		}
+		candidate := &resp.Candidates[idx]
+		candidate.RemovedBy = failedPredicates(synth, ad, common)
+		if failed := failedPredicates(synth, ad, supported); len(failed) > 0 {
+			if len(failedPredicates(synth, ad, unknown)) == 0 {
+				candidate.FeatureSupportUnknown = true
+			} else {
+				candidate.RemovedBy = append(candidate.RemovedBy, failed...)
+			}
+		}
+		candidate.Eligible = len(candidate.RemovedBy) == 0
+	}
+
+	// Run the real pipeline to get the sort results
+	sortedOrigins, sortedCaches, err := getSortedAds(synth, requestId)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	if val, exists := synth.Get("redirectInfo"); exists {
+		if redirectInfo, ok := val.(*server_structs.RedirectInfo); ok {
+			resp.Client = redirectInfo.ClientInfo
+			resp.SortMethod = redirectInfo.DirectorSortMethod
+			for serverURL, serverInfo := range redirectInfo.ServersInfo {
+				if idx, ok := candidateIdx[serverURL]; ok {
+					resp.Candidates[idx].Coordinate = &serverInfo.Coordinate
+					resp.Candidates[idx].Weights = &serverInfo.RedirectWeights
+				}
+			}
+		}
+	}
+
+	if err == nil {
+		// Eligible servers that the sort dropped, e.g. origins that don't have the object
+		// or caches truncated from the sort's working set
+		sortedURLs := make(map[string]bool, len(sortedOrigins)+len(sortedCaches))
+		for _, ad := range append(slices.Clone(sortedOrigins), sortedCaches...) {
+			sortedURLs[ad.ServerAd.URL.String()] = true
+		}
+		for idx := range resp.Candidates {
+			candidate := &resp.Candidates[idx]
+			if !candidate.Eligible || sortedURLs[candidate.URL] {
+				continue
+			}
+			if candidate.Type == server_structs.OriginType.String() {
+				candidate.RemovedBy = append(candidate.RemovedBy, explainNotAvailable)
+			} else if isCacheRequest(synth) {
+				candidate.RemovedBy = append(candidate.RemovedBy, explainNotSorted)
+			} else {
+				continue
+			}
+			candidate.Eligible = false
+		}
+
+		var chosen []server_structs.ServerAd
+		if service == "cache" {
+			chosen, _ = selectCacheRedirectServers(sortedOrigins, sortedCaches)
+			if len(chosen) == 0 {
+				resp.Error = "No caches can fulfill this request and no fallback origins with the 'DirectReads' capability found for this object"
+			}
+		} else {
+			chosen = selectOriginRedirectServers(synth, sortedOrigins, sortedCaches)
+		}
+		for rank, ad := range chosen {
+			resp.Order = append(resp.Order, explainOrderEntry{Name: ad.Name, URL: ad.URL.String(), Type: ad.Type})
+			if idx, ok := candidateIdx[ad.URL.String()]; ok {
+				resp.Candidates[idx].Rank = rank + 1
+			}
+		}
+	}
+
+	resp.TokenCheck.Present = synth.GetHeader("Authorization") != "" || synth.Query("authz") != ""
+	resp.TokenCheck.Status, err = validateClientToken(synth, requestId)
+	if err != nil {
+		resp.TokenCheck.Error = err.Error()
+		if resp.Error == "" {
+			resp.Error = err.Error()
+		}
+	}
+
+	ginCtx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,152 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestExplainRedirect(t *testing.T) {
+	setGinTestMode()
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	setupOverrideCache(t) // will map 192.168.1.4 --> Discovery building's lat/long
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.Director_CacheSortMethod.Set(string(server_structs.DistanceType)))
+	require.NoError(t, param.Director_FilterCachesInErrorState.Set(true))
+	require.NoError(t, param.Director_CheckOriginPresence.Set(false))
+
+	serverAds.DeleteAll()
+	t.Cleanup(serverAds.DeleteAll)
+	filteredServersMutex.Lock()
+	filteredServers = map[string]filterType{"Boise": tempFiltered}
+	filteredServersMutex.Unlock()
+	t.Cleanup(func() {
+		filteredServersMutex.Lock()
+		filteredServers = map[string]filterType{}
+		filteredServersMutex.Unlock()
+	})
+
+	nsAds := []server_structs.NamespaceAdV2{{
+		Path: "/foo",
+		Caps: server_structs.Capabilities{PublicReads: true, Reads: true, DirectReads: true},
+	}}
+	addAd := func(ad server_structs.ServerAd, sType server_structs.ServerType, ns []server_structs.NamespaceAdV2) {
+		ad.Type = sType.String()
+		ad.URL.Scheme = "https"
+		ad.Caps = server_structs.Capabilities{PublicReads: true, Reads: true, DirectReads: true}
+		serverAds.Set(ad.URL.String(), &server_structs.Advertisement{ServerAd: ad, NamespaceAds: ns}, ttlcache.DefaultTTL)
+	}
+	addAd(getAdBase("Origin", 43.0, -89.0), server_structs.OriginType, nsAds)
+	addAd(getAdBase("Chicago", 41.8781, -87.6298), server_structs.CacheType, nsAds)
+	addAd(getAdBase("LA", 34.0522, -118.2437), server_structs.CacheType, nsAds)
+	addAd(getAdBase("Boise", 43.6150, -116.2023), server_structs.CacheType, nsAds)
+	errored := getAdBase("Madison", 43.0731, -89.4012)
+	errored.Status = metrics.StatusCritical.String()
+	addAd(errored, server_structs.CacheType, nsAds)
+	addAd(getAdBase("Other", 40.7128, -74.0060), server_structs.CacheType, []server_structs.NamespaceAdV2{{Path: "/bar"}})
+
+	explain := func(t *testing.T, query url.Values) (int, explainResponse) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1.0/director/explain/foo/obj?"+query.Encode(), nil)
+		c.Params = gin.Params{{Key: "any", Value: "/foo/obj"}}
+		explainRedirect(c)
+
+		var resp explainResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		code, resp := explain(t, url.Values{"clientIP": {"192.168.1.4"}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp.Error)
+		assert.Equal(t, "192.168.1.4", resp.Client.IpAddr)
+		assert.Equal(t, server_structs.CoordinateSourceOverride, string(resp.Client.Coordinate.Source))
+		assert.Equal(t, string(server_structs.DistanceType), resp.SortMethod)
+
+		candidates := make(map[string]explainCandidate, len(resp.Candidates))
+		for _, candidate := range resp.Candidates {
+			candidates[candidate.Name] = candidate
+		}
+		require.Len(t, candidates, 6)
+		assert.Equal(t, []string{string(tempFiltered)}, candidates["Boise"].RemovedBy)
+		assert.Equal(t, []string{"cacheNotInErrorState"}, candidates["Madison"].RemovedBy)
+		assert.Equal(t, []string{explainNoNamespace}, candidates["Other"].RemovedBy)
+		assert.False(t, candidates["Madison"].Eligible)
+
+		require.True(t, candidates["Chicago"].Eligible)
+		assert.Equal(t, 1, candidates["Chicago"].Rank)
+		assert.Equal(t, 2, candidates["LA"].Rank)
+		assert.Equal(t, 0, candidates["Origin"].Rank)
+		require.NotNil(t, candidates["Chicago"].Weights)
+		assert.Greater(t, candidates["Chicago"].Weights.DistanceWeight, candidates["LA"].Weights.DistanceWeight)
+
+		require.Len(t, resp.Order, 2)
+		assert.Equal(t, "Chicago", resp.Order[0].Name)
+		assert.Equal(t, "LA", resp.Order[1].Name)
+		assert.False(t, resp.TokenCheck.Present)
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		code, resp := explain(t, url.Values{"service": {"origin"}, "verb": {"PUT"}, "clientIP": {"192.168.1.4"}})
+		require.Equal(t, http.StatusOK, code)
+		// The origin doesn't advertise writes, so the request would fail
+		assert.NotEmpty(t, resp.Error)
+		assert.Empty(t, resp.Order)
+		for _, candidate := range resp.Candidates {
+			if candidate.Name == "Origin" {
+				assert.Equal(t, []string{"originSupportsVerb"}, candidate.RemovedBy)
+			}
+		}
+
+		code, resp = explain(t, url.Values{"service": {"origin"}, "clientIP": {"192.168.1.4"}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp.Error)
+		require.Len(t, resp.Order, 1)
+		assert.Equal(t, "Origin", resp.Order[0].Name)
+	})
+
+	t.Run("bad-input", func(t *testing.T) {
+		code, _ := explain(t, url.Values{"clientIP": {"not-an-ip"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = explain(t, url.Values{"verb": {"PUT"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = explain(t, url.Values{"service": {"registry"}})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
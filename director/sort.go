@@ -56,6 +56,8 @@ type (
 		NamespaceAd  server_structs.NamespaceAdV2
 		RequestId    uuid.UUID
 		IsOriginSort bool
+		// The object path being requested, used by PolicySort to match namespace preferences.
+		RequestPath string
 	}
 
 	// A function type for filtering ads -- given a request and an ad, it should
@@ -64,6 +66,12 @@ type (
 	// A collection of these are used during Director matchmaking to produce the list of
 	// caches/origins that can fulfill the request.
 	AdPredicate func(ctx *gin.Context, ad copyAd) bool
+
+	// An AdPredicate along with the name reported by the explain API when it rejects an ad
+	namedAdPredicate struct {
+		name string
+		pred AdPredicate
+	}
 )
 
 // Constants for director sorting algorithms
@@ -79,12 +87,13 @@ const (
 //   - adaptive:  sort serverAds based on rules discussed in these places:
 //   - https://github.com/PelicanPlatform/pelican/discussions/1198
 //   - https://docs.google.com/document/d/e/2PACX-1vQg9biPzp3RbC5qVuJFvgMZHgIM-nw92JzjHkGl-h7djeNNXa68ckv2rAqtXEDYe8QvXL3oX0Fr0-bp/pub
+//   - policy: sort serverAds using the weighted terms and preference lists in Director.SortPolicyFile
 //
 // Note that if the client IP isn't overridden and MaxMind cannot resolve accurate coordinates for it, the client's
 // coordinate is randomly assigned within the contiguous US and cached for re-use. This means that distance-based sorts
 // will be effectively random the first time, but subsequent requests within a short time period will still likely
 // generate cache hits.
-func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Addr, ads []server_structs.ServerAd, nsAd server_structs.NamespaceAdV2, requestId uuid.UUID, isOriginSort bool, precomputedAvailMap map[string]bool, redirectInfo *server_structs.RedirectInfo) ([]server_structs.ServerAd, error) {
+func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Addr, ads []server_structs.ServerAd, nsAd server_structs.NamespaceAdV2, reqPath string, requestId uuid.UUID, isOriginSort bool, precomputedAvailMap map[string]bool, redirectInfo *server_structs.RedirectInfo) ([]server_structs.ServerAd, error) {
 	sortMethod := server_structs.SortType(param.Director_CacheSortMethod.GetString())
 	redirectInfo.DirectorSortMethod = sortMethod.String()
 	redirectInfo.ClientInfo.IpAddr = clientAddr.String()
@@ -98,6 +107,7 @@ func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Ad
 		NamespaceAd:     nsAd,
 		RequestId:       requestId,
 		IsOriginSort:    isOriginSort,
+		RequestPath:     reqPath,
 	}
 	var sortAlg SortAlgorithm
 	switch sortMethod {
@@ -109,6 +119,8 @@ func sortServerAds(ctx context.Context, ginCtx *gin.Context, clientAddr netip.Ad
 		sortAlg = &AdaptiveSort{}
 	case server_structs.RandomType:
 		sortAlg = &RandomSort{}
+	case server_structs.PolicyType:
+		sortAlg = &PolicySort{}
 	default:
 		// Never say never, but this should never get hit because we validate the value on Director startup.
 		// The only real way to get here is through writing bad unit tests.
@@ -447,6 +459,35 @@ func filterCaches(
 	return
 }
 
+// The predicates an origin must pass to serve a request with the given verb
+func originPredicates(reqVerb string) []namedAdPredicate {
+	return []namedAdPredicate{
+		{"originSupportsVerb", originSupportsVerb(reqVerb)},
+		{"originSupportsQuery", originSupportsQuery()},
+	}
+}
+
+// The cache predicates used by filterCaches, given the union of features required
+// by the origins that may fulfill the request.
+func cachePredicates(requiredFeatures map[string]features.Feature) (common, supported, unknown []namedAdPredicate) {
+	common = []namedAdPredicate{{"cacheNotFromTopoIfPubReads", cacheNotFromTopoIfPubReads()}}
+	if param.Director_FilterCachesInErrorState.GetBool() {
+		common = append(common, namedAdPredicate{"cacheNotInErrorState", cacheNotInErrorState()})
+	}
+	supported = []namedAdPredicate{{"cacheSupportsFeature", cacheSupportsFeature(requiredFeatures)}}
+	unknown = []namedAdPredicate{{"cacheMightSupportFeature", cacheMightSupportFeature(requiredFeatures)}}
+	return
+}
+
+// Strip the names from a list of predicates
+func predicateFuncs(preds []namedAdPredicate) []AdPredicate {
+	funcs := make([]AdPredicate, 0, len(preds))
+	for _, pred := range preds {
+		funcs = append(funcs, pred.pred)
+	}
+	return funcs
+}
+
 // Find and return a sorted list of all the origins/caches that may
 // be able to fulfill the request.
 func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedCaches []copyAd, err error) {
@@ -472,11 +513,7 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 	// Of the origins supporting the path, filter out those that don't support some other
 	// aspect of this request, e.g. trying to PUT to an origin/namespace that only supports
 	// GETs.
-	originPredicates := []AdPredicate{
-		originSupportsVerb(reqVerb),
-		originSupportsQuery(),
-	}
-	sortedOrigins = filterOrigins(ctx, originAds, originPredicates...)
+	sortedOrigins = filterOrigins(ctx, originAds, predicateFuncs(originPredicates(reqVerb))...)
 	if len(sortedOrigins) == 0 {
 		// Since caches are supposed to act on behalf of origins, the fact that there are no
 		// origins capable of supporting the request means we can fail early.
@@ -496,13 +533,8 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 	// 2. Supported predicates: if the cache passes the common predicate, we can mark whether we know it supports a feature.
 	// 3. Unknown predicates: if the cache passes the common predicate but we don't know if it supports a feature, we can
 	//    mark it as unknown.
-	commonPredicates := []AdPredicate{cacheNotFromTopoIfPubReads()}
-	if param.Director_FilterCachesInErrorState.GetBool() {
-		commonPredicates = append(commonPredicates, cacheNotInErrorState())
-	}
-	supportedPredicates := []AdPredicate{cacheSupportsFeature(requiredFeatures)}
-	unknownPredicates := []AdPredicate{cacheMightSupportFeature(requiredFeatures)}
-	sortedCaches, unknownCaches := filterCaches(ctx, cacheAds, commonPredicates, supportedPredicates, unknownPredicates)
+	commonPredicates, supportedPredicates, unknownPredicates := cachePredicates(requiredFeatures)
+	sortedCaches, unknownCaches := filterCaches(ctx, cacheAds, predicateFuncs(commonPredicates), predicateFuncs(supportedPredicates), predicateFuncs(unknownPredicates))
 
 	// Avoid sorting any slices we don't need to
 	shouldSortOrigins := isOriginRequest(ctx)
@@ -591,7 +623,7 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 		go func() {
 			defer wg.Done()
 
-			sortedServerAds, err := sortServerAds(pCtx, ctx, utils.ClientIPAddr(ctx), oServAds, nsAd, requestId, true, originAvailabilityMap, redirectInfo)
+			sortedServerAds, err := sortServerAds(pCtx, ctx, utils.ClientIPAddr(ctx), oServAds, nsAd, reqPath, requestId, true, originAvailabilityMap, redirectInfo)
 			if err != nil {
 				lastError = errors.Wrap(err, "failed to sort origins")
 				return
@@ -619,7 +651,7 @@ func getSortedAds(ctx *gin.Context, requestId uuid.UUID) (sortedOrigins, sortedC
 		go func() {
 			defer wg.Done()
 
-			sortedServerAds, err := sortServerAds(pCtx, ctx, utils.ClientIPAddr(ctx), cServAds, nsAd, requestId, false, nil, redirectInfo)
+			sortedServerAds, err := sortServerAds(pCtx, ctx, utils.ClientIPAddr(ctx), cServAds, nsAd, reqPath, requestId, false, nil, redirectInfo)
 			if err != nil {
 				lastError = errors.Wrap(err, "failed to sort caches")
 				return
@@ -447,27 +447,9 @@ func (as *AdaptiveSort) Sort(sAds []server_structs.ServerAd, sCtx SortContext) (
 	// Generate the availability map for just the working set. This is the key optimization:
 	// stat requests only go to the N closest servers after the distance-based truncation,
 	// rather than all servers that match the namespace.
-	var workingAvailMap map[string]bool
-	if sCtx.AvailabilityMap != nil {
-		// Override provided (e.g., in tests) — use directly without issuing stat queries.
-		workingAvailMap = sCtx.AvailabilityMap
-	} else if sCtx.GinCtx != nil {
-		var genErr error
-		if sCtx.IsOriginSort {
-			workingAvailMap, _, genErr = generateAvailabilityMaps(sCtx.GinCtx, workingSet, nil, sCtx.NamespaceAd, sCtx.RequestId)
-		} else {
-			_, workingAvailMap, genErr = generateAvailabilityMaps(sCtx.GinCtx, nil, workingSet, sCtx.NamespaceAd, sCtx.RequestId)
-		}
-		if genErr != nil {
-			if _, ok := genErr.(objectNotFoundErr); ok {
-				return nil, genErr
-			}
-			// Non-objectNotFound stat errors should not propagate to the client.
-			// Log and fall through with nil workingAvailMap (neutral weights).
-			log.Warningf("Request %s: Stat failed during sort, proceeding with neutral availability: %v",
-				sCtx.RequestId.String(), genErr)
-			workingAvailMap = nil
-		}
+	workingAvailMap, err := workingSetAvailability(sCtx, workingSet)
+	if err != nil {
+		return nil, err
 	}
 	// workingAvailMap == nil means no availability info; neutral weights will be used.
 
@@ -527,6 +509,36 @@ func (as *AdaptiveSort) Sort(sAds []server_structs.ServerAd, sCtx SortContext) (
 	return finalWeights.GetSortedAds(workingSet, smSortStochastic), nil
 }
 
+// Generate the availability map for a sort's working set, or use the override
+// provided in the SortContext.  Only an objectNotFoundErr is returned; other
+// stat failures are logged and yield a nil map so neutral weights are used.
+func workingSetAvailability(sCtx SortContext, workingSet []server_structs.ServerAd) (map[string]bool, error) {
+	if sCtx.AvailabilityMap != nil {
+		// Override provided (e.g., in tests) — use directly without issuing stat queries.
+		return sCtx.AvailabilityMap, nil
+	}
+	if sCtx.GinCtx == nil {
+		return nil, nil
+	}
+	var availMap map[string]bool
+	var genErr error
+	if sCtx.IsOriginSort {
+		availMap, _, genErr = generateAvailabilityMaps(sCtx.GinCtx, workingSet, nil, sCtx.NamespaceAd, sCtx.RequestId)
+	} else {
+		_, availMap, genErr = generateAvailabilityMaps(sCtx.GinCtx, nil, workingSet, sCtx.NamespaceAd, sCtx.RequestId)
+	}
+	if genErr != nil {
+		if _, ok := genErr.(objectNotFoundErr); ok {
+			return nil, genErr
+		}
+		// Non-objectNotFound stat errors should not propagate to the client.
+		log.Warningf("Request %s: Stat failed during sort, proceeding with neutral availability: %v",
+			sCtx.RequestId.String(), genErr)
+		return nil, nil
+	}
+	return availMap, nil
+}
+
 ///////////////////////////
 // OTHER MISC SORT STUFF //
 ///////////////////////////
@@ -0,0 +1,473 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"io"
+	"math"
+	"net/netip"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+type (
+	// Exponents applied to each term of the policy sort's multiplicative weight.
+	// A term with an exponent of 0 has no effect on the ordering.
+	SortPolicyTerms struct {
+		Distance     float64 `yaml:"Distance"`
+		IOLoad       float64 `yaml:"IOLoad"`
+		Status       float64 `yaml:"Status"`
+		Availability float64 `yaml:"Availability"`
+		SiteAffinity float64 `yaml:"SiteAffinity"`
+	}
+
+	// A client site.  Requests from any of ClientCIDRs favor the listed servers
+	// through the site affinity term.
+	SortPolicySite struct {
+		Name        string   `yaml:"Name"`
+		ClientCIDRs []string `yaml:"ClientCIDRs"`
+		Servers     []string `yaml:"Servers"`
+
+		prefixes []netip.Prefix
+	}
+
+	// An ordered list of servers that take precedence over all others for
+	// requests matching the namespace prefix and client networks.  An empty
+	// NamespacePrefix or ClientCIDRs matches any request.
+	SortPolicyPreference struct {
+		NamespacePrefix string   `yaml:"NamespacePrefix"`
+		ClientCIDRs     []string `yaml:"ClientCIDRs"`
+		Servers         []string `yaml:"Servers"`
+		Exclusive       bool     `yaml:"Exclusive"`
+
+		prefixes []netip.Prefix
+	}
+
+	// The contents of Director.SortPolicyFile
+	SortPolicy struct {
+		Terms              SortPolicyTerms        `yaml:"Terms"`
+		SiteAffinityFactor float64                `yaml:"SiteAffinityFactor"`
+		Deterministic      bool                   `yaml:"Deterministic"`
+		Sites              []SortPolicySite       `yaml:"Sites"`
+		Preferences        []SortPolicyPreference `yaml:"Preferences"`
+	}
+
+	// A sort driven by the operator-defined SortPolicy.  Servers named by the
+	// first matching preference list come first, in the listed order; the
+	// remainder are ordered by the product of the policy's weighted terms.
+	PolicySort struct{}
+)
+
+const defaultSiteAffinityFactor = 4.0
+
+var (
+	// The policy currently in effect; nil until one is loaded
+	sortPolicy atomic.Pointer[SortPolicy]
+
+	// Serializes reloads and remembers the file contents the current policy came from
+	sortPolicyMutex sync.Mutex
+	sortPolicyRaw   []byte
+)
+
+// Parse a list of IPs and CIDRs, turning bare IPs into single-host prefixes
+func parsePolicyCIDRs(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		if pfx, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, pfx.Masked())
+		} else if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = normalizeAddr(addr)
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		} else {
+			return nil, errors.Errorf("%q is neither an IP address nor a CIDR block", entry)
+		}
+	}
+	return prefixes, nil
+}
+
+// Parse and validate a sort policy.  Terms left out of the document default
+// to an exponent of 1.
+func parseSortPolicy(data []byte) (*SortPolicy, error) {
+	policy := &SortPolicy{
+		Terms:              SortPolicyTerms{Distance: 1, IOLoad: 1, Status: 1, Availability: 1, SiteAffinity: 1},
+		SiteAffinityFactor: defaultSiteAffinityFactor,
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Wrap(err, "failed to parse sort policy")
+	}
+
+	terms := map[string]float64{
+		"Distance":     policy.Terms.Distance,
+		"IOLoad":       policy.Terms.IOLoad,
+		"Status":       policy.Terms.Status,
+		"Availability": policy.Terms.Availability,
+		"SiteAffinity": policy.Terms.SiteAffinity,
+	}
+	for name, exp := range terms {
+		if exp < 0 || math.IsNaN(exp) || math.IsInf(exp, 0) {
+			return nil, errors.Errorf("exponent for term %s must be a non-negative number, got %v", name, exp)
+		}
+	}
+	if policy.SiteAffinityFactor < 1 {
+		return nil, errors.Errorf("SiteAffinityFactor must be at least 1, got %v", policy.SiteAffinityFactor)
+	}
+
+	for idx := range policy.Sites {
+		site := &policy.Sites[idx]
+		if len(site.ClientCIDRs) == 0 {
+			return nil, errors.Errorf("site %q must list at least one client CIDR", site.Name)
+		}
+		prefixes, err := parsePolicyCIDRs(site.ClientCIDRs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client network for site %q", site.Name)
+		}
+		site.prefixes = prefixes
+	}
+
+	for idx := range policy.Preferences {
+		pref := &policy.Preferences[idx]
+		if len(pref.Servers) == 0 {
+			return nil, errors.Errorf("preference %d must list at least one server", idx+1)
+		}
+		if pref.NamespacePrefix != "" {
+			if !strings.HasPrefix(pref.NamespacePrefix, "/") {
+				return nil, errors.Errorf("preference %d has namespace prefix %q, which must begin with '/'", idx+1, pref.NamespacePrefix)
+			}
+			pref.NamespacePrefix = path.Clean(pref.NamespacePrefix)
+		}
+		prefixes, err := parsePolicyCIDRs(pref.ClientCIDRs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client network for preference %d", idx+1)
+		}
+		pref.prefixes = prefixes
+	}
+	return policy, nil
+}
+
+// Load the sort policy from filename, replacing the one in effect.  If the
+// file is unchanged since the last load, nothing is done; if the new policy
+// is invalid, the previous one is kept and an error is returned.
+func loadSortPolicy(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return errors.Wrap(err, "failed to read director sort policy")
+	}
+
+	sortPolicyMutex.Lock()
+	defer sortPolicyMutex.Unlock()
+	if sortPolicy.Load() != nil && bytes.Equal(data, sortPolicyRaw) {
+		return nil
+	}
+	policy, err := parseSortPolicy(data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid director sort policy in %s", filename)
+	}
+	sortPolicy.Store(policy)
+	sortPolicyRaw = data
+	log.Infof("Loaded director sort policy from %s with %d site(s) and %d preference list(s)", filename, len(policy.Sites), len(policy.Preferences))
+	return nil
+}
+
+// Forget any loaded sort policy; used by tests
+func resetSortPolicy() {
+	sortPolicyMutex.Lock()
+	defer sortPolicyMutex.Unlock()
+	sortPolicy.Store(nil)
+	sortPolicyRaw = nil
+}
+
+// If the director is configured with the "policy" sort method, load
+// Director.SortPolicyFile and reload it whenever it changes.  A failure to
+// load the initial policy is returned; failures during later reloads are
+// logged and leave the previous policy in effect.
+func LaunchSortPolicyReload(ctx context.Context) error {
+	if server_structs.SortType(param.Director_CacheSortMethod.GetString()) != server_structs.PolicyType {
+		return nil
+	}
+	filename := param.Director_SortPolicyFile.GetString()
+	if err := loadSortPolicy(filename); err != nil {
+		return err
+	}
+	// Watch the parent directory so that editors which replace the file are noticed
+	server_utils.LaunchWatcherMaintenance(
+		ctx,
+		[]string{filepath.Dir(filename)},
+		"director sort policy reload",
+		time.Minute,
+		func(_ bool) error {
+			return loadSortPolicy(filename)
+		},
+	)
+	return nil
+}
+
+// Whether the client address falls in any of the prefixes.  An empty list
+// matches every client.
+func policyNetsContain(prefixes []netip.Prefix, addr netip.Addr) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	if !addr.IsValid() {
+		return false
+	}
+	addr = normalizeAddr(addr)
+	for _, pfx := range prefixes {
+		if pfx.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether a policy server identifier refers to the ad.  Servers may be
+// named by their advertised name, their URL, or their host (with or without port).
+func policyServerMatches(ad server_structs.ServerAd, id string) bool {
+	id = strings.TrimSuffix(id, "/")
+	return strings.EqualFold(id, ad.Name) ||
+		id == strings.TrimSuffix(ad.URL.String(), "/") ||
+		strings.EqualFold(id, ad.URL.Host) ||
+		strings.EqualFold(id, ad.URL.Hostname())
+}
+
+// Position (1-based) of the ad in the list of servers, or 0 if it is absent
+func policyServerRank(ad server_structs.ServerAd, servers []string) int {
+	for idx, id := range servers {
+		if policyServerMatches(ad, id) {
+			return idx + 1
+		}
+	}
+	return 0
+}
+
+// The first site containing the client, if any
+func (p *SortPolicy) siteFor(addr netip.Addr) *SortPolicySite {
+	for idx := range p.Sites {
+		if policyNetsContain(p.Sites[idx].prefixes, addr) {
+			return &p.Sites[idx]
+		}
+	}
+	return nil
+}
+
+// The first preference list matching the request, if any
+func (p *SortPolicy) preferenceFor(reqPath string, addr netip.Addr) *SortPolicyPreference {
+	reqPath = path.Clean("/" + reqPath)
+	for idx := range p.Preferences {
+		pref := &p.Preferences[idx]
+		if pref.NamespacePrefix != "" && pref.NamespacePrefix != "/" &&
+			reqPath != pref.NamespacePrefix && !strings.HasPrefix(reqPath, pref.NamespacePrefix+"/") {
+			continue
+		}
+		if !policyNetsContain(pref.prefixes, addr) {
+			continue
+		}
+		return pref
+	}
+	return nil
+}
+
+func (ps *PolicySort) Type() server_structs.SortType {
+	return server_structs.PolicyType
+}
+func (ps *PolicySort) String() string {
+	return string(server_structs.PolicyType)
+}
+func (ps *PolicySort) Sort(sAds []server_structs.ServerAd, sCtx SortContext) ([]server_structs.ServerAd, error) {
+	policy := sortPolicy.Load()
+	if policy == nil {
+		return nil, errors.New("no director sort policy has been loaded")
+	}
+	clientCoord := getClientCoordinate(sCtx.Ctx, sCtx.ClientAddr)
+	sCtx.RedirectInfo.ClientInfo.Coordinate = clientCoord
+
+	// Determine each server's preference rank, dropping unlisted servers from
+	// an exclusive list unless that would leave nothing to redirect to.
+	candidates := sAds
+	ranks := make([]int, len(sAds))
+	if pref := policy.preferenceFor(sCtx.RequestPath, sCtx.ClientAddr); pref != nil {
+		for idx, ad := range sAds {
+			ranks[idx] = policyServerRank(ad, pref.Servers)
+		}
+		if pref.Exclusive {
+			listed := make([]server_structs.ServerAd, 0, len(sAds))
+			listedRanks := make([]int, 0, len(sAds))
+			for idx, ad := range sAds {
+				if ranks[idx] > 0 {
+					listed = append(listed, ad)
+					listedRanks = append(listedRanks, ranks[idx])
+				}
+			}
+			if len(listed) > 0 {
+				candidates, ranks = listed, listedRanks
+			} else {
+				log.Warningf("Request %s: none of the servers in the exclusive sort policy preference for %s are available; considering all servers",
+					sCtx.RequestId.String(), sCtx.RequestPath)
+			}
+		}
+	}
+
+	weights := make([]*server_structs.RedirectWeights, len(candidates))
+	for idx := range candidates {
+		weights[idx] = &server_structs.RedirectWeights{
+			DistanceWeight:     1.0,
+			IOLoadWeight:       1.0,
+			StatusWeight:       1.0,
+			AvailabilityWeight: 1.0,
+			SiteAffinityWeight: 1.0,
+			PreferenceRank:     ranks[idx],
+		}
+	}
+	// Evaluate a term over the candidates (imputing medians for missing data)
+	// and record it, skipping terms the policy disables.
+	applyTerm := func(exp float64, ads []server_structs.ServerAd, idxMap []int,
+		weightFn func(int, server_structs.ServerAd) (float64, bool),
+		applyFn func(wStruct *server_structs.RedirectWeights, w float64),
+	) {
+		if exp == 0 {
+			return
+		}
+		for _, w := range computeWeights(ads, weightFn) {
+			applyFn(weights[idxMap[w.Index]], w.Weight)
+		}
+	}
+	allIdxs := make([]int, len(candidates))
+	for idx := range candidates {
+		allIdxs[idx] = idx
+	}
+
+	applyTerm(policy.Terms.Distance, candidates, allIdxs,
+		func(_ int, ad server_structs.ServerAd) (float64, bool) {
+			return distanceWeightFn(clientCoord.Lat, clientCoord.Long, ad.Latitude, ad.Longitude)
+		},
+		func(sw *server_structs.RedirectWeights, w float64) { sw.DistanceWeight = w },
+	)
+	applyTerm(policy.Terms.IOLoad, candidates, allIdxs,
+		func(_ int, ad server_structs.ServerAd) (float64, bool) {
+			return ioLoadWeightFn(ad.IOLoad)
+		},
+		func(sw *server_structs.RedirectWeights, w float64) { sw.IOLoadWeight = w },
+	)
+	applyTerm(policy.Terms.Status, candidates, allIdxs,
+		func(_ int, ad server_structs.ServerAd) (float64, bool) {
+			return statusWeightFn(ad.StatusWeight)
+		},
+		func(sw *server_structs.RedirectWeights, w float64) { sw.StatusWeight = w },
+	)
+	site := policy.siteFor(sCtx.ClientAddr)
+	applyTerm(policy.Terms.SiteAffinity, candidates, allIdxs,
+		func(_ int, ad server_structs.ServerAd) (float64, bool) {
+			if site != nil && policyServerRank(ad, site.Servers) > 0 {
+				return policy.SiteAffinityFactor, true
+			}
+			return 1.0, true
+		},
+		func(sw *server_structs.RedirectWeights, w float64) { sw.SiteAffinityWeight = w },
+	)
+
+	finalWeight := func(w *server_structs.RedirectWeights) float64 {
+		return math.Pow(w.DistanceWeight, policy.Terms.Distance) *
+			math.Pow(w.IOLoadWeight, policy.Terms.IOLoad) *
+			math.Pow(w.StatusWeight, policy.Terms.Status) *
+			math.Pow(w.AvailabilityWeight, policy.Terms.Availability) *
+			math.Pow(w.SiteAffinityWeight, policy.Terms.SiteAffinity)
+	}
+
+	// As with the adaptive sort, only query availability for a working set:
+	// every preferred server plus the best N of the rest by the other terms.
+	workingIdxs := allIdxs
+	if policy.Terms.Availability != 0 {
+		var unlisted []int
+		workingIdxs = make([]int, 0, len(candidates))
+		for idx := range candidates {
+			if ranks[idx] > 0 {
+				workingIdxs = append(workingIdxs, idx)
+			} else {
+				unlisted = append(unlisted, idx)
+			}
+		}
+		slices.SortStableFunc(unlisted, func(a, b int) int {
+			return cmp.Compare(finalWeight(weights[b]), finalWeight(weights[a]))
+		})
+		workingIdxs = append(workingIdxs, unlisted[:min(len(unlisted), param.Director_AdaptiveSortTruncateConstant.GetInt())]...)
+
+		workingSet := make([]server_structs.ServerAd, len(workingIdxs))
+		for idx, candIdx := range workingIdxs {
+			workingSet[idx] = candidates[candIdx]
+		}
+		availMap, err := workingSetAvailability(sCtx, workingSet)
+		if err != nil {
+			return nil, err
+		}
+		applyTerm(policy.Terms.Availability, workingSet, workingIdxs,
+			func(_ int, ad server_structs.ServerAd) (float64, bool) {
+				return availabilityWeightFn(ad, availMap, objAvailabilityFactor)
+			},
+			func(sw *server_structs.RedirectWeights, w float64) { sw.AvailabilityWeight = w },
+		)
+	}
+
+	// Split the working set into the preferred servers, ordered by rank, and
+	// the rest, ordered by their combined weight.
+	var preferredAds, otherAds []server_structs.ServerAd
+	var preferredWeights, otherWeights SwapMaps
+	sCtx.RedirectInfo.ServersInfo = make(map[string]*server_structs.ServerRedirectInfo)
+	for _, candIdx := range workingIdxs {
+		ad := candidates[candIdx]
+		w := weights[candIdx]
+		if rank := ranks[candIdx]; rank > 0 {
+			preferredWeights = append(preferredWeights, SwapMap{Weight: float64(len(candidates) - rank + 1), Index: len(preferredAds)})
+			preferredAds = append(preferredAds, ad)
+		} else {
+			otherWeights = append(otherWeights, SwapMap{Weight: finalWeight(w), Index: len(otherAds)})
+			otherAds = append(otherAds, ad)
+		}
+
+		// populate the RedirectInfo
+		thisServer := &server_structs.ServerRedirectInfo{}
+		thisServer.RedirectWeights = *w
+		thisServer.Coordinate = ad.Coordinate
+		sCtx.RedirectInfo.ServersInfo[ad.URL.String()] = thisServer
+	}
+
+	sortType := smSortStochastic
+	if policy.Deterministic {
+		sortType = smSortDescending
+	}
+	sorted := preferredWeights.GetSortedAds(preferredAds, smSortDescending)
+	return append(sorted, otherWeights.GetSortedAds(otherAds, sortType)...), nil
+}
@@ -0,0 +1,204 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package director
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/test_utils"
+)
+
+func TestParseSortPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		policy, err := parseSortPolicy([]byte("Terms:\n  IOLoad: 0\n"))
+		require.NoError(t, err)
+		assert.Equal(t, SortPolicyTerms{Distance: 1, IOLoad: 0, Status: 1, Availability: 1, SiteAffinity: 1}, policy.Terms)
+		assert.Equal(t, defaultSiteAffinityFactor, policy.SiteAffinityFactor)
+
+		policy, err = parseSortPolicy(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, policy.Terms.Distance)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"unknown field":     "Terms:\n  Distence: 1\n",
+			"negative exponent": "Terms:\n  Status: -1\n",
+			"small factor":      "SiteAffinityFactor: 0.5\n",
+			"bad cidr":          "Preferences:\n  - Servers: [a]\n    ClientCIDRs: [not-a-net]\n",
+			"no servers":        "Preferences:\n  - NamespacePrefix: /foo\n",
+			"relative prefix":   "Preferences:\n  - NamespacePrefix: foo\n    Servers: [a]\n",
+			"site without nets": "Sites:\n  - Name: a\n    Servers: [a]\n",
+		} {
+			_, err := parseSortPolicy([]byte(doc))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestSortPolicyPreferenceMatching(t *testing.T) {
+	policy, err := parseSortPolicy([]byte(`
+Preferences:
+  - NamespacePrefix: /campus/data
+    ClientCIDRs: ["10.1.0.0/16"]
+    Servers: [A]
+  - NamespacePrefix: /campus
+    Servers: [B]
+  - ClientCIDRs: ["10.2.3.4"]
+    Servers: [C]
+`))
+	require.NoError(t, err)
+
+	match := func(reqPath, addr string) string {
+		pref := policy.preferenceFor(reqPath, netip.MustParseAddr(addr))
+		if pref == nil {
+			return ""
+		}
+		return pref.Servers[0]
+	}
+	assert.Equal(t, "A", match("/campus/data/file", "10.1.2.3"))
+	assert.Equal(t, "A", match("/campus/data/file", "::ffff:10.1.2.3"))
+	assert.Equal(t, "B", match("/campus/data/file", "192.0.2.1"))
+	assert.Equal(t, "B", match("/campus", "192.0.2.1"))
+	assert.Equal(t, "C", match("/campusfoo/file", "10.2.3.4"))
+	assert.Equal(t, "", match("/other/file", "192.0.2.1"))
+}
+
+func TestPolicySort(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	setupOverrideCache(t) // will map 192.168.1.4 --> Discovery building's lat/long
+	t.Cleanup(resetSortPolicy)
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.Director_AdaptiveSortTruncateConstant.Set(6))
+
+	sAds := []server_structs.ServerAd{
+		getAdBase("LA", 34.0522, -118.2437),
+		getAdBase("Chicago", 41.8781, -87.6298),
+		getAdBase("NYC", 40.7128, -74.0060),
+	}
+	sortWith := func(t *testing.T, doc string, reqPath string) []string {
+		policy, err := parseSortPolicy([]byte(doc))
+		require.NoError(t, err)
+		sortPolicy.Store(policy)
+
+		sorted, err := (&PolicySort{}).Sort(sAds, SortContext{
+			ClientAddr:      netip.MustParseAddr("192.168.1.4"),
+			RedirectInfo:    &server_structs.RedirectInfo{},
+			AvailabilityMap: map[string]bool{},
+			RequestPath:     reqPath,
+		})
+		require.NoError(t, err)
+		names := make([]string, 0, len(sorted))
+		for _, ad := range sorted {
+			names = append(names, ad.Name)
+		}
+		return names
+	}
+
+	t.Run("no-policy", func(t *testing.T) {
+		resetSortPolicy()
+		_, err := (&PolicySort{}).Sort(sAds, SortContext{RedirectInfo: &server_structs.RedirectInfo{}})
+		assert.Error(t, err)
+	})
+
+	t.Run("weights-only", func(t *testing.T) {
+		assert.Equal(t, []string{"Chicago", "NYC", "LA"}, sortWith(t, "Deterministic: true\n", "/foo/bar"))
+	})
+
+	t.Run("preference-overrides-distance", func(t *testing.T) {
+		doc := `
+Deterministic: true
+Preferences:
+  - NamespacePrefix: /foo
+    ClientCIDRs: ["192.168.0.0/16"]
+    Servers: [LA, NYC]
+`
+		assert.Equal(t, []string{"LA", "NYC", "Chicago"}, sortWith(t, doc, "/foo/bar"))
+		assert.Equal(t, []string{"Chicago", "NYC", "LA"}, sortWith(t, doc, "/other"))
+	})
+
+	t.Run("exclusive", func(t *testing.T) {
+		doc := `
+Preferences:
+  - Servers: [LA]
+    Exclusive: true
+`
+		assert.Equal(t, []string{"LA"}, sortWith(t, doc, "/foo/bar"))
+
+		// If no listed server is available, all servers are considered
+		doc = `
+Deterministic: true
+Preferences:
+  - Servers: [Denver]
+    Exclusive: true
+`
+		assert.Equal(t, []string{"Chicago", "NYC", "LA"}, sortWith(t, doc, "/foo/bar"))
+	})
+
+	t.Run("site-affinity", func(t *testing.T) {
+		doc := `
+Deterministic: true
+SiteAffinityFactor: 1000
+Sites:
+  - Name: madison
+    ClientCIDRs: ["192.168.1.0/24"]
+    Servers: [LA]
+`
+		assert.Equal(t, []string{"LA", "Chicago", "NYC"}, sortWith(t, doc, "/foo/bar"))
+
+		// Disabling the term removes its effect
+		assert.Equal(t, []string{"Chicago", "NYC", "LA"}, sortWith(t, doc+"Terms:\n  SiteAffinity: 0\n", "/foo/bar"))
+	})
+}
+
+func TestLoadSortPolicy(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	t.Cleanup(resetSortPolicy)
+	resetSortPolicy()
+
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyFile, []byte("SiteAffinityFactor: 2\n"), 0644))
+	require.NoError(t, loadSortPolicy(policyFile))
+	first := sortPolicy.Load()
+	require.NotNil(t, first)
+	assert.Equal(t, 2.0, first.SiteAffinityFactor)
+
+	// Unchanged contents keep the same policy
+	require.NoError(t, loadSortPolicy(policyFile))
+	assert.Same(t, first, sortPolicy.Load())
+
+	// An invalid update is rejected and the previous policy remains
+	require.NoError(t, os.WriteFile(policyFile, []byte("SiteAffinityFactor: -2\n"), 0644))
+	assert.Error(t, loadSortPolicy(policyFile))
+	assert.Same(t, first, sortPolicy.Load())
+
+	require.NoError(t, os.WriteFile(policyFile, []byte("SiteAffinityFactor: 3\n"), 0644))
+	require.NoError(t, loadSortPolicy(policyFile))
+	assert.Equal(t, 3.0, sortPolicy.Load().SiteAffinityFactor)
+}
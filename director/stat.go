@@ -34,12 +34,15 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/pelican_url"
 	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/version"
 )
 
@@ -211,9 +214,12 @@ func NewObjectStat() *ObjectStat {
 }
 
 // Implementation of sending a HEAD request to an origin for an object
-func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, dataUrl url.URL, digest bool, token string, timeout time.Duration) (*objectMetadata, error) {
+func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, dataUrl url.URL, digest bool, token string, timeout time.Duration) (metadata *objectMetadata, err error) {
 	client := config.GetClient()
 	reqUrl := dataUrl.JoinPath(objectName)
+	ctx, span := tracing.StartClient(ctx, "director.sendHeadReq",
+		attribute.String("server.address", dataUrl.Host), attribute.Bool("pelican.digest", digest))
+	defer func() { tracing.End(span, err) }()
 	ctx, cancelFunc := context.WithTimeout(ctx, timeout)
 	defer cancelFunc()
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, reqUrl.String(), nil)
@@ -228,6 +234,7 @@ func (stat *ObjectStat) sendHeadReq(ctx context.Context, objectName string, data
 		req.Header.Set("Want-Digest", "crc32c")
 	}
 	req.Header.Set("User-Agent", "pelican-director/"+version.GetVersion())
+	tracing.Inject(ctx, req.Header)
 
 	res, err := client.Do(req)
 	if err != nil {
@@ -338,6 +345,16 @@ func (stat *ObjectStat) queryServersForObject(ctx context.Context, objectName st
 		option(&cfg)
 	}
 
+	ctx, span := tracing.Start(ctx, "director.queryServersForObject",
+		attribute.String("pelican.object", objectName), attribute.String("pelican.server_type", sType.String()))
+	defer func() {
+		span.SetAttributes(attribute.Int("pelican.stat.found", len(qResult.Objects)), attribute.String("pelican.stat.result", qResult.Msg))
+		if qResult.Status == queryFailed {
+			span.SetStatus(codes.Error, string(qResult.ErrorType))
+		}
+		span.End()
+	}()
+
 	ads := []server_structs.ServerAd{}
 
 	// Use the provided originAds and cacheAds if available
@@ -684,7 +701,9 @@ func generateAvailabilityMaps(ctx *gin.Context, origins, caches []server_structs
 		cAdsToQuery = nil
 	}
 
-	qr := q.Query(context.Background(), reqPath, st, 1, len(oAdsToQuery)+len(cAdsToQuery),
+	// The stat results are cached for other requests, so they shouldn't be
+	// cancelled if this client goes away; keep the request's trace, though.
+	qr := q.Query(context.WithoutCancel(ctx.Request.Context()), reqPath, st, 1, len(oAdsToQuery)+len(cAdsToQuery),
 		withOriginAds(oAdsToQuery), withCacheAds(cAdsToQuery), WithToken(reqParams.Get("authz")))
 
 	if qr.Status == queryFailed {
@@ -169,12 +169,12 @@ func TestPosixv2OriginListingHTTP(t *testing.T) {
 		},
 	}
 
-	err := origin_serve.InitializeHandlers(t.Context(), exports)
+	// Use an empty errgroup for testing
+	var egrp errgroup.Group
+	err := origin_serve.InitializeHandlers(t.Context(), &egrp, exports)
 	require.NoError(t, err)
 
 	// Initialize auth config (required by auth middleware)
-	// Use an empty errgroup for testing
-	var egrp errgroup.Group
 	err = origin_serve.InitAuthConfig(t.Context(), &egrp, exports)
 	require.NoError(t, err)
 
@@ -81,6 +81,9 @@ func (f Feature) GetCacheMax(version string) (string, error) {
 // featuresMap maps feature names to their corresponding Feature structs.
 var featuresMap = map[string]Feature{
 	"CacheAuthz": CacheAuthz,
+	"ChunkedUpload": ChunkedUpload,
+	"ThirdPartyCopy": ThirdPartyCopy,
+	"BrokerTunnel": BrokerTunnel,
 }
 
 // GetFeature retrieves a feature by its name.
@@ -106,3 +109,43 @@ var CacheAuthz = Feature{
 		},
 	},
 }
+
+var ChunkedUpload = Feature{
+	Name: "ChunkedUpload",
+	Origin: map[string]FeatureVersionInfo{
+		"v1.0.0": {
+			NotBeforePelican: "v7.25",
+			NotAfterPelican:  "",
+		},
+	},
+	Cache: map[string]FeatureVersionInfo{
+	},
+}
+
+var ThirdPartyCopy = Feature{
+	Name: "ThirdPartyCopy",
+	Origin: map[string]FeatureVersionInfo{
+		"v1.0.0": {
+			NotBeforePelican: "v7.25",
+			NotAfterPelican:  "",
+		},
+	},
+	Cache: map[string]FeatureVersionInfo{
+	},
+}
+
+var BrokerTunnel = Feature{
+	Name: "BrokerTunnel",
+	Origin: map[string]FeatureVersionInfo{
+		"v1.0.0": {
+			NotBeforePelican: "v7.26",
+			NotAfterPelican:  "",
+		},
+	},
+	Cache: map[string]FeatureVersionInfo{
+		"v1.0.0": {
+			NotBeforePelican: "v7.26",
+			NotAfterPelican:  "",
+		},
+	},
+}
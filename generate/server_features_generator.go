@@ -20,6 +20,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -51,9 +52,16 @@ func GenServerFeatures() {
 
 	decoder := yaml.NewDecoder(yamlFile)
 
-	var feature Feature
-	if err := decoder.Decode(&feature); err != nil {
-		panic(fmt.Errorf("document decode failed: %w", err))
+	// Each feature is its own YAML document
+	var features []Feature
+	for {
+		var feature Feature
+		if err := decoder.Decode(&feature); err == io.EOF {
+			break
+		} else if err != nil {
+			panic(fmt.Errorf("document decode failed: %w", err))
+		}
+		features = append(features, feature)
 	}
 
 	// Create the file to be generated
@@ -64,9 +72,9 @@ func GenServerFeatures() {
 	defer f.Close()
 
 	err = featuresTemplate.Execute(f, struct {
-		Feature Feature
+		Features []Feature
 	}{
-		Feature: feature,
+		Features: features,
 	})
 
 	if err != nil {
@@ -156,7 +164,9 @@ func (f Feature) GetCacheMax(version string) (string, error) {
 
 // featuresMap maps feature names to their corresponding Feature structs.
 var featuresMap = map[string]Feature{
-	"{{.Feature.Name}}": {{.Feature.Name}},
+	{{- range $feature := .Features }}
+	"{{$feature.Name}}": {{$feature.Name}},
+	{{- end }}
 }
 
 // GetFeature retrieves a feature by its name.
@@ -167,10 +177,12 @@ func GetFeature(name string) (Feature, error) {
 	return Feature{}, fmt.Errorf("feature '%s' not found", name)
 }
 
-var {{.Feature.Name}} = Feature{
-	Name: "{{.Feature.Name}}",
+{{- range $feature := .Features }}
+
+var {{$feature.Name}} = Feature{
+	Name: "{{$feature.Name}}",
 	Origin: map[string]FeatureVersionInfo{
-		{{- range $version := .Feature.Origin }}
+		{{- range $version := $feature.Origin }}
 		"{{$version.FeatureVersion}}": {
 			NotBeforePelican: "{{$version.NotBeforePelican}}",
 			NotAfterPelican:  "{{$version.NotAfterPelican}}",
@@ -178,7 +190,7 @@ var {{.Feature.Name}} = Feature{
 		{{- end }}
 	},
 	Cache: map[string]FeatureVersionInfo{
-		{{- range $version := .Feature.Cache }}
+		{{- range $version := $feature.Cache }}
 		"{{$version.FeatureVersion}}": {
 			NotBeforePelican: "{{$version.NotBeforePelican}}",
 			NotAfterPelican:  "{{$version.NotAfterPelican}}",
@@ -186,4 +198,5 @@ var {{.Feature.Name}} = Feature{
 		{{- end }}
 	},
 }
+{{- end }}
 `))
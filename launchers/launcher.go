@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -47,6 +48,7 @@ import (
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
 	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/web_ui"
 )
 
@@ -80,6 +82,21 @@ func LaunchModules(ctx context.Context, modules server_structs.ServerType) (serv
 		return
 	}
 
+	if err = tracing.Setup(ctx, tracingServiceName(modules)); err != nil {
+		err = errors.Wrap(err, "Failure when configuring tracing")
+		return
+	}
+	egrp.Go(func() error {
+		<-ctx.Done()
+		// Flush the spans recorded during shutdown
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracing.Shutdown(shutdownCtx); err != nil {
+			log.Warningln("Failed to flush trace spans:", err)
+		}
+		return nil
+	})
+
 	// After config is loaded, check if director should enable broker
 	if modules.IsEnabled(server_structs.DirectorType) && param.Director_EnableBroker.GetBool() {
 		modules.Set(server_structs.BrokerType)
@@ -429,6 +446,10 @@ func LaunchModules(ctx context.Context, modules server_structs.ServerType) (serv
 			log.Errorln("Failure when starting the local cache listener:", err)
 			return
 		}
+		if err = lc.LaunchTCPListener(ctx, egrp); err != nil {
+			log.Errorln("Failure when starting the local cache TCP listener:", err)
+			return
+		}
 
 	}
 
@@ -505,6 +526,19 @@ func LaunchModules(ctx context.Context, modules server_structs.ServerType) (serv
 	return
 }
 
+// The name under which the server's trace spans are recorded, such as
+// "pelican-origin" or "pelican-director-registry"
+func tracingServiceName(modules server_structs.ServerType) string {
+	name := "pelican"
+	for _, sType := range []server_structs.ServerType{server_structs.OriginType, server_structs.CacheType, server_structs.LocalCacheType,
+		server_structs.DirectorType, server_structs.RegistryType} {
+		if modules.IsEnabled(sType) {
+			name += "-" + strings.ToLower(sType.String())
+		}
+	}
+	return name
+}
+
 func handleGracefulShutdown(ctx context.Context, modules server_structs.ServerType, servers []server_structs.XRootDServer) {
 	if modules.IsEnabled(server_structs.OriginType) || modules.IsEnabled(server_structs.CacheType) {
 		log.Warnf("Waiting %s for in-flight transfers before shutting down", param.Xrootd_ShutdownTimeout.GetDuration().String())
@@ -56,7 +56,7 @@ func OriginServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group,
 
 	// Determine if we should use XRootD or native HTTP server
 	storageType := param.Origin_StorageType.GetString()
-	useXRootD := storageType != string(server_structs.OriginStoragePosixv2) && storageType != string(server_structs.OriginStorageSSH)
+	useXRootD := !server_utils.UsesNativeOriginBackend(server_structs.OriginStorageType(storageType))
 
 	if useXRootD {
 		metrics.SetComponentHealthStatus(metrics.OriginCache_XRootD, metrics.StatusWarning, "XRootD is initializing")
@@ -220,7 +220,7 @@ func OriginServeFinish(ctx context.Context, egrp *errgroup.Group, engine *gin.En
 
 	// Handle POSIXv2 and SSH-specific initialization now that the web server is running
 	storageType := param.Origin_StorageType.GetString()
-	useXRootD := storageType != string(server_structs.OriginStoragePosixv2) && storageType != string(server_structs.OriginStorageSSH)
+	useXRootD := !server_utils.UsesNativeOriginBackend(server_structs.OriginStorageType(storageType))
 	if !useXRootD {
 		// For SSH backend, initialize the SSH connection before setting up handlers
 		if storageType == string(server_structs.OriginStorageSSH) {
@@ -253,7 +253,7 @@ func OriginServeFinish(ctx context.Context, egrp *errgroup.Group, engine *gin.En
 			return errors.Wrap(err, "failed to initialize origin_serve auth config")
 		}
 
-		if err := origin_serve.InitializeHandlers(ctx, originExports); err != nil {
+		if err := origin_serve.InitializeHandlers(ctx, egrp, originExports); err != nil {
 			return errors.Wrap(err, "failed to initialize origin_serve handlers")
 		}
 
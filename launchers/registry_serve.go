@@ -75,6 +75,9 @@ func RegistryServe(ctx context.Context, engine *gin.Engine, egrp *errgroup.Group
 	// Launch registry prometheus metrics
 	registry.LaunchRegistryMetrics(ctx, egrp)
 
+	// Deliver namespace registration events to the configured webhooks
+	registry.LaunchNotifications(ctx, egrp)
+
 	egrp.Go(func() error {
 		<-ctx.Done()
 		return database.ShutdownDB()
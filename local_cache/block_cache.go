@@ -0,0 +1,540 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/units"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+type (
+	// The blocks of a partially-cached object present on disk.  The
+	// on-disk format (the .BLOCKS sentinel) is modeled on XRootD's cinfo
+	// files: a versioned little-endian header protected by a CRC32C
+	// checksum, followed by the block bitmap and its own checksum.
+	blockInfo struct {
+		BlockSize int64
+		FileSize  int64
+		bitmap    []byte
+	}
+
+	// A per-object lock serializing the block downloads of an object
+	blockLock struct {
+		mutex sync.Mutex
+		refs  int
+	}
+
+	// Writes a byte range download into its position of the data file;
+	// the data file is closed by the owner, not the transfer client.
+	blockWriter struct {
+		*io.OffsetWriter
+	}
+
+	// A reader for a range of a cached object, closing the object when done
+	rangeReader struct {
+		*io.SectionReader
+		fp *os.File
+	}
+)
+
+const (
+	blockInfoVersion = 1
+)
+
+var (
+	errRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+	crc32c = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func (bw blockWriter) Close() error {
+	return nil
+}
+
+func (rr *rangeReader) Close() error {
+	return rr.fp.Close()
+}
+
+// Parse LocalCache.BlockSize; a value without units is in bytes
+func getBlockSize() (int64, error) {
+	sizeStr := param.LocalCache_BlockSize.GetString()
+	blockSize, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		if blockSize, err = units.ParseStrictBytes(sizeStr); err != nil {
+			return 0, errors.Wrapf(err, "invalid value for %s", param.LocalCache_BlockSize.GetName())
+		}
+	}
+	if blockSize <= 0 {
+		return 0, errors.Errorf("%s must be positive", param.LocalCache_BlockSize.GetName())
+	}
+	return blockSize, nil
+}
+
+func newBlockInfo(fileSize, blockSize int64) *blockInfo {
+	bi := &blockInfo{BlockSize: blockSize, FileSize: fileSize}
+	bi.bitmap = make([]byte, (bi.numBlocks()+7)/8)
+	return bi
+}
+
+func (bi *blockInfo) numBlocks() int64 {
+	if bi.FileSize <= 0 {
+		return 0
+	}
+	return (bi.FileSize-1)/bi.BlockSize + 1
+}
+
+func (bi *blockInfo) has(block int64) bool {
+	return bi.bitmap[block/8]&(1<<(block%8)) != 0
+}
+
+func (bi *blockInfo) set(block int64) {
+	bi.bitmap[block/8] |= 1 << (block % 8)
+}
+
+// Whether every block of the object is present
+func (bi *blockInfo) complete() bool {
+	for block := int64(0); block < bi.numBlocks(); block++ {
+		if !bi.has(block) {
+			return false
+		}
+	}
+	return true
+}
+
+// The number of bytes of the object present on disk
+func (bi *blockInfo) cachedBytes() (size int64) {
+	for block := int64(0); block < bi.numBlocks(); block++ {
+		if bi.has(block) {
+			size += min(bi.BlockSize, bi.FileSize-block*bi.BlockSize)
+		}
+	}
+	return
+}
+
+// Whether every block present in other is also present in bi, for the
+// same object layout
+func (bi *blockInfo) covers(other *blockInfo) bool {
+	if bi.FileSize != other.FileSize || bi.BlockSize != other.BlockSize {
+		return false
+	}
+	for block := int64(0); block < other.numBlocks(); block++ {
+		if other.has(block) && !bi.has(block) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the runs of consecutive missing blocks between the first and last
+// blocks (inclusive), as [first, last] pairs
+func (bi *blockInfo) missingRuns(first, last int64) (runs [][2]int64) {
+	for block := first; block <= last; block++ {
+		if bi.has(block) {
+			continue
+		}
+		if len(runs) > 0 && runs[len(runs)-1][1] == block-1 {
+			runs[len(runs)-1][1] = block
+		} else {
+			runs = append(runs, [2]int64{block, block})
+		}
+	}
+	return
+}
+
+func (bi *blockInfo) Serialize() ([]byte, error) {
+	var header bytes.Buffer
+	for _, field := range []any{int32(blockInfoVersion), bi.BlockSize, bi.FileSize} {
+		if err := binary.Write(&header, binary.LittleEndian, field); err != nil {
+			return nil, errors.Wrap(err, "failed to serialize block info header")
+		}
+	}
+	var buf bytes.Buffer
+	buf.Write(header.Bytes())
+	if err := binary.Write(&buf, binary.LittleEndian, crc32.Checksum(header.Bytes(), crc32c)); err != nil {
+		return nil, errors.Wrap(err, "failed to serialize block info header checksum")
+	}
+	buf.Write(bi.bitmap)
+	if err := binary.Write(&buf, binary.LittleEndian, crc32.Checksum(bi.bitmap, crc32c)); err != nil {
+		return nil, errors.Wrap(err, "failed to serialize block bitmap checksum")
+	}
+	return buf.Bytes(), nil
+}
+
+func deserializeBlockInfo(data []byte) (*blockInfo, error) {
+	const headerSize = 4 + 8 + 8
+	if len(data) < headerSize+4+4 {
+		return nil, errors.New("block info is truncated")
+	}
+	reader := bytes.NewReader(data)
+	var version int32
+	bi := &blockInfo{}
+	for _, field := range []any{&version, &bi.BlockSize, &bi.FileSize} {
+		if err := binary.Read(reader, binary.LittleEndian, field); err != nil {
+			return nil, errors.Wrap(err, "failed to read block info header")
+		}
+	}
+	if version != blockInfoVersion {
+		return nil, errors.Errorf("unsupported block info version %d", version)
+	}
+	if binary.LittleEndian.Uint32(data[headerSize:]) != crc32.Checksum(data[:headerSize], crc32c) {
+		return nil, errors.New("block info header checksum mismatch")
+	}
+	if bi.BlockSize <= 0 || bi.FileSize < 0 {
+		return nil, errors.New("invalid block info header")
+	}
+	bitmap := data[headerSize+4 : len(data)-4]
+	if int64(len(bitmap)) != (bi.numBlocks()+7)/8 {
+		return nil, errors.New("block bitmap has the wrong length")
+	}
+	if binary.LittleEndian.Uint32(data[len(data)-4:]) != crc32.Checksum(bitmap, crc32c) {
+		return nil, errors.New("block bitmap checksum mismatch")
+	}
+	bi.bitmap = bytes.Clone(bitmap)
+	return bi, nil
+}
+
+func readBlockInfo(sentinelPath string) (*blockInfo, error) {
+	data, err := os.ReadFile(sentinelPath)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeBlockInfo(data)
+}
+
+// Atomically replace the .BLOCKS sentinel with the block info
+func writeBlockInfo(sentinelPath string, bi *blockInfo) error {
+	data, err := bi.Serialize()
+	if err != nil {
+		return err
+	}
+	fp, err := os.CreateTemp(filepath.Dir(sentinelPath), "."+filepath.Base(sentinelPath)+".")
+	if err != nil {
+		return errors.Wrap(err, "failed to create block info file")
+	}
+	defer os.Remove(fp.Name())
+	if _, err = fp.Write(data); err != nil {
+		fp.Close()
+		return errors.Wrap(err, "failed to write block info file")
+	}
+	if err = fp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write block info file")
+	}
+	return errors.Wrap(os.Rename(fp.Name(), sentinelPath), "failed to save block info file")
+}
+
+// Resolve a requested range against the object size.  A negative start
+// requests the last -start bytes of the object; a negative end (or one
+// past the end of the object) requests the remainder of the object.
+func resolveRange(start, end, size int64) (int64, int64, error) {
+	if start < 0 {
+		start = max(size+start, 0)
+		end = size - 1
+	} else if end < 0 || end >= size {
+		end = size - 1
+	}
+	if start >= size || start > end {
+		return 0, 0, errRangeNotSatisfiable
+	}
+	return start, end, nil
+}
+
+// Parse a HTTP Range header holding a single byte range into the start
+// and end offsets understood by GetRange; ok is false if the header is
+// not a single byte range (in which case the whole object should be served).
+func parseRangeHeader(header string) (start, end int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return
+	}
+	startStr, endStr, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return
+	}
+	var err error
+	if startStr == "" {
+		// A suffix range: the last N bytes
+		if start, err = strconv.ParseInt(endStr, 10, 64); err != nil || start <= 0 {
+			return 0, 0, false
+		}
+		return -start, -1, true
+	}
+	if start, err = strconv.ParseInt(startStr, 10, 64); err != nil || start < 0 {
+		return 0, 0, false
+	}
+	end = -1
+	if endStr != "" {
+		if end, err = strconv.ParseInt(endStr, 10, 64); err != nil || end < start {
+			return 0, 0, false
+		}
+	}
+	return start, end, true
+}
+
+// Acquire the block lock of an object, returning the function releasing it
+func (lc *LocalCache) lockBlocks(objectPath string) func() {
+	lc.blockMutex.Lock()
+	if lc.blockLocks == nil {
+		lc.blockLocks = make(map[string]*blockLock)
+	}
+	lock := lc.blockLocks[objectPath]
+	if lock == nil {
+		lock = &blockLock{}
+		lc.blockLocks[objectPath] = lock
+	}
+	lock.refs++
+	lc.blockMutex.Unlock()
+
+	lock.mutex.Lock()
+	return lc.unlockBlocks(objectPath, lock)
+}
+
+// Acquire the block lock of an object only if no one holds or awaits it;
+// ok is false if the lock is in use
+func (lc *LocalCache) tryLockBlocks(objectPath string) (unlock func(), ok bool) {
+	lc.blockMutex.Lock()
+	defer lc.blockMutex.Unlock()
+	if lc.blockLocks[objectPath] != nil {
+		return nil, false
+	}
+	if lc.blockLocks == nil {
+		lc.blockLocks = make(map[string]*blockLock)
+	}
+	lock := &blockLock{refs: 1}
+	lock.mutex.Lock()
+	lc.blockLocks[objectPath] = lock
+	return lc.unlockBlocks(objectPath, lock), true
+}
+
+// Return the function releasing a held block lock
+func (lc *LocalCache) unlockBlocks(objectPath string, lock *blockLock) func() {
+	return func() {
+		lock.mutex.Unlock()
+		lc.blockMutex.Lock()
+		defer lc.blockMutex.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(lc.blockLocks, objectPath)
+		}
+	}
+}
+
+// Dispatch the results of block downloads to the goroutines waiting on them
+func (lc *LocalCache) runBlockResults() error {
+	results := lc.blockTc.Results()
+	for {
+		select {
+		case <-lc.ctx.Done():
+			return nil
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+			lc.blockMutex.Lock()
+			waiter := lc.blockWaiters[result.ID()]
+			delete(lc.blockWaiters, result.ID())
+			lc.blockMutex.Unlock()
+			if waiter == nil {
+				log.Errorf("Block transfer results from job %s but no corresponding request known", result.ID())
+				continue
+			}
+			waiter <- result
+		}
+	}
+}
+
+// Create the empty data file of an object about to be cached block by block
+func createBlockData(localPath string) error {
+	if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		return errors.Wrap(err, "failed to create cache directory for object")
+	}
+	fp, err := os.OpenFile(localPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cache data file")
+	}
+	return fp.Close()
+}
+
+// Open the data file of a partially-cached object to write blocks into it,
+// checking that the blocks recorded in bi are still on disk.  The file is
+// never created here, so an object evicted since bi was read is not brought
+// back with zeros in place of the blocks bi claims.
+func openBlockData(localPath string, bi *blockInfo) (*os.File, error) {
+	fp, err := os.OpenFile(localPath, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open cache data file")
+	}
+	if bi.cachedBytes() > 0 {
+		onDisk, err := readBlockInfo(localPath + ".BLOCKS")
+		if err != nil || !onDisk.covers(bi) {
+			fp.Close()
+			return nil, errors.New("cached blocks of the object were removed")
+		}
+	}
+	return fp, nil
+}
+
+// Download the given run of blocks of an object into the data file and
+// record them in the block info
+func (lc *LocalCache) fetchBlocks(ctx context.Context, objectPath, token, localPath string, bi *blockInfo, first, last int64) error {
+	start := first * bi.BlockSize
+	end := min((last+1)*bi.BlockSize, bi.FileSize) - 1
+	log.Debugf("Downloading blocks %d-%d (bytes %d-%d) of %s", first, last, start, end, objectPath)
+
+	fp, err := openBlockData(localPath, bi)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	sourceURL := *lc.directorURL
+	sourceURL.Path = path.Join(sourceURL.Path, path.Clean(objectPath))
+	sourceURL.Scheme = "pelican"
+	tj, err := lc.blockTc.NewTransferJob(ctx, &sourceURL, "", false, false, client.WithToken(token),
+		client.WithByteRange(start, end), client.WithWriter(blockWriter{io.NewOffsetWriter(fp, start)}))
+	if err != nil {
+		return err
+	}
+	resultChan := make(chan client.TransferResults, 1)
+	lc.blockMutex.Lock()
+	lc.blockWaiters[tj.ID()] = resultChan
+	lc.blockMutex.Unlock()
+	defer func() {
+		lc.blockMutex.Lock()
+		delete(lc.blockWaiters, tj.ID())
+		lc.blockMutex.Unlock()
+	}()
+	if err = lc.blockTc.Submit(tj); err != nil {
+		return err
+	}
+
+	var result client.TransferResults
+	select {
+	case <-ctx.Done():
+		tj.Cancel()
+		return ctx.Err()
+	case result = <-resultChan:
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.TransferredBytes != end-start+1 {
+		return errors.Errorf("block download of %s returned %d bytes; expected %d", objectPath, result.TransferredBytes, end-start+1)
+	}
+	for block := first; block <= last; block++ {
+		bi.set(block)
+	}
+	return writeBlockInfo(localPath+".BLOCKS", bi)
+}
+
+// GetRange returns a reader for a byte range of the object, along with
+// the resolved range and the object size.  Only the blocks covering the
+// range that are not yet cached are downloaded; see resolveRange for the
+// interpretation of start and end.
+func (lc *LocalCache) GetRange(ctx context.Context, objectPath, token string, start, end int64) (reader io.ReadCloser, rangeStart, rangeEnd, size int64, err error) {
+	if !lc.ac.authorize(token_scopes.Wlcg_Storage_Read, objectPath, token) {
+		err = authorizationDenied
+		return
+	}
+
+	// Fully-cached objects are served directly
+	if fp := lc.getFromDisk(objectPath); fp != nil {
+		var finfo os.FileInfo
+		if finfo, err = fp.Stat(); err != nil {
+			fp.Close()
+			return
+		}
+		size = finfo.Size()
+		if rangeStart, rangeEnd, err = resolveRange(start, end, size); err != nil {
+			fp.Close()
+			return
+		}
+		lc.hitChan <- lruEntry{lastUse: time.Now(), path: objectPath, size: size}
+		reader = &rangeReader{io.NewSectionReader(fp, rangeStart, rangeEnd-rangeStart+1), fp}
+		return
+	}
+
+	unlock := lc.lockBlocks(objectPath)
+	defer unlock()
+
+	localPath := filepath.Join(lc.basePath, path.Clean(objectPath))
+	bi, biErr := readBlockInfo(localPath + ".BLOCKS")
+	if biErr != nil {
+		if !os.IsNotExist(biErr) {
+			log.Warningf("Discarding invalid block info for %s: %v", objectPath, biErr)
+		}
+		dUrl := *lc.directorURL
+		dUrl.Path = objectPath
+		dUrl.Scheme = "pelican"
+		var statInfo *client.FileInfo
+		if statInfo, err = client.DoStat(ctx, dUrl.String(), client.WithToken(token)); err != nil {
+			return
+		}
+		bi = newBlockInfo(statInfo.Size, lc.blockSize)
+		if err = createBlockData(localPath); err != nil {
+			return
+		}
+	}
+	size = bi.FileSize
+	if rangeStart, rangeEnd, err = resolveRange(start, end, size); err != nil {
+		return
+	}
+
+	for _, run := range bi.missingRuns(rangeStart/bi.BlockSize, rangeEnd/bi.BlockSize) {
+		if err = lc.fetchBlocks(ctx, objectPath, token, localPath, bi, run[0], run[1]); err != nil {
+			return
+		}
+	}
+
+	// Once every block is present, the object is an ordinary cache entry
+	if bi.complete() {
+		if fp, doneErr := os.OpenFile(localPath+".DONE", os.O_CREATE|os.O_WRONLY, os.FileMode(0600)); doneErr != nil {
+			log.Debugln("Unable to save a DONE file for cache path", objectPath)
+		} else {
+			fp.Close()
+			if rmErr := os.Remove(localPath + ".BLOCKS"); rmErr != nil {
+				log.Warningln("Failed to remove block info of completed object:", rmErr)
+			}
+		}
+	}
+	lc.hitChan <- lruEntry{lastUse: time.Now(), path: objectPath, size: bi.cachedBytes(), partial: !bi.complete()}
+
+	fp, err := os.Open(localPath)
+	if err != nil {
+		return
+	}
+	reader = &rangeReader{io.NewSectionReader(fp, rangeStart, rangeEnd-rangeStart+1), fp}
+	return
+}
@@ -0,0 +1,232 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+func TestBlockInfo(t *testing.T) {
+	bi := newBlockInfo(10*100+50, 100)
+	assert.Equal(t, int64(11), bi.numBlocks())
+	assert.Len(t, bi.bitmap, 2)
+	assert.Equal(t, [][2]int64{{0, 10}}, bi.missingRuns(0, 10))
+
+	for _, block := range []int64{2, 3, 7, 10} {
+		bi.set(block)
+	}
+	assert.False(t, bi.complete())
+	assert.Equal(t, int64(350), bi.cachedBytes(), "the last block is short")
+	assert.Equal(t, [][2]int64{{1, 1}, {4, 6}, {8, 9}}, bi.missingRuns(1, 10))
+	assert.Empty(t, bi.missingRuns(2, 3))
+
+	data, err := bi.Serialize()
+	require.NoError(t, err)
+	restored, err := deserializeBlockInfo(data)
+	require.NoError(t, err)
+	assert.Equal(t, bi, restored)
+
+	// Corruption of the header or the bitmap is detected
+	corrupt := append([]byte{}, data...)
+	corrupt[5] ^= 0xff
+	_, err = deserializeBlockInfo(corrupt)
+	assert.ErrorContains(t, err, "header checksum")
+	corrupt = append([]byte{}, data...)
+	corrupt[len(corrupt)-5] ^= 0x01
+	_, err = deserializeBlockInfo(corrupt)
+	assert.ErrorContains(t, err, "bitmap checksum")
+	_, err = deserializeBlockInfo(data[:10])
+	assert.Error(t, err)
+
+	for block := int64(0); block < bi.numBlocks(); block++ {
+		bi.set(block)
+	}
+	assert.True(t, bi.complete())
+	assert.Equal(t, int64(1050), bi.cachedBytes())
+}
+
+func TestParseRange(t *testing.T) {
+	for header, expected := range map[string]struct {
+		start, end int64
+		ok         bool
+	}{
+		"bytes=0-99":    {0, 99, true},
+		"bytes=100-":    {100, -1, true},
+		"bytes=-20":     {-20, -1, true},
+		"bytes=5-4":     {0, 0, false},
+		"bytes=0-1,5-6": {0, 0, false},
+		"items=0-1":     {0, 0, false},
+		"bytes=-":       {0, 0, false},
+		"":              {0, 0, false},
+	} {
+		start, end, ok := parseRangeHeader(header)
+		assert.Equal(t, expected.ok, ok, header)
+		if expected.ok {
+			assert.Equal(t, expected.start, start, header)
+			assert.Equal(t, expected.end, end, header)
+		}
+	}
+
+	for _, tc := range []struct {
+		start, end, size        int64
+		expectStart, expectEnd  int64
+		expectNotSatisfiableErr bool
+	}{
+		{start: 0, end: 99, size: 50, expectStart: 0, expectEnd: 49},
+		{start: 10, end: -1, size: 50, expectStart: 10, expectEnd: 49},
+		{start: -20, end: -1, size: 50, expectStart: 30, expectEnd: 49},
+		{start: -80, end: -1, size: 50, expectStart: 0, expectEnd: 49},
+		{start: 50, end: -1, size: 50, expectNotSatisfiableErr: true},
+		{start: 0, end: -1, size: 0, expectNotSatisfiableErr: true},
+	} {
+		start, end, err := resolveRange(tc.start, tc.end, tc.size)
+		if tc.expectNotSatisfiableErr {
+			assert.ErrorIs(t, err, errRangeNotSatisfiable)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.expectStart, start)
+		assert.Equal(t, tc.expectEnd, end)
+	}
+}
+
+// Partially-cached objects are restored from their block info
+func TestReconstructBlocks(t *testing.T) {
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+	}
+	objDir := filepath.Join(dataDir, "test")
+	require.NoError(t, os.MkdirAll(objDir, 0755))
+
+	bi := newBlockInfo(1000, 300)
+	bi.set(1)
+	bi.set(3)
+	require.NoError(t, os.WriteFile(filepath.Join(objDir, "partial"), make([]byte, 1000), 0644))
+	require.NoError(t, writeBlockInfo(filepath.Join(objDir, "partial.BLOCKS"), bi))
+
+	// Stale block info of a fully-downloaded object is ignored
+	require.NoError(t, os.WriteFile(filepath.Join(objDir, "full"), make([]byte, 1000), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(objDir, "full.DONE"), nil, 0644))
+	require.NoError(t, writeBlockInfo(filepath.Join(objDir, "full.BLOCKS"), bi))
+
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(objDir, "partial.BLOCKS"), old, old))
+
+	require.NoError(t, lc.ReconstructCache())
+	require.Len(t, lc.lruLookup, 2)
+	require.Contains(t, lc.lruLookup, "/test/partial")
+	assert.Equal(t, int64(400), lc.lruLookup["/test/partial"].size)
+	assert.Equal(t, int64(1000), lc.lruLookup["/test/full"].size)
+	assert.Equal(t, uint64(1400), lc.cacheSize)
+
+	// Evicting the partial object removes its block info
+	lc.lowWater = 1000
+	require.NoError(t, lc.purge())
+	_, err := os.Stat(filepath.Join(objDir, "partial.BLOCKS"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, uint64(1000), lc.cacheSize)
+}
+
+// Purges skip a partially-cached object while a range request holds its
+// block lock, and blocks are never written back into an evicted object
+func TestEvictDuringGetRange(t *testing.T) {
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		hitChan:          make(chan lruEntry), // Holds GetRange with the block lock taken
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+		ac:               &authConfig{tokenAuthz: ttlcache.New[string, acls]()},
+	}
+	lc.ac.tokenAuthz.Set("good-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/test")}, ttlcache.NoTTL)
+	localPath := filepath.Join(dataDir, "test", "partial")
+	require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+	content := make([]byte, 1000)
+	for idx := range content {
+		content[idx] = byte(idx)
+	}
+	require.NoError(t, os.WriteFile(localPath, content, 0644))
+	bi := newBlockInfo(1000, 100)
+	bi.set(0)
+	bi.set(1)
+	require.NoError(t, writeBlockInfo(localPath+".BLOCKS", bi))
+	require.NoError(t, lc.ReconstructCache())
+
+	type rangeResult struct {
+		reader io.ReadCloser
+		err    error
+	}
+	results := make(chan rangeResult, 1)
+	go func() {
+		reader, _, _, _, err := lc.GetRange(context.Background(), "/test/partial", "good-token", 0, 149)
+		results <- rangeResult{reader, err}
+	}()
+	require.Eventually(t, func() bool {
+		lc.blockMutex.Lock()
+		defer lc.blockMutex.Unlock()
+		return lc.blockLocks["/test/partial"] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The request still holds the block lock, so the object is not evicted
+	assert.Error(t, lc.purge())
+	_, err := os.Stat(localPath + ".BLOCKS")
+	require.NoError(t, err)
+	assert.Contains(t, lc.lruLookup, "/test/partial")
+
+	hit := <-lc.hitChan
+	assert.True(t, hit.partial)
+	result := <-results
+	require.NoError(t, result.err)
+	got, err := io.ReadAll(result.reader)
+	require.NoError(t, err)
+	require.NoError(t, result.reader.Close())
+	assert.Equal(t, content[:150], got)
+
+	// Once released, the object is evicted, and block info read before the
+	// eviction cannot be used to write into a recreated data file
+	require.NoError(t, lc.purge())
+	_, err = os.Stat(localPath)
+	assert.True(t, os.IsNotExist(err))
+	_, err = openBlockData(localPath, bi)
+	assert.Error(t, err)
+	_, err = os.Stat(localPath)
+	assert.True(t, os.IsNotExist(err), "the data file must not be recreated")
+
+	// Nor can it be used once the object was replaced
+	require.NoError(t, createBlockData(localPath))
+	_, err = openBlockData(localPath, bi)
+	assert.ErrorContains(t, err, "cached blocks of the object were removed")
+	fp, err := openBlockData(localPath, newBlockInfo(1000, 100))
+	require.NoError(t, err)
+	require.NoError(t, fp.Close())
+}
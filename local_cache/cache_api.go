@@ -29,7 +29,6 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -105,93 +104,8 @@ func (lc *LocalCache) LaunchListener(ctx context.Context, egrp *errgroup.Group)
 		return err
 	}
 
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "GET" && r.Method != "HEAD" {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		transferStatusStr := r.Header.Get("X-Transfer-Status")
-		sendTrailer := false
-		if transferStatusStr == "true" {
-			for _, encoding := range r.Header.Values("TE") {
-				if encoding == "trailers" {
-					sendTrailer = true
-					w.Header().Set("Trailer", "X-Transfer-Status")
-					break
-				}
-			}
-		}
-
-		authzHeader := r.Header.Get("Authorization")
-		bearerToken := ""
-		if strings.HasPrefix(authzHeader, "Bearer ") {
-			bearerToken = authzHeader[7:] // len("Bearer ") == 7
-		}
-		path := path.Clean(r.URL.Path)
-
-		var headerTimeout time.Duration = 0
-		timeoutStr := r.Header.Get("X-Pelican-Timeout")
-		if timeoutStr != "" {
-			if headerTimeout, err = time.ParseDuration(timeoutStr); err != nil {
-				log.Debugln("Invalid X-Pelican-Timeout value:", timeoutStr)
-			}
-		}
-		log.Debugln("Setting header timeout:", timeoutStr)
-
-		var size uint64
-		var reader io.ReadCloser
-		if r.Method == "HEAD" {
-			size, err = lc.Stat(path, bearerToken)
-			if err == nil {
-				w.Header().Set("Content-Length", strconv.FormatUint(size, 10))
-			}
-		} else {
-			ctx := context.Background()
-			if headerTimeout > 0 {
-				var cancelReqFunc context.CancelFunc
-				ctx, cancelReqFunc = context.WithTimeout(ctx, headerTimeout)
-				defer cancelReqFunc()
-			}
-			reader, err = lc.Get(ctx, path, bearerToken)
-		}
-		if errors.Is(err, authorizationDenied) {
-			w.WriteHeader(http.StatusForbidden)
-			if _, err = w.Write([]byte("Authorization Denied")); err != nil {
-				log.Errorln("Failed to write authorization denied to client")
-			}
-			return
-		} else if errors.Is(err, context.DeadlineExceeded) {
-			w.WriteHeader(http.StatusGatewayTimeout)
-			if _, err = w.Write([]byte("Upstream response timeout")); err != nil {
-				log.Errorln("Failed to write gateway timeout to client")
-			}
-			return
-		} else if err != nil {
-			log.Errorln("Failed to get file from cache:", err)
-			var sce *client.StatusCodeError
-			if errors.As(err, &sce) {
-				w.WriteHeader(int(*sce))
-			} else {
-				w.WriteHeader(http.StatusInternalServerError)
-				if _, err = w.Write([]byte("Unexpected internal error")); err != nil {
-					log.Errorln("Failed to write internal error message to client")
-				}
-			}
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		if r.Method == "HEAD" {
-			return
-		}
-		if _, err = io.Copy(w, reader); err != nil && sendTrailer {
-			// TODO: Enumerate more error values
-			w.Header().Set("X-Transfer-Status", fmt.Sprintf("%d: %s", 500, err))
-		} else if sendTrailer {
-			w.Header().Set("X-Transfer-Status", "200: OK")
-		}
-	}
 	srv := http.Server{
-		Handler: http.HandlerFunc(handler),
+		Handler: http.HandlerFunc(lc.serveObject),
 	}
 	egrp.Go(func() error {
 		return srv.Serve(listener)
@@ -212,10 +126,122 @@ func (lc *LocalCache) LaunchListener(ctx context.Context, egrp *errgroup.Group)
 	return
 }
 
+// Serve GET and HEAD requests for objects, fetching them through the cache
+func (lc *LocalCache) serveObject(w http.ResponseWriter, r *http.Request) {
+	var err error
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	transferStatusStr := r.Header.Get("X-Transfer-Status")
+	sendTrailer := false
+	if transferStatusStr == "true" {
+		for _, encoding := range r.Header.Values("TE") {
+			if encoding == "trailers" {
+				sendTrailer = true
+				w.Header().Set("Trailer", "X-Transfer-Status")
+				break
+			}
+		}
+	}
+
+	bearerToken := getBearerToken(r)
+	path := path.Clean(r.URL.Path)
+
+	var headerTimeout time.Duration = 0
+	timeoutStr := r.Header.Get("X-Pelican-Timeout")
+	if timeoutStr != "" {
+		if headerTimeout, err = time.ParseDuration(timeoutStr); err != nil {
+			log.Debugln("Invalid X-Pelican-Timeout value:", timeoutStr)
+		}
+	}
+	log.Debugln("Setting header timeout:", timeoutStr)
+
+	var size uint64
+	var reader io.ReadCloser
+	var rangeStart, rangeEnd, objectSize int64
+	rangeStart, rangeEnd, isRange := parseRangeHeader(r.Header.Get("Range"))
+	if r.Method == "HEAD" {
+		size, err = lc.Stat(path, bearerToken)
+		if err == nil {
+			w.Header().Set("Content-Length", strconv.FormatUint(size, 10))
+		}
+	} else {
+		ctx := context.Background()
+		if headerTimeout > 0 {
+			var cancelReqFunc context.CancelFunc
+			ctx, cancelReqFunc = context.WithTimeout(ctx, headerTimeout)
+			defer cancelReqFunc()
+		}
+		if isRange {
+			reader, rangeStart, rangeEnd, objectSize, err = lc.GetRange(ctx, path, bearerToken, rangeStart, rangeEnd)
+		} else {
+			reader, err = lc.Get(ctx, path, bearerToken)
+		}
+	}
+	if reader != nil {
+		defer reader.Close()
+	}
+	if errors.Is(err, authorizationDenied) {
+		w.WriteHeader(http.StatusForbidden)
+		if _, err = w.Write([]byte("Authorization Denied")); err != nil {
+			log.Errorln("Failed to write authorization denied to client")
+		}
+		return
+	} else if errors.Is(err, errRangeNotSatisfiable) {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", objectSize))
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		return
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+		if _, err = w.Write([]byte("Upstream response timeout")); err != nil {
+			log.Errorln("Failed to write gateway timeout to client")
+		}
+		return
+	} else if err != nil {
+		log.Errorln("Failed to get file from cache:", err)
+		var sce *client.StatusCodeError
+		if errors.As(err, &sce) {
+			w.WriteHeader(int(*sce))
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			if _, err = w.Write([]byte("Unexpected internal error")); err != nil {
+				log.Errorln("Failed to write internal error message to client")
+			}
+		}
+		return
+	}
+	if isRange && r.Method == "GET" {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rangeStart, rangeEnd, objectSize))
+		w.Header().Set("Content-Length", strconv.FormatInt(rangeEnd-rangeStart+1, 10))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	if r.Method == "HEAD" {
+		return
+	}
+	if _, err = io.Copy(w, reader); err != nil && sendTrailer {
+		// TODO: Enumerate more error values
+		w.Header().Set("X-Transfer-Status", fmt.Sprintf("%d: %s", 500, err))
+	} else if sendTrailer {
+		w.Header().Set("X-Transfer-Status", "200: OK")
+	}
+}
+
 // Register the control & monitoring routines with Gin
 func (lc *LocalCache) Register(ctx context.Context, router *gin.RouterGroup) {
 	router.POST("/api/v1.0/localcache/purge", func(ginCtx *gin.Context) { lc.purgeCmd(ginCtx) })
 	router.POST("/api/v1.0/localcache/purge_first", func(ginCtx *gin.Context) { lc.purgeFirstCmd(ginCtx) })
+	router.POST("/api/v1.0/localcache/pin", func(ginCtx *gin.Context) { lc.pinCmd(ginCtx) })
+	router.POST("/api/v1.0/localcache/unpin", func(ginCtx *gin.Context) { lc.unpinCmd(ginCtx) })
+	router.POST("/api/v1.0/localcache/prefetch", func(ginCtx *gin.Context) { lc.prefetchCmd(ginCtx) })
+	router.GET("/api/v1.0/localcache/prefetch/:id", func(ginCtx *gin.Context) { lc.prefetchStatusCmd(ginCtx) })
+	if lc.peers != nil {
+		router.GET(peerApiPrefix+"/jwks", func(ginCtx *gin.Context) { lc.peerKeysCmd(ginCtx) })
+		router.GET(peerApiPrefix+"/digest", func(ginCtx *gin.Context) { lc.peerDigestCmd(ginCtx) })
+		router.GET(peerApiPrefix+"/object/*path", func(ginCtx *gin.Context) { lc.peerObjectCmd(ginCtx) })
+	}
 }
 
 // Authorize the request then trigger the purge routine
@@ -305,3 +331,169 @@ func (lc *LocalCache) purgeFirstCmd(ginCtx *gin.Context) {
 	log.Infof("Successfully moved object to purge first heap (path: %s)", req.Path)
 	ginCtx.JSON(http.StatusOK, server_structs.SimpleApiResp{Status: server_structs.RespOK})
 }
+
+// Pin an object in the cache so that it is not evicted until the pin expires
+func (lc *LocalCache) pinCmd(ginCtx *gin.Context) {
+	status, verified, err := token.Verify(ginCtx, token.AuthOption{
+		Sources: []token.TokenSource{token.Header},
+		Issuers: []token.TokenIssuer{token.LocalIssuer, token.APITokenIssuer},
+		Scopes:  []token_scopes.TokenScope{token_scopes.Localcache_Purge},
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			status = http.StatusInternalServerError
+		}
+		ginCtx.AbortWithStatusJSON(
+			status,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	} else if !verified {
+		ginCtx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Unknown verification error"})
+		return
+	}
+	var req struct {
+		Path     string `json:"path"`
+		Lifetime string `json:"lifetime"`
+	}
+
+	if err = ginCtx.ShouldBindJSON(&req); err != nil {
+		log.Warningln("Received invalid JSON request")
+		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Invalid request format"})
+		return
+	}
+	var lifetime time.Duration
+	if req.Lifetime != "" {
+		if lifetime, err = time.ParseDuration(req.Lifetime); err != nil || lifetime <= 0 {
+			ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+				Status: server_structs.RespFailed, Msg: fmt.Sprintf("Invalid pin lifetime %q", req.Lifetime)})
+			return
+		}
+	}
+
+	status, err = lc.PinObject(req.Path, lifetime)
+	if err != nil {
+		log.Warningf("Failed to pin object (path: %s, error: %v)", req.Path, err)
+		ginCtx.AbortWithStatusJSON(status, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	}
+
+	log.Infof("Successfully pinned object (path: %s)", req.Path)
+	ginCtx.JSON(http.StatusOK, server_structs.SimpleApiResp{Status: server_structs.RespOK})
+}
+
+// Remove the pin from an object in the cache
+func (lc *LocalCache) unpinCmd(ginCtx *gin.Context) {
+	status, verified, err := token.Verify(ginCtx, token.AuthOption{
+		Sources: []token.TokenSource{token.Header},
+		Issuers: []token.TokenIssuer{token.LocalIssuer, token.APITokenIssuer},
+		Scopes:  []token_scopes.TokenScope{token_scopes.Localcache_Purge},
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			status = http.StatusInternalServerError
+		}
+		ginCtx.AbortWithStatusJSON(
+			status,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	} else if !verified {
+		ginCtx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Unknown verification error"})
+		return
+	}
+	var req struct {
+		Path string `json:"path"`
+	}
+
+	if err = ginCtx.ShouldBindJSON(&req); err != nil {
+		log.Warningln("Received invalid JSON request")
+		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Invalid request format"})
+		return
+	}
+
+	status, err = lc.UnpinObject(req.Path)
+	if err != nil {
+		log.Warningf("Failed to unpin object (path: %s, error: %v)", req.Path, err)
+		ginCtx.AbortWithStatusJSON(status, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	}
+
+	log.Infof("Successfully unpinned object (path: %s)", req.Path)
+	ginCtx.JSON(http.StatusOK, server_structs.SimpleApiResp{Status: server_structs.RespOK})
+}
+
+// Start prefetching the objects of a manifest into the cache
+func (lc *LocalCache) prefetchCmd(ginCtx *gin.Context) {
+	status, verified, err := token.Verify(ginCtx, token.AuthOption{
+		Sources: []token.TokenSource{token.Header},
+		Issuers: []token.TokenIssuer{token.LocalIssuer, token.APITokenIssuer},
+		Scopes:  []token_scopes.TokenScope{token_scopes.Localcache_Purge},
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			status = http.StatusInternalServerError
+		}
+		ginCtx.AbortWithStatusJSON(
+			status,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	} else if !verified {
+		ginCtx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Unknown verification error"})
+		return
+	}
+	var req PrefetchRequest
+	if err = ginCtx.ShouldBindJSON(&req); err != nil {
+		log.Warningln("Received invalid JSON request")
+		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Invalid request format"})
+		return
+	}
+
+	prefetchStatus, err := lc.Prefetch(req)
+	if err != nil {
+		ginCtx.AbortWithStatusJSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	}
+	ginCtx.JSON(http.StatusAccepted, prefetchStatus)
+}
+
+// Report the progress of a prefetch job
+func (lc *LocalCache) prefetchStatusCmd(ginCtx *gin.Context) {
+	status, verified, err := token.Verify(ginCtx, token.AuthOption{
+		Sources: []token.TokenSource{token.Header},
+		Issuers: []token.TokenIssuer{token.LocalIssuer, token.APITokenIssuer},
+		Scopes:  []token_scopes.TokenScope{token_scopes.Localcache_Purge},
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			status = http.StatusInternalServerError
+		}
+		ginCtx.AbortWithStatusJSON(
+			status,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: err.Error()})
+		return
+	} else if !verified {
+		ginCtx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			server_structs.SimpleApiResp{Status: server_structs.RespFailed, Msg: "Unknown verification error"})
+		return
+	}
+
+	prefetchStatus, ok := lc.PrefetchStatus(ginCtx.Param("id"))
+	if !ok {
+		ginCtx.AbortWithStatusJSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Prefetch job not found"})
+		return
+	}
+	ginCtx.JSON(http.StatusOK, prefetchStatus)
+}
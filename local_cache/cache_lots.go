@@ -0,0 +1,380 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/lotman"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	// A lot managed by the local cache, tracking the usage of the
+	// objects assigned to it.  Unlike LotMan, usage is not aggregated
+	// through the lot's parents.
+	cacheLot struct {
+		name    string
+		paths   []lotman.LotPath
+		mpa     lotman.MPA
+		usage   uint64
+		objects int64
+	}
+
+	// The lot configuration of the local cache, built from one of the
+	// Lotman.PolicyDefinitions
+	lotPolicy struct {
+		purgeOrder []string
+		lots       []*cacheLot
+		defaultLot *cacheLot
+	}
+)
+
+const (
+	bytesInGigabyte = 1000 * 1000 * 1000
+
+	// The name of the lot holding objects not matched by any lot path
+	defaultLotName = "default"
+)
+
+var defaultPurgeOrder = []string{"del", "exp", "opp", "ded"}
+
+// Load the lot policy named by LocalCache.LotPolicy.  Returns nil
+// if the local cache is not configured to use lots.
+func loadLotPolicy() (*lotPolicy, error) {
+	policyName := param.LocalCache_LotPolicy.GetString()
+	if policyName == "" {
+		return nil, nil
+	}
+	policies, err := lotman.GetPolicyMap()
+	if err != nil {
+		return nil, err
+	}
+	policy, ok := policies[policyName]
+	if !ok {
+		return nil, errors.Errorf("%s is set to %q but no such policy is defined in %s",
+			param.LocalCache_LotPolicy.GetName(), policyName, param.Lotman_PolicyDefinitions.GetName())
+	}
+
+	lp := &lotPolicy{purgeOrder: policy.PurgeOrder}
+	if len(lp.purgeOrder) == 0 {
+		lp.purgeOrder = defaultPurgeOrder
+	}
+	for _, category := range lp.purgeOrder {
+		if !slices.Contains(defaultPurgeOrder, category) {
+			return nil, errors.Errorf("invalid purge order %q in policy %q; must be one of %s",
+				category, policyName, strings.Join(defaultPurgeOrder, ", "))
+		}
+	}
+	for _, lot := range policy.Lots {
+		if lot.LotName == "" {
+			return nil, errors.Errorf("a lot in policy %q is missing its name", policyName)
+		}
+		cl := &cacheLot{name: lot.LotName}
+		if lot.MPA != nil {
+			cl.mpa = *lot.MPA
+		}
+		for _, lotPath := range lot.Paths {
+			lotPath.Path = path.Clean("/" + lotPath.Path)
+			cl.paths = append(cl.paths, lotPath)
+		}
+		lp.lots = append(lp.lots, cl)
+		if cl.name == defaultLotName {
+			lp.defaultLot = cl
+		}
+	}
+	if lp.defaultLot == nil {
+		lp.defaultLot = &cacheLot{name: defaultLotName}
+		lp.lots = append(lp.lots, lp.defaultLot)
+	}
+	log.Infof("Local cache is managing storage with the %d lots of policy %q (purge order %v)", len(lp.lots), policyName, lp.purgeOrder)
+	return lp, nil
+}
+
+// Return the lot an object belongs to: the lot with the longest path
+// matching the object, or the default lot.
+func (lp *lotPolicy) lotFor(objectPath string) *cacheLot {
+	objectPath = path.Clean("/" + objectPath)
+	result := lp.defaultLot
+	matchLen := -1
+	for _, lot := range lp.lots {
+		for _, lotPath := range lot.paths {
+			var matches bool
+			if lotPath.Recursive {
+				matches = lotPath.Path == "/" || objectPath == lotPath.Path || strings.HasPrefix(objectPath, lotPath.Path+"/")
+			} else {
+				matches = path.Dir(objectPath) == lotPath.Path
+			}
+			if matches && len(lotPath.Path) > matchLen {
+				result = lot
+				matchLen = len(lotPath.Path)
+			}
+		}
+	}
+	return result
+}
+
+// The lot's dedicated storage, in bytes
+func (lot *cacheLot) dedicatedBytes() uint64 {
+	if lot.mpa.DedicatedGB == nil || *lot.mpa.DedicatedGB < 0 {
+		return 0
+	}
+	return uint64(*lot.mpa.DedicatedGB * bytesInGigabyte)
+}
+
+// The lot's storage quota (dedicated plus opportunistic) in bytes; ok is
+// false if the lot has no quota.
+func (lot *cacheLot) quotaBytes() (quota uint64, ok bool) {
+	if lot.mpa.DedicatedGB == nil && lot.mpa.OpportunisticGB == nil {
+		return 0, false
+	}
+	quota = lot.dedicatedBytes()
+	if lot.mpa.OpportunisticGB != nil && *lot.mpa.OpportunisticGB > 0 {
+		quota += uint64(*lot.mpa.OpportunisticGB * bytesInGigabyte)
+	}
+	return quota, true
+}
+
+// Whether the lot's usage is over its storage or object quota
+func (lot *cacheLot) overQuota() bool {
+	if lot.mpa.MaxNumObjects != nil && lot.mpa.MaxNumObjects.Value > 0 && lot.objects > lot.mpa.MaxNumObjects.Value {
+		return true
+	}
+	quota, ok := lot.quotaBytes()
+	return ok && lot.usage > quota
+}
+
+// Whether the lot's objects are eligible for the given purge category
+func (lot *cacheLot) inCategory(category string, now time.Time) bool {
+	switch category {
+	case "del":
+		return lot.mpa.DeletionTime != nil && lot.mpa.DeletionTime.Value > 0 && now.UnixMilli() > lot.mpa.DeletionTime.Value
+	case "exp":
+		return lot.mpa.ExpirationTime != nil && lot.mpa.ExpirationTime.Value > 0 && now.UnixMilli() > lot.mpa.ExpirationTime.Value
+	case "opp":
+		quota, ok := lot.quotaBytes()
+		return ok && lot.usage > quota
+	case "ded":
+		return lot.usage > lot.dedicatedBytes()
+	}
+	return false
+}
+
+// Whether the entry is pinned in the cache at the given time
+func (entry *lruEntry) pinned(now time.Time) bool {
+	return now.Before(entry.pinnedUntil)
+}
+
+// Assign a new cache entry to its lot, if lots are in use
+func (lc *LocalCache) addToLot(entry *lruEntry) {
+	if lc.lots == nil {
+		return
+	}
+	entry.lot = lc.lots.lotFor(entry.path)
+	entry.lot.usage += uint64(entry.size)
+	entry.lot.objects++
+}
+
+// Evict the least-recently-used objects of a lot until it is within its
+// quota; the cache's mutex must be held
+func (lc *LocalCache) enforceQuota(lot *cacheLot) (err error) {
+	lc.purgeMutex.Lock()
+	defer lc.purgeMutex.Unlock()
+	defer lc.rebuildHeaps()
+
+	log.Debugf("Lot %s is over its quota (%d bytes in %d objects); evicting its least-recently-used objects", lot.name, lot.usage, lot.objects)
+	err = lc.evictWhile(lc.sortedEntries(func(entry *lruEntry) bool { return entry.lot == lot }), time.Now(), func() bool {
+		return lot.overQuota()
+	})
+	if err == nil && lot.overQuota() {
+		log.Warningf("Lot %s remains over its quota; its remaining objects are pinned", lot.name)
+	}
+	return
+}
+
+// Return the cache entries matching the filter, least-recently-used first
+func (lc *LocalCache) sortedEntries(filter func(*lruEntry) bool) (entries []*lruEntry) {
+	for _, entry := range lc.lruLookup {
+		if filter == nil || filter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b *lruEntry) int {
+		return a.lastUse.Compare(b.lastUse)
+	})
+	return
+}
+
+// Evict the entries, in order, as long as more space is needed; pinned
+// entries and those whose block lock is in use are skipped.  The cache's mutex must be held, so that pins cannot
+// change while the entries are checked.  The first error is returned after attempting all
+// evictions, except for a timeout which stops the eviction immediately.
+func (lc *LocalCache) evictWhile(entries []*lruEntry, start time.Time, needed func() bool) (err error) {
+	now := time.Now()
+	for _, entry := range entries {
+		if !needed() {
+			return
+		}
+		if entry.pinned(now) {
+			continue
+		}
+		// Objects whose blocks are being fetched are left for a later purge
+		unlock, ok := lc.tryLockBlocks(entry.path)
+		if !ok {
+			continue
+		}
+		evictErr := lc.evict(entry)
+		unlock()
+		if evictErr != nil && err == nil {
+			err = evictErr
+		}
+		if time.Since(start) > 3*time.Second {
+			log.Warningln("Purge timeout while evicting objects")
+			return purgeTimeout
+		}
+	}
+	return
+}
+
+// Remove an object and its sentinel files from the cache, updating
+// the in-memory bookkeeping.  The caller must hold the object's block
+// lock.  The heaps are not updated; callers must invoke rebuildHeaps once
+// done.
+func (lc *LocalCache) evict(entry *lruEntry) (err error) {
+	localPath := path.Join(lc.basePath, path.Clean(entry.path))
+	// Partially-cached objects have a .BLOCKS sentinel instead of a .DONE one
+	for _, sentinel := range []string{".DONE", ".BLOCKS", ".PURGEFIRST", ".PIN"} {
+		if rmErr := os.Remove(localPath + sentinel); rmErr != nil && !os.IsNotExist(rmErr) {
+			log.Warningf("Failed to purge %s file: %v", strings.TrimPrefix(sentinel, "."), rmErr)
+			if err == nil {
+				err = rmErr
+			}
+		}
+	}
+	if rmErr := os.Remove(localPath); rmErr != nil {
+		log.Warningln("Failed to purge file:", rmErr)
+		if err == nil {
+			err = rmErr
+		}
+	} else {
+		log.Debugf("Successfully purged file %s", localPath)
+	}
+
+	delete(lc.lruLookup, entry.path)
+	delete(lc.purgeFirstLookup, entry.path)
+	lc.cacheSize -= uint64(entry.size)
+	if entry.lot != nil {
+		entry.lot.usage -= uint64(entry.size)
+		entry.lot.objects--
+	}
+	return
+}
+
+// Rebuild the LRU heaps from the lookup maps after objects were evicted
+func (lc *LocalCache) rebuildHeaps() {
+	lc.lru = lc.lru[:0]
+	for _, entry := range lc.lruLookup {
+		lc.lru = append(lc.lru, entry)
+	}
+	lc.purgeFirstHeap = lc.purgeFirstHeap[:0]
+	for _, entry := range lc.purgeFirstLookup {
+		lc.purgeFirstHeap = append(lc.purgeFirstHeap, entry)
+	}
+}
+
+// Parse the contents of a .PIN sentinel file, the RFC 3339 expiration of the pin
+func readPinSentinel(sentinelPath string) (time.Time, error) {
+	contents, err := os.ReadFile(sentinelPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(contents)))
+}
+
+// PinObject pins the given object path in the cache for the lifetime
+// (or LocalCache.DefaultPinLifetime if lifetime is not positive); the
+// object is not evicted by purges or lot quotas until the pin expires.
+// Pinning an already-pinned object replaces the expiration of the pin.
+func (lc *LocalCache) PinObject(objectPath string, lifetime time.Duration) (int, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	entry, exists := lc.lruLookup[objectPath]
+	if !exists {
+		log.Warningf("Object not found in cache (path: %s)", objectPath)
+		return http.StatusNotFound, errors.New("object not found in cache")
+	}
+
+	safePath, err := securejoin.SecureJoin(lc.basePath, objectPath)
+	if err != nil {
+		log.Warnf("Invalid path: %v", err)
+		return http.StatusBadRequest, errors.New("invalid path (outside base directory)")
+	}
+
+	if lifetime <= 0 {
+		lifetime = param.LocalCache_DefaultPinLifetime.GetDuration()
+	}
+	pinnedUntil := time.Now().Add(lifetime).Truncate(time.Second)
+	sentinelPath := safePath + ".PIN"
+	if err = os.WriteFile(sentinelPath, []byte(pinnedUntil.Format(time.RFC3339)), 0600); err != nil {
+		log.Errorf("Failed to create sentinel file %s: %v", sentinelPath, err)
+		return http.StatusInternalServerError, errors.New("failed to create sentinel file")
+	}
+	entry.pinnedUntil = pinnedUntil
+	log.Debugf("Pinned object %s until %s", objectPath, pinnedUntil)
+
+	return http.StatusOK, nil
+}
+
+// UnpinObject removes the pin from the given object path, making it
+// eligible for eviction again
+func (lc *LocalCache) UnpinObject(objectPath string) (int, error) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	entry, exists := lc.lruLookup[objectPath]
+	if !exists {
+		log.Warningf("Object not found in cache (path: %s)", objectPath)
+		return http.StatusNotFound, errors.New("object not found in cache")
+	}
+
+	safePath, err := securejoin.SecureJoin(lc.basePath, objectPath)
+	if err != nil {
+		log.Warnf("Invalid path: %v", err)
+		return http.StatusBadRequest, errors.New("invalid path (outside base directory)")
+	}
+
+	if err = os.Remove(safePath + ".PIN"); err != nil && !os.IsNotExist(err) {
+		log.Errorf("Failed to remove sentinel file %s.PIN: %v", safePath, err)
+		return http.StatusInternalServerError, errors.New("failed to remove sentinel file")
+	}
+	entry.pinnedUntil = time.Time{}
+	log.Debugf("Unpinned object %s", objectPath)
+
+	return http.StatusOK, nil
+}
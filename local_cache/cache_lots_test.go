@@ -0,0 +1,254 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// Each lot is sized in units of 1000 bytes
+const lotsConfig = `
+LocalCache:
+  LotPolicy: local-policy
+Lotman:
+  PolicyDefinitions:
+    - PolicyName: local-policy
+      PurgeOrder: ["exp", "ded"]
+      Lots:
+        - LotName: expired
+          Owner: https://example.com
+          Paths:
+            - Path: /old
+              Recursive: true
+          ManagementPolicyAttrs:
+            DedicatedGB: 0.00001
+            ExpirationTime:
+              Value: 1000
+        - LotName: project
+          Owner: https://example.com
+          Paths:
+            - Path: /project
+              Recursive: true
+          ManagementPolicyAttrs:
+            DedicatedGB: 0.000002
+            OpportunisticGB: 0.000002
+        - LotName: flat
+          Owner: https://example.com
+          Paths:
+            - Path: /flat
+              Recursive: false
+`
+
+func TestLotPolicy(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(lotsConfig)))
+
+	lots, err := loadLotPolicy()
+	require.NoError(t, err)
+	require.NotNil(t, lots)
+	assert.Equal(t, []string{"exp", "ded"}, lots.purgeOrder)
+	require.Len(t, lots.lots, 4, "an implicit default lot should be added")
+
+	for objectPath, lotName := range map[string]string{
+		"/project/a":     "project",
+		"/project/x/y/z": "project",
+		"/projectx/a":    defaultLotName,
+		"/flat/a":        "flat",
+		"/flat/sub/a":    defaultLotName,
+		"/other/a":       defaultLotName,
+	} {
+		assert.Equal(t, lotName, lots.lotFor(objectPath).name, objectPath)
+	}
+
+	require.NoError(t, param.LocalCache_LotPolicy.Set("missing-policy"))
+	_, err = loadLotPolicy()
+	assert.ErrorContains(t, err, "missing-policy")
+
+	require.NoError(t, param.LocalCache_LotPolicy.Set(""))
+	lots, err = loadLotPolicy()
+	require.NoError(t, err)
+	assert.Nil(t, lots)
+}
+
+func TestLotPurge(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(lotsConfig)))
+	lots, err := loadLotPolicy()
+	require.NoError(t, err)
+
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		highWater:        100000,
+		lowWater:         3000,
+		lots:             lots,
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+	}
+	age := time.Now().Add(-time.Hour)
+	writeObject := func(objectPath string) {
+		localPath := filepath.Join(dataDir, objectPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+		require.NoError(t, os.WriteFile(localPath, make([]byte, 1000), 0644))
+		require.NoError(t, os.WriteFile(localPath+".DONE", nil, 0644))
+		require.NoError(t, os.Chtimes(localPath, age, age))
+		age = age.Add(time.Minute)
+	}
+	exists := func(objectPath string) bool {
+		_, err := os.Stat(filepath.Join(dataDir, objectPath))
+		return err == nil
+	}
+	for _, objectPath := range []string{"/other/a", "/project/a", "/old/a", "/project/b", "/other/b"} {
+		writeObject(objectPath)
+	}
+	require.NoError(t, lc.ReconstructCache())
+	assert.Equal(t, uint64(5000), lc.cacheSize)
+	assert.Equal(t, uint64(2000), lc.lruLookup["/project/a"].lot.usage)
+
+	// The expired lot goes first, then the least-recently-used object of the
+	// default lot, which has no dedicated space; the project lot is within its
+	// dedicated space and is left alone.
+	require.NoError(t, lc.purge())
+	assert.Equal(t, uint64(3000), lc.cacheSize)
+	assert.False(t, exists("/old/a"))
+	assert.False(t, exists("/old/a.DONE"))
+	assert.False(t, exists("/other/a"))
+	assert.True(t, exists("/other/b"))
+	assert.True(t, exists("/project/a"))
+	assert.True(t, exists("/project/b"))
+	assert.Len(t, lc.lru, 3)
+
+	// Exceeding the project quota evicts the oldest unpinned project object
+	status, err := lc.PinObject("/project/a", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	status, err = lc.PinObject("/project/missing", time.Hour)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	for _, objectPath := range []string{"/project/c", "/project/d", "/project/e"} {
+		writeObject(objectPath)
+		lc.lruHit(lruEntry{lastUse: time.Now(), path: objectPath, size: 1000})
+	}
+	project := lc.lruLookup["/project/a"].lot
+	assert.Equal(t, uint64(4000), project.usage)
+	assert.Equal(t, int64(4), project.objects)
+	assert.True(t, exists("/project/a"))
+	assert.False(t, exists("/project/b"))
+	assert.True(t, exists("/project/e"))
+
+	// Pins survive a restart, and purges never remove pinned objects
+	require.NoError(t, lc.ReconstructCache())
+	assert.Equal(t, uint64(5000), lc.cacheSize)
+	assert.True(t, lc.lruLookup["/project/a"].pinned(time.Now()))
+	lc.lowWater = 0
+	assert.Error(t, lc.purge())
+	assert.Equal(t, uint64(1000), lc.cacheSize)
+	assert.True(t, exists("/project/a"))
+	assert.True(t, exists("/project/a.PIN"))
+
+	_, err = lc.UnpinObject("/project/a")
+	require.NoError(t, err)
+	assert.False(t, exists("/project/a.PIN"))
+	require.NoError(t, lc.purge())
+	assert.Zero(t, lc.cacheSize)
+	assert.Zero(t, project.usage)
+	assert.Empty(t, lc.lruLookup)
+
+	// A partially-cached object growing past the quota evicts older objects
+	for _, objectPath := range []string{"/project/f", "/project/g", "/project/h"} {
+		writeObject(objectPath)
+		lc.lruHit(lruEntry{lastUse: time.Now(), path: objectPath, size: 1000, partial: objectPath == "/project/h"})
+	}
+	assert.Equal(t, uint64(3000), project.usage)
+	lc.lruHit(lruEntry{lastUse: time.Now(), path: "/project/h", size: 3000, partial: true})
+	assert.Equal(t, uint64(4000), project.usage)
+	assert.Equal(t, uint64(4000), lc.cacheSize)
+	assert.False(t, exists("/project/f"))
+	assert.True(t, exists("/project/g"))
+	assert.True(t, exists("/project/h"))
+}
+
+// Pins and purges requested through the API race with runMux recording
+// and evicting objects; run with -race to detect unsynchronized access
+func TestPinDuringEviction(t *testing.T) {
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		highWater:        4000,
+		lowWater:         2000,
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "test"), 0755))
+	writeObject := func(objectPath string) bool {
+		return assert.NoError(t, os.WriteFile(filepath.Join(dataDir, objectPath), make([]byte, 1000), 0644))
+	}
+
+	// Stand in for runMux, recording new objects and purging old ones
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 200; idx++ {
+			objectPath := fmt.Sprintf("/test/obj%d", idx)
+			if !writeObject(objectPath) {
+				return
+			}
+			lc.lruHit(lruEntry{lastUse: time.Now(), path: objectPath, size: 1000})
+		}
+	}()
+
+	// Every object successfully pinned survives the purges that follow
+	var pinned []string
+	for idx := 0; ; idx++ {
+		select {
+		case <-done:
+		default:
+			objectPath := fmt.Sprintf("/test/obj%d", idx%200)
+			if status, err := lc.PinObject(objectPath, time.Hour); err == nil {
+				assert.Equal(t, http.StatusOK, status)
+				pinned = append(pinned, objectPath)
+			}
+			_ = lc.purge()
+			continue
+		}
+		break
+	}
+	for _, objectPath := range pinned {
+		_, err := os.Stat(filepath.Join(dataDir, objectPath))
+		assert.NoError(t, err, objectPath)
+		assert.Contains(t, lc.lruLookup, objectPath)
+	}
+}
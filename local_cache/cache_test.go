@@ -145,6 +145,69 @@ func TestHttpReq(t *testing.T) {
 	assert.Equal(t, "Hello, World!", string(body))
 }
 
+// Request a byte range of an object through the local cache socket
+//
+// Only the blocks covering the range should be downloaded; once the
+// remainder of the object is requested, it becomes fully cached.
+func TestRangeReq(t *testing.T) {
+	t.Cleanup(test_utils.SetupTestLogging(t))
+	server_utils.ResetTestState()
+	require.NoError(t, param.LocalCache_BlockSize.Set("1MiB"))
+	ft := fed_test_utils.NewFedTest(t, authOriginCfg)
+
+	contents := make([]byte, 5*1024*1024+100)
+	for idx := range contents {
+		contents[idx] = byte(idx % 251)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(ft.Exports[0].StoragePrefix, "ranged.bin"), contents, 0644))
+
+	transport := config.GetTransport().Clone()
+	transport.DialContext = func(_ context.Context, _, _ string) (net.Conn, error) {
+		return net.Dial("unix", param.LocalCache_Socket.GetString())
+	}
+	client := &http.Client{Transport: transport}
+	getRange := func(rangeHdr string) (*http.Response, []byte) {
+		req, err := http.NewRequest("GET", "http://localhost/test/ranged.bin", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+ft.Token)
+		req.Header.Set("Range", rangeHdr)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	localPath := filepath.Join(param.LocalCache_DataLocation.GetString(), "test", "ranged.bin")
+	resp, body := getRange("bytes=4194304-4194313")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("bytes 4194304-4194313/%d", len(contents)), resp.Header.Get("Content-Range"))
+	assert.Equal(t, contents[4194304:4194314], body)
+	_, err := os.Stat(localPath + ".BLOCKS")
+	assert.NoError(t, err)
+	_, err = os.Stat(localPath + ".DONE")
+	assert.True(t, os.IsNotExist(err), "the object should only be partially cached")
+	info, err := os.Stat(localPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5*1024*1024), info.Size(), "only the data up to the end of the fetched block should be written")
+
+	resp, body = getRange("bytes=-100")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, contents[len(contents)-100:], body)
+
+	resp, _ = getRange(fmt.Sprintf("bytes=%d-", len(contents)))
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+
+	resp, body = getRange("bytes=0-")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, contents, body)
+	_, err = os.Stat(localPath + ".DONE")
+	assert.NoError(t, err)
+	_, err = os.Stat(localPath + ".BLOCKS")
+	assert.True(t, os.IsNotExist(err), "a fully-cached object should not have block info")
+}
+
 // Test startup when the local cache socket wasn't cleaned up.
 //
 // Ensure that the cache.sock existing doesn't prevent the local cache
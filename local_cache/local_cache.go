@@ -39,6 +39,7 @@ import (
 
 	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/google/uuid"
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/lestrrat-go/option"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -80,13 +81,29 @@ type (
 		purgeFirstHeap   lru
 		purgeFirstLookup map[string]*lruEntry
 
+		lots *lotPolicy // The lot configuration; nil if lots are not in use
+
+		// Block-level caching of byte ranges
+		blockSize    int64
+		blockTc      *client.TransferClient
+		blockMutex   sync.Mutex
+		blockLocks   map[string]*blockLock
+		blockWaiters map[string]chan client.TransferResults
+
+		peers *peerSet // The peer caches sharing objects; nil if peer sharing is disabled
+
+		prefetchJobs *ttlcache.Cache[string, *prefetchJob]
+
 		cacheSize uint64 // Total cache size
 	}
 
 	lruEntry struct {
-		lastUse time.Time
-		path    string
-		size    int64
+		lastUse     time.Time
+		path        string
+		size        int64
+		pinnedUntil time.Time // The entry is not evicted before this time
+		lot         *cacheLot // The lot of the entry; nil if lots are not in use
+		partial     bool      // Only some blocks of the object are cached
 	}
 
 	lru []*lruEntry
@@ -268,6 +285,15 @@ func NewLocalCache(ctx context.Context, egrp *errgroup.Group, options ...LocalCa
 	lowWater := (cacheSize / 100) * uint64(lowWaterPercentage)
 	log.Infof("Cache size is %d bytes; for purge, high water mark is %d bytes, low water mark is %d bytes", cacheSize, highWater, lowWater)
 
+	lots, err := loadLotPolicy()
+	if err != nil {
+		return
+	}
+	blockSize, err := getBlockSize()
+	if err != nil {
+		return
+	}
+
 	fedInfo, err := config.GetFederation(ctx)
 	if err != nil {
 		return
@@ -304,6 +330,11 @@ func NewLocalCache(ctx context.Context, egrp *errgroup.Group, options ...LocalCa
 		directorURL:      directorUrl,
 		lruLookup:        make(map[string]*lruEntry),
 		purgeFirstLookup: make(map[string]*lruEntry),
+		lots:             lots,
+		blockSize:        blockSize,
+		blockLocks:       make(map[string]*blockLock),
+		blockWaiters:     make(map[string]chan client.TransferResults),
+		prefetchJobs:     newPrefetchJobs(),
 	}
 
 	// Initialize heaps before reconstructing cache
@@ -323,13 +354,32 @@ func NewLocalCache(ctx context.Context, egrp *errgroup.Group, options ...LocalCa
 		}
 		return
 	}
+	if lc.blockTc, err = lc.te.NewClient(client.WithAcquireToken(false)); err != nil {
+		shutdownErr := lc.te.Shutdown()
+		if shutdownErr != nil {
+			log.Errorln("Failed to shutdown transfer engine")
+		}
+		return
+	}
 	if !deferConfig {
 		if err = lc.Config(egrp); err != nil {
 			log.Warningln("First attempt to update cache's authorization failed:", err)
 		}
 	}
 
+	if param.LocalCache_EnablePeers.GetBool() {
+		federation := fedInfo.DiscoveryEndpoint
+		if federation == "" {
+			federation = fedInfo.DirectorEndpoint
+		}
+		lc.peers = newPeerSet(lc, federation)
+		if err = lc.peers.launch(ctx, egrp); err != nil {
+			return
+		}
+	}
+
 	egrp.Go(lc.runMux)
+	egrp.Go(lc.runBlockResults)
 
 	log.Debugln("Successfully created a new local cache object")
 	return
@@ -509,10 +559,21 @@ func (sc *LocalCache) runMux() error {
 				} else {
 					fp.Close()
 				}
+				// Any blocks cached from range requests were replaced by the full object
+				if err := os.Remove(filepath.Join(sc.basePath, reqPath) + ".BLOCKS"); err != nil && !os.IsNotExist(err) {
+					log.Warningln("Failed to remove block info of downloaded object:", err)
+				}
 				sc.lruHit(lruEntry{lastUse: time.Now(), path: reqPath, size: results.TransferredBytes})
 			}
 		} else if chosen == lenChan+2 {
 			// Ticker has fired - update progress
+			if sc.peers != nil {
+				func() {
+					sc.mutex.RLock()
+					defer sc.mutex.RUnlock()
+					sc.peers.updateDigest(sc.lruLookup)
+				}()
+			}
 			jobsToDelete := make([]string, 0)
 			for path, dl := range activeJobs {
 				if _, err := dl.tj.GetLookupStatus(); err != nil {
@@ -643,136 +704,114 @@ func (sc *LocalCache) runMux() error {
 	}
 }
 
+// Record a use of a cache entry, purging the cache if it has grown past
+// its limits.  The lookup maps, heaps and entries are only changed with
+// the cache's mutex held, whether by runMux or by the API handlers.
 func (lc *LocalCache) lruHit(hit lruEntry) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
 	entry := lc.lruLookup[hit.path]
+	grown := false
 	if entry == nil {
 		entry = &hit
 		lc.lruLookup[hit.path] = entry
 		lc.lru = append(lc.lru, entry)
 		lc.cacheSize += uint64(hit.size)
-		if lc.cacheSize > lc.highWater {
-			if err := lc.purge(); err != nil {
-				log.Warningln("Failure when purging cache:", err)
+		lc.addToLot(entry)
+		grown = true
+	} else {
+		entry.lastUse = hit.lastUse
+		entry.partial = hit.partial
+		if hit.size > entry.size {
+			// A partially-cached object has grown
+			lc.cacheSize += uint64(hit.size - entry.size)
+			if entry.lot != nil {
+				entry.lot.usage += uint64(hit.size - entry.size)
 			}
+			entry.size = hit.size
+			grown = true
 		}
 	}
-	entry.lastUse = hit.lastUse
-	if hit.size > entry.size {
-		entry.size = hit.size
+	if !grown {
+		return
+	}
+	if entry.lot != nil && entry.lot.overQuota() {
+		if err := lc.enforceQuota(entry.lot); err != nil {
+			log.Warningf("Failure when enforcing the quota of lot %s: %v", entry.lot.name, err)
+		}
 	}
+	if lc.cacheSize > lc.highWater {
+		if err := lc.purgeLocked(); err != nil {
+			log.Warningln("Failure when purging cache:", err)
+		}
+	}
+}
+
+// Purge the cache down to the low water mark.  Objects marked as purge first
+// are evicted first, then (if lots are in use) the objects of the lots in each
+// category of the policy's purge order, and finally the least-recently-used
+// objects.  Pinned objects are never evicted.
+func (lc *LocalCache) purge() error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	return lc.purgeLocked()
 }
 
-func (lc *LocalCache) purge() (err error) {
+// Purge the cache as purge does; the cache's mutex must be held
+func (lc *LocalCache) purgeLocked() (err error) {
 	log.Debugln("Starting purge routine")
 	lc.purgeMutex.Lock()
 	defer lc.purgeMutex.Unlock()
+	defer lc.rebuildHeaps()
 
 	start := time.Now()
+	needed := func() bool { return lc.cacheSize > lc.lowWater }
 
-	// Purge purgeFirstHeap first
-	heap.Init(&lc.purgeFirstHeap)
 	log.Debugf("Purging `PURGEFIRST` objects first; cache size is %d, low watermark is %d", lc.cacheSize, lc.lowWater)
-	for lc.cacheSize > lc.lowWater {
-		if len(lc.purgeFirstHeap) == 0 {
-			log.Debugln("Purge first heap is empty, moving to main LRU heap")
-			break
-		}
-		entry := heap.Pop(&lc.purgeFirstHeap).(*lruEntry)
-		if entry == nil {
-			log.Warningln("Consistency error: purge ran but no entry provided")
-			continue
-		}
-		if entry.path == "" {
-			log.Warningln("Consistency error: purge ran on an empty path")
-			continue
-		}
+	purgeFirst := lc.sortedEntries(func(entry *lruEntry) bool {
+		_, ok := lc.purgeFirstLookup[entry.path]
+		return ok
+	})
+	if err = lc.evictWhile(purgeFirst, start, needed); err == purgeTimeout {
+		return
+	}
 
-		localPath := path.Join(lc.basePath, path.Clean(entry.path))
-		if rmErr := os.Remove(localPath + ".DONE"); rmErr != nil {
-			log.Warningln("Failed to purge DONE file:", rmErr)
-			if err == nil {
-				err = rmErr
-			}
-		}
-		if rmErr := os.Remove(localPath + ".PURGEFIRST"); rmErr != nil {
-			if !os.IsNotExist(rmErr) {
-				log.Warningln("Failed to purge PURGEFIRST file:", rmErr)
-				if err == nil {
-					err = rmErr
+	if lc.lots != nil {
+		for _, category := range lc.lots.purgeOrder {
+			for _, lot := range lc.lots.lots {
+				if !needed() {
+					break
+				}
+				if !lot.inCategory(category, start) {
+					continue
+				}
+				log.Debugf("Purging lot %s (category %s); cache size is %d, low watermark is %d", lot.name, category, lc.cacheSize, lc.lowWater)
+				entries := lc.sortedEntries(func(entry *lruEntry) bool { return entry.lot == lot })
+				evictErr := lc.evictWhile(entries, start, func() bool {
+					return needed() && lot.inCategory(category, start)
+				})
+				if evictErr == purgeTimeout {
+					return evictErr
+				} else if evictErr != nil && err == nil {
+					err = evictErr
 				}
 			}
 		}
-		if rmErr := os.Remove(localPath); rmErr != nil {
-			log.Warningln("Failed to purge file:", rmErr)
-			if err == nil {
-				err = rmErr
-			}
-		} else {
-			log.Debugf("Successfully purged file %s", localPath)
-		}
-
-		// Remove from lruHeap as well
-		if lruIndex := lc.getHeapIndex(entry, lc.lru); lruIndex != -1 {
-			heap.Remove(&lc.lru, lruIndex)
-			delete(lc.lruLookup, entry.path)
-			log.Debugf("Purged file %s from both purge first and LRU heap", entry.path)
-		}
-
-		delete(lc.purgeFirstLookup, entry.path)
-		lc.cacheSize -= uint64(entry.size)
-
-		if time.Since(start) > 3*time.Second {
-			err = purgeTimeout
-			log.Warningln("Purge timeout while clearing purge first objects")
-			return
-		}
 	}
 
-	// Now purge from the main LRU heap if lowWater is still not reached
-	heap.Init(&lc.lru)
+	// Now purge from the main LRU if lowWater is still not reached
 	log.Debugf("Purging main cache; cache size is %d, low watermark is %d", lc.cacheSize, lc.lowWater)
-	for lc.cacheSize > lc.lowWater {
-		if len(lc.lru) == 0 {
-			err = errors.New("purge ran until cache was empty")
-			log.Warningln("Potential consistency error: purge ran until cache was empty")
-			break
-		}
-		entry := heap.Pop(&lc.lru).(*lruEntry)
-		if entry == nil {
-			log.Warningln("Consistency error: purge ran but no entry provided")
-			continue
-		}
-		if entry.path == "" {
-			log.Warningln("Consistency error: purge ran on an empty path")
-			continue
-		}
-
-		localPath := path.Join(lc.basePath, path.Clean(entry.path))
-		if rmErr := os.Remove(localPath + ".DONE"); rmErr != nil {
-			log.Warningln("Failed to purge DONE file:", rmErr)
-			if err == nil {
-				err = rmErr
-			}
-		}
-		if rmErr := os.Remove(localPath); rmErr != nil {
-			log.Warningln("Failed to purge file:", rmErr)
-			if err == nil {
-				err = rmErr
-			}
-		} else {
-			log.Debugf("Successfully purged file %s", localPath)
-		}
-		delete(lc.lruLookup, entry.path)
-		lc.cacheSize -= uint64(entry.size)
-
-		// Ensure purge does not block for too long
-		if time.Since(start) > 3*time.Second {
-			err = purgeTimeout
-			log.Warningln("Purge timeout while clearing main LRU heap")
-			break
-		}
+	evictErr := lc.evictWhile(lc.sortedEntries(nil), start, needed)
+	if evictErr == purgeTimeout {
+		return evictErr
+	} else if evictErr != nil && err == nil {
+		err = evictErr
+	}
+	if needed() {
+		err = errors.New("purge ran until all unpinned objects were evicted")
+		log.Warningln("Potential consistency error: purge ran until all unpinned objects were evicted")
 	}
-
 	return
 }
 
@@ -819,6 +858,12 @@ func (sc *LocalCache) Get(ctx context.Context, path, token string) (io.ReadClose
 		return fp, nil
 	}
 
+	if sc.peers != nil {
+		if fp := sc.peers.fetch(ctx, path, token); fp != nil {
+			return fp, nil
+		}
+	}
+
 	return sc.newCacheReader(ctx, path, token)
 
 }
@@ -1091,15 +1136,6 @@ func (lc *LocalCache) MarkObjectPurgeFirst(objectPath string) (int, error) {
 	return http.StatusOK, nil
 }
 
-func (lc *LocalCache) getHeapIndex(entry *lruEntry, heapList lru) int {
-	for i, e := range heapList {
-		if e == entry {
-			return i
-		}
-	}
-	return -1 // Entry not found
-}
-
 // ReconstructCache rebuilds the in-memory data structures of the LocalCache based on the files
 // present in the directory specified by "LocalCache.DataLocation".
 func (lc *LocalCache) ReconstructCache() error {
@@ -1114,6 +1150,12 @@ func (lc *LocalCache) ReconstructCache() error {
 	lc.purgeFirstHeap = nil
 	lc.purgeFirstLookup = make(map[string]*lruEntry)
 	lc.cacheSize = 0
+	if lc.lots != nil {
+		for _, lot := range lc.lots.lots {
+			lot.usage = 0
+			lot.objects = 0
+		}
+	}
 
 	// Scan basePath directory
 	err := filepath.WalkDir(lc.basePath, func(filePath string, d fs.DirEntry, err error) error {
@@ -1142,6 +1184,7 @@ func (lc *LocalCache) ReconstructCache() error {
 			lc.lruLookup[entry.path] = entry
 
 			lc.cacheSize += uint64(entry.size)
+			lc.addToLot(entry)
 
 			// Check if .PURGEFIRST sentinel exists
 			purgeFirstPath := dataFilePath + ".PURGEFIRST"
@@ -1149,6 +1192,41 @@ func (lc *LocalCache) ReconstructCache() error {
 				lc.purgeFirstHeap = append(lc.purgeFirstHeap, entry)
 				lc.purgeFirstLookup[entry.path] = entry
 			}
+
+			// Check if .PIN sentinel exists
+			if pinnedUntil, err := readPinSentinel(dataFilePath + ".PIN"); err == nil {
+				entry.pinnedUntil = pinnedUntil
+			} else if !os.IsNotExist(err) {
+				log.Warningf("Ignoring invalid pin for %s: %v", entry.path, err)
+			}
+		} else if strings.HasSuffix(d.Name(), ".BLOCKS") {
+			// A partially-cached object; skip it if the object was fully downloaded
+			dataFilePath := strings.TrimSuffix(filePath, ".BLOCKS")
+			if _, err := os.Stat(dataFilePath + ".DONE"); err == nil {
+				return nil
+			}
+			bi, err := readBlockInfo(filePath)
+			if err != nil {
+				log.Warningf("Ignoring invalid block info %s: %v", filePath, err)
+				return nil
+			}
+			fileInfo, err := os.Stat(filePath)
+			if err != nil {
+				return nil
+			}
+			entry := &lruEntry{
+				path:    strings.TrimPrefix(dataFilePath, lc.basePath),
+				size:    bi.cachedBytes(),
+				lastUse: fileInfo.ModTime(),
+				partial: true,
+			}
+			lc.lru = append(lc.lru, entry)
+			lc.lruLookup[entry.path] = entry
+			lc.cacheSize += uint64(entry.size)
+			lc.addToLot(entry)
+			if pinnedUntil, err := readPinSentinel(dataFilePath + ".PIN"); err == nil {
+				entry.pinnedUntil = pinnedUntil
+			}
 		}
 
 		return nil
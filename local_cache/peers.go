@@ -0,0 +1,670 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/metrics"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/token"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+type (
+	// A bloom filter of object paths, used as the digest of a local
+	// cache's inventory
+	bloomFilter struct {
+		K    uint32 `json:"k"`
+		Bits []byte `json:"bits"`
+	}
+
+	// The inventory digest published to peers
+	peerDigest struct {
+		Generated time.Time    `json:"generated"`
+		Objects   int          `json:"objects"`
+		Filter    *bloomFilter `json:"filter"`
+	}
+
+	// The UDP message a local cache announces itself with
+	peerAnnouncement struct {
+		URL        string `json:"url"`
+		Federation string `json:"federation"`
+	}
+
+	peerInfo struct {
+		url      string
+		static   bool      // Configured through LocalCache.Peers rather than discovered
+		lastSeen time.Time // The last announcement from a discovered peer
+		digest   *bloomFilter
+	}
+
+	// An in-progress fetch of an object from the peers
+	peerFetch struct {
+		done chan struct{}
+		ok   bool
+	}
+
+	// The peer local caches sharing objects with this one
+	peerSet struct {
+		lc           *LocalCache
+		selfURL      string
+		federation   string
+		client       *http.Client
+		timeout      time.Duration   // The longest a peer may take to send an object
+		allowedHosts map[string]bool // The hosts whose discovery announcements are accepted
+
+		// The public keys each peer signs its requests with
+		peerKeys *ttlcache.Cache[string, jwk.Set]
+
+		// Look up the size and checksums of an object in the federation,
+		// against which objects fetched from peers are verified
+		statObject func(ctx context.Context, objectPath, token string) (*client.FileInfo, error)
+
+		mutex sync.RWMutex
+		peers map[string]*peerInfo
+
+		digest      atomic.Pointer[[]byte] // The serialized digest of this cache
+		digestBuilt time.Time              // Only accessed from the cache's main goroutine
+
+		fetchMutex sync.Mutex
+		fetches    map[string]*peerFetch
+	}
+)
+
+const (
+	// Bloom filter sizing for a 1% false positive rate
+	bloomBitsPerObject = 9.6
+	bloomHashes        = 7
+
+	peerApiPrefix = "/api/v1.0/localcache/peer"
+
+	// The lifetime of the tokens a cache presents to its peers, and how long
+	// the keys of a peer are trusted before they are fetched again
+	peerTokenLifetime = 5 * time.Minute
+	peerKeysLifetime  = 10 * time.Minute
+)
+
+var (
+	errPeerNotFound = errors.New("object not found at peer")
+
+	crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func newBloomFilter(objects int) *bloomFilter {
+	bits := max(uint64(math.Ceil(float64(objects)*bloomBitsPerObject)), 64)
+	return &bloomFilter{K: bloomHashes, Bits: make([]byte, (bits+7)/8)}
+}
+
+// Compute the bit positions of the key via double hashing
+func (bf *bloomFilter) positions(key string) []uint64 {
+	h1 := fnv.New64a()
+	_, _ = h1.Write([]byte(key))
+	h2 := fnv.New64()
+	_, _ = h2.Write([]byte(key))
+	a, b := h1.Sum64(), h2.Sum64()|1
+	nbits := uint64(len(bf.Bits)) * 8
+	positions := make([]uint64, bf.K)
+	for idx := range positions {
+		positions[idx] = (a + uint64(idx)*b) % nbits
+	}
+	return positions
+}
+
+func (bf *bloomFilter) add(key string) {
+	for _, pos := range bf.positions(key) {
+		bf.Bits[pos/8] |= 1 << (pos % 8)
+	}
+}
+
+func (bf *bloomFilter) mayContain(key string) bool {
+	if len(bf.Bits) == 0 {
+		return false
+	}
+	for _, pos := range bf.positions(key) {
+		if bf.Bits[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func newPeerSet(lc *LocalCache, federation string) *peerSet {
+	ps := &peerSet{
+		lc:           lc,
+		selfURL:      strings.TrimSuffix(param.Server_ExternalWebUrl.GetString(), "/"),
+		federation:   federation,
+		client:       &http.Client{Transport: config.GetTransport()},
+		timeout:      param.LocalCache_PeerTimeout.GetDuration(),
+		allowedHosts: make(map[string]bool),
+		peerKeys:     ttlcache.New(ttlcache.WithTTL[string, jwk.Set](peerKeysLifetime), ttlcache.WithDisableTouchOnHit[string, jwk.Set]()),
+		peers:        make(map[string]*peerInfo),
+		fetches:      make(map[string]*peerFetch),
+	}
+	ps.statObject = ps.statFromFederation
+	for _, host := range param.LocalCache_PeerAllowedHosts.GetStringSlice() {
+		ps.allowedHosts[strings.ToLower(host)] = true
+	}
+	for _, peerURL := range param.LocalCache_Peers.GetStringSlice() {
+		parsed, err := parsePeerURL(peerURL)
+		if err != nil {
+			log.Warningf("Ignoring local cache peer %q: %v", peerURL, err)
+			continue
+		}
+		if peerURL = parsed.String(); peerURL != ps.selfURL {
+			ps.peers[peerURL] = &peerInfo{url: peerURL, static: true}
+		}
+	}
+	metrics.PelicanLocalCachePeers.Set(float64(len(ps.peers)))
+	return ps
+}
+
+// Parse the web URL of a peer, which must use https so that the peer's
+// identity is established by its certificate
+func parsePeerURL(peerURL string) (*url.URL, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(peerURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, errors.New("peers must have an https URL")
+	}
+	return parsed, nil
+}
+
+// Launch the peer discovery and digest refresh routines
+func (ps *peerSet) launch(ctx context.Context, egrp *errgroup.Group) error {
+	var conn *net.UDPConn
+	var discoveryAddr *net.UDPAddr
+	if addrStr := param.LocalCache_PeerDiscoveryAddress.GetString(); addrStr != "" {
+		var err error
+		if discoveryAddr, err = net.ResolveUDPAddr("udp4", addrStr); err != nil {
+			return errors.Wrapf(err, "invalid %s", param.LocalCache_PeerDiscoveryAddress.GetName())
+		}
+		if conn, err = net.ListenUDP("udp4", &net.UDPAddr{Port: discoveryAddr.Port}); err != nil {
+			return errors.Wrap(err, "failed to listen for local cache peer announcements")
+		}
+		egrp.Go(func() error {
+			<-ctx.Done()
+			return conn.Close()
+		})
+		egrp.Go(func() error {
+			ps.listen(ctx, conn)
+			return nil
+		})
+	}
+
+	egrp.Go(func() error {
+		interval := param.LocalCache_PeerDigestInterval.GetDuration()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if conn != nil {
+				ps.announce(conn, discoveryAddr)
+			}
+			ps.expire(3 * interval)
+			ps.refreshDigests(ctx)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+	log.Infof("Local cache peer sharing is enabled with %d configured peers", len(param.LocalCache_Peers.GetStringSlice()))
+	return nil
+}
+
+// Receive the announcements of other local caches
+func (ps *peerSet) listen(ctx context.Context, conn *net.UDPConn) {
+	buf := make([]byte, 4096)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warningln("Failed to receive local cache peer announcement:", err)
+			}
+			return
+		}
+		var ann peerAnnouncement
+		if err := json.Unmarshal(buf[:n], &ann); err != nil {
+			log.Debugln("Ignoring malformed local cache peer announcement:", err)
+			continue
+		}
+		ps.handleAnnouncement(ann)
+	}
+}
+
+func (ps *peerSet) announce(conn *net.UDPConn, addr *net.UDPAddr) {
+	data, err := json.Marshal(peerAnnouncement{URL: ps.selfURL, Federation: ps.federation})
+	if err != nil {
+		return
+	}
+	if _, err = conn.WriteToUDP(data, addr); err != nil {
+		log.Warningln("Failed to announce local cache to peers:", err)
+	}
+}
+
+// Record an announcement from a (possible) peer.  Announcements are not
+// authenticated, so only https peers in the same federation whose host is
+// allowed by LocalCache.PeerAllowedHosts are used.
+func (ps *peerSet) handleAnnouncement(ann peerAnnouncement) {
+	if ann.Federation != ps.federation {
+		return
+	}
+	parsed, err := parsePeerURL(ann.URL)
+	if err != nil {
+		log.Debugf("Ignoring announcement of local cache peer %q: %v", ann.URL, err)
+		return
+	}
+	peerURL := parsed.String()
+	if peerURL == ps.selfURL {
+		return
+	}
+	if !ps.allowedHosts[strings.ToLower(parsed.Hostname())] {
+		log.Debugf("Ignoring announcement of local cache peer %s, whose host is not in %s", peerURL, param.LocalCache_PeerAllowedHosts.GetName())
+		return
+	}
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	peer := ps.peers[peerURL]
+	if peer == nil {
+		log.Infoln("Discovered local cache peer", peerURL)
+		peer = &peerInfo{url: peerURL}
+		ps.peers[peerURL] = peer
+		metrics.PelicanLocalCachePeers.Set(float64(len(ps.peers)))
+	}
+	peer.lastSeen = time.Now()
+}
+
+// Forget discovered peers that have stopped announcing themselves
+func (ps *peerSet) expire(maxAge time.Duration) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	for peerURL, peer := range ps.peers {
+		if !peer.static && time.Since(peer.lastSeen) > maxAge {
+			log.Infoln("Forgetting local cache peer", peerURL)
+			delete(ps.peers, peerURL)
+		}
+	}
+	metrics.PelicanLocalCachePeers.Set(float64(len(ps.peers)))
+}
+
+// Download the digest of every peer
+func (ps *peerSet) refreshDigests(ctx context.Context) {
+	ps.mutex.RLock()
+	peerURLs := make([]string, 0, len(ps.peers))
+	for peerURL := range ps.peers {
+		peerURLs = append(peerURLs, peerURL)
+	}
+	ps.mutex.RUnlock()
+
+	for _, peerURL := range peerURLs {
+		digest, err := ps.fetchDigest(ctx, peerURL)
+		if err != nil {
+			log.Debugf("Failed to fetch the digest of local cache peer %s: %v", peerURL, err)
+		}
+		ps.mutex.Lock()
+		if peer := ps.peers[peerURL]; peer != nil {
+			// A peer without a digest is not used until it responds again
+			peer.digest = nil
+			if digest != nil {
+				peer.digest = digest.Filter
+			}
+		}
+		ps.mutex.Unlock()
+	}
+}
+
+// Create a request to the peer, authenticated with a token signed by this
+// cache; the tokens of the cache's own clients are never sent to peers
+func (ps *peerSet) newPeerRequest(ctx context.Context, peerURL, apiPath string) (*http.Request, error) {
+	tc := token.NewWLCGToken()
+	tc.Lifetime = peerTokenLifetime
+	tc.Issuer = ps.selfURL
+	tc.Subject = ps.selfURL
+	tc.AddAudiences(peerURL)
+	tc.AddScopes(token_scopes.Localcache_Peer)
+	tok, err := tc.CreateToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the token for the peer")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peerURL+peerApiPrefix+apiPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	return req, nil
+}
+
+// Verify that the request comes from a known peer: its token must be
+// signed with the keys published at the web URL of one of the peers
+func (ps *peerSet) authenticate(ctx context.Context, bearerToken string) error {
+	unverified, err := jwt.Parse([]byte(bearerToken), jwt.WithVerify(false))
+	if err != nil {
+		return errors.Wrap(err, "invalid token")
+	}
+	peerURL := unverified.Issuer()
+	ps.mutex.RLock()
+	known := ps.peers[peerURL] != nil
+	ps.mutex.RUnlock()
+	if !known {
+		return errors.Errorf("token issuer %q is not a known peer", peerURL)
+	}
+
+	var keys jwk.Set
+	if item := ps.peerKeys.Get(peerURL); item != nil {
+		keys = item.Value()
+	} else {
+		if keys, err = jwk.Fetch(ctx, peerURL+peerApiPrefix+"/jwks", jwk.WithHTTPClient(ps.client)); err != nil {
+			return errors.Wrapf(err, "failed to fetch the keys of peer %s", peerURL)
+		}
+		ps.peerKeys.Set(peerURL, keys, ttlcache.DefaultTTL)
+	}
+	_, err = jwt.Parse([]byte(bearerToken), jwt.WithKeySet(keys), jwt.WithValidate(true), jwt.WithIssuer(peerURL),
+		jwt.WithAudience(ps.selfURL), jwt.WithValidator(token_scopes.CreateScopeValidator([]token_scopes.TokenScope{token_scopes.Localcache_Peer}, false)))
+	return err
+}
+
+func (ps *peerSet) fetchDigest(ctx context.Context, peerURL string) (*peerDigest, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := ps.newPeerRequest(ctx, peerURL, "/digest")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ps.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("peer returned status %d", resp.StatusCode)
+	}
+	digest := &peerDigest{}
+	if err = json.NewDecoder(resp.Body).Decode(digest); err != nil {
+		return nil, errors.Wrap(err, "invalid digest")
+	}
+	if digest.Filter == nil {
+		return nil, errors.New("digest is missing its filter")
+	}
+	return digest, nil
+}
+
+// Rebuild the digest of the fully-cached objects if it is out of date.
+// Must be invoked from the cache's main goroutine, which owns the entries.
+func (ps *peerSet) updateDigest(entries map[string]*lruEntry) {
+	if time.Since(ps.digestBuilt) < param.LocalCache_PeerDigestInterval.GetDuration() {
+		return
+	}
+	ps.digestBuilt = time.Now()
+	digest := peerDigest{Generated: ps.digestBuilt, Filter: newBloomFilter(len(entries))}
+	for objectPath, entry := range entries {
+		if !entry.partial {
+			digest.Filter.add(objectPath)
+			digest.Objects++
+		}
+	}
+	data, err := json.Marshal(digest)
+	if err != nil {
+		log.Warningln("Failed to serialize the local cache digest:", err)
+		return
+	}
+	ps.digest.Store(&data)
+}
+
+// Return the peers whose digest contains the object
+func (ps *peerSet) candidates(objectPath string) (peerURLs []string) {
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+	for peerURL, peer := range ps.peers {
+		if peer.digest != nil && peer.digest.mayContain(objectPath) {
+			peerURLs = append(peerURLs, peerURL)
+		}
+	}
+	return
+}
+
+// Look up the object's size and CRC32C checksum through the director
+func (ps *peerSet) statFromFederation(ctx context.Context, objectPath, token string) (*client.FileInfo, error) {
+	dUrl := *ps.lc.directorURL
+	dUrl.Path = objectPath
+	dUrl.Scheme = "pelican"
+	return client.DoStat(ctx, dUrl.String(), client.WithToken(token), client.WithRequestChecksums([]client.ChecksumType{client.AlgCRC32C}))
+}
+
+// Fetch the object from a peer holding it, returning the cached object or
+// nil if no peer could provide it.  The client's token is only used to look
+// up the object's metadata in the federation, which the object is verified
+// against.  Concurrent requests for the same object share a single fetch.
+func (ps *peerSet) fetch(ctx context.Context, objectPath, token string) *os.File {
+	objectPath = path.Clean("/" + objectPath)
+	candidates := ps.candidates(objectPath)
+	if len(candidates) == 0 {
+		metrics.PelicanLocalCachePeerRequestsTotal.WithLabelValues(string(metrics.PeerMiss)).Inc()
+		return nil
+	}
+
+	ps.fetchMutex.Lock()
+	if inflight := ps.fetches[objectPath]; inflight != nil {
+		ps.fetchMutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-inflight.done:
+		}
+		if !inflight.ok {
+			return nil
+		}
+		return ps.lc.getFromDisk(objectPath)
+	}
+	current := &peerFetch{done: make(chan struct{})}
+	ps.fetches[objectPath] = current
+	ps.fetchMutex.Unlock()
+	defer func() {
+		ps.fetchMutex.Lock()
+		delete(ps.fetches, objectPath)
+		ps.fetchMutex.Unlock()
+		close(current.done)
+	}()
+
+	// Without metadata to verify against, the object comes from the origin
+	info, err := ps.statObject(ctx, objectPath, token)
+	if err != nil {
+		log.Debugf("Not fetching %s from local cache peers as its metadata is unavailable: %v", objectPath, err)
+		metrics.PelicanLocalCachePeerRequestsTotal.WithLabelValues(string(metrics.PeerError)).Inc()
+		return nil
+	}
+
+	result := metrics.PeerMiss
+	for _, peerURL := range candidates {
+		size, err := ps.fetchFrom(ctx, peerURL, objectPath, info)
+		if err == nil {
+			log.Debugf("Fetched %s (%d bytes) from local cache peer %s", objectPath, size, peerURL)
+			metrics.PelicanLocalCachePeerRequestsTotal.WithLabelValues(string(metrics.PeerHit)).Inc()
+			metrics.PelicanLocalCachePeerBytesReceivedTotal.Add(float64(size))
+			current.ok = true
+			ps.lc.hitChan <- lruEntry{lastUse: time.Now(), path: objectPath, size: size}
+			return ps.lc.getFromDisk(objectPath)
+		} else if !errors.Is(err, errPeerNotFound) {
+			log.Debugf("Failed to fetch %s from local cache peer %s: %v", objectPath, peerURL, err)
+			result = metrics.PeerError
+		}
+	}
+	metrics.PelicanLocalCachePeerRequestsTotal.WithLabelValues(string(result)).Inc()
+	return nil
+}
+
+// Download the object from the peer into the cache, returning its size.
+// The object is only committed to the cache if its size and checksum match
+// info.  Peers that don't send the object within the peer timeout are
+// abandoned.
+func (ps *peerSet) fetchFrom(ctx context.Context, peerURL, objectPath string, info *client.FileInfo) (size int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+	req, err := ps.newPeerRequest(ctx, peerURL, "/object"+objectPath)
+	if err != nil {
+		return
+	}
+	resp, err := ps.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, errPeerNotFound
+	} else if resp.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("peer returned status %d", resp.StatusCode)
+	}
+
+	localPath := filepath.Join(ps.lc.basePath, path.Clean(objectPath))
+	if err = os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		return
+	}
+	fp, err := os.CreateTemp(filepath.Dir(localPath), "."+filepath.Base(localPath)+".")
+	if err != nil {
+		return
+	}
+	defer os.Remove(fp.Name())
+	checksum := crc32.New(crc32cTable)
+	size, err = io.Copy(io.MultiWriter(fp, checksum), resp.Body)
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+	if size != info.Size {
+		return 0, errors.Errorf("peer sent %d bytes; the object has %d", size, info.Size)
+	}
+	expected := info.Checksums[client.HttpDigestFromChecksum(client.AlgCRC32C)]
+	if expected == "" {
+		return 0, errors.New("the federation did not report the object's checksum")
+	}
+	if actual := hex.EncodeToString(checksum.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return 0, errors.Errorf("peer sent an object with checksum %s; expected %s", actual, expected)
+	}
+	if err = os.Rename(fp.Name(), localPath); err != nil {
+		return
+	}
+	if err = os.WriteFile(localPath+".DONE", nil, 0600); err != nil {
+		return
+	}
+	if rmErr := os.Remove(localPath + ".BLOCKS"); rmErr != nil && !os.IsNotExist(rmErr) {
+		log.Warningln("Failed to remove block info of object fetched from peer:", rmErr)
+	}
+	return
+}
+
+// Abort the request unless it comes from a known peer
+func (lc *LocalCache) authorizePeer(ginCtx *gin.Context) bool {
+	bearerToken := strings.TrimPrefix(ginCtx.GetHeader("Authorization"), "Bearer ")
+	if err := lc.peers.authenticate(ginCtx.Request.Context(), bearerToken); err != nil {
+		log.Debugln("Rejecting local cache peer request:", err)
+		ginCtx.AbortWithStatusJSON(http.StatusForbidden, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Authorization Denied"})
+		return false
+	}
+	return true
+}
+
+// Serve the public keys this cache signs its peer requests with
+func (lc *LocalCache) peerKeysCmd(ginCtx *gin.Context) {
+	jwks, err := config.GetIssuerPublicJWKS()
+	if err != nil {
+		ginCtx.AbortWithStatusJSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Failed to load the cache's public keys"})
+		return
+	}
+	ginCtx.JSON(http.StatusOK, jwks)
+}
+
+// Serve the digest of this cache to peers
+func (lc *LocalCache) peerDigestCmd(ginCtx *gin.Context) {
+	if !lc.authorizePeer(ginCtx) {
+		return
+	}
+	data := lc.peers.digest.Load()
+	if data == nil {
+		ginCtx.AbortWithStatusJSON(http.StatusServiceUnavailable, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Digest is not yet available"})
+		return
+	}
+	ginCtx.Data(http.StatusOK, "application/json", *data)
+}
+
+// Serve a fully-cached object to a known peer, which authorizes its own
+// clients.  Objects not in the cache are never downloaded on behalf of a
+// peer.
+func (lc *LocalCache) peerObjectCmd(ginCtx *gin.Context) {
+	if !lc.authorizePeer(ginCtx) {
+		return
+	}
+	objectPath := path.Clean("/" + ginCtx.Param("path"))
+	fp := lc.getFromDisk(objectPath)
+	if fp == nil {
+		ginCtx.AbortWithStatusJSON(http.StatusNotFound, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Object is not in the cache"})
+		return
+	}
+	defer fp.Close()
+	finfo, err := fp.Stat()
+	if err != nil {
+		ginCtx.AbortWithStatusJSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed, Msg: "Failed to determine cached file size for object"})
+		return
+	}
+	lc.hitChan <- lruEntry{lastUse: time.Now(), path: objectPath, size: finfo.Size()}
+
+	ginCtx.Header("Content-Length", strconv.FormatInt(finfo.Size(), 10))
+	ginCtx.Status(http.StatusOK)
+	written, err := io.Copy(ginCtx.Writer, fp)
+	metrics.PelicanLocalCachePeerBytesServedTotal.Add(float64(written))
+	if err != nil {
+		log.Debugf("Failed to send %s to local cache peer: %v", objectPath, err)
+	}
+}
@@ -0,0 +1,259 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+func TestBloomFilter(t *testing.T) {
+	bf := newBloomFilter(1000)
+	for idx := 0; idx < 1000; idx++ {
+		bf.add(fmt.Sprintf("/test/object-%d", idx))
+	}
+	data, err := json.Marshal(bf)
+	require.NoError(t, err)
+	restored := &bloomFilter{}
+	require.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, bf, restored)
+
+	for idx := 0; idx < 1000; idx++ {
+		assert.True(t, restored.mayContain(fmt.Sprintf("/test/object-%d", idx)))
+	}
+	falsePositives := 0
+	for idx := 0; idx < 10000; idx++ {
+		if restored.mayContain(fmt.Sprintf("/other/object-%d", idx)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 300, "false positive rate should be near 1%")
+	assert.False(t, (&bloomFilter{}).mayContain("/test/object-1"))
+}
+
+// Create a local cache sharing objects with peers, with a single token
+// authorized to read the /test namespace
+func newPeerTestCache(t *testing.T) *LocalCache {
+	lc := &LocalCache{
+		basePath:         t.TempDir(),
+		hitChan:          make(chan lruEntry, 64),
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+		ac:               &authConfig{tokenAuthz: ttlcache.New[string, acls]()},
+	}
+	lc.ac.tokenAuthz.Set("good-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/test")}, ttlcache.NoTTL)
+	lc.peers = newPeerSet(lc, "https://federation.example.com")
+	return lc
+}
+
+// Serve the local cache's peer API over TLS, returning its URL
+func servePeerTestCache(t *testing.T, lc *LocalCache) *httptest.Server {
+	engine := gin.New()
+	lc.Register(context.Background(), engine.Group(""))
+	svr := httptest.NewTLSServer(engine)
+	t.Cleanup(svr.Close)
+	lc.peers.selfURL = svr.URL
+	lc.peers.client = svr.Client()
+	return svr
+}
+
+// Make the caches each other's peers
+func pairPeerTestCaches(caches ...*LocalCache) {
+	for _, lc := range caches {
+		for _, other := range caches {
+			if other != lc {
+				lc.peers.peers[other.peers.selfURL] = &peerInfo{url: other.peers.selfURL, static: true}
+			}
+		}
+	}
+}
+
+func TestPeerSharing(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, param.LocalCache_PeerDigestInterval.Set(time.Minute))
+	require.NoError(t, param.LocalCache_PeerTimeout.Set(time.Minute))
+	keysDir := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, param.IssuerKeysDirectory.Set(keysDir))
+	config.ResetIssuerPrivateKeys()
+	t.Cleanup(config.ResetIssuerPrivateKeys)
+	_, err := config.GeneratePEM(keysDir)
+	require.NoError(t, err)
+
+	// The serving peer holds two complete objects and a partially-cached
+	// object
+	serving := newPeerTestCache(t)
+	for objectPath, contents := range map[string]string{"/test/hello": "hello world", "/test/corrupt": "corrupted!"} {
+		localPath := filepath.Join(serving.basePath, objectPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+		require.NoError(t, os.WriteFile(localPath, []byte(contents), 0644))
+		require.NoError(t, os.WriteFile(localPath+".DONE", nil, 0644))
+	}
+	bi := newBlockInfo(1000, 100)
+	bi.set(0)
+	require.NoError(t, os.WriteFile(filepath.Join(serving.basePath, "test", "partial"), make([]byte, 1000), 0644))
+	require.NoError(t, writeBlockInfo(filepath.Join(serving.basePath, "test", "partial.BLOCKS"), bi))
+	require.NoError(t, serving.ReconstructCache())
+	serving.peers.updateDigest(serving.lruLookup)
+	servingSvr := servePeerTestCache(t, serving)
+
+	// The federation's metadata for the objects; the serving peer's copy of
+	// /test/corrupt differs from it
+	var statTokens []string
+	fetching := newPeerTestCache(t)
+	fetching.peers.statObject = func(_ context.Context, objectPath, token string) (*client.FileInfo, error) {
+		statTokens = append(statTokens, token)
+		contents := map[string]string{"/test/hello": "hello world", "/test/corrupt": "the object"}[objectPath]
+		checksum := fmt.Sprintf("%08x", crc32.Checksum([]byte(contents), crc32cTable))
+		return &client.FileInfo{Size: int64(len(contents)), Checksums: map[string]string{"crc32c": checksum}}, nil
+	}
+	servePeerTestCache(t, fetching)
+	pairPeerTestCaches(serving, fetching)
+
+	fetching.peers.refreshDigests(context.Background())
+	assert.Equal(t, []string{servingSvr.URL}, fetching.peers.candidates("/test/hello"))
+	assert.Empty(t, fetching.peers.candidates("/test/partial"), "partially-cached objects are not advertised")
+	assert.Empty(t, fetching.peers.candidates("/test/missing"))
+
+	fp := fetching.peers.fetch(context.Background(), "/test/hello", "good-token")
+	require.NotNil(t, fp)
+	contents, err := io.ReadAll(fp)
+	require.NoError(t, err)
+	require.NoError(t, fp.Close())
+	assert.Equal(t, "hello world", string(contents))
+	_, err = os.Stat(filepath.Join(fetching.basePath, "test", "hello.DONE"))
+	assert.NoError(t, err)
+	hit := <-fetching.hitChan
+	assert.Equal(t, int64(11), hit.size)
+	// The client's token is only used to look up the object's metadata
+	assert.Equal(t, []string{"good-token"}, statTokens)
+
+	// An object that doesn't match the federation's metadata is not kept
+	assert.Nil(t, fetching.peers.fetch(context.Background(), "/test/corrupt", "good-token"))
+	_, err = os.Stat(filepath.Join(fetching.basePath, "test", "corrupt"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, fetching.peers.fetch(context.Background(), "/test/missing", "good-token"))
+
+	// Peers only accept requests signed by a known peer
+	get := func(authorization string) int {
+		req, err := http.NewRequest(http.MethodGet, servingSvr.URL+peerApiPrefix+"/object/test/hello", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := servingSvr.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, get(""))
+	assert.Equal(t, http.StatusForbidden, get("Bearer good-token"))
+	req, err := fetching.peers.newPeerRequest(context.Background(), servingSvr.URL, "/object/test/hello")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get(req.Header.Get("Authorization")))
+	delete(serving.peers.peers, fetching.peers.selfURL)
+	assert.Equal(t, http.StatusForbidden, get(req.Header.Get("Authorization")))
+}
+
+// A peer that stalls while sending an object is abandoned once the peer
+// timeout expires, so the object comes from the origin instead
+func TestPeerTimeout(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.LocalCache_PeerTimeout.Set(200*time.Millisecond))
+	keysDir := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, param.IssuerKeysDirectory.Set(keysDir))
+	config.ResetIssuerPrivateKeys()
+	t.Cleanup(config.ResetIssuerPrivateKeys)
+	_, err := config.GeneratePEM(keysDir)
+	require.NoError(t, err)
+
+	stalled := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "11")
+		_, _ = w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(stalled.Close)
+
+	fetching := newPeerTestCache(t)
+	fetching.peers.selfURL = "https://fetching.example.com"
+	fetching.peers.client = stalled.Client()
+	fetching.peers.statObject = func(context.Context, string, string) (*client.FileInfo, error) {
+		checksum := fmt.Sprintf("%08x", crc32.Checksum([]byte("hello world"), crc32cTable))
+		return &client.FileInfo{Size: 11, Checksums: map[string]string{"crc32c": checksum}}, nil
+	}
+	digest := newBloomFilter(1)
+	digest.add("/test/hello")
+	fetching.peers.peers[stalled.URL] = &peerInfo{url: stalled.URL, static: true, digest: digest}
+
+	start := time.Now()
+	assert.Nil(t, fetching.peers.fetch(context.Background(), "/test/hello", "good-token"))
+	assert.Less(t, time.Since(start), 10*time.Second)
+	_, err = os.Stat(filepath.Join(fetching.basePath, "test", "hello.DONE"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPeerDiscovery(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.Server_ExternalWebUrl.Set("https://self.example.com"))
+	require.NoError(t, param.LocalCache_PeerAllowedHosts.Set([]string{"self.example.com", "peer.example.com"}))
+	require.NoError(t, param.LocalCache_Peers.Set([]string{"http://static.example.com", "https://static.example.com/"}))
+
+	lc := newPeerTestCache(t)
+	require.Len(t, lc.peers.peers, 1, "only https peers are configured")
+	assert.Contains(t, lc.peers.peers, "https://static.example.com")
+	delete(lc.peers.peers, "https://static.example.com")
+
+	lc.peers.handleAnnouncement(peerAnnouncement{URL: "https://self.example.com", Federation: lc.peers.federation})
+	lc.peers.handleAnnouncement(peerAnnouncement{URL: "https://peer.example.com", Federation: "https://other.example.com"})
+	lc.peers.handleAnnouncement(peerAnnouncement{URL: "http://peer.example.com", Federation: lc.peers.federation})
+	lc.peers.handleAnnouncement(peerAnnouncement{URL: "https://stranger.example.com", Federation: lc.peers.federation})
+	lc.peers.handleAnnouncement(peerAnnouncement{URL: "https://peer.example.com/", Federation: lc.peers.federation})
+	require.Len(t, lc.peers.peers, 1)
+	assert.Contains(t, lc.peers.peers, "https://peer.example.com")
+
+	lc.peers.expire(time.Hour)
+	assert.Len(t, lc.peers.peers, 1)
+	time.Sleep(10 * time.Millisecond)
+	lc.peers.expire(time.Millisecond)
+	assert.Empty(t, lc.peers.peers)
+}
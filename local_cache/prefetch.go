@@ -0,0 +1,265 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"math"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+type (
+	// A manifest of objects to download into the cache ahead of their use
+	PrefetchRequest struct {
+		Objects     []string `json:"objects"`
+		Prefixes    []string `json:"prefixes"`              // Listed recursively; every object found is prefetched
+		Token       string   `json:"token,omitempty"`       // Token used to read the objects from the federation
+		PinLifetime string   `json:"pinLifetime,omitempty"` // If set, each prefetched object is pinned for this duration
+	}
+
+	PrefetchFailure struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}
+
+	PrefetchStatus struct {
+		ID        string            `json:"id"`
+		State     PrefetchState     `json:"state"`
+		Created   time.Time         `json:"created"`
+		Completed *time.Time        `json:"completed,omitempty"`
+		Total     int               `json:"total"`     // Number of objects to prefetch; grows while prefixes are listed
+		Succeeded int               `json:"succeeded"` // Number of objects now in the cache
+		Failed    int               `json:"failed"`
+		Bytes     int64             `json:"bytes"` // Size of the objects now in the cache
+		Failures  []PrefetchFailure `json:"failures"`
+	}
+
+	PrefetchState string
+
+	prefetchJob struct {
+		mutex  sync.Mutex
+		status PrefetchStatus
+	}
+)
+
+const (
+	PrefetchListing   PrefetchState = "listing"
+	PrefetchRunning   PrefetchState = "running"
+	PrefetchCompleted PrefetchState = "completed"
+)
+
+func newPrefetchJobs() *ttlcache.Cache[string, *prefetchJob] {
+	return ttlcache.New[string, *prefetchJob](ttlcache.WithTTL[string, *prefetchJob](24 * time.Hour))
+}
+
+func (job *prefetchJob) getStatus() PrefetchStatus {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	status := job.status
+	status.Failures = slices.Clone(job.status.Failures)
+	return status
+}
+
+func (job *prefetchJob) update(fn func(status *PrefetchStatus)) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	fn(&job.status)
+}
+
+// Start prefetching the objects in the manifest, returning the initial
+// status of the prefetch job
+func (lc *LocalCache) Prefetch(request PrefetchRequest) (status PrefetchStatus, err error) {
+	var pinLifetime time.Duration
+	if request.PinLifetime != "" {
+		if pinLifetime, err = time.ParseDuration(request.PinLifetime); err != nil {
+			err = errors.Wrap(err, "invalid pin lifetime")
+			return
+		}
+	}
+	if len(request.Objects) == 0 && len(request.Prefixes) == 0 {
+		err = errors.New("prefetch manifest is empty")
+		return
+	}
+
+	job := &prefetchJob{status: PrefetchStatus{
+		ID:       uuid.NewString(),
+		State:    PrefetchListing,
+		Created:  time.Now(),
+		Failures: make([]PrefetchFailure, 0),
+	}}
+	lc.prefetchJobs.Set(job.status.ID, job, ttlcache.DefaultTTL)
+	status = job.getStatus()
+
+	lc.egrp.Go(func() error {
+		lc.runPrefetch(lc.ctx, job, request, pinLifetime)
+		return nil
+	})
+	return
+}
+
+// Return the status of a prefetch job
+func (lc *LocalCache) PrefetchStatus(id string) (status PrefetchStatus, ok bool) {
+	item := lc.prefetchJobs.Get(id)
+	if item == nil {
+		return
+	}
+	return item.Value().getStatus(), true
+}
+
+func (lc *LocalCache) runPrefetch(ctx context.Context, job *prefetchJob, request PrefetchRequest, pinLifetime time.Duration) {
+	log.Infof("Starting prefetch job %s of %d objects and %d prefixes", job.status.ID, len(request.Objects), len(request.Prefixes))
+	egrp, ctx := errgroup.WithContext(ctx)
+	egrp.SetLimit(max(param.LocalCache_PrefetchConcurrency.GetInt(), 1))
+
+	queued := make(map[string]bool)
+	enqueue := func(objectPath string) {
+		objectPath = path.Clean("/" + objectPath)
+		if queued[objectPath] {
+			return
+		}
+		queued[objectPath] = true
+		job.update(func(status *PrefetchStatus) { status.Total++ })
+		egrp.Go(func() error {
+			size, err := lc.prefetchObject(ctx, objectPath, request.Token, pinLifetime)
+			job.update(func(status *PrefetchStatus) {
+				if err != nil {
+					status.Failed++
+					status.Failures = append(status.Failures, PrefetchFailure{Path: objectPath, Error: err.Error()})
+				} else {
+					status.Succeeded++
+					status.Bytes += size
+				}
+			})
+			if err != nil {
+				log.Debugf("Failed to prefetch %s: %v", objectPath, err)
+			}
+			return nil
+		})
+	}
+
+	for _, objectPath := range request.Objects {
+		enqueue(objectPath)
+	}
+	for _, prefix := range request.Prefixes {
+		listUrl := *lc.directorURL
+		listUrl.Path = path.Clean("/" + prefix)
+		listUrl.Scheme = "pelican"
+		infos, err := client.DoList(ctx, listUrl.String(), client.WithToken(request.Token), client.WithRecursive(true))
+		if err != nil {
+			job.update(func(status *PrefetchStatus) {
+				status.Failed++
+				status.Failures = append(status.Failures, PrefetchFailure{Path: listUrl.Path, Error: err.Error()})
+			})
+			log.Debugf("Failed to list prefix %s for prefetch: %v", listUrl.Path, err)
+			continue
+		}
+		for _, info := range infos {
+			if !info.IsCollection {
+				enqueue(info.Name)
+			}
+		}
+	}
+	job.update(func(status *PrefetchStatus) { status.State = PrefetchRunning })
+
+	_ = egrp.Wait()
+	job.update(func(status *PrefetchStatus) {
+		completed := time.Now()
+		status.State = PrefetchCompleted
+		status.Completed = &completed
+	})
+	final := job.getStatus()
+	log.Infof("Prefetch job %s completed: %d of %d objects cached (%d bytes), %d failures", final.ID, final.Succeeded, final.Total, final.Bytes, final.Failed)
+}
+
+// Download a single object into the cache through the transfer client,
+// returning its size once it is fully cached.  As with Get, the token must
+// authorize reading the object even if it is already in the cache.
+func (lc *LocalCache) prefetchObject(ctx context.Context, objectPath, token string, pinLifetime time.Duration) (size int64, err error) {
+	if !lc.ac.authorize(token_scopes.Wlcg_Storage_Read, objectPath, token) {
+		return 0, authorizationDenied
+	}
+
+	if fp := lc.getFromDisk(objectPath); fp != nil {
+		finfo, statErr := fp.Stat()
+		fp.Close()
+		if statErr != nil {
+			return 0, statErr
+		}
+		size = finfo.Size()
+		lc.hitChan <- lruEntry{lastUse: time.Now(), path: objectPath, size: size}
+	} else {
+		// The waiter is only notified once the download completes or fails
+		results := make(chan *downloadStatus, 1)
+		sizeReq := availSizeReq{
+			ctx:     ctx,
+			request: req{id: uuid.New(), path: objectPath, token: token},
+			size:    math.MaxInt64,
+			results: results,
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case lc.sizeReq <- sizeReq:
+		}
+		var ds *downloadStatus
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case ds = <-results:
+		}
+		if ds == nil {
+			return 0, errors.New("internal error - cache sent a nil result")
+		}
+		if dlErr := ds.err.Load(); dlErr != nil && *dlErr != nil {
+			return 0, *dlErr
+		}
+		size = ds.size.Load()
+	}
+
+	if pinLifetime > 0 {
+		// Wait for the cache to record the new object before pinning it
+		for attempt := 0; ; attempt++ {
+			if _, err = lc.PinObject(objectPath, pinLifetime); err == nil || attempt >= 10 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return size, ctx.Err()
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+		if err != nil {
+			err = errors.Wrap(err, "object was cached but could not be pinned")
+		}
+	}
+	return
+}
@@ -0,0 +1,150 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+func TestPrefetch(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.LocalCache_PrefetchConcurrency.Set(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	egrp, ctx := errgroup.WithContext(ctx)
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, egrp.Wait())
+	})
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		ctx:              ctx,
+		egrp:             egrp,
+		basePath:         dataDir,
+		highWater:        100000,
+		lowWater:         50000,
+		hitChan:          make(chan lruEntry, 64),
+		sizeReq:          make(chan availSizeReq),
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+		prefetchJobs:     newPrefetchJobs(),
+		ac:               &authConfig{tokenAuthz: ttlcache.New[string, acls]()},
+	}
+	lc.ac.tokenAuthz.Set("good-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/test")}, ttlcache.NoTTL)
+	lc.ac.tokenAuthz.Set("other-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/other")}, ttlcache.NoTTL)
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "test"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "test", "cached"), []byte("cached"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "test", "cached.DONE"), nil, 0644))
+	require.NoError(t, lc.ReconstructCache())
+
+	// Stand in for the cache's main goroutine, "downloading" every object
+	// except for one
+	downloads := make(chan string, 10)
+	egrp.Go(func() error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-lc.hitChan:
+			case req := <-lc.sizeReq:
+				downloads <- req.request.path
+				ds := &downloadStatus{}
+				if req.request.path == "/test/fail" {
+					err := errors.New("object not found")
+					ds.err.Store(&err)
+				} else {
+					localPath := filepath.Join(dataDir, req.request.path)
+					require.NoError(t, os.WriteFile(localPath, []byte("contents"), 0644))
+					require.NoError(t, os.WriteFile(localPath+".DONE", nil, 0644))
+					ds.size.Store(8)
+					lc.lruHit(lruEntry{lastUse: time.Now(), path: req.request.path, size: 8})
+				}
+				ds.done.Store(true)
+				req.results <- ds
+			}
+		}
+	})
+
+	_, err := lc.Prefetch(PrefetchRequest{})
+	assert.ErrorContains(t, err, "empty")
+	_, err = lc.Prefetch(PrefetchRequest{Objects: []string{"/test/a"}, PinLifetime: "forever"})
+	assert.ErrorContains(t, err, "pin lifetime")
+
+	prefetch := func(request PrefetchRequest) PrefetchStatus {
+		status, err := lc.Prefetch(request)
+		require.NoError(t, err)
+		if status.State != PrefetchCompleted {
+			assert.Nil(t, status.Completed)
+		}
+		require.Eventually(t, func() bool {
+			status, _ = lc.PrefetchStatus(status.ID)
+			return status.State == PrefetchCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NotNil(t, status.Completed)
+		assert.False(t, status.Completed.Before(status.Created))
+		return status
+	}
+
+	// A token that cannot read the objects may neither download nor pin
+	// them, even those already in the cache
+	status := prefetch(PrefetchRequest{
+		Objects:     []string{"/test/a", "/test/cached"},
+		Token:       "other-token",
+		PinLifetime: "1h",
+	})
+	assert.Equal(t, 0, status.Succeeded)
+	assert.Equal(t, 2, status.Failed)
+	assert.Empty(t, downloads)
+	assert.False(t, lc.lruLookup["/test/cached"].pinned(time.Now()))
+
+	status = prefetch(PrefetchRequest{
+		Objects:     []string{"/test/a", "test/b", "/test/fail", "/test/a", "/test/cached"},
+		Token:       "good-token",
+		PinLifetime: "1h",
+	})
+
+	assert.Equal(t, 4, status.Total, "duplicate objects are only prefetched once")
+	assert.Equal(t, 3, status.Succeeded)
+	assert.Equal(t, int64(22), status.Bytes)
+	assert.Equal(t, 1, status.Failed)
+	require.Len(t, status.Failures, 1)
+	assert.Equal(t, "/test/fail", status.Failures[0].Path)
+	assert.Len(t, downloads, 3, "cached objects are not downloaded again")
+	for _, objectPath := range []string{"/test/a", "/test/b", "/test/cached"} {
+		assert.True(t, lc.lruLookup[objectPath].pinned(time.Now()), objectPath)
+	}
+
+	_, ok := lc.PrefetchStatus("missing")
+	assert.False(t, ok)
+}
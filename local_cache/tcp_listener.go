@@ -0,0 +1,306 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/xml"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/client"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+type (
+	// A cached object, as reported by the inventory API
+	inventoryObject struct {
+		Path        string     `json:"path"`
+		Size        int64      `json:"size"`
+		LastUse     time.Time  `json:"lastUse"`
+		PurgeFirst  bool       `json:"purgeFirst"`
+		Partial     bool       `json:"partial"`
+		PinnedUntil *time.Time `json:"pinnedUntil,omitempty"`
+		Lot         string     `json:"lot,omitempty"`
+	}
+
+	cacheInventory struct {
+		Size      uint64            `json:"size"`
+		HighWater uint64            `json:"highWater"`
+		LowWater  uint64            `json:"lowWater"`
+		Objects   []inventoryObject `json:"objects"`
+	}
+
+	// The WebDAV response to a PROPFIND request
+	davMultistatus struct {
+		XMLName   xml.Name      `xml:"D:multistatus"`
+		XmlnsD    string        `xml:"xmlns:D,attr"`
+		Responses []davResponse `xml:"D:response"`
+	}
+
+	davResponse struct {
+		Href     string      `xml:"D:href"`
+		Propstat davPropstat `xml:"D:propstat"`
+	}
+
+	davPropstat struct {
+		Prop   davProp `xml:"D:prop"`
+		Status string  `xml:"D:status"`
+	}
+
+	davProp struct {
+		ResourceType  davResourceType `xml:"D:resourcetype"`
+		ContentLength *int64          `xml:"D:getcontentlength,omitempty"`
+		LastModified  string          `xml:"D:getlastmodified,omitempty"`
+		ETag          string          `xml:"D:getetag,omitempty"`
+	}
+
+	davResourceType struct {
+		Collection *struct{} `xml:"D:collection,omitempty"`
+	}
+)
+
+const inventoryApiPath = "/api/v1.0/localcache/status"
+
+// Launch the optional TCP listener; unlike the unix socket, it also serves
+// directory listings and the inventory of the cache
+func (lc *LocalCache) LaunchTCPListener(ctx context.Context, egrp *errgroup.Group) (err error) {
+	addr := param.LocalCache_ListenAddress.GetString()
+	if addr == "" {
+		return
+	}
+
+	certFile := param.LocalCache_TLSCertificate.GetString()
+	keyFile := param.LocalCache_TLSKey.GetString()
+	var tlsConfig *tls.Config
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return errors.Errorf("both %s and %s must be set to serve TLS", param.LocalCache_TLSCertificate.GetName(), param.LocalCache_TLSKey.GetName())
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to load the local cache's TLS certificate")
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to create TCP listener for local cache")
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	srv := http.Server{
+		Handler: http.HandlerFunc(lc.serveTCP),
+	}
+	egrp.Go(func() error {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	egrp.Go(func() error {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		return nil
+	})
+	log.Infof("Local cache listening at %s (TLS: %t)", listener.Addr(), tlsConfig != nil)
+	return
+}
+
+func (lc *LocalCache) serveTCP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case path.Clean(r.URL.Path) == inventoryApiPath:
+		lc.serveInventory(w, r)
+	case r.Method == "PROPFIND":
+		lc.serveListing(w, r)
+	default:
+		lc.serveObject(w, r)
+	}
+}
+
+func getBearerToken(r *http.Request) string {
+	if authzHeader := r.Header.Get("Authorization"); strings.HasPrefix(authzHeader, "Bearer ") {
+		return authzHeader[7:] // len("Bearer ") == 7
+	}
+	return ""
+}
+
+// Return the objects in the cache under the prefix that the token may read
+func (lc *LocalCache) Inventory(prefix, token string) (inventory cacheInventory) {
+	prefix = path.Clean("/" + prefix)
+	dirPrefix := strings.TrimSuffix(prefix, "/") + "/"
+
+	// Copy the matching entries under the mutex; checking the token may
+	// require verifying it, which must not block the cache's bookkeeping
+	lc.mutex.RLock()
+	inventory = cacheInventory{
+		Size:      lc.cacheSize,
+		HighWater: lc.highWater,
+		LowWater:  lc.lowWater,
+		Objects:   make([]inventoryObject, 0),
+	}
+	var candidates []inventoryObject
+	for objectPath, entry := range lc.lruLookup {
+		if objectPath != prefix && !strings.HasPrefix(objectPath, dirPrefix) {
+			continue
+		}
+		_, purgeFirst := lc.purgeFirstLookup[objectPath]
+		obj := inventoryObject{
+			Path:       objectPath,
+			Size:       entry.size,
+			LastUse:    entry.lastUse,
+			PurgeFirst: purgeFirst,
+			Partial:    entry.partial,
+		}
+		if !entry.pinnedUntil.IsZero() {
+			pinnedUntil := entry.pinnedUntil
+			obj.PinnedUntil = &pinnedUntil
+		}
+		if entry.lot != nil {
+			obj.Lot = entry.lot.name
+		}
+		candidates = append(candidates, obj)
+	}
+	lc.mutex.RUnlock()
+
+	for _, obj := range candidates {
+		if lc.ac.authorize(token_scopes.Wlcg_Storage_Read, obj.Path, token) {
+			inventory.Objects = append(inventory.Objects, obj)
+		}
+	}
+	slices.SortFunc(inventory.Objects, func(a, b inventoryObject) int { return strings.Compare(a.Path, b.Path) })
+	return
+}
+
+// Serve the inventory of the cache
+func (lc *LocalCache) serveInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	prefix := r.URL.Query().Get("prefix")
+	data, err := json.Marshal(lc.Inventory(prefix, getBearerToken(r)))
+	if err != nil {
+		log.Errorln("Failed to serialize the local cache inventory:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		log.Debugln("Failed to write the local cache inventory to client:", err)
+	}
+}
+
+func newDavResponse(info client.FileInfo) davResponse {
+	resp := davResponse{
+		Href:     info.Name,
+		Propstat: davPropstat{Status: "HTTP/1.1 200 OK"},
+	}
+	if info.IsCollection {
+		resp.Propstat.Prop.ResourceType.Collection = &struct{}{}
+		if !strings.HasSuffix(resp.Href, "/") {
+			resp.Href += "/"
+		}
+	} else {
+		size := info.Size
+		resp.Propstat.Prop.ContentLength = &size
+	}
+	if !info.ModTime.IsZero() {
+		resp.Propstat.Prop.LastModified = info.ModTime.UTC().Format(http.TimeFormat)
+	}
+	resp.Propstat.Prop.ETag = info.ETag
+	return resp
+}
+
+// Pass a PROPFIND request through to the federation, returning the listing
+// as a WebDAV multistatus response.  Depths of 0 and 1 are supported.
+func (lc *LocalCache) serveListing(w http.ResponseWriter, r *http.Request) {
+	objectPath := path.Clean(r.URL.Path)
+	bearerToken := getBearerToken(r)
+	if !lc.ac.authorize(token_scopes.Wlcg_Storage_Read, objectPath, bearerToken) {
+		w.WriteHeader(http.StatusForbidden)
+		if _, err := w.Write([]byte("Authorization Denied")); err != nil {
+			log.Errorln("Failed to write authorization denied to client")
+		}
+		return
+	}
+	depth := r.Header.Get("Depth")
+	if depth != "" && depth != "0" && depth != "1" {
+		w.WriteHeader(http.StatusForbidden)
+		if _, err := w.Write([]byte("Only listings of depth 0 or 1 are supported")); err != nil {
+			log.Errorln("Failed to write depth error to client")
+		}
+		return
+	}
+
+	dUrl := *lc.directorURL
+	dUrl.Path = objectPath
+	dUrl.Scheme = "pelican"
+	statInfo, err := client.DoStat(r.Context(), dUrl.String(), client.WithToken(bearerToken))
+	var listing []client.FileInfo
+	if err == nil && statInfo.IsCollection && depth != "0" {
+		listing, err = client.DoList(r.Context(), dUrl.String(), client.WithToken(bearerToken))
+	}
+	if err != nil {
+		log.Debugf("Failed to list %s: %v", objectPath, err)
+		var sce *client.StatusCodeError
+		if errors.As(err, &sce) {
+			w.WriteHeader(int(*sce))
+		} else {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		return
+	}
+
+	statInfo.Name = objectPath
+	multistatus := davMultistatus{XmlnsD: "DAV:", Responses: []davResponse{newDavResponse(*statInfo)}}
+	for _, info := range listing {
+		multistatus.Responses = append(multistatus.Responses, newDavResponse(info))
+	}
+	data, err := xml.Marshal(multistatus)
+	if err != nil {
+		log.Errorln("Failed to serialize listing:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	if _, err = w.Write(append([]byte(xml.Header), data...)); err != nil {
+		log.Debugln("Failed to write listing to client:", err)
+	}
+}
@@ -0,0 +1,177 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package local_cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+func TestInventory(t *testing.T) {
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		highWater:        10000,
+		lowWater:         5000,
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+		ac:               &authConfig{tokenAuthz: ttlcache.New[string, acls]()},
+	}
+	lc.ac.tokenAuthz.Set("good-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/test")}, ttlcache.NoTTL)
+	for _, objectPath := range []string{"/test/a", "/test/sub/b", "/private/c"} {
+		localPath := filepath.Join(dataDir, objectPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+		require.NoError(t, os.WriteFile(localPath, []byte("data"), 0644))
+		require.NoError(t, os.WriteFile(localPath+".DONE", nil, 0644))
+	}
+	require.NoError(t, lc.ReconstructCache())
+	_, err := lc.MarkObjectPurgeFirst("/test/sub/b")
+	require.NoError(t, err)
+
+	svr := httptest.NewServer(http.HandlerFunc(lc.serveTCP))
+	t.Cleanup(svr.Close)
+	getInventory := func(query, token string) (inventory cacheInventory) {
+		req, err := http.NewRequest(http.MethodGet, svr.URL+inventoryApiPath+query, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&inventory))
+		return
+	}
+
+	// Only the objects readable with the token are listed
+	inventory := getInventory("", "good-token")
+	assert.Equal(t, uint64(12), inventory.Size)
+	assert.Equal(t, uint64(10000), inventory.HighWater)
+	require.Len(t, inventory.Objects, 2)
+	assert.Equal(t, "/test/a", inventory.Objects[0].Path)
+	assert.False(t, inventory.Objects[0].PurgeFirst)
+	assert.Equal(t, "/test/sub/b", inventory.Objects[1].Path)
+	assert.Equal(t, int64(4), inventory.Objects[1].Size)
+	assert.True(t, inventory.Objects[1].PurgeFirst)
+
+	inventory = getInventory("?prefix=/test/sub", "good-token")
+	require.Len(t, inventory.Objects, 1)
+	assert.Equal(t, "/test/sub/b", inventory.Objects[0].Path)
+	assert.Empty(t, getInventory("?prefix=/tes", "good-token").Objects)
+	assert.Empty(t, getInventory("", "").Objects)
+
+	// Listings are authorized before contacting the federation
+	for token, depth := range map[string]string{"": "1", "good-token": "infinity"} {
+		req, err := http.NewRequest("PROPFIND", svr.URL+"/test", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Depth", depth)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+
+	// Methods other than GET and HEAD are not served for objects
+	resp, err := http.Post(svr.URL+"/test/a", "text/plain", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+// Inventories are safe to take while the cache is evicting objects; run
+// with -race to detect unsynchronized access to the cache's bookkeeping
+func TestInventoryDuringEviction(t *testing.T) {
+	dataDir := t.TempDir()
+	lc := &LocalCache{
+		basePath:         dataDir,
+		highWater:        4000,
+		lowWater:         2000,
+		lruLookup:        make(map[string]*lruEntry),
+		purgeFirstLookup: make(map[string]*lruEntry),
+		ac:               &authConfig{tokenAuthz: ttlcache.New[string, acls]()},
+	}
+	lc.ac.tokenAuthz.Set("good-token", acls{token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Read, "/test")}, ttlcache.NoTTL)
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "test"), 0755))
+
+	// Stand in for runMux, recording new objects and purging old ones
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for idx := 0; idx < 200; idx++ {
+			objectPath := fmt.Sprintf("/test/obj%d", idx)
+			localPath := filepath.Join(dataDir, objectPath)
+			if !assert.NoError(t, os.WriteFile(localPath, make([]byte, 1000), 0644)) {
+				return
+			}
+			lc.lruHit(lruEntry{lastUse: time.Now(), path: objectPath, size: 1000})
+		}
+	}()
+
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		inventory := lc.Inventory("/test", "good-token")
+		assert.LessOrEqual(t, len(inventory.Objects), 5)
+	}
+	inventory := lc.Inventory("/test", "good-token")
+	assert.NotEmpty(t, inventory.Objects)
+	assert.LessOrEqual(t, inventory.Size, lc.highWater)
+}
+
+func TestTCPListenerConfig(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	ctx, cancel := context.WithCancel(context.Background())
+	egrp, ctx := errgroup.WithContext(ctx)
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, egrp.Wait())
+	})
+
+	lc := &LocalCache{}
+	require.NoError(t, lc.LaunchTCPListener(ctx, egrp), "the listener is disabled by default")
+
+	require.NoError(t, param.LocalCache_ListenAddress.Set("127.0.0.1:0"))
+	require.NoError(t, param.LocalCache_TLSCertificate.Set(filepath.Join(t.TempDir(), "cert.pem")))
+	assert.ErrorContains(t, lc.LaunchTCPListener(ctx, egrp), "LocalCache.TLSKey")
+
+	require.NoError(t, param.LocalCache_TLSCertificate.Set(""))
+	require.NoError(t, lc.LaunchTCPListener(ctx, egrp))
+}
@@ -29,7 +29,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -39,10 +38,8 @@ import (
 	"unsafe"
 
 	"github.com/ebitengine/purego"
-	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/param"
@@ -82,80 +79,6 @@ var (
 )
 
 type (
-	Int64FromFloat struct {
-		Value int64 `mapstructure:"Value"`
-	}
-
-	LotPath struct {
-		Path      string `json:"path" mapstructure:"Path"`
-		Recursive bool   `json:"recursive" mapstructure:"Recursive"`
-		LotName   string `json:"lot_name,omitempty"` // Not used when creating lots, but some queries will populate the field
-	}
-
-	LotValueMapInt struct {
-		LotName string         `json:"lot_name"`
-		Value   Int64FromFloat `json:"value"`
-	}
-
-	LotValueMapFloat struct {
-		LotName string  `json:"lot_name"`
-		Value   float64 `json:"value"`
-	}
-
-	MPA struct {
-		DedicatedGB     *float64        `json:"dedicated_GB,omitempty" mapstructure:"DedicatedGB"`
-		OpportunisticGB *float64        `json:"opportunistic_GB,omitempty" mapstructure:"OpportunisticGB"`
-		MaxNumObjects   *Int64FromFloat `json:"max_num_objects,omitempty" mapstructure:"MaxNumObjects"`
-		CreationTime    *Int64FromFloat `json:"creation_time,omitempty" mapstructure:"CreationTime"`
-		ExpirationTime  *Int64FromFloat `json:"expiration_time,omitempty" mapstructure:"ExpirationTime"`
-		DeletionTime    *Int64FromFloat `json:"deletion_time,omitempty" mapstructure:"DeletionTime"`
-	}
-
-	RestrictiveMPA struct {
-		DedicatedGB     LotValueMapFloat `json:"dedicated_GB"`
-		OpportunisticGB LotValueMapFloat `json:"opportunistic_GB"`
-		MaxNumObjects   LotValueMapInt   `json:"max_num_objects"`
-		CreationTime    LotValueMapInt   `json:"creation_time"`
-		ExpirationTime  LotValueMapInt   `json:"expiration_time"`
-		DeletionTime    LotValueMapInt   `json:"deletion_time"`
-	}
-
-	UsageMapFloat struct {
-		SelfContrib     float64 `json:"self_contrib,omitempty"`
-		ChildrenContrib float64 `json:"children_contrib,omitempty"`
-		Total           float64 `json:"total"`
-	}
-
-	UsageMapInt struct {
-		SelfContrib     Int64FromFloat `json:"self_contrib,omitempty"`
-		ChildrenContrib Int64FromFloat `json:"children_contrib,omitempty"`
-		Total           Int64FromFloat `json:"total"`
-	}
-
-	LotUsage struct {
-		GBBeingWritten      UsageMapFloat `json:"GB_being_written,omitempty"`
-		ObjectsBeingWritten UsageMapInt   `json:"objects_being_written,omitempty"`
-		DedicatedGB         UsageMapFloat `json:"dedicated_GB,omitempty"`
-		OpportunisticGB     UsageMapFloat `json:"opportunistic_GB,omitempty"`
-		NumObjects          UsageMapInt   `json:"num_objects,omitempty"`
-		TotalGB             UsageMapFloat `json:"total_GB,omitempty"`
-	}
-
-	Lot struct {
-		LotName string `json:"lot_name" mapstructure:"LotName"`
-		Owner   string `json:"owner,omitempty" mapstructure:"Owner"`
-		// We don't expose Owners via map structure because that's not something we can configure. It's a derived value
-		Owners  []string `json:"owners,omitempty"`
-		Parents []string `json:"parents" mapstructure:"Parents"`
-		// While we _could_ expose Children, that complicates things so for now we keep it hidden from the config
-		Children *[]string `json:"children,omitempty"`
-		Paths    []LotPath `json:"paths,omitempty" mapstructure:"Paths"`
-		MPA      *MPA      `json:"management_policy_attrs,omitempty" mapstructure:"ManagementPolicyAttrs"`
-		// Again, these are derived
-		RestrictiveMPA *RestrictiveMPA `json:"restrictive_management_policy_attrs,omitempty"`
-		Usage          *LotUsage       `json:"usage,omitempty"`
-	}
-
 	ParentUpdate struct {
 		Current string `json:"current"`
 		New     string `json:"new"`
@@ -191,36 +114,12 @@ type (
 		LotName string   `json:"lot_name"`
 		Parents []string `json:"parents"`
 	}
-
-	PurgePolicy struct {
-		PurgeOrder               []string `mapstructure:"PurgeOrder"`
-		PolicyName               string   `mapstructure:"PolicyName"`
-		DiscoverPrefixes         bool     `mapstructure:"DiscoverPrefixes"`
-		MergeLocalWithDiscovered bool     `mapstructure:"MergeLocalWithDiscovered"`
-		DivideUnallocated        bool     `mapstructure:"DivideUnallocated"`
-		Lots                     []Lot    `mapstructure:"Lots"`
-	}
 )
 
 const (
 	bytesInGigabyte = 1000 * 1000 * 1000
 )
 
-// Lotman has a tendency to return an int as 123.0 instead of 123. This struct is used to unmarshal
-// those values into an int64
-func (i *Int64FromFloat) UnmarshalJSON(b []byte) error {
-	var f float64
-	if err := json.Unmarshal(b, &f); err != nil {
-		return err
-	}
-	i.Value = int64(f)
-	return nil
-}
-
-func (i Int64FromFloat) MarshalJSON() ([]byte, error) {
-	return json.Marshal(i.Value)
-}
-
 // Convert a cArray to a Go slice of strings. The cArray is a null-terminated
 // array of null-terminated strings.
 func cArrToGoArr(cArr *unsafe.Pointer) []string {
@@ -451,50 +350,6 @@ func mergeLotMaps(map1, map2 map[string]Lot) (map[string]Lot, error) {
 	return result, nil
 }
 
-// A hook function for mapstructure that validates that all fields in the map are present in the struct.
-// Used to verify the user's input for PolicyDefinitions, since these aren't top-level fields in parameters.yaml
-func validateFieldsHook() mapstructure.DecodeHookFunc {
-	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
-		if from.Kind() != reflect.Map || to.Kind() != reflect.Struct {
-			return data, nil
-		}
-
-		mapKeys := reflect.ValueOf(data).MapKeys()
-		structFields := make(map[string]struct{})
-		for i := 0; i < to.NumField(); i++ {
-			field := to.Field(i)
-			// Normalize the field name to lowercase
-			structFields[strings.ToLower(field.Tag.Get("mapstructure"))] = struct{}{}
-		}
-
-		// Check for unknown fields
-		for _, key := range mapKeys {
-			if _, ok := structFields[strings.ToLower(key.String())]; !ok {
-				return nil, fmt.Errorf("unknown configuration field in Lotman policy definitions: %s", key.String())
-			}
-		}
-
-		return data, nil
-	}
-}
-
-// Grab a map of policy definitions from the config file, where the policy
-// name is the key and its attributes comprise the value.
-func GetPolicyMap() (map[string]PurgePolicy, error) {
-	policyMap := make(map[string]PurgePolicy)
-	var policies []PurgePolicy
-	// Use custom decoder hook to validate fields. This validates all the way down to the bottom of the lot object.
-	if err := viper.UnmarshalKey(param.Lotman_PolicyDefinitions.GetName(), &policies, viper.DecodeHook(validateFieldsHook())); err != nil {
-		return policyMap, errors.Wrap(err, "error unmarshalling Lotman policy definitions")
-	}
-
-	for _, policy := range policies {
-		policyMap[policy.PolicyName] = policy
-	}
-
-	return policyMap, nil
-}
-
 // Given a filesystem path, try to get the amount of total and free disk space.
 func getDiskUsage(path string) (total uint64, free uint64, err error) {
 	var stat syscall.Statfs_t
@@ -0,0 +1,54 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type LocalCachePeerResult string
+
+const (
+	PeerHit   LocalCachePeerResult = "hit"   // The object was fetched from a peer
+	PeerMiss  LocalCachePeerResult = "miss"  // No peer had the object
+	PeerError LocalCachePeerResult = "error" // A peer advertising the object failed to serve it
+)
+
+var (
+	PelicanLocalCachePeerRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pelican_localcache_peer_requests_total",
+		Help: "The number of local cache misses looked up at peer local caches, by result (hit, miss, error)",
+	}, []string{"result"})
+
+	PelicanLocalCachePeerBytesReceivedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pelican_localcache_peer_bytes_received_total",
+		Help: "The number of bytes the local cache fetched from peer local caches",
+	})
+
+	PelicanLocalCachePeerBytesServedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pelican_localcache_peer_bytes_served_total",
+		Help: "The number of bytes the local cache served to peer local caches",
+	})
+
+	PelicanLocalCachePeers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pelican_localcache_peers",
+		Help: "The number of peer local caches known to the local cache",
+	})
+)
@@ -25,4 +25,20 @@ var (
 		Name: "pelican_broker_object_requests_total",
 		Help: "The number of object requests made to the service via a connection broker.",
 	})
+
+	PelicanBrokerDialWaitTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pelican_broker_dial_wait_seconds",
+		Help:    "The time spent waiting for a connection to a service behind a connection broker.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"source"})
+
+	PelicanBrokerPoolRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pelican_broker_pool_requests_total",
+		Help: "The number of connections requested from the pool of already-reversed connections, by result (hit or miss).",
+	}, []string{"result"})
+
+	PelicanBrokerReversalFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pelican_broker_reversal_failures_total",
+		Help: "The number of failed connection reversals to services behind a connection broker.",
+	})
 )
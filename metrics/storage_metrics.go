@@ -352,6 +352,39 @@ var (
 		Name: "pelican_storage_rate_limit_wait_seconds_total",
 		Help: "Cumulative time spent waiting for rate limiter tokens",
 	}, []string{"backend", "username"})
+
+	// Per-user fair-share bandwidth metrics for the native origin.  The
+	// scope label is "origin" for the origin-wide caps or the federation
+	// prefix for per-export caps; direction is "read" or "write".
+	OriginBandwidthBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pelican_origin_bandwidth_bytes_total",
+		Help: "Total bytes charged against the origin's bandwidth caps",
+	}, []string{"scope", "direction", "username"})
+
+	OriginBandwidthThrottledSeconds = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pelican_origin_bandwidth_throttled_seconds_total",
+		Help: "Cumulative time transfers were delayed by the origin's bandwidth caps",
+	}, []string{"scope", "direction", "username"})
+
+	OriginBandwidthUserTokens = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pelican_origin_bandwidth_user_tokens_bytes",
+		Help: "Bytes currently available to the user in the bandwidth cap's token bucket; negative values indicate debt",
+	}, []string{"scope", "direction", "username"})
+
+	OriginBandwidthUserCapacity = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pelican_origin_bandwidth_user_capacity_bytes",
+		Help: "The user's current fair share of the bandwidth cap's token bucket",
+	}, []string{"scope", "direction", "username"})
+
+	OriginBandwidthUserWaiters = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pelican_origin_bandwidth_user_waiters",
+		Help: "Number of the user's transfers currently blocked on the bandwidth cap",
+	}, []string{"scope", "direction", "username"})
+
+	OriginBandwidthActiveUsers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pelican_origin_bandwidth_active_users",
+		Help: "Number of users currently sharing the bandwidth cap",
+	}, []string{"scope", "direction"})
 )
 
 // Backend label values
@@ -178,13 +178,13 @@ func (server *OriginServer) CreateAdvertisement(name, id, originUrlStr, originWe
 	// Get the overall health status as reported by the origin.
 	status := metrics.GetHealthStatus().OverallStatus
 
-	// For POSIXv2 and SSH origins co-located with a director, DataURL (which becomes
+	// For natively-served origins (POSIXv2, SSH, ...) co-located with a director, DataURL (which becomes
 	// ServerAd.URL) should have the /api/v1.0/origin/data prefix so the director redirects
 	// to the right endpoint. When the origin is standalone, older clients cannot handle
 	// non-empty resource paths, so we advertise the base URL.
 	// WebURL stays as the base server URL for web browser access.
 	dataUrlToAdvertise := originUrlStr
-	if (ost == server_structs.OriginStoragePosixv2 || ost == server_structs.OriginStorageSSH) && config.IsServerEnabled(server_structs.DirectorType) {
+	if server_utils.UsesNativeOriginBackend(ost) && config.IsServerEnabled(server_structs.DirectorType) {
 		if parsedUrl, err := url.Parse(originUrlStr); err == nil {
 			parsedUrl.Path = "/api/v1.0/origin/data"
 			dataUrlToAdvertise = parsedUrl.String()
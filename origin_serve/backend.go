@@ -19,6 +19,8 @@
 package origin_serve
 
 import (
+	"context"
+	"net/http"
 	"os"
 	"strings"
 
@@ -26,6 +28,7 @@ import (
 	"golang.org/x/net/webdav"
 
 	"github.com/pelicanplatform/pelican/server_utils"
+	"github.com/pelicanplatform/pelican/tracing"
 )
 
 // ---------------------------------------------------------------------------
@@ -49,6 +52,21 @@ func (b *localBackend) Checksummer() server_utils.OriginChecksummer {
 	return &xattrChecksumAdapter{storagePrefix: b.storagePrefix}
 }
 
+// setPelicanHeaders copies the stashed Pelican headers, and the trace
+// context, from ctx onto an outgoing request made by a backend to its
+// upstream storage service.
+func setPelicanHeaders(ctx context.Context, req *http.Request) {
+	if h := server_utils.PelicanHeadersFromContext(ctx); h != nil {
+		if h.JobId != "" {
+			req.Header.Set("X-Pelican-JobId", h.JobId)
+		}
+		if h.Timeout != "" {
+			req.Header.Set("X-Pelican-Timeout", h.Timeout)
+		}
+	}
+	tracing.Inject(ctx, req.Header)
+}
+
 // ---------------------------------------------------------------------------
 // xattrChecksumAdapter — adapts XattrChecksummer to OriginChecksummer
 // ---------------------------------------------------------------------------
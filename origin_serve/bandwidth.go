@@ -0,0 +1,356 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/webdav"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/byte_rate"
+	"github.com/pelicanplatform/pelican/htb"
+	"github.com/pelicanplatform/pelican/metrics"
+)
+
+const (
+	// bandwidthStatsInterval controls how often per-user HTB statistics are
+	// copied into the Prometheus gauges.
+	bandwidthStatsInterval = 5 * time.Second
+
+	// Scope label used for the origin-wide limiter; per-export limiters
+	// use the export's federation prefix.
+	bandwidthScopeOrigin = "origin"
+
+	directionRead  = "read"
+	directionWrite = "write"
+)
+
+type (
+	// bandwidthLimiter caps the read and write throughput of a single scope
+	// (the whole origin or one export).  Each direction is a hierarchical
+	// token bucket, denominated in bytes, whose children are the users of
+	// that scope; when several users compete, each gets a fair share of the
+	// cap while idle users' shares are lent to the busy ones.
+	bandwidthLimiter struct {
+		scope      string
+		read       *htb.HTB // nil when reads are unlimited
+		write      *htb.HTB // nil when writes are unlimited
+		readLimit  byte_rate.ByteRate
+		writeLimit byte_rate.ByteRate
+	}
+
+	// bandwidthLimitedFs wraps a webdav.FileSystem so that every file opened
+	// through it is throttled by a chain of limiters (export first, then
+	// origin).  It sits above the backend so the same caps apply whether the
+	// export is served from POSIX, SSH, S3 or HTTPS storage.
+	bandwidthLimitedFs struct {
+		webdav.FileSystem
+		limiters []*bandwidthLimiter
+	}
+
+	// bandwidthLimitedFile charges every byte read or written to the
+	// requesting user's bucket in each limiter.  Time spent waiting for
+	// tokens is also reported through the storage rate-limit metrics shared
+	// with the I/O-time limiter.
+	bandwidthLimitedFile struct {
+		webdav.File
+		limiters []*bandwidthLimiter
+		username string
+		ctx      context.Context
+	}
+)
+
+var (
+	// Limiters installed by the most recent InitializeHandlers call, keyed
+	// by scope and sampled by the stats goroutine.  A later call reuses the
+	// limiter of any scope whose caps are unchanged, so reinitializing the
+	// handlers does not reset the users' buckets.
+	bandwidthLimitersMu sync.Mutex
+	bandwidthLimiters   map[string]*bandwidthLimiter
+
+	// Cancels the running stats goroutine, if any
+	bandwidthMetricsCancel context.CancelFunc
+)
+
+// newBandwidthLimiter creates a limiter for the given scope.  Returns nil
+// if neither direction is capped.  Each bucket holds one second's worth of
+// tokens, allowing that much burst above the configured rate.
+func newBandwidthLimiter(scope string, readLimit, writeLimit byte_rate.ByteRate) *bandwidthLimiter {
+	if readLimit <= 0 && writeLimit <= 0 {
+		return nil
+	}
+	bl := &bandwidthLimiter{scope: scope, readLimit: readLimit, writeLimit: writeLimit}
+	if readLimit > 0 {
+		bl.read = htb.New(float64(readLimit), int64(readLimit))
+	}
+	if writeLimit > 0 {
+		bl.write = htb.New(float64(writeLimit), int64(writeLimit))
+	}
+	return bl
+}
+
+// sameCaps reports whether bl enforces the given read and write caps
+func (bl *bandwidthLimiter) sameCaps(readLimit, writeLimit byte_rate.ByteRate) bool {
+	return max(bl.readLimit, 0) == max(readLimit, 0) && max(bl.writeLimit, 0) == max(writeLimit, 0)
+}
+
+// bucket returns the HTB for the given direction (nil if unlimited) along
+// with the largest single request to make of it: one tick's worth of
+// tokens, so that a large buffer cannot starve other users.
+func (bl *bandwidthLimiter) bucket(direction string) (*htb.HTB, int64) {
+	bucket, limit := bl.read, bl.readLimit
+	if direction == directionWrite {
+		bucket, limit = bl.write, bl.writeLimit
+	}
+	stepMax := int64(limit) / 10
+	if stepMax <= 0 {
+		stepMax = 1
+	}
+	return bucket, stepMax
+}
+
+// newBandwidthLimitedFs wraps fs with the given limiters, skipping nil
+// entries.  If no limiter remains, fs is returned unchanged.
+func newBandwidthLimitedFs(fs webdav.FileSystem, limiters ...*bandwidthLimiter) webdav.FileSystem {
+	active := make([]*bandwidthLimiter, 0, len(limiters))
+	for _, bl := range limiters {
+		if bl != nil {
+			active = append(active, bl)
+		}
+	}
+	if len(active) == 0 {
+		return fs
+	}
+	return &bandwidthLimitedFs{FileSystem: fs, limiters: active}
+}
+
+// bandwidthUserID returns the HTB child key for the request: the username
+// the UserMapper derived from the token, or "unauthenticated" for
+// anonymous (e.g. public) reads.
+func bandwidthUserID(ctx context.Context) string {
+	if username := usernameFromContext(ctx); username != "" {
+		return username
+	}
+	return "unauthenticated"
+}
+
+// OpenFile implements webdav.FileSystem
+func (bfs *bandwidthLimitedFs) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	file, err := bfs.FileSystem.OpenFile(ctx, name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &bandwidthLimitedFile{
+		File:     file,
+		limiters: bfs.limiters,
+		username: bandwidthUserID(ctx),
+		ctx:      ctx,
+	}, nil
+}
+
+// Read implements io.Reader.  As in metricsFile.rateLimitedIO, tokens are
+// taken from each limiter before the I/O and any the read did not use are
+// returned afterwards; a read asks for at most one step of tokens, so
+// large buffers produce short reads rather than long waits.
+func (bf *bandwidthLimitedFile) Read(p []byte) (int, error) {
+	if step := bf.stepMax(directionRead); int64(len(p)) > step {
+		p = p[:step]
+	}
+	tokens, err := bf.acquire(directionRead, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	n, err := bf.File.Read(p)
+	bf.settle(directionRead, tokens, n)
+	return n, err
+}
+
+// Write implements io.Writer, writing one step of tokens at a time
+func (bf *bandwidthLimitedFile) Write(p []byte) (written int, err error) {
+	step := bf.stepMax(directionWrite)
+	for len(p) > 0 {
+		chunk := p[:min(int64(len(p)), step)]
+		tokens, acquireErr := bf.acquire(directionWrite, int64(len(chunk)))
+		if acquireErr != nil {
+			return written, acquireErr
+		}
+		var n int
+		n, err = bf.File.Write(chunk)
+		bf.settle(directionWrite, tokens, n)
+		written += n
+		if err != nil {
+			return
+		}
+		p = p[n:]
+	}
+	return
+}
+
+// stepMax returns the largest request to make of the limiters in the given
+// direction, or math.MaxInt64 if none of them limits it
+func (bf *bandwidthLimitedFile) stepMax(direction string) int64 {
+	step := int64(math.MaxInt64)
+	for _, bl := range bf.limiters {
+		if bucket, stepMax := bl.bucket(direction); bucket != nil {
+			step = min(step, stepMax)
+		}
+	}
+	return step
+}
+
+// acquire takes n tokens from the user's bucket in every limiter, blocking
+// until they are available.  The returned allocations are indexed like
+// bf.limiters, with nil entries for limiters that do not cap the direction.
+func (bf *bandwidthLimitedFile) acquire(direction string, n int64) ([]*htb.Tokens, error) {
+	tokens := make([]*htb.Tokens, len(bf.limiters))
+	for idx, bl := range bf.limiters {
+		bucket, _ := bl.bucket(direction)
+		if bucket == nil {
+			continue
+		}
+		start := time.Now()
+		allocation, err := bucket.Wait(bf.ctx, bf.username, n)
+		if err != nil {
+			bf.settle(direction, tokens, 0)
+			return nil, err
+		}
+		tokens[idx] = allocation
+		if waited := time.Since(start); waited > time.Millisecond {
+			metrics.OriginBandwidthThrottledSeconds.WithLabelValues(bl.scope, direction, bf.username).Add(waited.Seconds())
+			metrics.StorageRateLimitWaitsTotal.WithLabelValues(metrics.BackendPOSIXv2, bf.username).Inc()
+			metrics.StorageRateLimitWaitTime.WithLabelValues(metrics.BackendPOSIXv2, bf.username).Add(waited.Seconds())
+		}
+	}
+	return tokens, nil
+}
+
+// settle charges the n bytes transferred against the allocations made by
+// acquire and returns the unused tokens to their buckets
+func (bf *bandwidthLimitedFile) settle(direction string, tokens []*htb.Tokens, n int) {
+	for idx, allocation := range tokens {
+		if allocation == nil {
+			continue
+		}
+		bl := bf.limiters[idx]
+		if n > 0 {
+			allocation.Use(int64(n))
+			metrics.OriginBandwidthBytesTotal.WithLabelValues(bl.scope, direction, bf.username).Add(float64(n))
+		}
+		bucket, _ := bl.bucket(direction)
+		bucket.Return(allocation)
+	}
+}
+
+// getBandwidthLimiter returns the installed limiter for scope if its caps
+// are unchanged, otherwise a new one (nil if neither direction is capped).
+func getBandwidthLimiter(scope string, readLimit, writeLimit byte_rate.ByteRate) *bandwidthLimiter {
+	bandwidthLimitersMu.Lock()
+	existing := bandwidthLimiters[scope]
+	bandwidthLimitersMu.Unlock()
+	if existing != nil && existing.sameCaps(readLimit, writeLimit) {
+		return existing
+	}
+	return newBandwidthLimiter(scope, readLimit, writeLimit)
+}
+
+// setBandwidthLimiters records the active limiters for metrics collection
+func setBandwidthLimiters(limiters []*bandwidthLimiter) {
+	bandwidthLimitersMu.Lock()
+	defer bandwidthLimitersMu.Unlock()
+	bandwidthLimiters = make(map[string]*bandwidthLimiter, len(limiters))
+	for _, bl := range limiters {
+		bandwidthLimiters[bl.scope] = bl
+	}
+}
+
+// updateBandwidthMetrics copies the per-user HTB state of every active
+// limiter into the Prometheus gauges.  Users the HTB has dropped for
+// inactivity disappear from the gauges as well.
+func updateBandwidthMetrics() {
+	bandwidthLimitersMu.Lock()
+	limiters := make([]*bandwidthLimiter, 0, len(bandwidthLimiters))
+	for _, bl := range bandwidthLimiters {
+		limiters = append(limiters, bl)
+	}
+	bandwidthLimitersMu.Unlock()
+
+	metrics.OriginBandwidthUserTokens.Reset()
+	metrics.OriginBandwidthUserCapacity.Reset()
+	metrics.OriginBandwidthUserWaiters.Reset()
+	metrics.OriginBandwidthActiveUsers.Reset()
+
+	for _, bl := range limiters {
+		for _, direction := range []string{directionRead, directionWrite} {
+			bucket, _ := bl.bucket(direction)
+			if bucket == nil {
+				continue
+			}
+			stats := bucket.GetStats()
+			metrics.OriginBandwidthActiveUsers.WithLabelValues(bl.scope, direction).Set(float64(stats.NumChildren))
+			for username, child := range stats.ChildrenStats {
+				metrics.OriginBandwidthUserTokens.WithLabelValues(bl.scope, direction, username).Set(child.Tokens)
+				metrics.OriginBandwidthUserCapacity.WithLabelValues(bl.scope, direction, username).Set(float64(child.Capacity))
+				metrics.OriginBandwidthUserWaiters.WithLabelValues(bl.scope, direction, username).Set(float64(child.NumWaiters))
+			}
+		}
+	}
+}
+
+// launchBandwidthMetrics periodically refreshes the per-user bandwidth
+// gauges until ctx is cancelled.  Any refresher started by an earlier
+// call is stopped first, so at most one runs however many times the
+// handlers are initialized.
+func launchBandwidthMetrics(ctx context.Context, egrp *errgroup.Group) {
+	stopBandwidthMetricsRefresher()
+
+	ctx, cancel := context.WithCancel(ctx)
+	bandwidthLimitersMu.Lock()
+	bandwidthMetricsCancel = cancel
+	bandwidthLimitersMu.Unlock()
+
+	egrp.Go(func() error {
+		defer cancel()
+		ticker := time.NewTicker(bandwidthStatsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				updateBandwidthMetrics()
+			}
+		}
+	})
+}
+
+// stopBandwidthMetricsRefresher stops the goroutine started by
+// launchBandwidthMetrics, if one is running.
+func stopBandwidthMetricsRefresher() {
+	bandwidthLimitersMu.Lock()
+	defer bandwidthLimitersMu.Unlock()
+	if bandwidthMetricsCancel != nil {
+		bandwidthMetricsCancel()
+		bandwidthMetricsCancel = nil
+	}
+}
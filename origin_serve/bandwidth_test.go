@@ -0,0 +1,240 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pelicanplatform/pelican/metrics"
+)
+
+func TestNewBandwidthLimitedFsPassthrough(t *testing.T) {
+	fs := newAferoFileSystem(afero.NewMemMapFs(), "", nil)
+	assert.Nil(t, newBandwidthLimiter("origin", 0, 0))
+	assert.Same(t, fs, newBandwidthLimitedFs(fs, nil, nil))
+}
+
+func TestBandwidthLimitedRead(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	content := bytes.Repeat([]byte("x"), 150*1024)
+	require.NoError(t, afero.WriteFile(memFs, "/obj", content, 0644))
+
+	// 100KB/s with a one-second burst: the last 50KB must wait ~0.5s
+	limiter := newBandwidthLimiter("/test", 100*1024, 0)
+	fs := newBandwidthLimitedFs(newAferoFileSystem(memFs, "", nil), limiter)
+
+	ctx := setUserInfo(context.Background(), &userInfo{User: "alice"})
+	f, err := fs.OpenFile(ctx, "/obj", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	start := time.Now()
+	got, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	stats := limiter.read.GetStats()
+	assert.Contains(t, stats.ChildrenStats, "alice")
+	assert.Equal(t, float64(len(content)), testutil.ToFloat64(metrics.OriginBandwidthBytesTotal.WithLabelValues("/test", "read", "alice")))
+	// Throttling is reported through the storage rate-limit metrics as well
+	assert.Greater(t, testutil.ToFloat64(metrics.StorageRateLimitWaitsTotal.WithLabelValues(metrics.BackendPOSIXv2, "alice")), 0.0)
+	assert.Greater(t, testutil.ToFloat64(metrics.StorageRateLimitWaitTime.WithLabelValues(metrics.BackendPOSIXv2, "alice")), 0.0)
+
+	// Writes are unlimited for this scope
+	w, err := fs.OpenFile(ctx, "/new", os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	start = time.Now()
+	_, err = w.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+}
+
+func TestBandwidthLimitedWrite(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	content := bytes.Repeat([]byte("y"), 150*1024)
+
+	// A single large write is split into steps and throttled like reads
+	limiter := newBandwidthLimiter("/test-write", 0, 100*1024)
+	fs := newBandwidthLimitedFs(newAferoFileSystem(memFs, "", nil), limiter)
+
+	ctx := setUserInfo(context.Background(), &userInfo{User: "carol"})
+	f, err := fs.OpenFile(ctx, "/obj", os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	start := time.Now()
+	n, err := f.Write(content)
+	require.NoError(t, err)
+	assert.Equal(t, len(content), n)
+	require.NoError(t, f.Close())
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	got, err := afero.ReadFile(memFs, "/obj")
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.Equal(t, float64(len(content)), testutil.ToFloat64(metrics.OriginBandwidthBytesTotal.WithLabelValues("/test-write", "write", "carol")))
+}
+
+func TestBandwidthLimitedReadCancelled(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(memFs, "/obj", bytes.Repeat([]byte("x"), 64*1024), 0644))
+
+	limiter := newBandwidthLimiter("/test", 1024, 0)
+	fs := newBandwidthLimitedFs(newAferoFileSystem(memFs, "", nil), limiter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	f, err := fs.OpenFile(ctx, "/obj", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = io.ReadAll(f)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBandwidthLimitedFairShare(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	content := bytes.Repeat([]byte("x"), 1024)
+	require.NoError(t, afero.WriteFile(memFs, "/obj", content, 0644))
+
+	// Two users hammering a 200KB/s cap should each get about half of it
+	// once the initial one-second burst has been spent
+	limiter := newBandwidthLimiter("/fair", 200*1024, 0)
+	fs := newBandwidthLimitedFs(newAferoFileSystem(memFs, "", nil), limiter)
+
+	var wg sync.WaitGroup
+	var totals [2]int
+	warmup := time.Now().Add(time.Second)
+	deadline := warmup.Add(2 * time.Second)
+	for i, user := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func(i int, user string) {
+			defer wg.Done()
+			ctx := setUserInfo(context.Background(), &userInfo{User: user})
+			buf := make([]byte, len(content))
+			for time.Now().Before(deadline) {
+				f, err := fs.OpenFile(ctx, "/obj", os.O_RDONLY, 0)
+				if !assert.NoError(t, err) {
+					return
+				}
+				n, err := io.ReadFull(f, buf)
+				f.Close()
+				if !assert.NoError(t, err) {
+					return
+				}
+				if time.Now().After(warmup) {
+					totals[i] += n
+				}
+			}
+		}(i, user)
+	}
+	wg.Wait()
+
+	t.Logf("alice read %d bytes, bob read %d bytes", totals[0], totals[1])
+	require.Greater(t, totals[1], 0)
+	assert.InDelta(t, 1.0, float64(totals[0])/float64(totals[1]), 0.3)
+}
+
+func TestGetBandwidthLimiterReuse(t *testing.T) {
+	t.Cleanup(func() { setBandwidthLimiters(nil) })
+
+	first := getBandwidthLimiter("/reuse", 1024, 0)
+	require.NotNil(t, first)
+	setBandwidthLimiters([]*bandwidthLimiter{first})
+
+	// Unchanged caps keep the existing buckets; changed caps replace them
+	assert.Same(t, first, getBandwidthLimiter("/reuse", 1024, 0))
+	assert.NotSame(t, first, getBandwidthLimiter("/reuse", 2048, 0))
+	assert.Nil(t, getBandwidthLimiter("/other", 0, 0))
+}
+
+func TestLaunchBandwidthMetricsReplacesRefresher(t *testing.T) {
+	var egrp errgroup.Group
+	launchBandwidthMetrics(t.Context(), &egrp)
+	launchBandwidthMetrics(t.Context(), &egrp)
+	stopBandwidthMetricsRefresher()
+
+	// Both refreshers exit although the context they were started with is live
+	done := make(chan struct{})
+	go func() {
+		_ = egrp.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("bandwidth metrics refresher did not stop")
+	}
+}
+
+func TestBandwidthMetrics(t *testing.T) {
+	t.Cleanup(func() { setBandwidthLimiters(nil) })
+
+	memFs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(memFs, "/obj", []byte("hello"), 0644))
+
+	originLimiter := newBandwidthLimiter(bandwidthScopeOrigin, 1024*1024, 1024*1024)
+	exportLimiter := newBandwidthLimiter("/metrics-test", 1024*1024, 0)
+	fs := newBandwidthLimitedFs(newAferoFileSystem(memFs, "", nil), exportLimiter, originLimiter)
+	setBandwidthLimiters([]*bandwidthLimiter{originLimiter, exportLimiter})
+
+	var wg sync.WaitGroup
+	for _, user := range []string{"alice", "bob", ""} {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			ctx := context.Background()
+			if user != "" {
+				ctx = setUserInfo(ctx, &userInfo{User: user})
+			}
+			f, err := fs.OpenFile(ctx, "/obj", os.O_RDONLY, 0)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer f.Close()
+			_, err = io.ReadAll(f)
+			assert.NoError(t, err)
+		}(user)
+	}
+	wg.Wait()
+
+	updateBandwidthMetrics()
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.OriginBandwidthActiveUsers.WithLabelValues("/metrics-test", "read")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.OriginBandwidthActiveUsers.WithLabelValues(bandwidthScopeOrigin, "read")))
+	assert.Equal(t, 5.0, testutil.ToFloat64(metrics.OriginBandwidthBytesTotal.WithLabelValues("/metrics-test", "read", "unauthenticated")))
+	// Each of the three users gets a third of the one-second bucket
+	assert.Equal(t, float64(1024*1024/3), testutil.ToFloat64(metrics.OriginBandwidthUserCapacity.WithLabelValues("/metrics-test", "read", "alice")))
+
+	// Unused directions are not reported
+	count := testutil.CollectAndCount(metrics.OriginBandwidthActiveUsers)
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,386 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/json"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+const (
+	// The chunks of an upload to <dir>/<name> are staged in the hidden directory
+	// <dir>/.<name>.pelican-upload-<id> so the final rename stays on one filesystem
+	chunkedUploadStagingInfix = ".pelican-upload-"
+	chunkedUploadManifest     = "manifest.json"
+	chunkedUploadAssembled    = "assembled"
+)
+
+var chunkedUploadIDRegex = regexp.MustCompile(`^[0-9a-f]{16,64}$`)
+
+// Returns true if the request is part of the chunked upload protocol
+func isChunkedUploadRequest(r *http.Request) bool {
+	return (r.Method == http.MethodPost || r.Method == http.MethodPut) && r.URL.Query().Has(server_structs.ChunkedUploadQuery)
+}
+
+// Handle a request of the chunked upload protocol (see server_structs.ChunkedUploadQuery)
+// for the object at objectPath, relative to the root of fs.
+func handleChunkedUpload(c *gin.Context, fs webdav.FileSystem, objectPath string) {
+	// The client checks the origin's version against the ChunkedUpload feature
+	c.Header("Server", "pelican/"+config.GetVersion())
+
+	objectPath = path.Clean("/" + objectPath)
+	if objectPath == "/" || strings.HasSuffix(c.Param("path"), "/") {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "chunked uploads must target an object"})
+		return
+	}
+	query := c.Request.URL.Query()
+	id := query.Get(server_structs.ChunkedUploadQuery)
+
+	switch {
+	case c.Request.Method == http.MethodPost && id == server_structs.ChunkedUploadCreate:
+		createChunkedUpload(c, fs, objectPath)
+	case !chunkedUploadIDRegex.MatchString(id):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid upload ID"})
+	case c.Request.Method == http.MethodPut && query.Has(server_structs.ChunkedUploadChunkQuery):
+		putUploadChunk(c, fs, objectPath, id, query.Get(server_structs.ChunkedUploadChunkQuery))
+	case c.Request.Method == http.MethodPost && query.Get(server_structs.ChunkedUploadCommitQuery) == "true":
+		commitChunkedUpload(c, fs, objectPath, id)
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unrecognized chunked upload request"})
+	}
+}
+
+func chunkedUploadStagingDir(objectPath, id string) string {
+	return path.Join(path.Dir(objectPath), "."+path.Base(objectPath)+chunkedUploadStagingInfix+id)
+}
+
+// Return the length of chunk idx of the upload
+func chunkLength(manifest *server_structs.ChunkedUploadRequest, idx int) int64 {
+	start := int64(idx) * manifest.ChunkSize
+	return min(manifest.ChunkSize, manifest.Size-start)
+}
+
+func readUploadManifest(ctx context.Context, fs webdav.FileSystem, stagingDir string) (*server_structs.ChunkedUploadRequest, error) {
+	f, err := fs.OpenFile(ctx, path.Join(stagingDir, chunkedUploadManifest), os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	manifest := &server_structs.ChunkedUploadRequest{}
+	if err := json.NewDecoder(f).Decode(manifest); err != nil {
+		return nil, errors.Wrap(err, "corrupt upload manifest")
+	}
+	return manifest, nil
+}
+
+func writeFileContents(ctx context.Context, fs webdav.FileSystem, name string, contents []byte) error {
+	f, err := fs.OpenFile(ctx, name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(contents); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Return the indices of the chunks in the staging area that are complete
+func receivedChunks(ctx context.Context, fs webdav.FileSystem, stagingDir string, manifest *server_structs.ChunkedUploadRequest) ([]int, error) {
+	dir, err := fs.OpenFile(ctx, stagingDir, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	entries, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+	chunks := server_structs.ChunkedUploadChunkCount(manifest.Size, manifest.ChunkSize)
+	received := []int{}
+	for _, entry := range entries {
+		idx, err := strconv.Atoi(entry.Name())
+		if err != nil || idx < 0 || idx >= chunks || entry.Size() != chunkLength(manifest, idx) {
+			continue
+		}
+		received = append(received, idx)
+	}
+	return received, nil
+}
+
+// Remove the staging areas of abandoned uploads in dir
+func sweepStaleUploads(ctx context.Context, fs webdav.FileSystem, dir string) {
+	lifetime := param.Origin_ChunkedUploadStagingLifetime.GetDuration()
+	if lifetime <= 0 {
+		return
+	}
+	f, err := fs.OpenFile(ctx, dir, os.O_RDONLY, 0)
+	if err != nil {
+		return
+	}
+	entries, err := f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ".") || !strings.Contains(entry.Name(), chunkedUploadStagingInfix) ||
+			time.Since(entry.ModTime()) < lifetime {
+			continue
+		}
+		log.Debugf("Removing stale chunked upload staging area %s", path.Join(dir, entry.Name()))
+		if err := fs.RemoveAll(ctx, path.Join(dir, entry.Name())); err != nil {
+			log.Warningf("Failed to remove stale chunked upload staging area %s: %v", path.Join(dir, entry.Name()), err)
+		}
+	}
+}
+
+func createChunkedUpload(c *gin.Context, fs webdav.FileSystem, objectPath string) {
+	ctx := c.Request.Context()
+	req := server_structs.ChunkedUploadRequest{}
+	if err := json.NewDecoder(io.LimitReader(c.Request.Body, 4096)).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid chunked upload request: " + err.Error()})
+		return
+	}
+	if !chunkedUploadIDRegex.MatchString(req.ID) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid upload ID"})
+		return
+	}
+	chunks := server_structs.ChunkedUploadChunkCount(req.Size, req.ChunkSize)
+	if chunks == 0 || chunks > server_structs.ChunkedUploadMaxChunks {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid upload or chunk size"})
+		return
+	}
+
+	sweepStaleUploads(ctx, fs, path.Dir(objectPath))
+	stagingDir := chunkedUploadStagingDir(objectPath, req.ID)
+	manifest, err := readUploadManifest(ctx, fs, stagingDir)
+	if err != nil || *manifest != req {
+		// Nothing (reusable) was staged; start afresh
+		if err := fs.RemoveAll(ctx, stagingDir); err != nil && !os.IsNotExist(err) {
+			c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		contents, _ := json.Marshal(req)
+		if err = fs.Mkdir(ctx, stagingDir, 0755); err == nil {
+			err = writeFileContents(ctx, fs, path.Join(stagingDir, chunkedUploadManifest), contents)
+		}
+		if err != nil {
+			log.Warningf("Failed to create chunked upload staging area %s: %v", stagingDir, err)
+			c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": "failed to create staging area: " + err.Error()})
+			return
+		}
+		manifest = &req
+	}
+
+	received, err := receivedChunks(ctx, fs, stagingDir, manifest)
+	if err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	log.Debugf("Chunked upload %s of %s: %d of %d chunks already staged", req.ID, objectPath, len(received), chunks)
+	c.JSON(http.StatusOK, server_structs.ChunkedUploadSession{
+		ID:        req.ID,
+		Size:      req.Size,
+		ChunkSize: req.ChunkSize,
+		Chunks:    chunks,
+		Received:  received,
+	})
+}
+
+func putUploadChunk(c *gin.Context, fs webdav.FileSystem, objectPath, id, chunkStr string) {
+	ctx := c.Request.Context()
+	stagingDir := chunkedUploadStagingDir(objectPath, id)
+	manifest, err := readUploadManifest(ctx, fs, stagingDir)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown upload"})
+		return
+	}
+	idx, err := strconv.Atoi(chunkStr)
+	if err != nil || idx < 0 || idx >= server_structs.ChunkedUploadChunkCount(manifest.Size, manifest.ChunkSize) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid chunk index"})
+		return
+	}
+	expected := chunkLength(manifest, idx)
+	if c.Request.ContentLength >= 0 && c.Request.ContentLength != expected {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "chunk " + chunkStr + " must be " + strconv.FormatInt(expected, 10) + " bytes"})
+		return
+	}
+
+	// Write to a temporary name so a partially-received chunk is never mistaken for a complete one
+	partName := path.Join(stagingDir, chunkStr+".part")
+	f, err := fs.OpenFile(ctx, partName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	written, err := io.Copy(f, io.LimitReader(c.Request.Body, expected+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != expected {
+		err = errors.Errorf("received %d bytes for chunk %d but expected %d", written, idx, expected)
+	}
+	if err == nil {
+		err = fs.Rename(ctx, partName, path.Join(stagingDir, chunkStr))
+	}
+	if err != nil {
+		_ = fs.RemoveAll(ctx, partName)
+		log.Debugf("Failed to store chunk %d of upload %s: %v", idx, id, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusCreated)
+}
+
+// Return a hash for the algorithm named in an RFC 3230 digest
+func newDigestHash(alg string) (ChecksumType, hash.Hash, bool) {
+	switch strings.ToLower(alg) {
+	case "md5":
+		return ChecksumTypeMD5, md5.New(), true
+	case "sha", "sha-1", "sha1":
+		return ChecksumTypeSHA1, sha1.New(), true
+	case "crc32":
+		return ChecksumTypeCRC32, crc32.NewIEEE(), true
+	case "crc32c":
+		return ChecksumTypeCRC32C, crc32.New(crc32.MakeTable(crc32.Castagnoli)), true
+	}
+	return "", nil, false
+}
+
+func commitChunkedUpload(c *gin.Context, fs webdav.FileSystem, objectPath, id string) {
+	ctx := c.Request.Context()
+	stagingDir := chunkedUploadStagingDir(objectPath, id)
+	manifest, err := readUploadManifest(ctx, fs, stagingDir)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "unknown upload"})
+		return
+	}
+	chunks := server_structs.ChunkedUploadChunkCount(manifest.Size, manifest.ChunkSize)
+	received, err := receivedChunks(ctx, fs, stagingDir, manifest)
+	if err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(received) != chunks {
+		// Tell the client what is still missing
+		c.AbortWithStatusJSON(http.StatusConflict, server_structs.ChunkedUploadSession{
+			ID:        id,
+			Size:      manifest.Size,
+			ChunkSize: manifest.ChunkSize,
+			Chunks:    chunks,
+			Received:  received,
+		})
+		return
+	}
+
+	// Verify every digest the client provided that we know how to compute;
+	// at least one is required
+	type digestCheck struct {
+		checksumType ChecksumType
+		hash         hash.Hash
+		expected     string
+	}
+	checks := []digestCheck{}
+	writers := []io.Writer{}
+	for _, digest := range strings.Split(c.GetHeader("Digest"), ",") {
+		alg, value, found := strings.Cut(strings.TrimSpace(digest), "=")
+		if !found {
+			continue
+		}
+		if checksumType, h, ok := newDigestHash(alg); ok {
+			checks = append(checks, digestCheck{checksumType, h, value})
+			writers = append(writers, h)
+		}
+	}
+	// Without a checksum, a corrupt chunk would go unnoticed
+	if len(checks) == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "committing a chunked upload requires a Digest header with a supported checksum"})
+		return
+	}
+
+	assembledName := path.Join(stagingDir, chunkedUploadAssembled)
+	out, err := fs.OpenFile(ctx, assembledName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	writer := io.MultiWriter(append(writers, out)...)
+	for idx := 0; idx < chunks && err == nil; idx++ {
+		var chunk webdav.File
+		if chunk, err = fs.OpenFile(ctx, path.Join(stagingDir, strconv.Itoa(idx)), os.O_RDONLY, 0); err == nil {
+			_, err = io.Copy(writer, chunk)
+			chunk.Close()
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Warningf("Failed to assemble chunked upload %s of %s: %v", id, objectPath, err)
+		_ = fs.RemoveAll(ctx, assembledName)
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": "failed to assemble upload: " + err.Error()})
+		return
+	}
+
+	digests := make([]string, 0, len(checks))
+	for _, check := range checks {
+		sum := check.hash.Sum(nil)
+		if !strings.EqualFold(rfc3230Value(check.checksumType, sum), check.expected) {
+			// One of the chunks is corrupt and there's no telling which; start over
+			log.Warningf("Checksum mismatch in chunked upload %s of %s (%s)", id, objectPath, check.checksumType)
+			_ = fs.RemoveAll(ctx, stagingDir)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "checksum mismatch for " + string(check.checksumType)})
+			return
+		}
+		digests = append(digests, formatRFC3230(check.checksumType, sum))
+	}
+
+	if err := fs.Rename(ctx, assembledName, objectPath); err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": "failed to move upload into place: " + err.Error()})
+		return
+	}
+	if err := fs.RemoveAll(ctx, stagingDir); err != nil {
+		log.Warningf("Failed to remove chunked upload staging area %s: %v", stagingDir, err)
+	}
+	log.Debugf("Completed chunked upload %s of %s (%d bytes in %d chunks)", id, objectPath, manifest.Size, chunks)
+	c.Header("Digest", strings.Join(digests, ","))
+	c.Status(http.StatusCreated)
+}
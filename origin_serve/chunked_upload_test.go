@@ -0,0 +1,152 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+func TestChunkedUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	memFs := afero.NewMemMapFs()
+	fs := newAferoFileSystem(memFs, "", nil)
+	engine := gin.New()
+	engine.Any("/test/*path", func(c *gin.Context) {
+		handleChunkedUpload(c, fs, c.Param("path"))
+	})
+
+	content := make([]byte, 10*1024+17)
+	for idx := range content {
+		content[idx] = byte(idx % 251)
+	}
+	const id = "0123456789abcdef0123456789abcdef"
+	const chunkSize = 4096
+	digest := fmt.Sprintf("crc32c=%08x", crc32.Checksum(content, crc32.MakeTable(crc32.Castagnoli)))
+
+	do := func(method, query string, body []byte, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/test/dir/obj?"+query, bytes.NewReader(body))
+		for idx := 0; idx+1 < len(headers); idx += 2 {
+			req.Header.Set(headers[idx], headers[idx+1])
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	create := func() server_structs.ChunkedUploadSession {
+		body, err := json.Marshal(server_structs.ChunkedUploadRequest{ID: id, Size: int64(len(content)), ChunkSize: chunkSize})
+		require.NoError(t, err)
+		w := do(http.MethodPost, "pelican.upload=create", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Server"), "pelican/")
+		session := server_structs.ChunkedUploadSession{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+		return session
+	}
+	putChunk := func(idx int) *httptest.ResponseRecorder {
+		end := min((idx+1)*chunkSize, len(content))
+		return do(http.MethodPut, fmt.Sprintf("pelican.upload=%s&pelican.chunk=%d", id, idx), content[idx*chunkSize:end])
+	}
+
+	session := create()
+	assert.Equal(t, 3, session.Chunks)
+	assert.Empty(t, session.Received)
+
+	require.Equal(t, http.StatusCreated, putChunk(2).Code)
+	require.Equal(t, http.StatusCreated, putChunk(0).Code)
+	// A chunk of the wrong size is rejected
+	w := do(http.MethodPut, fmt.Sprintf("pelican.upload=%s&pelican.chunk=1", id), content[:10])
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Reopening the upload reports what has already been staged
+	session = create()
+	assert.ElementsMatch(t, []int{0, 2}, session.Received)
+
+	// Committing with a missing chunk reports it
+	w = do(http.MethodPost, "pelican.upload="+id+"&pelican.commit=true", nil, "Digest", digest)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	require.Equal(t, http.StatusCreated, putChunk(1).Code)
+	// Committing without a supported checksum is rejected, leaving the
+	// chunks in place
+	w = do(http.MethodPost, "pelican.upload="+id+"&pelican.commit=true", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = do(http.MethodPost, "pelican.upload="+id+"&pelican.commit=true", nil, "Digest", "unknown=abc")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	_, err := memFs.Stat("/dir/obj")
+	assert.True(t, os.IsNotExist(err))
+	assert.ElementsMatch(t, []int{0, 1, 2}, create().Received)
+
+	w = do(http.MethodPost, "pelican.upload="+id+"&pelican.commit=true", nil, "Digest", digest)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, digest, w.Header().Get("Digest"))
+
+	got, err := afero.ReadFile(memFs, "/dir/obj")
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	_, err = memFs.Stat(chunkedUploadStagingDir("/dir/obj", id))
+	assert.True(t, os.IsNotExist(err))
+
+	t.Run("checksum-mismatch", func(t *testing.T) {
+		create()
+		for idx := 0; idx < 3; idx++ {
+			require.Equal(t, http.StatusCreated, putChunk(idx).Code)
+		}
+		w := do(http.MethodPost, "pelican.upload="+id+"&pelican.commit=true", nil, "Digest", "crc32c=00000000")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		// The staging area is discarded so the next attempt starts over
+		assert.Empty(t, create().Received)
+	})
+
+	t.Run("bad-requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "pelican.upload=../../etc&pelican.chunk=0", []byte("x")).Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "pelican.upload=ffffffffffffffff&pelican.chunk=0", []byte("x")).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "pelican.upload=create", []byte(`{"id":"abc","size":1,"chunkSize":1}`)).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "pelican.upload=create", []byte(`{"id":"0123456789abcdef","size":0,"chunkSize":1}`)).Code)
+	})
+
+	t.Run("stale-staging-removed", func(t *testing.T) {
+		config.ResetConfig()
+		t.Cleanup(config.ResetConfig)
+		require.NoError(t, param.Origin_ChunkedUploadStagingLifetime.Set(24*time.Hour))
+		stale := "/dir/.other" + chunkedUploadStagingInfix + "fedcba9876543210"
+		require.NoError(t, memFs.MkdirAll(stale, 0755))
+		old := time.Now().Add(-30 * 24 * time.Hour)
+		require.NoError(t, memFs.Chtimes(stale, old, old))
+		create()
+		_, err := memFs.Stat(stale)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -27,6 +27,8 @@ import (
 	"syscall"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/pelicanplatform/pelican/server_utils"
 )
 
 // ErrorHandler manages consistent error handling and HTTP status code mapping
@@ -77,6 +79,13 @@ func (eh *ErrorHandler) MapToHTTPStatus(err error) int {
 		return http.StatusOK
 	}
 
+	// Errors that know their own status (e.g. failures reported by an
+	// upstream storage service) take precedence.
+	var coder server_utils.HTTPStatusCoder
+	if errors.As(err, &coder) {
+		return coder.HTTPStatusCode()
+	}
+
 	// Use errors.Is for standard error comparisons (more reliable than string matching)
 	if errors.Is(err, fs.ErrPermission) {
 		return http.StatusForbidden
@@ -31,6 +31,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"golang.org/x/net/webdav"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/identity"
@@ -40,6 +41,7 @@ import (
 	"github.com/pelicanplatform/pelican/server_utils"
 	"github.com/pelicanplatform/pelican/ssh_posixv2"
 	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
 	"github.com/pelicanplatform/pelican/utils"
 )
 
@@ -48,6 +50,7 @@ var (
 	webdavHandlers     map[string]*webdav.Handler
 	exportPrefixMap    map[string]string // Maps federation prefix to storage prefix
 	handlersRegistered bool              // Tracks whether handlers have been registered
+	errorHandler       = NewErrorHandler()
 )
 
 const (
@@ -145,6 +148,7 @@ func ResetHandlers() {
 	webdavHandlers = nil
 	exportPrefixMap = nil
 	handlersRegistered = false
+	setBandwidthLimiters(nil)
 }
 
 // extractTokens extracts bearer tokens from the request
@@ -195,10 +199,11 @@ func extractTokens(r *http.Request) []string {
 //   - read:   no mutation (GET, HEAD, OPTIONS, PROPFIND)
 //   - create: mutation without data loss -- adds new state but does not
 //     destroy existing data (PUT, POST, MKCOL, LOCK, UNLOCK, PROPPATCH,
-//     and COPY: COPY only adds a resource at the destination; RFC 4918's
-//     Overwrite header would make it destructive but Pelican does not
-//     currently honor it, so today's COPY is purely additive. Promote
-//     to modify if we ever wire up overwrite-aware COPY.)
+//     and COPY: a WebDAV COPY only adds a resource at the destination;
+//     RFC 4918's Overwrite header would make it destructive but Pelican
+//     does not currently honor it, so today's COPY is purely additive.
+//     A third-party COPY that replaces an existing object additionally
+//     checks for modify in handleThirdPartyCopy.)
 //   - modify: mutation that may cause data loss (DELETE, MOVE, and any
 //     unknown method)
 func getActionFromMethod(method string) token_scopes.TokenScope {
@@ -451,7 +456,7 @@ func xrdMonitoringMiddleware() gin.HandlerFunc {
 }
 
 // InitializeHandlers initializes the WebDAV handlers for each export
-func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport) error {
+func InitializeHandlers(ctx context.Context, egrp *errgroup.Group, exports []server_utils.OriginExport) error {
 	// Validate that if DisableDirectClients is enabled, no exports have DirectReads
 	if param.Origin_DisableDirectClients.GetBool() {
 		for _, export := range exports {
@@ -474,6 +479,17 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 	// Determine storage type for filesystem creation
 	storageType := server_structs.OriginStorageType(param.Origin_StorageType.GetString())
 
+	// Per-user fair-share bandwidth caps: one limiter for the whole origin,
+	// plus one for each export that sets its own caps.
+	originLimiter := getBandwidthLimiter(bandwidthScopeOrigin,
+		param.Origin_ReadBandwidthLimit.GetByteRate(), param.Origin_WriteBandwidthLimit.GetByteRate())
+	limiters := []*bandwidthLimiter{}
+	if originLimiter != nil {
+		log.Infof("Applying origin-wide bandwidth caps (read: %s, write: %s)",
+			param.Origin_ReadBandwidthLimit.GetByteRate(), param.Origin_WriteBandwidthLimit.GetByteRate())
+		limiters = append(limiters, originLimiter)
+	}
+
 	for _, export := range exports {
 		var backend server_utils.OriginBackend
 
@@ -492,6 +508,20 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 				return fmt.Errorf("failed to create SSH backend for %s: %w", export.FederationPrefix, err)
 			}
 			backend = sshBackend
+		case server_structs.OriginStorageS3:
+			// Serve the export directly from the S3 bucket
+			s3Backend, err := newS3Backend(export)
+			if err != nil {
+				return fmt.Errorf("failed to create S3 backend for %s: %w", export.FederationPrefix, err)
+			}
+			backend = s3Backend
+		case server_structs.OriginStorageHTTPS:
+			// Proxy reads and listings to the upstream HTTP server
+			httpsBackend, err := newHTTPSBackend(export)
+			if err != nil {
+				return fmt.Errorf("failed to create HTTPS backend for %s: %w", export.FederationPrefix, err)
+			}
+			backend = httpsBackend
 		default:
 			// Use local filesystem (POSIXv2)
 			// Create a filesystem for this export with auto-directory creation
@@ -537,9 +567,16 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 			backend = newLocalBackend(fs, export.StoragePrefix)
 		}
 
+		exportLimiter := getBandwidthLimiter(export.FederationPrefix, export.ReadBandwidthLimit, export.WriteBandwidthLimit)
+		if exportLimiter != nil {
+			log.Infof("Applying bandwidth caps to %s (read: %s, write: %s)",
+				export.FederationPrefix, export.ReadBandwidthLimit, export.WriteBandwidthLimit)
+			limiters = append(limiters, exportLimiter)
+		}
+
 		// Create a WebDAV handler
 		handler := &webdav.Handler{
-			FileSystem: backend.FileSystem(),
+			FileSystem: newBandwidthLimitedFs(backend.FileSystem(), exportLimiter, originLimiter),
 			LockSystem: webdav.NewMemLS(),
 			Logger:     logger,
 		}
@@ -550,6 +587,13 @@ func InitializeHandlers(ctx context.Context, exports []server_utils.OriginExport
 		log.Infof("Initialized WebDAV handler for %s -> %s (storage: %s)", export.FederationPrefix, export.StoragePrefix, storageType)
 	}
 
+	setBandwidthLimiters(limiters)
+	if len(limiters) > 0 {
+		launchBandwidthMetrics(ctx, egrp)
+	} else {
+		stopBandwidthMetricsRefresher()
+	}
+
 	return nil
 }
 
@@ -586,6 +630,7 @@ func RegisterHandlers(engine *gin.Engine, directorEnabled bool) error {
 
 		// Create a route group for this prefix
 		group := engine.Group(routePrefix)
+		group.Use(tracing.Middleware("origin"))
 		group.Use(httpMetricsMiddleware())
 		group.Use(authMiddleware())
 		group.Use(xrdMonitoringMiddleware())
@@ -610,6 +655,42 @@ func RegisterHandlers(engine *gin.Engine, directorEnabled bool) error {
 			// that forward requests can propagate them.
 			req := server_utils.StashPelicanHeaders(c.Request)
 
+			// Chunked uploads are staged next to the destination and renamed
+			// into place, so they're limited to local storage.
+			if isChunkedUploadRequest(req) {
+				if _, isLocal := backend.(*localBackend); !isLocal || !param.Origin_EnableChunkedUploads.GetBool() {
+					c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": "chunked uploads are not supported by this export"})
+					return
+				}
+				c.Request = req
+				handleChunkedUpload(c, handler.FileSystem, wildcardPath)
+				return
+			}
+
+			// Third-party copies pull the object from another server into the export
+			if isThirdPartyCopyRequest(req) {
+				if !param.Origin_EnableThirdPartyCopy.GetBool() {
+					c.AbortWithStatusJSON(http.StatusNotImplemented, gin.H{"error": "third-party copies are not supported by this origin"})
+					return
+				}
+				c.Request = req
+				handleThirdPartyCopy(c, handler.FileSystem, wildcardPath)
+				return
+			}
+
+			// Proxying backends get a chance to report upstream
+			// failures with a meaningful status before the WebDAV
+			// handler runs.
+			if preparer, ok := backend.(server_utils.OriginRequestPreparer); ok &&
+				(c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead) {
+				ctx, err := preparer.PrepareRequest(req.Context(), wildcardPath)
+				if err != nil {
+					c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+					return
+				}
+				req = req.WithContext(ctx)
+			}
+
 			if c.Request.Method == http.MethodHead {
 				// For HEAD requests, pass the original request to the WebDAV handler
 				// (it needs the full URL so its Prefix stripping works correctly).
@@ -0,0 +1,548 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/pkg/errors"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// ---------------------------------------------------------------------------
+// httpsBackend — read-only OriginBackend proxying to an upstream HTTP server
+// ---------------------------------------------------------------------------
+
+// httpsListingTTL is how long entries returned by an upstream listing
+// are reused to answer Stat calls.  The WebDAV PROPFIND handler stats
+// every child of a collection, so without this a listing of N entries
+// would cost N extra upstream HEAD requests.
+const httpsListingTTL = 10 * time.Second
+
+// httpsBackend serves an export by forwarding reads, HEADs and WebDAV
+// listings to the upstream server at Origin.HttpServiceUrl.  Objects
+// live at <service URL>/<storage prefix>/<path>.  The export is
+// read-only; mutating operations fail with os.ErrPermission.
+type httpsBackend struct {
+	serviceURL    *url.URL
+	storagePrefix string
+	tokenFile     string
+	httpClient    *http.Client
+	listingCache  *ttlcache.Cache[string, os.FileInfo]
+}
+
+// upstreamError reports a non-successful response from the upstream
+// server.  It implements server_utils.HTTPStatusCoder so that the
+// origin's ErrorHandler relays an appropriate status to the client.
+type upstreamError struct {
+	op         string
+	name       string
+	statusCode int
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("upstream %s of %s failed with status %d", e.op, e.name, e.statusCode)
+}
+
+// HTTPStatusCode translates the upstream status for the client.  Client
+// errors that describe the object are passed through; authorization
+// failures mean the origin's own credentials were rejected, which the
+// client sees as a 403; anything else is a gateway failure.
+func (e *upstreamError) HTTPStatusCode() int {
+	switch e.statusCode {
+	case http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return e.statusCode
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// Unwrap exposes the matching fs error so that the WebDAV handler
+// treats upstream 404s and 403s like their local equivalents.
+func (e *upstreamError) Unwrap() error {
+	switch e.statusCode {
+	case http.StatusNotFound:
+		return os.ErrNotExist
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return os.ErrPermission
+	}
+	return nil
+}
+
+// newHTTPSBackend constructs the backend for an HTTPS export.
+func newHTTPSBackend(export server_utils.OriginExport) (*httpsBackend, error) {
+	serviceURL, err := url.Parse(param.Origin_HttpServiceUrl.GetString())
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", param.Origin_HttpServiceUrl.GetName())
+	}
+	if serviceURL.Scheme == "" || serviceURL.Host == "" {
+		return nil, errors.Errorf("%s must be an absolute URL", param.Origin_HttpServiceUrl.GetName())
+	}
+	return newHTTPSBackendFromURL(serviceURL, export.StoragePrefix, param.Origin_HttpAuthTokenFile.GetString(),
+		&http.Client{Transport: config.GetTransport()}), nil
+}
+
+// newHTTPSBackendFromURL builds an httpsBackend for an already-parsed
+// service URL and HTTP client.
+func newHTTPSBackendFromURL(serviceURL *url.URL, storagePrefix, tokenFile string, client *http.Client) *httpsBackend {
+	return &httpsBackend{
+		serviceURL:    serviceURL,
+		storagePrefix: storagePrefix,
+		tokenFile:     tokenFile,
+		httpClient:    client,
+		listingCache: ttlcache.New[string, os.FileInfo](
+			ttlcache.WithTTL[string, os.FileInfo](httpsListingTTL),
+			ttlcache.WithCapacity[string, os.FileInfo](4096),
+			ttlcache.WithDisableTouchOnHit[string, os.FileInfo](),
+		),
+	}
+}
+
+// CheckAvailability fails when a token file is configured but cannot be
+// read, since every upstream request would then be unauthenticated.
+func (b *httpsBackend) CheckAvailability() error {
+	if b.tokenFile == "" {
+		return nil
+	}
+	if _, err := b.readToken(); err != nil {
+		return &backendUnavailableError{statusCode: http.StatusServiceUnavailable, message: "origin's upstream credentials are unavailable"}
+	}
+	return nil
+}
+
+func (b *httpsBackend) FileSystem() webdav.FileSystem               { return b }
+func (b *httpsBackend) Checksummer() server_utils.OriginChecksummer { return b }
+
+// backendUnavailableError is returned from CheckAvailability and carries
+// the status code for the client.
+type backendUnavailableError struct {
+	statusCode int
+	message    string
+}
+
+func (e *backendUnavailableError) Error() string       { return e.message }
+func (e *backendUnavailableError) HTTPStatusCode() int { return e.statusCode }
+
+// readToken returns the bearer token for the upstream server.  The file
+// is re-read on every request so that externally refreshed tokens are
+// picked up without restarting the origin.
+func (b *httpsBackend) readToken() (string, error) {
+	data, err := os.ReadFile(b.tokenFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", param.Origin_HttpAuthTokenFile.GetName())
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// upstreamURL maps a path within the export onto the upstream server.
+// A trailing slash is preserved because many servers only answer
+// collection requests on the slash-terminated URL.
+func (b *httpsBackend) upstreamURL(name string) string {
+	u := *b.serviceURL
+	p := path.Join("/", u.Path, b.storagePrefix, path.Clean("/"+name))
+	if strings.HasSuffix(name, "/") && !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	u.Path = p
+	u.RawPath = ""
+	return u.String()
+}
+
+// do sends a request to the upstream server with the configured token
+// and the client's Pelican headers attached.
+func (b *httpsBackend) do(ctx context.Context, method, name string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.upstreamURL(name), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create upstream %s request", method)
+	}
+	for k, vals := range header {
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
+	if b.tokenFile != "" {
+		tok, err := b.readToken()
+		if err != nil {
+			return nil, err
+		}
+		if tok != "" {
+			req.Header.Set("Authorization", "Bearer "+tok)
+		}
+	}
+	setPelicanHeaders(ctx, req)
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "upstream %s request failed", method)
+	}
+	return resp, nil
+}
+
+// head issues a HEAD for name and returns the response headers.
+func (b *httpsBackend) head(ctx context.Context, name string, header http.Header) (*http.Response, error) {
+	resp, err := b.do(ctx, http.MethodHead, name, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &upstreamError{op: "HEAD", name: name, statusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// httpsStatKey is the context key under which PrepareRequest stashes the
+// stat result for the request's object.
+type httpsStatKey struct{}
+
+type httpsStatEntry struct {
+	name string
+	info os.FileInfo
+}
+
+// PrepareRequest stats the object upstream before a GET or HEAD so that
+// upstream failures reach the client with the right status.  The result
+// is stashed in the context so OpenFile does not repeat the HEAD.
+func (b *httpsBackend) PrepareRequest(ctx context.Context, relativePath string) (context.Context, error) {
+	info, err := b.Stat(ctx, relativePath)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, httpsStatKey{}, &httpsStatEntry{name: path.Clean("/" + relativePath), info: info}), nil
+}
+
+func (b *httpsBackend) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (b *httpsBackend) RemoveAll(ctx context.Context, name string) error {
+	return os.ErrPermission
+}
+
+func (b *httpsBackend) Rename(ctx context.Context, oldName, newName string) error {
+	return os.ErrPermission
+}
+
+func (b *httpsBackend) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, os.ErrPermission
+	}
+	info, err := b.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &httpsFile{backend: b, ctx: ctx, name: name, info: info}, nil
+}
+
+// Stat HEADs the object upstream.  Servers that answer a collection HEAD
+// with a redirect to the slash-terminated URL, or that mark it with a
+// directory content type, are reported as directories.
+func (b *httpsBackend) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	clean := path.Clean("/" + name)
+	if entry, ok := ctx.Value(httpsStatKey{}).(*httpsStatEntry); ok && entry.name == clean {
+		return entry.info, nil
+	}
+	if clean == "/" {
+		return &httpsFileInfo{name: "/", isDir: true, modTime: time.Now()}, nil
+	}
+	if item := b.listingCache.Get(clean); item != nil {
+		return item.Value(), nil
+	}
+
+	resp, err := b.head(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	info := &httpsFileInfo{
+		name:    path.Base(clean),
+		size:    resp.ContentLength,
+		modTime: time.Now(),
+		etag:    resp.Header.Get("ETag"),
+	}
+	if info.size < 0 {
+		info.size = 0
+	}
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			info.modTime = t
+		}
+	}
+	if ct := resp.Header.Get("Content-Type"); strings.HasPrefix(ct, "httpd/unix-directory") ||
+		strings.HasSuffix(resp.Request.URL.Path, "/") {
+		info.isDir = true
+	}
+	return info, nil
+}
+
+// GetDigests forwards the client's Want-Digest to the upstream server and
+// returns the values from its Digest response header.
+func (b *httpsBackend) GetDigests(relativePath string, wantDigest string) ([]string, error) {
+	resp, err := b.head(context.Background(), relativePath, http.Header{"Want-Digest": {wantDigest}})
+	if err != nil {
+		return nil, err
+	}
+	var digests []string
+	for _, hdr := range resp.Header.Values("Digest") {
+		for _, d := range strings.Split(hdr, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				digests = append(digests, d)
+			}
+		}
+	}
+	return digests, nil
+}
+
+// httpsFileInfo implements os.FileInfo and webdav.ETager for upstream
+// objects.
+type httpsFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	etag    string
+}
+
+func (fi *httpsFileInfo) Name() string       { return fi.name }
+func (fi *httpsFileInfo) Size() int64        { return fi.size }
+func (fi *httpsFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *httpsFileInfo) IsDir() bool        { return fi.isDir }
+func (fi *httpsFileInfo) Sys() interface{}   { return nil }
+func (fi *httpsFileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+
+// ETag passes the upstream ETag through to clients.
+func (fi *httpsFileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+// ---------------------------------------------------------------------------
+// httpsFile — read-only webdav.File for an upstream object
+// ---------------------------------------------------------------------------
+
+// httpsFile opens a GET lazily on the first Read, starting at the
+// current offset with a Range header, and reopens it after a Seek.
+type httpsFile struct {
+	backend    *httpsBackend
+	ctx        context.Context
+	name       string
+	info       os.FileInfo
+	reader     io.ReadCloser
+	readOffset int64
+
+	// Listing; the collection is fetched once and handed out across Readdir calls
+	dirEntries []os.FileInfo
+	dirListed  bool
+}
+
+func (f *httpsFile) Read(p []byte) (int, error) {
+	if f.info.IsDir() {
+		return 0, errors.New("is a directory")
+	}
+	if f.readOffset >= f.info.Size() && f.info.Size() > 0 {
+		return 0, io.EOF
+	}
+	if f.reader == nil {
+		header := http.Header{}
+		if f.readOffset > 0 {
+			header.Set("Range", fmt.Sprintf("bytes=%d-", f.readOffset))
+		}
+		resp, err := f.backend.do(f.ctx, http.MethodGet, f.name, header, nil)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case resp.StatusCode == http.StatusPartialContent:
+		case resp.StatusCode == http.StatusOK && f.readOffset == 0:
+		case resp.StatusCode == http.StatusOK:
+			// The server ignored our Range header; skip ahead ourselves.
+			if _, err := io.CopyN(io.Discard, resp.Body, f.readOffset); err != nil {
+				resp.Body.Close()
+				return 0, errors.Wrap(err, "failed to skip to requested offset")
+			}
+		default:
+			resp.Body.Close()
+			return 0, &upstreamError{op: "GET", name: f.name, statusCode: resp.StatusCode}
+		}
+		f.reader = resp.Body
+	}
+	n, err := f.reader.Read(p)
+	f.readOffset += int64(n)
+	return n, err
+}
+
+func (f *httpsFile) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.readOffset + offset
+	case io.SeekEnd:
+		newOffset = f.info.Size() + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+	if newOffset != f.readOffset && f.reader != nil {
+		f.reader.Close()
+		f.reader = nil
+	}
+	f.readOffset = newOffset
+	return newOffset, nil
+}
+
+func (f *httpsFile) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *httpsFile) Close() error {
+	if f.reader != nil {
+		err := f.reader.Close()
+		f.reader = nil
+		return err
+	}
+	return nil
+}
+
+func (f *httpsFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// httpsMultistatus is the subset of a WebDAV PROPFIND response needed to
+// build directory listings.
+type httpsMultistatus struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		Href     string `xml:"href"`
+		PropStat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ContentLength string `xml:"getcontentlength"`
+				LastModified  string `xml:"getlastmodified"`
+				ETag          string `xml:"getetag"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// Readdir lists the collection with an upstream PROPFIND (Depth: 1).
+// As with os.File, a positive count returns at most count entries per
+// call and io.EOF once the listing is exhausted; otherwise all remaining
+// entries are returned.
+func (f *httpsFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.dirListed {
+		infos, err := f.listDir()
+		if err != nil {
+			return nil, err
+		}
+		f.dirEntries = infos
+		f.dirListed = true
+	}
+
+	if count <= 0 {
+		infos := f.dirEntries
+		f.dirEntries = nil
+		return infos, nil
+	}
+	if len(f.dirEntries) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(f.dirEntries))
+	infos := f.dirEntries[:count:count]
+	f.dirEntries = f.dirEntries[count:]
+	return infos, nil
+}
+
+// listDir fetches every immediate child of the collection
+func (f *httpsFile) listDir() ([]os.FileInfo, error) {
+	dirName := strings.TrimSuffix(f.name, "/") + "/"
+	resp, err := f.backend.do(f.ctx, "PROPFIND", dirName, http.Header{"Depth": {"1"}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, &upstreamError{op: "PROPFIND", name: f.name, statusCode: resp.StatusCode}
+	}
+
+	var ms httpsMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, errors.Wrap(err, "failed to parse upstream PROPFIND response")
+	}
+
+	self := path.Clean(resp.Request.URL.Path)
+	dirClean := path.Clean("/" + f.name)
+	var infos []os.FileInfo
+	for _, r := range ms.Responses {
+		hrefPath := r.Href
+		if u, err := url.Parse(r.Href); err == nil {
+			hrefPath = u.Path
+		}
+		hrefPath = path.Clean(hrefPath)
+		if hrefPath == self || len(r.PropStat) == 0 {
+			continue
+		}
+		prop := r.PropStat[0].Prop
+		info := &httpsFileInfo{
+			name:    path.Base(hrefPath),
+			isDir:   prop.ResourceType.Collection != nil,
+			modTime: time.Now(),
+			etag:    prop.ETag,
+		}
+		if prop.ContentLength != "" {
+			info.size, _ = strconv.ParseInt(prop.ContentLength, 10, 64)
+		}
+		if prop.LastModified != "" {
+			if t, err := http.ParseTime(prop.LastModified); err == nil {
+				info.modTime = t
+			}
+		}
+		infos = append(infos, info)
+		f.backend.listingCache.Set(path.Join(dirClean, info.name), info, ttlcache.DefaultTTL)
+	}
+	return infos, nil
+}
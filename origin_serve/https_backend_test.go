@@ -0,0 +1,191 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// newTestUpstream serves /upstream/files/{hello.txt,sub/} and rejects
+// requests lacking the expected bearer token.
+func newTestUpstream(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	content := []byte("hello from upstream")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/upstream/files/hello.txt" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+			if strings.Contains(r.Header.Get("Want-Digest"), "md5") {
+				w.Header().Set("Digest", "md5=d2h5bm90")
+			}
+			w.Header().Set("ETag", `"abc"`)
+			http.ServeContent(w, r, "hello.txt", time.Unix(1700000000, 0), bytes.NewReader(content))
+		case r.URL.Path == "/upstream/files/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/upstream/files/" && r.Method == "PROPFIND":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0"?><D:multistatus xmlns:D="DAV:">`+
+				`<D:response><D:href>/upstream/files/</D:href><D:propstat><D:prop><D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>`+
+				`<D:response><D:href>/upstream/files/hello.txt</D:href><D:propstat><D:prop><D:resourcetype/><D:getcontentlength>19</D:getcontentlength></D:prop></D:propstat></D:response>`+
+				`<D:response><D:href>/upstream/files/sub/</D:href><D:propstat><D:prop><D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>`+
+				`</D:multistatus>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestHTTPSBackend(t *testing.T, srv *httptest.Server, token string) *httpsBackend {
+	t.Helper()
+	tokFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokFile, []byte(token+"\n"), 0600))
+	u, err := url.Parse(srv.URL + "/upstream")
+	require.NoError(t, err)
+	return newHTTPSBackendFromURL(u, "/files", tokFile, srv.Client())
+}
+
+func TestHTTPSBackend(t *testing.T) {
+	srv := newTestUpstream(t, "secret")
+	backend := newTestHTTPSBackend(t, srv, "secret")
+	handler := &webdav.Handler{FileSystem: backend.FileSystem(), LockSystem: webdav.NewMemLS()}
+
+	do := func(method, target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.NoError(t, backend.CheckAvailability())
+
+	t.Run("Get", func(t *testing.T) {
+		rec := do(http.MethodGet, "/hello.txt", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "hello from upstream", rec.Body.String())
+		assert.Equal(t, `"abc"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("GetRange", func(t *testing.T) {
+		rec := do(http.MethodGet, "/hello.txt", map[string]string{"Range": "bytes=6-9"})
+		require.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "from", rec.Body.String())
+	})
+
+	t.Run("Propfind", func(t *testing.T) {
+		rec := do("PROPFIND", "/", map[string]string{"Depth": "1"})
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		assert.Contains(t, rec.Body.String(), "/hello.txt")
+		assert.Contains(t, rec.Body.String(), "/sub/")
+	})
+
+	t.Run("ReaddirCount", func(t *testing.T) {
+		dir, err := backend.FileSystem().OpenFile(t.Context(), "/", 0, 0)
+		require.NoError(t, err)
+		defer dir.Close()
+
+		// Entries are handed out at most count at a time, then io.EOF
+		var names []string
+		for {
+			infos, err := dir.Readdir(1)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.Len(t, infos, 1)
+			for _, info := range infos {
+				names = append(names, info.Name())
+			}
+		}
+		assert.ElementsMatch(t, []string{"hello.txt", "sub"}, names)
+
+		// A non-positive count returns whatever is left, without an error
+		infos, err := dir.Readdir(-1)
+		require.NoError(t, err)
+		assert.Empty(t, infos)
+	})
+
+	t.Run("PutRejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/new.txt", strings.NewReader("data"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.NotEqual(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		digests, err := backend.Checksummer().GetDigests("/hello.txt", "md5")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"md5=d2h5bm90"}, digests)
+	})
+
+	t.Run("PrepareRequestStatus", func(t *testing.T) {
+		var preparer server_utils.OriginRequestPreparer = backend
+		eh := NewErrorHandler()
+
+		ctx, err := preparer.PrepareRequest(context.Background(), "/hello.txt")
+		require.NoError(t, err)
+		info, err := backend.Stat(ctx, "/hello.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int64(19), info.Size())
+
+		_, err = preparer.PrepareRequest(context.Background(), "/missing")
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, eh.MapToHTTPStatus(err))
+
+		_, err = preparer.PrepareRequest(context.Background(), "/broken")
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadGateway, eh.MapToHTTPStatus(err))
+	})
+}
+
+func TestHTTPSBackendBadToken(t *testing.T) {
+	srv := newTestUpstream(t, "secret")
+	backend := newTestHTTPSBackend(t, srv, "wrong")
+
+	_, err := backend.PrepareRequest(context.Background(), "/hello.txt")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, NewErrorHandler().MapToHTTPStatus(err))
+
+	backend.tokenFile = filepath.Join(t.TempDir(), "missing")
+	assert.Error(t, backend.CheckAvailability())
+}
@@ -40,6 +40,7 @@ import (
 	promtest "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/pelicanplatform/pelican/metrics"
 	"github.com/pelicanplatform/pelican/server_structs"
@@ -118,7 +119,8 @@ func setupE2ETestServer(t *testing.T) (*httptest.Server, string, func()) {
 	}
 
 	// Initialize handlers
-	err = InitializeHandlers(t.Context(), exports)
+	var egrp errgroup.Group
+	err = InitializeHandlers(t.Context(), &egrp, exports)
 	require.NoError(t, err)
 
 	// Initialize auth config for the test (required even for public reads)
@@ -283,7 +285,8 @@ func TestMetricsRecordedForAuthRejection(t *testing.T) {
 		},
 	}
 
-	require.NoError(t, InitializeHandlers(context.Background(), exports))
+	var egrp errgroup.Group
+	require.NoError(t, InitializeHandlers(context.Background(), &egrp, exports))
 
 	ac := &authConfig{}
 	ac.exports.Store(&exports)
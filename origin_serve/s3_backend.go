@@ -0,0 +1,993 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+const (
+	// s3DefaultPartSize is the size of each part in a multipart upload.
+	// S3 requires every part except the last to be at least 5 MiB.
+	s3DefaultPartSize = 16 * 1024 * 1024
+
+	// s3UnsignedPayload is used as the payload hash for all requests so
+	// that request bodies can be streamed without hashing them first.
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// ---------------------------------------------------------------------------
+// s3Client — minimal SigV4-signed client for the S3 REST API
+// ---------------------------------------------------------------------------
+
+// s3Client issues the subset of S3 REST calls needed by the origin:
+// HEAD/GET/PUT/DELETE object, ListObjectsV2, CopyObject and the
+// multipart upload calls.  Requests are signed with AWS Signature V4
+// when credentials are configured and sent anonymously otherwise.
+type s3Client struct {
+	serviceURL *url.URL
+	region     string
+	urlStyle   string // "path" or "virtual"
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+}
+
+// s3Error describes a non-successful response from the S3 service.
+type s3Error struct {
+	op         string
+	key        string
+	statusCode int
+	code       string
+}
+
+func (e *s3Error) Error() string {
+	if e.code != "" {
+		return fmt.Sprintf("S3 %s of %q failed with status %d (%s)", e.op, e.key, e.statusCode, e.code)
+	}
+	return fmt.Sprintf("S3 %s of %q failed with status %d", e.op, e.key, e.statusCode)
+}
+
+// Unwrap maps well-known S3 status codes onto the fs errors that the
+// webdav handler and ErrorHandler understand.
+func (e *s3Error) Unwrap() error {
+	switch e.statusCode {
+	case http.StatusNotFound:
+		return os.ErrNotExist
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return os.ErrPermission
+	}
+	return nil
+}
+
+// newS3Error builds an s3Error from resp, consuming (but not closing)
+// the response body to extract the S3 error code.
+func newS3Error(op, key string, resp *http.Response) error {
+	e := &s3Error{op: op, key: key, statusCode: resp.StatusCode}
+	var body struct {
+		Code string `xml:"Code"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024)); err == nil && len(data) > 0 {
+		if xml.Unmarshal(data, &body) == nil {
+			e.code = body.Code
+		}
+	}
+	return e
+}
+
+// s3URIEncode percent-encodes s as required by SigV4: every byte other
+// than the RFC 3986 unreserved characters is escaped.  When keepSlash is
+// set, '/' is left intact (used for object key paths).
+func s3URIEncode(s string, keepSlash bool) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' || (keepSlash && c == '/') {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// s3CanonicalQuery returns the SigV4 canonical form of query, which is
+// also used verbatim as the request's raw query so both always agree.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		vals := append([]string(nil), query[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, s3URIEncode(k, false)+"="+s3URIEncode(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// objectURL returns the URL of key in bucket honoring the configured
+// URL style.  An empty key addresses the bucket itself.
+func (c *s3Client) objectURL(bucket, key string, query url.Values) *url.URL {
+	u := *c.serviceURL
+	basePath := strings.TrimSuffix(u.Path, "/")
+	objPath := "/" + key
+	if c.urlStyle == "virtual" && bucket != "" {
+		u.Host = bucket + "." + u.Host
+	} else if bucket != "" {
+		objPath = "/" + bucket + objPath
+	}
+	u.Path = basePath + objPath
+	u.RawPath = s3URIEncode(basePath, true) + s3URIEncode(objPath, true)
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// sign adds AWS Signature V4 headers to req.  Only the host and x-amz-*
+// headers are signed; the payload is always declared unsigned.
+func (c *s3Client) sign(req *http.Request, now time.Time) {
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	if c.accessKey == "" {
+		return
+	}
+
+	amzDate := now.UTC().Format("20060102T150405Z")
+	shortDate := now.UTC().Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headerNames := []string{"host"}
+	canonicalHeaders := map[string]string{"host": req.URL.Host}
+	for name, vals := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headerNames = append(headerNames, lower)
+			canonicalHeaders[lower] = strings.TrimSpace(strings.Join(vals, ","))
+		}
+	}
+	sort.Strings(headerNames)
+
+	var canonHdr strings.Builder
+	for _, name := range headerNames {
+		canonHdr.WriteString(name + ":" + canonicalHeaders[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHdr.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := shortDate + "/" + c.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(crHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+// do builds, signs and sends a request.  body may be nil; when it is a
+// *bytes.Reader the content length is set from it.
+func (c *s3Client) do(ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body io.Reader) (*http.Response, error) {
+	u := c.objectURL(bucket, key, query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create S3 %s request", method)
+	}
+	// http.NewRequest re-parses the URL; restore our exact encoding so
+	// the signed path is the one sent on the wire.
+	req.URL = u
+	for name, vals := range header {
+		for _, v := range vals {
+			req.Header.Add(name, v)
+		}
+	}
+	setPelicanHeaders(ctx, req)
+	c.sign(req, time.Now())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "S3 %s request failed", method)
+	}
+	return resp, nil
+}
+
+// s3ObjectInfo is the metadata returned by headObject and listObjects.
+type s3ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	ETag         string
+}
+
+func (c *s3Client) headObject(ctx context.Context, bucket, key string) (*s3ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, bucket, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newS3Error("HEAD", key, resp)
+	}
+	info := &s3ObjectInfo{Key: key, Size: resp.ContentLength, ETag: resp.Header.Get("ETag")}
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			info.LastModified = t
+		}
+	}
+	return info, nil
+}
+
+// getObject starts a GET of key beginning at offset.  The caller must
+// close the returned body.  Reading at or past the end of the object
+// yields an empty body.
+func (c *s3Client) getObject(ctx context.Context, bucket, key string, offset int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.do(ctx, http.MethodGet, bucket, key, nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	default:
+		defer resp.Body.Close()
+		return nil, newS3Error("GET", key, resp)
+	}
+}
+
+func (c *s3Client) putObject(ctx context.Context, bucket, key string, data []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, bucket, key, nil, nil, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newS3Error("PUT", key, resp)
+	}
+	return nil
+}
+
+func (c *s3Client) copyObject(ctx context.Context, bucket, srcKey, dstKey string) error {
+	src := "/" + srcKey
+	if bucket != "" {
+		src = "/" + bucket + src
+	}
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", s3URIEncode(src, true))
+	resp, err := c.do(ctx, http.MethodPut, bucket, dstKey, nil, header, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newS3Error("COPY", srcKey, resp)
+	}
+	return nil
+}
+
+func (c *s3Client) deleteObject(ctx context.Context, bucket, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return newS3Error("DELETE", key, resp)
+	}
+	return nil
+}
+
+// s3ListResult is the ListObjectsV2 response body.
+type s3ListResult struct {
+	XMLName               xml.Name `xml:"ListBucketResult"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+}
+
+// listObjects pages through ListObjectsV2 for prefix.  When delimiter
+// is non-empty, the returned prefixes are the "subdirectories" directly
+// beneath prefix.  Listing stops once maxKeys entries (objects plus
+// prefixes) have been collected; maxKeys <= 0 means no limit.
+func (c *s3Client) listObjects(ctx context.Context, bucket, prefix, delimiter string, maxKeys int) (objects []s3ObjectInfo, prefixes []string, err error) {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if maxKeys > 0 {
+			query.Set("max-keys", strconv.Itoa(maxKeys))
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := c.do(ctx, http.MethodGet, bucket, "", query, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = newS3Error("LIST", prefix, resp)
+			resp.Body.Close()
+			return nil, nil, err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse S3 ListObjectsV2 response")
+		}
+
+		for _, obj := range result.Contents {
+			info := s3ObjectInfo{Key: obj.Key, Size: obj.Size, ETag: obj.ETag}
+			if t, err := time.Parse(time.RFC3339, obj.LastModified); err == nil {
+				info.LastModified = t
+			}
+			objects = append(objects, info)
+		}
+		for _, p := range result.CommonPrefixes {
+			prefixes = append(prefixes, p.Prefix)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, prefixes, nil
+		}
+		if maxKeys > 0 && len(objects)+len(prefixes) >= maxKeys {
+			return objects, prefixes, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (c *s3Client) createMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newS3Error("CreateMultipartUpload", key, resp)
+	}
+	var result struct {
+		UploadId string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", errors.Wrap(err, "failed to parse S3 CreateMultipartUpload response")
+	}
+	if result.UploadId == "" {
+		return "", errors.New("S3 CreateMultipartUpload response did not contain an upload ID")
+	}
+	return result.UploadId, nil
+}
+
+func (c *s3Client) uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int, data []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+	resp, err := c.do(ctx, http.MethodPut, bucket, key, query, nil, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newS3Error("UploadPart", key, resp)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// s3CompletedPart is one entry of a CompleteMultipartUpload request.
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (c *s3Client) completeMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode S3 CompleteMultipartUpload request")
+	}
+	resp, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadID}}, nil, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newS3Error("CompleteMultipartUpload", key, resp)
+	}
+	// S3 may report a failure inside a 200 response.
+	data, _ := io.ReadAll(resp.Body)
+	var errBody struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+	}
+	if xml.Unmarshal(data, &errBody) == nil && errBody.Code != "" {
+		return &s3Error{op: "CompleteMultipartUpload", key: key, statusCode: resp.StatusCode, code: errBody.Code}
+	}
+	return nil
+}
+
+func (c *s3Client) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return newS3Error("AbortMultipartUpload", key, resp)
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// s3Backend — OriginBackend / webdav.FileSystem backed by an S3 bucket
+// ---------------------------------------------------------------------------
+
+// s3Backend serves an export from an S3-compatible bucket.  S3 has no
+// real directories; a "directory" exists whenever at least one key lives
+// beneath it, and MKCOL creates an empty "<dir>/" marker object.
+type s3Backend struct {
+	client        *s3Client
+	bucket        string
+	storagePrefix string
+	partSize      int
+}
+
+// readS3Keyfile returns the trimmed contents of an S3 key file.
+func readS3Keyfile(name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read S3 key file %s", name)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// newS3Backend constructs the backend for an S3 export using the
+// per-export bucket/key files and the origin-wide S3 service settings.
+func newS3Backend(export server_utils.OriginExport) (*s3Backend, error) {
+	serviceURL, err := url.Parse(param.Origin_S3ServiceUrl.GetString())
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %s", param.Origin_S3ServiceUrl.GetName())
+	}
+	if serviceURL.Scheme == "" || serviceURL.Host == "" {
+		return nil, errors.Errorf("%s must be an absolute URL", param.Origin_S3ServiceUrl.GetName())
+	}
+
+	urlStyle := param.Origin_S3UrlStyle.GetString()
+	if urlStyle == "" {
+		urlStyle = "path"
+	}
+	if urlStyle != "path" && urlStyle != "virtual" {
+		return nil, errors.Errorf("invalid %s %q; must be \"path\" or \"virtual\"", param.Origin_S3UrlStyle.GetName(), urlStyle)
+	}
+	if urlStyle == "virtual" && export.S3Bucket == "" {
+		return nil, errors.Errorf("export %s must set a bucket when using virtual-style S3 URLs", export.FederationPrefix)
+	}
+
+	client := &s3Client{
+		serviceURL: serviceURL,
+		region:     param.Origin_S3Region.GetString(),
+		urlStyle:   urlStyle,
+		httpClient: &http.Client{Transport: config.GetTransport()},
+	}
+	if export.S3AccessKeyfile != "" {
+		if client.accessKey, err = readS3Keyfile(export.S3AccessKeyfile); err != nil {
+			return nil, err
+		}
+		if client.secretKey, err = readS3Keyfile(export.S3SecretKeyfile); err != nil {
+			return nil, err
+		}
+		if client.region == "" {
+			return nil, errors.Errorf("%s is required to sign S3 requests", param.Origin_S3Region.GetName())
+		}
+	}
+
+	return &s3Backend{
+		client:        client,
+		bucket:        export.S3Bucket,
+		storagePrefix: export.StoragePrefix,
+		partSize:      s3DefaultPartSize,
+	}, nil
+}
+
+func (b *s3Backend) CheckAvailability() error      { return nil }
+func (b *s3Backend) FileSystem() webdav.FileSystem { return b }
+
+// Checksummer returns nil: S3 ETags are only MD5 digests for
+// single-part, unencrypted uploads, so they cannot be advertised as a
+// content digest in general.
+func (b *s3Backend) Checksummer() server_utils.OriginChecksummer { return nil }
+
+// resolve maps a WebDAV name onto a bucket and object key.  When the
+// export has no bucket configured, the first path component names the
+// bucket (path-style access to all buckets at the service URL).
+func (b *s3Backend) resolve(name string) (bucket, key string, err error) {
+	full := strings.TrimPrefix(path.Join("/", b.storagePrefix, path.Clean("/"+name)), "/")
+	if b.bucket != "" {
+		return b.bucket, full, nil
+	}
+	bucket, key, _ = strings.Cut(full, "/")
+	if bucket == "" {
+		return "", "", errors.Wrap(os.ErrPermission, "listing buckets is not supported")
+	}
+	return bucket, key, nil
+}
+
+// dirPrefix returns the key prefix under which children of key live.
+func dirPrefix(key string) string {
+	if key == "" {
+		return ""
+	}
+	return key + "/"
+}
+
+func (b *s3Backend) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if _, err := b.Stat(ctx, name); err == nil {
+		return os.ErrExist
+	}
+	bucket, key, err := b.resolve(name)
+	if err != nil {
+		return err
+	}
+	return b.client.putObject(ctx, bucket, dirPrefix(key), nil)
+}
+
+func (b *s3Backend) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	bucket, key, err := b.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	f := &s3File{backend: b, ctx: ctx, name: name, bucket: bucket, key: key}
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC) != 0 {
+		f.writing = true
+		f.modTime = time.Now()
+		return f, nil
+	}
+	info, err := b.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	f.info = info
+	return f, nil
+}
+
+func (b *s3Backend) RemoveAll(ctx context.Context, name string) error {
+	bucket, key, err := b.resolve(name)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		// Prohibit removing the root of the export, as webdav.Dir does.
+		return os.ErrInvalid
+	}
+	if err := b.client.deleteObject(ctx, bucket, key); err != nil {
+		return err
+	}
+	objects, _, err := b.client.listObjects(ctx, bucket, dirPrefix(key), "", 0)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := b.client.deleteObject(ctx, bucket, obj.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rename copies every object under oldName to newName and then deletes
+// the originals.  S3 has no atomic rename, so a failure part way through
+// may leave objects at both locations.
+func (b *s3Backend) Rename(ctx context.Context, oldName, newName string) error {
+	bucket, oldKey, err := b.resolve(oldName)
+	if err != nil {
+		return err
+	}
+	newBucket, newKey, err := b.resolve(newName)
+	if err != nil {
+		return err
+	}
+	if bucket != newBucket {
+		return errors.Wrap(os.ErrInvalid, "cannot rename across S3 buckets")
+	}
+	if oldKey == "" || newKey == "" {
+		return os.ErrInvalid
+	}
+
+	info, err := b.Stat(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := b.client.copyObject(ctx, bucket, oldKey, newKey); err != nil {
+			return err
+		}
+		return b.client.deleteObject(ctx, bucket, oldKey)
+	}
+
+	objects, _, err := b.client.listObjects(ctx, bucket, dirPrefix(oldKey), "", 0)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		dst := dirPrefix(newKey) + strings.TrimPrefix(obj.Key, dirPrefix(oldKey))
+		if err := b.client.copyObject(ctx, bucket, obj.Key, dst); err != nil {
+			return err
+		}
+	}
+	for _, obj := range objects {
+		if err := b.client.deleteObject(ctx, bucket, obj.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stat issues a HEAD for the object and, if none exists, checks whether
+// any keys exist beneath the name so it can be reported as a directory.
+func (b *s3Backend) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	bucket, key, err := b.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(path.Clean("/" + name))
+	if key == "" {
+		return &s3FileInfo{name: base, isDir: true, modTime: time.Now()}, nil
+	}
+
+	obj, err := b.client.headObject(ctx, bucket, key)
+	if err == nil {
+		return &s3FileInfo{name: base, size: obj.Size, modTime: obj.LastModified, etag: obj.ETag}, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	objects, prefixes, err := b.client.listObjects(ctx, bucket, dirPrefix(key), "/", 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 && len(prefixes) == 0 {
+		return nil, os.ErrNotExist
+	}
+	modTime := time.Now()
+	if len(objects) > 0 && !objects[0].LastModified.IsZero() {
+		modTime = objects[0].LastModified
+	}
+	return &s3FileInfo{name: base, isDir: true, modTime: modTime}, nil
+}
+
+// s3FileInfo implements os.FileInfo and webdav.ETager for S3 objects.
+type s3FileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	etag    string
+}
+
+func (fi *s3FileInfo) Name() string       { return fi.name }
+func (fi *s3FileInfo) Size() int64        { return fi.size }
+func (fi *s3FileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *s3FileInfo) IsDir() bool        { return fi.isDir }
+func (fi *s3FileInfo) Sys() interface{}   { return nil }
+func (fi *s3FileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ETag returns the object's S3 ETag so clients see a stable validator.
+func (fi *s3FileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+// ---------------------------------------------------------------------------
+// s3File — webdav.File for a single S3 object or prefix
+// ---------------------------------------------------------------------------
+
+// s3File reads objects lazily with ranged GETs and writes them by
+// buffering one part at a time.  Small files are stored with a single
+// PUT on Close; once the buffer fills, a multipart upload is started and
+// completed on Close.
+type s3File struct {
+	backend *s3Backend
+	ctx     context.Context
+	name    string
+	bucket  string
+	key     string
+	info    os.FileInfo
+
+	// Reading
+	reader     io.ReadCloser
+	readOffset int64
+
+	// Listing; the prefix is listed once and handed out across Readdir calls
+	dirEntries []os.FileInfo
+	dirListed  bool
+
+	// Writing
+	writing  bool
+	buf      []byte
+	written  int64
+	uploadID string
+	parts    []s3CompletedPart
+	writeErr error
+	modTime  time.Time
+}
+
+func (f *s3File) Read(p []byte) (int, error) {
+	if f.writing {
+		return 0, os.ErrInvalid
+	}
+	if f.info != nil && f.info.IsDir() {
+		return 0, errors.New("is a directory")
+	}
+	if f.info != nil && f.readOffset >= f.info.Size() {
+		return 0, io.EOF
+	}
+	if f.reader == nil {
+		body, err := f.backend.client.getObject(f.ctx, f.bucket, f.key, f.readOffset)
+		if err != nil {
+			return 0, err
+		}
+		f.reader = body
+	}
+	n, err := f.reader.Read(p)
+	f.readOffset += int64(n)
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	var newOffset int64
+	switch whence {
+	case io.SeekStart:
+		newOffset = offset
+	case io.SeekCurrent:
+		newOffset = f.readOffset + offset
+	case io.SeekEnd:
+		info, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		newOffset = info.Size() + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if newOffset < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+	if newOffset != f.readOffset && f.reader != nil {
+		f.reader.Close()
+		f.reader = nil
+	}
+	f.readOffset = newOffset
+	return newOffset, nil
+}
+
+func (f *s3File) Write(p []byte) (int, error) {
+	if !f.writing {
+		return 0, os.ErrInvalid
+	}
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	f.buf = append(f.buf, p...)
+	f.written += int64(len(p))
+	f.modTime = time.Now()
+	for len(f.buf) >= f.backend.partSize {
+		if err := f.flushPart(f.buf[:f.backend.partSize]); err != nil {
+			f.writeErr = err
+			return 0, err
+		}
+		f.buf = append(f.buf[:0], f.buf[f.backend.partSize:]...)
+	}
+	return len(p), nil
+}
+
+// flushPart uploads data as the next part, starting the multipart
+// upload on first use.
+func (f *s3File) flushPart(data []byte) error {
+	if f.uploadID == "" {
+		id, err := f.backend.client.createMultipartUpload(f.ctx, f.bucket, f.key)
+		if err != nil {
+			return err
+		}
+		f.uploadID = id
+	}
+	partNumber := len(f.parts) + 1
+	etag, err := f.backend.client.uploadPart(f.ctx, f.bucket, f.key, f.uploadID, partNumber, data)
+	if err != nil {
+		return err
+	}
+	f.parts = append(f.parts, s3CompletedPart{PartNumber: partNumber, ETag: etag})
+	return nil
+}
+
+// finishWrite commits everything written so far to S3.
+func (f *s3File) finishWrite() error {
+	if f.writeErr != nil {
+		if f.uploadID != "" {
+			_ = f.backend.client.abortMultipartUpload(context.Background(), f.bucket, f.key, f.uploadID)
+		}
+		return f.writeErr
+	}
+	if f.uploadID == "" {
+		return f.backend.client.putObject(f.ctx, f.bucket, f.key, f.buf)
+	}
+	if len(f.buf) > 0 {
+		if err := f.flushPart(f.buf); err != nil {
+			_ = f.backend.client.abortMultipartUpload(context.Background(), f.bucket, f.key, f.uploadID)
+			return err
+		}
+	}
+	if err := f.backend.client.completeMultipartUpload(f.ctx, f.bucket, f.key, f.uploadID, f.parts); err != nil {
+		_ = f.backend.client.abortMultipartUpload(context.Background(), f.bucket, f.key, f.uploadID)
+		return err
+	}
+	return nil
+}
+
+func (f *s3File) Close() error {
+	var err error
+	if f.reader != nil {
+		err = f.reader.Close()
+		f.reader = nil
+	}
+	if f.writing {
+		f.writing = false
+		err = f.finishWrite()
+		f.buf = nil
+	}
+	return err
+}
+
+// Readdir lists the immediate children of the prefix using
+// ListObjectsV2 with a "/" delimiter.  As with os.File, a positive count
+// returns at most count entries per call and io.EOF once the listing is
+// exhausted; otherwise all remaining entries are returned.
+func (f *s3File) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.dirListed {
+		infos, err := f.listDir()
+		if err != nil {
+			return nil, err
+		}
+		f.dirEntries = infos
+		f.dirListed = true
+	}
+
+	if count <= 0 {
+		infos := f.dirEntries
+		f.dirEntries = nil
+		return infos, nil
+	}
+	if len(f.dirEntries) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(f.dirEntries))
+	infos := f.dirEntries[:count:count]
+	f.dirEntries = f.dirEntries[count:]
+	return infos, nil
+}
+
+// listDir fetches every immediate child of the prefix
+func (f *s3File) listDir() ([]os.FileInfo, error) {
+	prefix := dirPrefix(f.key)
+	objects, prefixes, err := f.backend.client.listObjects(f.ctx, f.bucket, prefix, "/", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]os.FileInfo, 0, len(objects)+len(prefixes))
+	for _, p := range prefixes {
+		name := strings.TrimSuffix(strings.TrimPrefix(p, prefix), "/")
+		if name == "" {
+			continue
+		}
+		infos = append(infos, &s3FileInfo{name: name, isDir: true, modTime: time.Now()})
+	}
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj.Key, prefix)
+		if name == "" {
+			// The directory marker object itself
+			continue
+		}
+		infos = append(infos, &s3FileInfo{name: name, size: obj.Size, modTime: obj.LastModified, etag: obj.ETag})
+	}
+	return infos, nil
+}
+
+// Stat returns the object's metadata.  While writing, the answer is
+// synthesised locally because the object does not exist in S3 until
+// Close completes the upload.
+func (f *s3File) Stat() (os.FileInfo, error) {
+	if f.writing {
+		return &s3FileInfo{name: path.Base(f.name), size: f.written, modTime: f.modTime}, nil
+	}
+	if f.info != nil {
+		return f.info, nil
+	}
+	info, err := f.backend.Stat(f.ctx, f.name)
+	if err != nil {
+		return nil, err
+	}
+	f.info = info
+	return info, nil
+}
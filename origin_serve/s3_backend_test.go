@@ -0,0 +1,340 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+)
+
+// fakeS3 is an in-memory, path-style S3 service implementing just enough
+// of the REST API for the s3Backend: object GET/HEAD/PUT/DELETE,
+// CopyObject, ListObjectsV2 and multipart uploads.
+type fakeS3 struct {
+	mu        sync.Mutex
+	bucket    string
+	objects   map[string][]byte
+	uploads   map[string]map[int][]byte
+	nextID    int
+	requireV4 bool
+	sawAuth   bool
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=") {
+		s.sawAuth = true
+	} else if s.requireV4 {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	q := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && q.Get("list-type") == "2":
+		s.list(w, q)
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		s.uploads[id] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		data, _ := io.ReadAll(r.Body)
+		num, _ := strconv.Atoi(q.Get("partNumber"))
+		s.uploads[q.Get("uploadId")][num] = data
+		w.Header().Set("ETag", fmt.Sprintf("\"part%d\"", num))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		parts := s.uploads[q.Get("uploadId")]
+		nums := make([]int, 0, len(parts))
+		for n := range parts {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		var buf bytes.Buffer
+		for _, n := range nums {
+			buf.Write(parts[n])
+		}
+		s.objects[key] = buf.Bytes()
+		delete(s.uploads, q.Get("uploadId"))
+		fmt.Fprint(w, "<CompleteMultipartUploadResult/>")
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		delete(s.uploads, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		src, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+		data, ok := s.objects[strings.TrimPrefix(src, "/"+s.bucket+"/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.objects[key] = append([]byte(nil), data...)
+		fmt.Fprint(w, "<CopyObjectResult/>")
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[key] = data
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", fmt.Sprintf("\"%x\"", len(data)))
+		http.ServeContent(w, r, key, time.Unix(1700000000, 0), bytes.NewReader(data))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeS3) list(w http.ResponseWriter, q url.Values) {
+	prefix, delim := q.Get("prefix"), q.Get("delimiter")
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var result s3ListResult
+	seen := map[string]bool{}
+	for _, k := range keys {
+		rest := strings.TrimPrefix(k, prefix)
+		if delim != "" {
+			if idx := strings.Index(rest, delim); idx >= 0 {
+				cp := prefix + rest[:idx+1]
+				if !seen[cp] {
+					seen[cp] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, struct {
+						Prefix string `xml:"Prefix"`
+					}{cp})
+				}
+				continue
+			}
+		}
+		result.Contents = append(result.Contents, struct {
+			Key          string `xml:"Key"`
+			LastModified string `xml:"LastModified"`
+			ETag         string `xml:"ETag"`
+			Size         int64  `xml:"Size"`
+		}{Key: k, LastModified: "2023-11-14T22:13:20Z", Size: int64(len(s.objects[k]))})
+	}
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func newTestS3Backend(t *testing.T, fake *fakeS3, storagePrefix string) *s3Backend {
+	t.Helper()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	return &s3Backend{
+		client: &s3Client{
+			serviceURL: u,
+			region:     "us-east-1",
+			urlStyle:   "path",
+			accessKey:  "AKIDEXAMPLE",
+			secretKey:  "secret",
+			httpClient: srv.Client(),
+		},
+		bucket:        fake.bucket,
+		storagePrefix: storagePrefix,
+		partSize:      s3DefaultPartSize,
+	}
+}
+
+func TestS3BackendWebDAV(t *testing.T) {
+	fake := newFakeS3("test-bucket")
+	fake.requireV4 = true
+	backend := newTestS3Backend(t, fake, "/data")
+	// Force multipart uploads for anything larger than 1 KiB
+	backend.partSize = 1024
+
+	handler := &webdav.Handler{
+		FileSystem: backend.FileSystem(),
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	do := func(method, target string, body []byte, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	small := []byte("hello, s3")
+	large := bytes.Repeat([]byte("0123456789"), 500)
+
+	t.Run("PutSmall", func(t *testing.T) {
+		rec := do(http.MethodPut, "/dir/small.txt", small, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, small, fake.objects["data/dir/small.txt"])
+		assert.True(t, fake.sawAuth)
+	})
+
+	t.Run("PutMultipart", func(t *testing.T) {
+		rec := do(http.MethodPut, "/dir/large.bin", large, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, large, fake.objects["data/dir/large.bin"])
+		assert.Empty(t, fake.uploads, "multipart upload should be completed")
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		rec := do(http.MethodGet, "/dir/large.bin", nil, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, large, rec.Body.Bytes())
+	})
+
+	t.Run("GetRange", func(t *testing.T) {
+		rec := do(http.MethodGet, "/dir/large.bin", nil, map[string]string{"Range": "bytes=4990-4999"})
+		require.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, large[4990:5000], rec.Body.Bytes())
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		rec := do(http.MethodHead, "/dir/small.txt", nil, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, strconv.Itoa(len(small)), rec.Header().Get("Content-Length"))
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		rec := do(http.MethodGet, "/dir/missing", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Mkcol", func(t *testing.T) {
+		rec := do("MKCOL", "/dir/sub", nil, nil)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		_, ok := fake.objects["data/dir/sub/"]
+		assert.True(t, ok, "directory marker should exist")
+	})
+
+	t.Run("Propfind", func(t *testing.T) {
+		rec := do("PROPFIND", "/dir/", nil, map[string]string{"Depth": "1"})
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, "/dir/small.txt")
+		assert.Contains(t, body, "/dir/large.bin")
+		assert.Contains(t, body, "/dir/sub/")
+	})
+
+	t.Run("ReaddirCount", func(t *testing.T) {
+		dir, err := backend.FileSystem().OpenFile(t.Context(), "/dir", 0, 0)
+		require.NoError(t, err)
+		defer dir.Close()
+
+		// Entries are handed out at most count at a time, then io.EOF
+		var names []string
+		for {
+			infos, err := dir.Readdir(2)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(infos), 2)
+			for _, info := range infos {
+				names = append(names, info.Name())
+			}
+		}
+		assert.ElementsMatch(t, []string{"small.txt", "large.bin", "sub"}, names)
+
+		// A non-positive count returns whatever is left, without an error
+		infos, err := dir.Readdir(-1)
+		require.NoError(t, err)
+		assert.Empty(t, infos)
+	})
+
+	t.Run("Move", func(t *testing.T) {
+		rec := do("MOVE", "/dir/small.txt", nil, map[string]string{"Destination": "/dir/moved.txt"})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, small, fake.objects["data/dir/moved.txt"])
+		_, ok := fake.objects["data/dir/small.txt"]
+		assert.False(t, ok)
+	})
+
+	t.Run("DeleteDir", func(t *testing.T) {
+		rec := do(http.MethodDelete, "/dir", nil, nil)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, fake.objects)
+	})
+}
+
+func TestS3BackendResolve(t *testing.T) {
+	b := &s3Backend{bucket: "bucket", storagePrefix: "/"}
+	bucket, key, err := b.resolve("/a/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "bucket", bucket)
+	assert.Equal(t, "a/b.txt", key)
+
+	// Without a configured bucket, the first component names the bucket
+	b = &s3Backend{storagePrefix: "/"}
+	bucket, key, err = b.resolve("/other/a/../b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "other", bucket)
+	assert.Equal(t, "b.txt", key)
+
+	_, _, err = b.resolve("/")
+	assert.Error(t, err)
+}
+
+func TestS3ObjectURLEncoding(t *testing.T) {
+	u, err := url.Parse("https://s3.example.com")
+	require.NoError(t, err)
+	c := &s3Client{serviceURL: u, urlStyle: "path"}
+	got := c.objectURL("bucket", "dir/a file+x.txt", url.Values{"prefix": {"a b"}, "list-type": {"2"}})
+	assert.Equal(t, "/bucket/dir/a%20file%2Bx.txt", got.EscapedPath())
+	assert.Equal(t, "list-type=2&prefix=a%20b", got.RawQuery)
+
+	c.urlStyle = "virtual"
+	got = c.objectURL("bucket", "obj", nil)
+	assert.Equal(t, "bucket.s3.example.com", got.Host)
+	assert.Equal(t, "/obj", got.EscapedPath())
+}
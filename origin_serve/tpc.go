@@ -0,0 +1,234 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/token_scopes"
+	"github.com/pelicanplatform/pelican/tracing"
+)
+
+const (
+	// Headers of the form TransferHeader<Name> are sent to the source as <Name>
+	tpcTransferHeaderPrefix = "Transferheader"
+
+	// A pulled object is written to <dir>/.<name>.pelican-tpc-<random> and
+	// renamed into place once complete
+	tpcStagingInfix = ".pelican-tpc-"
+)
+
+// How often performance markers are sent while the object is being pulled
+var tpcPerfMarkerInterval = 5 * time.Second
+
+// Returns the client used to pull sources; a variable so tests can trust
+// their own servers
+var tpcClient = config.GetClient
+
+// Refuse redirects away from https so that the delegated credentials in the
+// TransferHeader headers are never sent in cleartext
+func tpcCheckRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return errors.Errorf("refusing to follow redirect to non-https URL %s", req.URL.Redacted())
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// Counts the bytes written to the destination
+type tpcProgressWriter struct {
+	writer io.Writer
+	total  atomic.Int64
+}
+
+func (pw *tpcProgressWriter) Write(p []byte) (n int, err error) {
+	n, err = pw.writer.Write(p)
+	pw.total.Add(int64(n))
+	return
+}
+
+// Returns true if the request is an HTTP third-party copy in pull mode: a
+// COPY with a Source header naming the object to fetch.  A COPY with a
+// Destination header is a WebDAV copy within the export.
+func isThirdPartyCopyRequest(r *http.Request) bool {
+	return r.Method == "COPY" && r.Header.Get("Source") != ""
+}
+
+// Returns true if one of the request's tokens grants the action on the
+// request's path
+func requestAuthorizedFor(r *http.Request, action token_scopes.TokenScope) bool {
+	ac := GetAuthConfig()
+	if ac == nil {
+		return false
+	}
+	resource := strings.TrimPrefix(r.URL.Path, "/api/v1.0/origin/data")
+	for _, tok := range extractTokens(r) {
+		if ac.authorize(action, resource, tok) {
+			return true
+		}
+	}
+	return false
+}
+
+// Write a performance marker in the format used by WLCG HTTP-TPC clients
+func writePerfMarker(w io.Writer, transferred int64) {
+	fmt.Fprintf(w, "Perf Marker\n\tTimestamp: %d\n\tStripe Index: 0\n\tStripe Bytes Transferred: %d\n\tTotal Stripe Count: 1\nEnd\n",
+		time.Now().Unix(), transferred)
+}
+
+// Handle a pull-mode HTTP third-party copy, fetching the object named by the
+// Source header and writing it to objectPath, relative to the root of fs.
+//
+// authMiddleware has already checked the request grants storage.create on the
+// destination; replacing an existing object also requires storage.modify.
+// Once the source responds, the origin replies 202 Accepted and streams
+// performance markers until the copy ends with a "success:" or "failure:" line.
+func handleThirdPartyCopy(c *gin.Context, fs webdav.FileSystem, objectPath string) {
+	c.Header("Server", "pelican/"+config.GetVersion())
+	ctx := c.Request.Context()
+
+	objectPath = path.Clean("/" + objectPath)
+	if objectPath == "/" || strings.HasSuffix(c.Param("path"), "/") {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "third-party copies must target an object"})
+		return
+	}
+	// Only https sources are accepted, as the request's TransferHeader
+	// headers usually carry a token delegated to the source
+	source, err := url.Parse(c.GetHeader("Source"))
+	if err != nil || source.Scheme != "https" || source.Host == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "the Source header must be an https URL"})
+		return
+	}
+
+	if info, err := fs.Stat(ctx, objectPath); err == nil {
+		if info.IsDir() {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "destination is a collection"})
+			return
+		}
+		if strings.EqualFold(c.GetHeader("Overwrite"), "F") {
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "destination already exists"})
+			return
+		}
+		if !requestAuthorizedFor(c.Request, token_scopes.Wlcg_Storage_Modify) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "replacing an existing object requires the storage.modify scope"})
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.String(), nil)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for name, values := range c.Request.Header {
+		if forwarded, found := strings.CutPrefix(name, tpcTransferHeaderPrefix); found && forwarded != "" {
+			req.Header[textproto.CanonicalMIMEHeaderKey(forwarded)] = values
+		}
+	}
+	req.Header.Set("User-Agent", "pelican-origin/"+config.GetVersion())
+	tracing.Inject(ctx, req.Header)
+	client := *tpcClient()
+	client.CheckRedirect = tpcCheckRedirect
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Debugf("Third-party copy of %s to %s failed: %v", source.Redacted(), objectPath, err)
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "failed to contact source: " + err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Debugf("Third-party copy of %s to %s failed: source responded with %s", source.Redacted(), objectPath, resp.Status)
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": "source responded with " + resp.Status})
+		return
+	}
+
+	// Stage the object so a failed copy never leaves a partial object behind
+	suffix := make([]byte, 8)
+	_, _ = rand.Read(suffix)
+	stagingName := path.Join(path.Dir(objectPath), "."+path.Base(objectPath)+tpcStagingInfix+hex.EncodeToString(suffix))
+	out, err := fs.OpenFile(ctx, stagingName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		c.AbortWithStatusJSON(errorHandler.MapToHTTPStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/plain")
+	c.Status(http.StatusAccepted)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	progress := &tpcProgressWriter{writer: out}
+	copyDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(progress, resp.Body)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil && resp.ContentLength >= 0 && progress.total.Load() != resp.ContentLength {
+			err = errors.Errorf("received %d bytes but the source reported %d", progress.total.Load(), resp.ContentLength)
+		}
+		copyDone <- err
+	}()
+
+	ticker := time.NewTicker(tpcPerfMarkerInterval)
+	defer ticker.Stop()
+	for copying := true; copying; {
+		select {
+		case <-ticker.C:
+			writePerfMarker(c.Writer, progress.total.Load())
+			c.Writer.Flush()
+		case err = <-copyDone:
+			copying = false
+		}
+	}
+	if err == nil {
+		err = fs.Rename(ctx, stagingName, objectPath)
+	}
+	if err != nil {
+		_ = fs.RemoveAll(ctx, stagingName)
+		log.Warningf("Third-party copy of %s to %s failed: %v", source.Redacted(), objectPath, err)
+		fmt.Fprintf(c.Writer, "failure: %s\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		return
+	}
+	log.Debugf("Completed third-party copy of %s to %s (%d bytes)", source.Redacted(), objectPath, progress.total.Load())
+	writePerfMarker(c.Writer, progress.total.Load())
+	fmt.Fprint(c.Writer, "success: Created\n")
+}
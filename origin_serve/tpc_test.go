@@ -0,0 +1,163 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package origin_serve
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/token_scopes"
+)
+
+func TestThirdPartyCopy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	memFs := afero.NewMemMapFs()
+	fs := newAferoFileSystem(memFs, "", nil)
+	engine := gin.New()
+	engine.Any("/test/*path", func(c *gin.Context) {
+		handleThirdPartyCopy(c, fs, c.Param("path"))
+	})
+	engine.Handle("COPY", "/test/*path", func(c *gin.Context) {
+		handleThirdPartyCopy(c, fs, c.Param("path"))
+	})
+
+	// Tokens granting create, and create plus modify, on /test
+	ac := &authConfig{tokenAuthz: ttlcache.New[string, cachedTokenInfo]()}
+	ac.tokenAuthz.Set("create", cachedTokenInfo{Scopes: []token_scopes.ResourceScope{
+		token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Create, "/test"),
+	}}, ttlcache.NoTTL)
+	ac.tokenAuthz.Set("modify", cachedTokenInfo{Scopes: []token_scopes.ResourceScope{
+		token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Create, "/test"),
+		token_scopes.NewResourceScope(token_scopes.Wlcg_Storage_Modify, "/test"),
+	}}, ttlcache.NoTTL)
+	oldAC := globalAuthConfig
+	globalAuthConfig = ac
+	t.Cleanup(func() { globalAuthConfig = oldAC })
+
+	content := bytes.Repeat([]byte("third-party copy "), 1000)
+	var sourceAuth string
+	source := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sourceAuth = r.Header.Get("Authorization")
+		if r.URL.Path == "/src/redirect" {
+			http.Redirect(w, r, "http://"+r.Host+"/src/obj", http.StatusFound)
+			return
+		}
+		if r.URL.Path != "/src/obj" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// Send the object slowly enough for a performance marker to be sent
+		w.Header().Set("Content-Length", "17000")
+		_, _ = w.Write(content[:1000])
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write(content[1000:])
+	}))
+	t.Cleanup(source.Close)
+	oldClient := tpcClient
+	tpcClient = source.Client
+	t.Cleanup(func() { tpcClient = oldClient })
+	oldInterval := tpcPerfMarkerInterval
+	tpcPerfMarkerInterval = 10 * time.Millisecond
+	t.Cleanup(func() { tpcPerfMarkerInterval = oldInterval })
+
+	do := func(dest, src, token string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("COPY", "/test"+dest, nil)
+		req.Header.Set("Source", src)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("TransferHeaderAuthorization", "Bearer source-token")
+		for idx := 0; idx+1 < len(headers); idx += 2 {
+			req.Header.Set(headers[idx], headers[idx+1])
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/dir/obj", source.URL+"/src/obj", "create")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	body := w.Body.String()
+	assert.Contains(t, body, "Perf Marker\n")
+	assert.Contains(t, body, "Stripe Bytes Transferred: 17000\n")
+	assert.True(t, strings.HasSuffix(body, "success: Created\n"), body)
+	assert.Equal(t, "Bearer source-token", sourceAuth)
+	got, err := afero.ReadFile(memFs, "/dir/obj")
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+
+	// No staging files are left behind
+	entries, err := afero.ReadDir(memFs, "/dir")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	t.Run("overwrite-requires-modify", func(t *testing.T) {
+		w := do("/dir/obj", source.URL+"/src/obj", "create")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		w = do("/dir/obj", source.URL+"/src/obj", "modify", "Overwrite", "F")
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		w = do("/dir/obj", source.URL+"/src/obj", "modify")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "success:")
+	})
+
+	t.Run("source-errors", func(t *testing.T) {
+		w := do("/dir/missing", source.URL+"/src/missing", "create")
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		_, err := memFs.Stat("/dir/missing")
+		assert.True(t, os.IsNotExist(err))
+
+		assert.Equal(t, http.StatusBadRequest, do("/dir/other", "file:///etc/passwd", "create").Code)
+
+		// Delegated credentials are never sent in cleartext
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("request sent to an http source")
+		}))
+		t.Cleanup(plain.Close)
+		assert.Equal(t, http.StatusBadRequest, do("/dir/other", plain.URL+"/src/obj", "create").Code)
+		sourceAuth = ""
+		assert.Equal(t, http.StatusBadGateway, do("/dir/other", source.URL+"/src/redirect", "create").Code)
+		assert.Equal(t, "Bearer source-token", sourceAuth)
+		assert.Equal(t, http.StatusBadRequest, do("/", source.URL+"/src/obj", "create").Code)
+	})
+
+	t.Run("truncated-source", func(t *testing.T) {
+		truncated := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "100")
+			_, _ = w.Write([]byte("short"))
+		}))
+		t.Cleanup(truncated.Close)
+		tpcClient = truncated.Client
+		w := do("/dir/truncated", truncated.URL+"/obj", "create")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "failure:")
+		_, err := memFs.Stat("/dir/truncated")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
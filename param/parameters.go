@@ -138,6 +138,9 @@ var runtimeConfigurableMap = map[string]bool{
 	"Cache.Url": false,
 	"Cache.XRootDPrefix": false,
 	"Client.AssumeDirectorServerHeader": false,
+	"Client.ChunkedUploadChunkSize": false,
+	"Client.ChunkedUploadStreams": false,
+	"Client.ChunkedUploadThreshold": false,
 	"Client.CredentialFile": false,
 	"Client.DirectorRetries": false,
 	"Client.DisableHttpProxy": false,
@@ -146,16 +149,22 @@ var runtimeConfigurableMap = map[string]bool{
 	"Client.IsPlugin": false,
 	"Client.MaximumDownloadSpeed": false,
 	"Client.MinimumDownloadSpeed": false,
+	"Client.ParallelDownloadEndpoints": false,
+	"Client.ParallelDownloadStreams": false,
+	"Client.ParallelDownloadThreshold": false,
 	"Client.PreferredCaches": false,
+	"Client.ResumeDownloads": false,
 	"Client.SlowTransferRampupTime": false,
 	"Client.SlowTransferWindow": false,
 	"Client.StoppedTransferTimeout": false,
 	"Client.WorkerCount": false,
+	"ClientAgent.BandwidthLimit": false,
 	"ClientAgent.DbLocation": false,
 	"ClientAgent.HistoryRetentionDays": false,
 	"ClientAgent.IdleTimeout": false,
 	"ClientAgent.MaxConcurrentJobs": false,
 	"ClientAgent.PidFile": false,
+	"ClientAgent.ProgressEventInterval": false,
 	"ClientAgent.ProgressUpdateInterval": false,
 	"ClientAgent.Socket": false,
 	"ConfigLocations": false,
@@ -189,6 +198,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Director.OriginCacheHealthTestInterval": false,
 	"Director.OriginResponseHostnames": false,
 	"Director.RegistryQueryInterval": false,
+	"Director.SortPolicyFile": false,
 	"Director.StatConcurrencyLimit": false,
 	"Director.StatTimeout": false,
 	"Director.SupportContactEmail": false,
@@ -228,12 +238,25 @@ var runtimeConfigurableMap = map[string]bool{
 	"Issuer.UserStripDomain": false,
 	"IssuerKey": false,
 	"IssuerKeysDirectory": false,
+	"LocalCache.BlockSize": false,
 	"LocalCache.DataLocation": false,
+	"LocalCache.DefaultPinLifetime": false,
+	"LocalCache.EnablePeers": false,
 	"LocalCache.HighWaterMarkPercentage": false,
+	"LocalCache.ListenAddress": false,
+	"LocalCache.LotPolicy": false,
 	"LocalCache.LowWaterMarkPercentage": false,
+	"LocalCache.PeerAllowedHosts": false,
+	"LocalCache.PeerDigestInterval": false,
+	"LocalCache.PeerDiscoveryAddress": false,
+	"LocalCache.PeerTimeout": false,
+	"LocalCache.Peers": false,
+	"LocalCache.PrefetchConcurrency": false,
 	"LocalCache.RunLocation": false,
 	"LocalCache.Size": false,
 	"LocalCache.Socket": false,
+	"LocalCache.TLSCertificate": false,
+	"LocalCache.TLSKey": false,
 	"Logging.Cache.Http": true,
 	"Logging.Cache.Lotman": true,
 	"Logging.Cache.Ofs": true,
@@ -264,6 +287,11 @@ var runtimeConfigurableMap = map[string]bool{
 	"Lotman.PolicyDefinitions": false,
 	"MinimumDownloadSpeed": false,
 	"Monitoring.AggregatePrefixes": false,
+	"Monitoring.AlertEmailFrom": false,
+	"Monitoring.AlertEmailRelay": false,
+	"Monitoring.AlertEmailTo": false,
+	"Monitoring.AlertWebhookUrl": false,
+	"Monitoring.AlertmanagerUrl": false,
 	"Monitoring.DataLocation": false,
 	"Monitoring.DataRetention": false,
 	"Monitoring.DataRetentionSize": false,
@@ -275,6 +303,8 @@ var runtimeConfigurableMap = map[string]bool{
 	"Monitoring.PortHigher": false,
 	"Monitoring.PortLower": false,
 	"Monitoring.PromQLAuthorization": false,
+	"Monitoring.RuleEvaluationInterval": false,
+	"Monitoring.RuleFiles": false,
 	"Monitoring.SampleLimit": false,
 	"Monitoring.StorageCriticalThreshold": false,
 	"Monitoring.StorageHealthCheckInterval": false,
@@ -291,6 +321,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"OIDC.Scopes": false,
 	"OIDC.TokenEndpoint": false,
 	"OIDC.UserInfoEndpoint": false,
+	"Origin.ChunkedUploadStagingLifetime": false,
 	"Origin.Concurrency": false,
 	"Origin.ConcurrencyDegradedThreshold": false,
 	"Origin.DbLocation": false,
@@ -302,6 +333,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.DiskUsageCalculationRateLimit": false,
 	"Origin.EnableAtomicUploads": false,
 	"Origin.EnableBroker": false,
+	"Origin.EnableChunkedUploads": false,
 	"Origin.EnableCmsd": false,
 	"Origin.EnableDirListing": false,
 	"Origin.EnableDirectReads": false,
@@ -310,9 +342,11 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.EnableIssuer": false,
 	"Origin.EnableListings": false,
 	"Origin.EnableMacaroons": false,
+	"Origin.EnableNativeBackend": false,
 	"Origin.EnableOIDC": false,
 	"Origin.EnablePublicReads": false,
 	"Origin.EnableReads": false,
+	"Origin.EnableThirdPartyCopy": false,
 	"Origin.EnableVoms": false,
 	"Origin.EnableWrite": false,
 	"Origin.EnableWrites": false,
@@ -337,6 +371,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.MultiuserVarlinkSocketPath": false,
 	"Origin.NamespacePrefix": false,
 	"Origin.Port": false,
+	"Origin.ReadBandwidthLimit": false,
 	"Origin.RunLocation": false,
 	"Origin.S3AccessKeyfile": false,
 	"Origin.S3Bucket": false,
@@ -383,6 +418,7 @@ var runtimeConfigurableMap = map[string]bool{
 	"Origin.UploadTempLocation": false,
 	"Origin.Url": false,
 	"Origin.UserMapfileRefreshInterval": false,
+	"Origin.WriteBandwidthLimit": false,
 	"Origin.XRootDPrefix": false,
 	"Origin.XRootServiceUrl": false,
 	"Plugin.DirectorDecisionPercentage": false,
@@ -390,12 +426,16 @@ var runtimeConfigurableMap = map[string]bool{
 	"Registry.AdminUsers": false,
 	"Registry.CustomRegistrationFields": false,
 	"Registry.DbLocation": false,
+	"Registry.EventRetention": false,
 	"Registry.Institutions": false,
 	"Registry.InstitutionsUrl": false,
 	"Registry.InstitutionsUrlReloadMinutes": false,
 	"Registry.RequireCacheApproval": false,
 	"Registry.RequireKeyChaining": false,
 	"Registry.RequireOriginApproval": false,
+	"Registry.WebhookMaxAttempts": false,
+	"Registry.WebhookTimeout": false,
+	"Registry.WebhookUrls": false,
 	"RuntimeDir": false,
 	"Server.AdLifetime": false,
 	"Server.AdminGroups": false,
@@ -462,9 +502,20 @@ var runtimeConfigurableMap = map[string]bool{
 	"Topology.DisableDowntime": false,
 	"Topology.DisableOriginX509": false,
 	"Topology.DisableOrigins": false,
+	"Tracing.Endpoint": false,
+	"Tracing.Exporter": false,
+	"Tracing.File": false,
+	"Tracing.SamplePercent": false,
 	"Transport.BrokerEndpointCacheTTL": false,
+	"Transport.BrokerPoolIdleTimeout": false,
+	"Transport.BrokerPoolPerService": false,
+	"Transport.BrokerPoolSize": false,
+	"Transport.BrokerTunnelLifetime": false,
+	"Transport.BrokerTunnelMaxStreams": false,
+	"Transport.BrokerTunnelsPerService": false,
 	"Transport.DialerKeepAlive": false,
 	"Transport.DialerTimeout": false,
+	"Transport.EnableBrokerTunnels": false,
 	"Transport.ExpectContinueTimeout": false,
 	"Transport.IdleConnTimeout": false,
 	"Transport.MaxIdleConns": false,
@@ -553,6 +604,7 @@ var stringAccessors = map[string]func(*Config) string{
 	"Director.DefaultResponse": func(c *Config) string { return c.Director.DefaultResponse },
 	"Director.GeoIPLocation": func(c *Config) string { return c.Director.GeoIPLocation },
 	"Director.MaxMindKeyFile": func(c *Config) string { return c.Director.MaxMindKeyFile },
+	"Director.SortPolicyFile": func(c *Config) string { return c.Director.SortPolicyFile },
 	"Director.SupportContactEmail": func(c *Config) string { return c.Director.SupportContactEmail },
 	"Director.SupportContactUrl": func(c *Config) string { return c.Director.SupportContactUrl },
 	"Federation.DiscoveryUrl": func(c *Config) string { return c.Federation.DiscoveryUrl },
@@ -573,10 +625,16 @@ var stringAccessors = map[string]func(*Config) string{
 	"Issuer.QDLLocation": func(c *Config) string { return c.Issuer.QDLLocation },
 	"Issuer.ScitokensServerLocation": func(c *Config) string { return c.Issuer.ScitokensServerLocation },
 	"Issuer.TomcatLocation": func(c *Config) string { return c.Issuer.TomcatLocation },
+	"LocalCache.BlockSize": func(c *Config) string { return c.LocalCache.BlockSize },
 	"LocalCache.DataLocation": func(c *Config) string { return c.LocalCache.DataLocation },
+	"LocalCache.ListenAddress": func(c *Config) string { return c.LocalCache.ListenAddress },
+	"LocalCache.LotPolicy": func(c *Config) string { return c.LocalCache.LotPolicy },
+	"LocalCache.PeerDiscoveryAddress": func(c *Config) string { return c.LocalCache.PeerDiscoveryAddress },
 	"LocalCache.RunLocation": func(c *Config) string { return c.LocalCache.RunLocation },
 	"LocalCache.Size": func(c *Config) string { return c.LocalCache.Size },
 	"LocalCache.Socket": func(c *Config) string { return c.LocalCache.Socket },
+	"LocalCache.TLSCertificate": func(c *Config) string { return c.LocalCache.TLSCertificate },
+	"LocalCache.TLSKey": func(c *Config) string { return c.LocalCache.TLSKey },
 	"Logging.Cache.Http": func(c *Config) string { return c.Logging.Cache.Http },
 	"Logging.Cache.Lotman": func(c *Config) string { return c.Logging.Cache.Lotman },
 	"Logging.Cache.Ofs": func(c *Config) string { return c.Logging.Cache.Ofs },
@@ -599,6 +657,10 @@ var stringAccessors = map[string]func(*Config) string{
 	"Lotman.EnabledPolicy": func(c *Config) string { return c.Lotman.EnabledPolicy },
 	"Lotman.LibLocation": func(c *Config) string { return c.Lotman.LibLocation },
 	"Lotman.LotHome": func(c *Config) string { return c.Lotman.LotHome },
+	"Monitoring.AlertEmailFrom": func(c *Config) string { return c.Monitoring.AlertEmailFrom },
+	"Monitoring.AlertEmailRelay": func(c *Config) string { return c.Monitoring.AlertEmailRelay },
+	"Monitoring.AlertWebhookUrl": func(c *Config) string { return c.Monitoring.AlertWebhookUrl },
+	"Monitoring.AlertmanagerUrl": func(c *Config) string { return c.Monitoring.AlertmanagerUrl },
 	"Monitoring.DataLocation": func(c *Config) string { return c.Monitoring.DataLocation },
 	"Monitoring.DataRetentionSize": func(c *Config) string { return c.Monitoring.DataRetentionSize },
 	"OIDC.AuthorizationEndpoint": func(c *Config) string { return c.OIDC.AuthorizationEndpoint },
@@ -691,6 +753,9 @@ var stringAccessors = map[string]func(*Config) string{
 	"StagePlugin.MountPrefix": func(c *Config) string { return c.StagePlugin.MountPrefix },
 	"StagePlugin.OriginPrefix": func(c *Config) string { return c.StagePlugin.OriginPrefix },
 	"StagePlugin.ShadowOriginPrefix": func(c *Config) string { return c.StagePlugin.ShadowOriginPrefix },
+	"Tracing.Endpoint": func(c *Config) string { return c.Tracing.Endpoint },
+	"Tracing.Exporter": func(c *Config) string { return c.Tracing.Exporter },
+	"Tracing.File": func(c *Config) string { return c.Tracing.File },
 	"Xrootd.Authfile": func(c *Config) string { return c.Xrootd.Authfile },
 	"Xrootd.ConfigFile": func(c *Config) string { return c.Xrootd.ConfigFile },
 	"Xrootd.DetailedMonitoringHost": func(c *Config) string { return c.Xrootd.DetailedMonitoringHost },
@@ -744,7 +809,11 @@ var stringSliceAccessors = map[string]func(*Config) []string{
 	"Director.OriginResponseHostnames": func(c *Config) []string { return c.Director.OriginResponseHostnames },
 	"Issuer.GroupRequirements": func(c *Config) []string { return c.Issuer.GroupRequirements },
 	"Issuer.RedirectUris": func(c *Config) []string { return c.Issuer.RedirectUris },
+	"LocalCache.PeerAllowedHosts": func(c *Config) []string { return c.LocalCache.PeerAllowedHosts },
+	"LocalCache.Peers": func(c *Config) []string { return c.LocalCache.Peers },
 	"Monitoring.AggregatePrefixes": func(c *Config) []string { return c.Monitoring.AggregatePrefixes },
+	"Monitoring.AlertEmailTo": func(c *Config) []string { return c.Monitoring.AlertEmailTo },
+	"Monitoring.RuleFiles": func(c *Config) []string { return c.Monitoring.RuleFiles },
 	"OIDC.Scopes": func(c *Config) []string { return c.OIDC.Scopes },
 	"Origin.DefaultChecksumTypes": func(c *Config) []string { return c.Origin.DefaultChecksumTypes },
 	"Origin.ExportVolumes": func(c *Config) []string { return c.Origin.ExportVolumes },
@@ -753,6 +822,7 @@ var stringSliceAccessors = map[string]func(*Config) []string{
 	"Origin.ScitokensRestrictedPaths": func(c *Config) []string { return c.Origin.ScitokensRestrictedPaths },
 	"Origin.SupportedChecksumTypes": func(c *Config) []string { return c.Origin.SupportedChecksumTypes },
 	"Registry.AdminUsers": func(c *Config) []string { return c.Registry.AdminUsers },
+	"Registry.WebhookUrls": func(c *Config) []string { return c.Registry.WebhookUrls },
 	"Server.AdminGroups": func(c *Config) []string { return c.Server.AdminGroups },
 	"Server.DirectorUrls": func(c *Config) []string { return c.Server.DirectorUrls },
 	"Server.Modules": func(c *Config) []string { return c.Server.Modules },
@@ -797,9 +867,15 @@ var intAccessors = map[string]func(*Config) int{
 	"Cache.Port": func(c *Config) int { return c.Cache.Port },
 	"ClientAgent.HistoryRetentionDays": func(c *Config) int { return c.ClientAgent.HistoryRetentionDays },
 	"ClientAgent.MaxConcurrentJobs": func(c *Config) int { return c.ClientAgent.MaxConcurrentJobs },
+	"Client.ChunkedUploadChunkSize": func(c *Config) int { return c.Client.ChunkedUploadChunkSize },
+	"Client.ChunkedUploadStreams": func(c *Config) int { return c.Client.ChunkedUploadStreams },
+	"Client.ChunkedUploadThreshold": func(c *Config) int { return c.Client.ChunkedUploadThreshold },
 	"Client.DirectorRetries": func(c *Config) int { return c.Client.DirectorRetries },
 	"Client.MaximumDownloadSpeed": func(c *Config) int { return c.Client.MaximumDownloadSpeed },
 	"Client.MinimumDownloadSpeed": func(c *Config) int { return c.Client.MinimumDownloadSpeed },
+	"Client.ParallelDownloadEndpoints": func(c *Config) int { return c.Client.ParallelDownloadEndpoints },
+	"Client.ParallelDownloadStreams": func(c *Config) int { return c.Client.ParallelDownloadStreams },
+	"Client.ParallelDownloadThreshold": func(c *Config) int { return c.Client.ParallelDownloadThreshold },
 	"Client.WorkerCount": func(c *Config) int { return c.Client.WorkerCount },
 	"Director.AdaptiveSortTruncateConstant": func(c *Config) int { return c.Director.AdaptiveSortTruncateConstant },
 	"Director.CachePresenceCapacity": func(c *Config) int { return c.Director.CachePresenceCapacity },
@@ -808,6 +884,7 @@ var intAccessors = map[string]func(*Config) int{
 	"Director.StatConcurrencyLimit": func(c *Config) int { return c.Director.StatConcurrencyLimit },
 	"LocalCache.HighWaterMarkPercentage": func(c *Config) int { return c.LocalCache.HighWaterMarkPercentage },
 	"LocalCache.LowWaterMarkPercentage": func(c *Config) int { return c.LocalCache.LowWaterMarkPercentage },
+	"LocalCache.PrefetchConcurrency": func(c *Config) int { return c.LocalCache.PrefetchConcurrency },
 	"MinimumDownloadSpeed": func(c *Config) int { return c.MinimumDownloadSpeed },
 	"Monitoring.LabelLimit": func(c *Config) int { return c.Monitoring.LabelLimit },
 	"Monitoring.LabelNameLengthLimit": func(c *Config) int { return c.Monitoring.LabelNameLengthLimit },
@@ -826,12 +903,18 @@ var intAccessors = map[string]func(*Config) int{
 	"Origin.SSH.MaxRetries": func(c *Config) int { return c.Origin.SSH.MaxRetries },
 	"Origin.SSH.Port": func(c *Config) int { return c.Origin.SSH.Port },
 	"Plugin.DirectorDecisionPercentage": func(c *Config) int { return c.Plugin.DirectorDecisionPercentage },
+	"Registry.WebhookMaxAttempts": func(c *Config) int { return c.Registry.WebhookMaxAttempts },
 	"Server.DatabaseBackup.MaxCount": func(c *Config) int { return c.Server.DatabaseBackup.MaxCount },
 	"Server.IssuerPort": func(c *Config) int { return c.Server.IssuerPort },
 	"Server.UILoginRateLimit": func(c *Config) int { return c.Server.UILoginRateLimit },
 	"Server.WebPort": func(c *Config) int { return c.Server.WebPort },
 	"Shoveler.PortHigher": func(c *Config) int { return c.Shoveler.PortHigher },
 	"Shoveler.PortLower": func(c *Config) int { return c.Shoveler.PortLower },
+	"Tracing.SamplePercent": func(c *Config) int { return c.Tracing.SamplePercent },
+	"Transport.BrokerPoolPerService": func(c *Config) int { return c.Transport.BrokerPoolPerService },
+	"Transport.BrokerPoolSize": func(c *Config) int { return c.Transport.BrokerPoolSize },
+	"Transport.BrokerTunnelMaxStreams": func(c *Config) int { return c.Transport.BrokerTunnelMaxStreams },
+	"Transport.BrokerTunnelsPerService": func(c *Config) int { return c.Transport.BrokerTunnelsPerService },
 	"Transport.MaxIdleConns": func(c *Config) int { return c.Transport.MaxIdleConns },
 	"Xrootd.DetailedMonitoringPort": func(c *Config) int { return c.Xrootd.DetailedMonitoringPort },
 	"Xrootd.LocalMonitoringPort": func(c *Config) int { return c.Xrootd.LocalMonitoringPort },
@@ -870,7 +953,10 @@ func (iP IntParam) Set(value int) error {
 }
 
 var byteRateAccessors = map[string]func(*Config) byte_rate.ByteRate{
+	"ClientAgent.BandwidthLimit": func(c *Config) byte_rate.ByteRate { return c.ClientAgent.BandwidthLimit },
+	"Origin.ReadBandwidthLimit": func(c *Config) byte_rate.ByteRate { return c.Origin.ReadBandwidthLimit },
 	"Origin.TransferRateLimit": func(c *Config) byte_rate.ByteRate { return c.Origin.TransferRateLimit },
+	"Origin.WriteBandwidthLimit": func(c *Config) byte_rate.ByteRate { return c.Origin.WriteBandwidthLimit },
 }
 
 func (bRP ByteRateParam) GetByteRate() byte_rate.ByteRate {
@@ -927,6 +1013,7 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Client.DisableProxyFallback": func(c *Config) bool { return c.Client.DisableProxyFallback },
 	"Client.EnableOverwrites": func(c *Config) bool { return c.Client.EnableOverwrites },
 	"Client.IsPlugin": func(c *Config) bool { return c.Client.IsPlugin },
+	"Client.ResumeDownloads": func(c *Config) bool { return c.Client.ResumeDownloads },
 	"Debug": func(c *Config) bool { return c.Debug },
 	"Director.AssumePresenceAtSingleOrigin": func(c *Config) bool { return c.Director.AssumePresenceAtSingleOrigin },
 	"Director.CachesPullFromCaches": func(c *Config) bool { return c.Director.CachesPullFromCaches },
@@ -941,6 +1028,7 @@ var boolAccessors = map[string]func(*Config) bool{
 	"DisableProxyFallback": func(c *Config) bool { return c.DisableProxyFallback },
 	"Issuer.OIDCPreferClaimsFromIDToken": func(c *Config) bool { return c.Issuer.OIDCPreferClaimsFromIDToken },
 	"Issuer.UserStripDomain": func(c *Config) bool { return c.Issuer.UserStripDomain },
+	"LocalCache.EnablePeers": func(c *Config) bool { return c.LocalCache.EnablePeers },
 	"Logging.DisableProgressBars": func(c *Config) bool { return c.Logging.DisableProgressBars },
 	"Lotman.EnableAPI": func(c *Config) bool { return c.Lotman.EnableAPI },
 	"Monitoring.EnablePrometheus": func(c *Config) bool { return c.Monitoring.EnablePrometheus },
@@ -950,6 +1038,7 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Origin.DisableDirectClients": func(c *Config) bool { return c.Origin.DisableDirectClients },
 	"Origin.EnableAtomicUploads": func(c *Config) bool { return c.Origin.EnableAtomicUploads },
 	"Origin.EnableBroker": func(c *Config) bool { return c.Origin.EnableBroker },
+	"Origin.EnableChunkedUploads": func(c *Config) bool { return c.Origin.EnableChunkedUploads },
 	"Origin.EnableCmsd": func(c *Config) bool { return c.Origin.EnableCmsd },
 	"Origin.EnableDirListing": func(c *Config) bool { return c.Origin.EnableDirListing },
 	"Origin.EnableDirectReads": func(c *Config) bool { return c.Origin.EnableDirectReads },
@@ -958,9 +1047,11 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Origin.EnableIssuer": func(c *Config) bool { return c.Origin.EnableIssuer },
 	"Origin.EnableListings": func(c *Config) bool { return c.Origin.EnableListings },
 	"Origin.EnableMacaroons": func(c *Config) bool { return c.Origin.EnableMacaroons },
+	"Origin.EnableNativeBackend": func(c *Config) bool { return c.Origin.EnableNativeBackend },
 	"Origin.EnableOIDC": func(c *Config) bool { return c.Origin.EnableOIDC },
 	"Origin.EnablePublicReads": func(c *Config) bool { return c.Origin.EnablePublicReads },
 	"Origin.EnableReads": func(c *Config) bool { return c.Origin.EnableReads },
+	"Origin.EnableThirdPartyCopy": func(c *Config) bool { return c.Origin.EnableThirdPartyCopy },
 	"Origin.EnableVoms": func(c *Config) bool { return c.Origin.EnableVoms },
 	"Origin.EnableWrite": func(c *Config) bool { return c.Origin.EnableWrite },
 	"Origin.EnableWrites": func(c *Config) bool { return c.Origin.EnableWrites },
@@ -987,6 +1078,7 @@ var boolAccessors = map[string]func(*Config) bool{
 	"Topology.DisableDowntime": func(c *Config) bool { return c.Topology.DisableDowntime },
 	"Topology.DisableOriginX509": func(c *Config) bool { return c.Topology.DisableOriginX509 },
 	"Topology.DisableOrigins": func(c *Config) bool { return c.Topology.DisableOrigins },
+	"Transport.EnableBrokerTunnels": func(c *Config) bool { return c.Transport.EnableBrokerTunnels },
 	"Xrootd.AutoShutdownEnabled": func(c *Config) bool { return c.Xrootd.AutoShutdownEnabled },
 	"Xrootd.EnableLocalMonitoring": func(c *Config) bool { return c.Xrootd.EnableLocalMonitoring },
 }
@@ -1026,6 +1118,7 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Cache.SelfTestInterval": func(c *Config) time.Duration { return c.Cache.SelfTestInterval },
 	"Cache.SelfTestMaxAge": func(c *Config) time.Duration { return c.Cache.SelfTestMaxAge },
 	"ClientAgent.IdleTimeout": func(c *Config) time.Duration { return c.ClientAgent.IdleTimeout },
+	"ClientAgent.ProgressEventInterval": func(c *Config) time.Duration { return c.ClientAgent.ProgressEventInterval },
 	"ClientAgent.ProgressUpdateInterval": func(c *Config) time.Duration { return c.ClientAgent.ProgressUpdateInterval },
 	"Client.SlowTransferRampupTime": func(c *Config) time.Duration { return c.Client.SlowTransferRampupTime },
 	"Client.SlowTransferWindow": func(c *Config) time.Duration { return c.Client.SlowTransferWindow },
@@ -1042,13 +1135,18 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Issuer.DynamicClientStaleTimeout": func(c *Config) time.Duration { return c.Issuer.DynamicClientStaleTimeout },
 	"Issuer.DynamicClientUnusedTimeout": func(c *Config) time.Duration { return c.Issuer.DynamicClientUnusedTimeout },
 	"Issuer.RefreshTokenGracePeriod": func(c *Config) time.Duration { return c.Issuer.RefreshTokenGracePeriod },
+	"LocalCache.DefaultPinLifetime": func(c *Config) time.Duration { return c.LocalCache.DefaultPinLifetime },
+	"LocalCache.PeerDigestInterval": func(c *Config) time.Duration { return c.LocalCache.PeerDigestInterval },
+	"LocalCache.PeerTimeout": func(c *Config) time.Duration { return c.LocalCache.PeerTimeout },
 	"Logging.Client.ProgressInterval": func(c *Config) time.Duration { return c.Logging.Client.ProgressInterval },
 	"Lotman.DefaultLotDeletionLifetime": func(c *Config) time.Duration { return c.Lotman.DefaultLotDeletionLifetime },
 	"Lotman.DefaultLotExpirationLifetime": func(c *Config) time.Duration { return c.Lotman.DefaultLotExpirationLifetime },
 	"Monitoring.DataRetention": func(c *Config) time.Duration { return c.Monitoring.DataRetention },
+	"Monitoring.RuleEvaluationInterval": func(c *Config) time.Duration { return c.Monitoring.RuleEvaluationInterval },
 	"Monitoring.StorageHealthCheckInterval": func(c *Config) time.Duration { return c.Monitoring.StorageHealthCheckInterval },
 	"Monitoring.TokenExpiresIn": func(c *Config) time.Duration { return c.Monitoring.TokenExpiresIn },
 	"Monitoring.TokenRefreshInterval": func(c *Config) time.Duration { return c.Monitoring.TokenRefreshInterval },
+	"Origin.ChunkedUploadStagingLifetime": func(c *Config) time.Duration { return c.Origin.ChunkedUploadStagingLifetime },
 	"Origin.DiskUsageCalculationDelay": func(c *Config) time.Duration { return c.Origin.DiskUsageCalculationDelay },
 	"Origin.DiskUsageCalculationInterval": func(c *Config) time.Duration { return c.Origin.DiskUsageCalculationInterval },
 	"Origin.SSH.ChallengeTimeout": func(c *Config) time.Duration { return c.Origin.SSH.ChallengeTimeout },
@@ -1059,13 +1157,17 @@ var durationAccessors = map[string]func(*Config) time.Duration{
 	"Origin.SelfTestInterval": func(c *Config) time.Duration { return c.Origin.SelfTestInterval },
 	"Origin.SelfTestMaxAge": func(c *Config) time.Duration { return c.Origin.SelfTestMaxAge },
 	"Origin.UserMapfileRefreshInterval": func(c *Config) time.Duration { return c.Origin.UserMapfileRefreshInterval },
+	"Registry.EventRetention": func(c *Config) time.Duration { return c.Registry.EventRetention },
 	"Registry.InstitutionsUrlReloadMinutes": func(c *Config) time.Duration { return c.Registry.InstitutionsUrlReloadMinutes },
+	"Registry.WebhookTimeout": func(c *Config) time.Duration { return c.Registry.WebhookTimeout },
 	"Server.AdLifetime": func(c *Config) time.Duration { return c.Server.AdLifetime },
 	"Server.AdvertisementInterval": func(c *Config) time.Duration { return c.Server.AdvertisementInterval },
 	"Server.DatabaseBackup.Frequency": func(c *Config) time.Duration { return c.Server.DatabaseBackup.Frequency },
 	"Server.RegistrationRetryInterval": func(c *Config) time.Duration { return c.Server.RegistrationRetryInterval },
 	"Server.StartupTimeout": func(c *Config) time.Duration { return c.Server.StartupTimeout },
 	"Transport.BrokerEndpointCacheTTL": func(c *Config) time.Duration { return c.Transport.BrokerEndpointCacheTTL },
+	"Transport.BrokerPoolIdleTimeout": func(c *Config) time.Duration { return c.Transport.BrokerPoolIdleTimeout },
+	"Transport.BrokerTunnelLifetime": func(c *Config) time.Duration { return c.Transport.BrokerTunnelLifetime },
 	"Transport.DialerKeepAlive": func(c *Config) time.Duration { return c.Transport.DialerKeepAlive },
 	"Transport.DialerTimeout": func(c *Config) time.Duration { return c.Transport.DialerTimeout },
 	"Transport.ExpectContinueTimeout": func(c *Config) time.Duration { return c.Transport.ExpectContinueTimeout },
@@ -1204,6 +1306,9 @@ var allParameterNames = []string{
 	"Cache.Url",
 	"Cache.XRootDPrefix",
 	"Client.AssumeDirectorServerHeader",
+	"Client.ChunkedUploadChunkSize",
+	"Client.ChunkedUploadStreams",
+	"Client.ChunkedUploadThreshold",
 	"Client.CredentialFile",
 	"Client.DirectorRetries",
 	"Client.DisableHttpProxy",
@@ -1212,16 +1317,22 @@ var allParameterNames = []string{
 	"Client.IsPlugin",
 	"Client.MaximumDownloadSpeed",
 	"Client.MinimumDownloadSpeed",
+	"Client.ParallelDownloadEndpoints",
+	"Client.ParallelDownloadStreams",
+	"Client.ParallelDownloadThreshold",
 	"Client.PreferredCaches",
+	"Client.ResumeDownloads",
 	"Client.SlowTransferRampupTime",
 	"Client.SlowTransferWindow",
 	"Client.StoppedTransferTimeout",
 	"Client.WorkerCount",
+	"ClientAgent.BandwidthLimit",
 	"ClientAgent.DbLocation",
 	"ClientAgent.HistoryRetentionDays",
 	"ClientAgent.IdleTimeout",
 	"ClientAgent.MaxConcurrentJobs",
 	"ClientAgent.PidFile",
+	"ClientAgent.ProgressEventInterval",
 	"ClientAgent.ProgressUpdateInterval",
 	"ClientAgent.Socket",
 	"ConfigLocations",
@@ -1255,6 +1366,7 @@ var allParameterNames = []string{
 	"Director.OriginCacheHealthTestInterval",
 	"Director.OriginResponseHostnames",
 	"Director.RegistryQueryInterval",
+	"Director.SortPolicyFile",
 	"Director.StatConcurrencyLimit",
 	"Director.StatTimeout",
 	"Director.SupportContactEmail",
@@ -1294,12 +1406,25 @@ var allParameterNames = []string{
 	"Issuer.UserStripDomain",
 	"IssuerKey",
 	"IssuerKeysDirectory",
+	"LocalCache.BlockSize",
 	"LocalCache.DataLocation",
+	"LocalCache.DefaultPinLifetime",
+	"LocalCache.EnablePeers",
 	"LocalCache.HighWaterMarkPercentage",
+	"LocalCache.ListenAddress",
+	"LocalCache.LotPolicy",
 	"LocalCache.LowWaterMarkPercentage",
+	"LocalCache.PeerAllowedHosts",
+	"LocalCache.PeerDigestInterval",
+	"LocalCache.PeerDiscoveryAddress",
+	"LocalCache.PeerTimeout",
+	"LocalCache.Peers",
+	"LocalCache.PrefetchConcurrency",
 	"LocalCache.RunLocation",
 	"LocalCache.Size",
 	"LocalCache.Socket",
+	"LocalCache.TLSCertificate",
+	"LocalCache.TLSKey",
 	"Logging.Cache.Http",
 	"Logging.Cache.Lotman",
 	"Logging.Cache.Ofs",
@@ -1330,6 +1455,11 @@ var allParameterNames = []string{
 	"Lotman.PolicyDefinitions",
 	"MinimumDownloadSpeed",
 	"Monitoring.AggregatePrefixes",
+	"Monitoring.AlertEmailFrom",
+	"Monitoring.AlertEmailRelay",
+	"Monitoring.AlertEmailTo",
+	"Monitoring.AlertWebhookUrl",
+	"Monitoring.AlertmanagerUrl",
 	"Monitoring.DataLocation",
 	"Monitoring.DataRetention",
 	"Monitoring.DataRetentionSize",
@@ -1341,6 +1471,8 @@ var allParameterNames = []string{
 	"Monitoring.PortHigher",
 	"Monitoring.PortLower",
 	"Monitoring.PromQLAuthorization",
+	"Monitoring.RuleEvaluationInterval",
+	"Monitoring.RuleFiles",
 	"Monitoring.SampleLimit",
 	"Monitoring.StorageCriticalThreshold",
 	"Monitoring.StorageHealthCheckInterval",
@@ -1357,6 +1489,7 @@ var allParameterNames = []string{
 	"OIDC.Scopes",
 	"OIDC.TokenEndpoint",
 	"OIDC.UserInfoEndpoint",
+	"Origin.ChunkedUploadStagingLifetime",
 	"Origin.Concurrency",
 	"Origin.ConcurrencyDegradedThreshold",
 	"Origin.DbLocation",
@@ -1368,6 +1501,7 @@ var allParameterNames = []string{
 	"Origin.DiskUsageCalculationRateLimit",
 	"Origin.EnableAtomicUploads",
 	"Origin.EnableBroker",
+	"Origin.EnableChunkedUploads",
 	"Origin.EnableCmsd",
 	"Origin.EnableDirListing",
 	"Origin.EnableDirectReads",
@@ -1376,9 +1510,11 @@ var allParameterNames = []string{
 	"Origin.EnableIssuer",
 	"Origin.EnableListings",
 	"Origin.EnableMacaroons",
+	"Origin.EnableNativeBackend",
 	"Origin.EnableOIDC",
 	"Origin.EnablePublicReads",
 	"Origin.EnableReads",
+	"Origin.EnableThirdPartyCopy",
 	"Origin.EnableVoms",
 	"Origin.EnableWrite",
 	"Origin.EnableWrites",
@@ -1403,6 +1539,7 @@ var allParameterNames = []string{
 	"Origin.MultiuserVarlinkSocketPath",
 	"Origin.NamespacePrefix",
 	"Origin.Port",
+	"Origin.ReadBandwidthLimit",
 	"Origin.RunLocation",
 	"Origin.S3AccessKeyfile",
 	"Origin.S3Bucket",
@@ -1449,6 +1586,7 @@ var allParameterNames = []string{
 	"Origin.UploadTempLocation",
 	"Origin.Url",
 	"Origin.UserMapfileRefreshInterval",
+	"Origin.WriteBandwidthLimit",
 	"Origin.XRootDPrefix",
 	"Origin.XRootServiceUrl",
 	"Plugin.DirectorDecisionPercentage",
@@ -1456,12 +1594,16 @@ var allParameterNames = []string{
 	"Registry.AdminUsers",
 	"Registry.CustomRegistrationFields",
 	"Registry.DbLocation",
+	"Registry.EventRetention",
 	"Registry.Institutions",
 	"Registry.InstitutionsUrl",
 	"Registry.InstitutionsUrlReloadMinutes",
 	"Registry.RequireCacheApproval",
 	"Registry.RequireKeyChaining",
 	"Registry.RequireOriginApproval",
+	"Registry.WebhookMaxAttempts",
+	"Registry.WebhookTimeout",
+	"Registry.WebhookUrls",
 	"RuntimeDir",
 	"Server.AdLifetime",
 	"Server.AdminGroups",
@@ -1528,9 +1670,20 @@ var allParameterNames = []string{
 	"Topology.DisableDowntime",
 	"Topology.DisableOriginX509",
 	"Topology.DisableOrigins",
+	"Tracing.Endpoint",
+	"Tracing.Exporter",
+	"Tracing.File",
+	"Tracing.SamplePercent",
 	"Transport.BrokerEndpointCacheTTL",
+	"Transport.BrokerPoolIdleTimeout",
+	"Transport.BrokerPoolPerService",
+	"Transport.BrokerPoolSize",
+	"Transport.BrokerTunnelLifetime",
+	"Transport.BrokerTunnelMaxStreams",
+	"Transport.BrokerTunnelsPerService",
 	"Transport.DialerKeepAlive",
 	"Transport.DialerTimeout",
+	"Transport.EnableBrokerTunnels",
 	"Transport.ExpectContinueTimeout",
 	"Transport.IdleConnTimeout",
 	"Transport.MaxIdleConns",
@@ -1592,6 +1745,7 @@ var (
 	Director_DefaultResponse = StringParam{"Director.DefaultResponse"}
 	Director_GeoIPLocation = StringParam{"Director.GeoIPLocation"}
 	Director_MaxMindKeyFile = StringParam{"Director.MaxMindKeyFile"}
+	Director_SortPolicyFile = StringParam{"Director.SortPolicyFile"}
 	Director_SupportContactEmail = StringParam{"Director.SupportContactEmail"}
 	Director_SupportContactUrl = StringParam{"Director.SupportContactUrl"}
 	Federation_DiscoveryUrl = StringParam{"Federation.DiscoveryUrl"}
@@ -1612,10 +1766,16 @@ var (
 	Issuer_QDLLocation = StringParam{"Issuer.QDLLocation"}
 	Issuer_ScitokensServerLocation = StringParam{"Issuer.ScitokensServerLocation"}
 	Issuer_TomcatLocation = StringParam{"Issuer.TomcatLocation"}
+	LocalCache_BlockSize = StringParam{"LocalCache.BlockSize"}
 	LocalCache_DataLocation = StringParam{"LocalCache.DataLocation"}
+	LocalCache_ListenAddress = StringParam{"LocalCache.ListenAddress"}
+	LocalCache_LotPolicy = StringParam{"LocalCache.LotPolicy"}
+	LocalCache_PeerDiscoveryAddress = StringParam{"LocalCache.PeerDiscoveryAddress"}
 	LocalCache_RunLocation = StringParam{"LocalCache.RunLocation"}
 	LocalCache_Size = StringParam{"LocalCache.Size"}
 	LocalCache_Socket = StringParam{"LocalCache.Socket"}
+	LocalCache_TLSCertificate = StringParam{"LocalCache.TLSCertificate"}
+	LocalCache_TLSKey = StringParam{"LocalCache.TLSKey"}
 	Logging_Cache_Http = StringParam{"Logging.Cache.Http"}
 	Logging_Cache_Lotman = StringParam{"Logging.Cache.Lotman"}
 	Logging_Cache_Ofs = StringParam{"Logging.Cache.Ofs"}
@@ -1638,6 +1798,10 @@ var (
 	Lotman_EnabledPolicy = StringParam{"Lotman.EnabledPolicy"}
 	Lotman_LibLocation = StringParam{"Lotman.LibLocation"}
 	Lotman_LotHome = StringParam{"Lotman.LotHome"}
+	Monitoring_AlertEmailFrom = StringParam{"Monitoring.AlertEmailFrom"}
+	Monitoring_AlertEmailRelay = StringParam{"Monitoring.AlertEmailRelay"}
+	Monitoring_AlertWebhookUrl = StringParam{"Monitoring.AlertWebhookUrl"}
+	Monitoring_AlertmanagerUrl = StringParam{"Monitoring.AlertmanagerUrl"}
 	Monitoring_DataLocation = StringParam{"Monitoring.DataLocation"}
 	Monitoring_DataRetentionSize = StringParam{"Monitoring.DataRetentionSize"}
 	OIDC_AuthorizationEndpoint = StringParam{"OIDC.AuthorizationEndpoint"}
@@ -1730,6 +1894,9 @@ var (
 	StagePlugin_MountPrefix = StringParam{"StagePlugin.MountPrefix"}
 	StagePlugin_OriginPrefix = StringParam{"StagePlugin.OriginPrefix"}
 	StagePlugin_ShadowOriginPrefix = StringParam{"StagePlugin.ShadowOriginPrefix"}
+	Tracing_Endpoint = StringParam{"Tracing.Endpoint"}
+	Tracing_Exporter = StringParam{"Tracing.Exporter"}
+	Tracing_File = StringParam{"Tracing.File"}
 	Xrootd_Authfile = StringParam{"Xrootd.Authfile"}
 	Xrootd_ConfigFile = StringParam{"Xrootd.ConfigFile"}
 	Xrootd_DetailedMonitoringHost = StringParam{"Xrootd.DetailedMonitoringHost"}
@@ -1755,7 +1922,11 @@ var (
 	Director_OriginResponseHostnames = StringSliceParam{"Director.OriginResponseHostnames"}
 	Issuer_GroupRequirements = StringSliceParam{"Issuer.GroupRequirements"}
 	Issuer_RedirectUris = StringSliceParam{"Issuer.RedirectUris"}
+	LocalCache_PeerAllowedHosts = StringSliceParam{"LocalCache.PeerAllowedHosts"}
+	LocalCache_Peers = StringSliceParam{"LocalCache.Peers"}
 	Monitoring_AggregatePrefixes = StringSliceParam{"Monitoring.AggregatePrefixes"}
+	Monitoring_AlertEmailTo = StringSliceParam{"Monitoring.AlertEmailTo"}
+	Monitoring_RuleFiles = StringSliceParam{"Monitoring.RuleFiles"}
 	OIDC_Scopes = StringSliceParam{"OIDC.Scopes"}
 	Origin_DefaultChecksumTypes = StringSliceParam{"Origin.DefaultChecksumTypes"}
 	Origin_ExportVolumes = StringSliceParam{"Origin.ExportVolumes"}
@@ -1764,6 +1935,7 @@ var (
 	Origin_ScitokensRestrictedPaths = StringSliceParam{"Origin.ScitokensRestrictedPaths"}
 	Origin_SupportedChecksumTypes = StringSliceParam{"Origin.SupportedChecksumTypes"}
 	Registry_AdminUsers = StringSliceParam{"Registry.AdminUsers"}
+	Registry_WebhookUrls = StringSliceParam{"Registry.WebhookUrls"}
 	Server_AdminGroups = StringSliceParam{"Server.AdminGroups"}
 	Server_DirectorUrls = StringSliceParam{"Server.DirectorUrls"}
 	Server_Modules = StringSliceParam{"Server.Modules"}
@@ -1780,9 +1952,15 @@ var (
 	Cache_Port = IntParam{"Cache.Port"}
 	ClientAgent_HistoryRetentionDays = IntParam{"ClientAgent.HistoryRetentionDays"}
 	ClientAgent_MaxConcurrentJobs = IntParam{"ClientAgent.MaxConcurrentJobs"}
+	Client_ChunkedUploadChunkSize = IntParam{"Client.ChunkedUploadChunkSize"}
+	Client_ChunkedUploadStreams = IntParam{"Client.ChunkedUploadStreams"}
+	Client_ChunkedUploadThreshold = IntParam{"Client.ChunkedUploadThreshold"}
 	Client_DirectorRetries = IntParam{"Client.DirectorRetries"}
 	Client_MaximumDownloadSpeed = IntParam{"Client.MaximumDownloadSpeed"}
 	Client_MinimumDownloadSpeed = IntParam{"Client.MinimumDownloadSpeed"}
+	Client_ParallelDownloadEndpoints = IntParam{"Client.ParallelDownloadEndpoints"}
+	Client_ParallelDownloadStreams = IntParam{"Client.ParallelDownloadStreams"}
+	Client_ParallelDownloadThreshold = IntParam{"Client.ParallelDownloadThreshold"}
 	Client_WorkerCount = IntParam{"Client.WorkerCount"}
 	Director_AdaptiveSortTruncateConstant = IntParam{"Director.AdaptiveSortTruncateConstant"}
 	Director_CachePresenceCapacity = IntParam{"Director.CachePresenceCapacity"}
@@ -1791,6 +1969,7 @@ var (
 	Director_StatConcurrencyLimit = IntParam{"Director.StatConcurrencyLimit"}
 	LocalCache_HighWaterMarkPercentage = IntParam{"LocalCache.HighWaterMarkPercentage"}
 	LocalCache_LowWaterMarkPercentage = IntParam{"LocalCache.LowWaterMarkPercentage"}
+	LocalCache_PrefetchConcurrency = IntParam{"LocalCache.PrefetchConcurrency"}
 	MinimumDownloadSpeed = IntParam{"MinimumDownloadSpeed"}
 	Monitoring_LabelLimit = IntParam{"Monitoring.LabelLimit"}
 	Monitoring_LabelNameLengthLimit = IntParam{"Monitoring.LabelNameLengthLimit"}
@@ -1809,12 +1988,18 @@ var (
 	Origin_SSH_MaxRetries = IntParam{"Origin.SSH.MaxRetries"}
 	Origin_SSH_Port = IntParam{"Origin.SSH.Port"}
 	Plugin_DirectorDecisionPercentage = IntParam{"Plugin.DirectorDecisionPercentage"}
+	Registry_WebhookMaxAttempts = IntParam{"Registry.WebhookMaxAttempts"}
 	Server_DatabaseBackup_MaxCount = IntParam{"Server.DatabaseBackup.MaxCount"}
 	Server_IssuerPort = IntParam{"Server.IssuerPort"}
 	Server_UILoginRateLimit = IntParam{"Server.UILoginRateLimit"}
 	Server_WebPort = IntParam{"Server.WebPort"}
 	Shoveler_PortHigher = IntParam{"Shoveler.PortHigher"}
 	Shoveler_PortLower = IntParam{"Shoveler.PortLower"}
+	Tracing_SamplePercent = IntParam{"Tracing.SamplePercent"}
+	Transport_BrokerPoolPerService = IntParam{"Transport.BrokerPoolPerService"}
+	Transport_BrokerPoolSize = IntParam{"Transport.BrokerPoolSize"}
+	Transport_BrokerTunnelMaxStreams = IntParam{"Transport.BrokerTunnelMaxStreams"}
+	Transport_BrokerTunnelsPerService = IntParam{"Transport.BrokerTunnelsPerService"}
 	Transport_MaxIdleConns = IntParam{"Transport.MaxIdleConns"}
 	Xrootd_DetailedMonitoringPort = IntParam{"Xrootd.DetailedMonitoringPort"}
 	Xrootd_LocalMonitoringPort = IntParam{"Xrootd.LocalMonitoringPort"}
@@ -1825,7 +2010,10 @@ var (
 )
 
 var (
+	ClientAgent_BandwidthLimit = ByteRateParam{"ClientAgent.BandwidthLimit"}
+	Origin_ReadBandwidthLimit = ByteRateParam{"Origin.ReadBandwidthLimit"}
 	Origin_TransferRateLimit = ByteRateParam{"Origin.TransferRateLimit"}
+	Origin_WriteBandwidthLimit = ByteRateParam{"Origin.WriteBandwidthLimit"}
 )
 
 var (
@@ -1845,6 +2033,7 @@ var (
 	Client_DisableProxyFallback = BoolParam{"Client.DisableProxyFallback"}
 	Client_EnableOverwrites = BoolParam{"Client.EnableOverwrites"}
 	Client_IsPlugin = BoolParam{"Client.IsPlugin"}
+	Client_ResumeDownloads = BoolParam{"Client.ResumeDownloads"}
 	Debug = BoolParam{"Debug"}
 	Director_AssumePresenceAtSingleOrigin = BoolParam{"Director.AssumePresenceAtSingleOrigin"}
 	Director_CachesPullFromCaches = BoolParam{"Director.CachesPullFromCaches"}
@@ -1859,6 +2048,7 @@ var (
 	DisableProxyFallback = BoolParam{"DisableProxyFallback"}
 	Issuer_OIDCPreferClaimsFromIDToken = BoolParam{"Issuer.OIDCPreferClaimsFromIDToken"}
 	Issuer_UserStripDomain = BoolParam{"Issuer.UserStripDomain"}
+	LocalCache_EnablePeers = BoolParam{"LocalCache.EnablePeers"}
 	Logging_DisableProgressBars = BoolParam{"Logging.DisableProgressBars"}
 	Lotman_EnableAPI = BoolParam{"Lotman.EnableAPI"}
 	Monitoring_EnablePrometheus = BoolParam{"Monitoring.EnablePrometheus"}
@@ -1868,6 +2058,7 @@ var (
 	Origin_DisableDirectClients = BoolParam{"Origin.DisableDirectClients"}
 	Origin_EnableAtomicUploads = BoolParam{"Origin.EnableAtomicUploads"}
 	Origin_EnableBroker = BoolParam{"Origin.EnableBroker"}
+	Origin_EnableChunkedUploads = BoolParam{"Origin.EnableChunkedUploads"}
 	Origin_EnableCmsd = BoolParam{"Origin.EnableCmsd"}
 	Origin_EnableDirListing = BoolParam{"Origin.EnableDirListing"}
 	Origin_EnableDirectReads = BoolParam{"Origin.EnableDirectReads"}
@@ -1876,9 +2067,11 @@ var (
 	Origin_EnableIssuer = BoolParam{"Origin.EnableIssuer"}
 	Origin_EnableListings = BoolParam{"Origin.EnableListings"}
 	Origin_EnableMacaroons = BoolParam{"Origin.EnableMacaroons"}
+	Origin_EnableNativeBackend = BoolParam{"Origin.EnableNativeBackend"}
 	Origin_EnableOIDC = BoolParam{"Origin.EnableOIDC"}
 	Origin_EnablePublicReads = BoolParam{"Origin.EnablePublicReads"}
 	Origin_EnableReads = BoolParam{"Origin.EnableReads"}
+	Origin_EnableThirdPartyCopy = BoolParam{"Origin.EnableThirdPartyCopy"}
 	Origin_EnableVoms = BoolParam{"Origin.EnableVoms"}
 	Origin_EnableWrite = BoolParam{"Origin.EnableWrite"}
 	Origin_EnableWrites = BoolParam{"Origin.EnableWrites"}
@@ -1905,6 +2098,7 @@ var (
 	Topology_DisableDowntime = BoolParam{"Topology.DisableDowntime"}
 	Topology_DisableOriginX509 = BoolParam{"Topology.DisableOriginX509"}
 	Topology_DisableOrigins = BoolParam{"Topology.DisableOrigins"}
+	Transport_EnableBrokerTunnels = BoolParam{"Transport.EnableBrokerTunnels"}
 	Xrootd_AutoShutdownEnabled = BoolParam{"Xrootd.AutoShutdownEnabled"}
 	Xrootd_EnableLocalMonitoring = BoolParam{"Xrootd.EnableLocalMonitoring"}
 )
@@ -1916,6 +2110,7 @@ var (
 	Cache_SelfTestInterval = DurationParam{"Cache.SelfTestInterval"}
 	Cache_SelfTestMaxAge = DurationParam{"Cache.SelfTestMaxAge"}
 	ClientAgent_IdleTimeout = DurationParam{"ClientAgent.IdleTimeout"}
+	ClientAgent_ProgressEventInterval = DurationParam{"ClientAgent.ProgressEventInterval"}
 	ClientAgent_ProgressUpdateInterval = DurationParam{"ClientAgent.ProgressUpdateInterval"}
 	Client_SlowTransferRampupTime = DurationParam{"Client.SlowTransferRampupTime"}
 	Client_SlowTransferWindow = DurationParam{"Client.SlowTransferWindow"}
@@ -1932,13 +2127,18 @@ var (
 	Issuer_DynamicClientStaleTimeout = DurationParam{"Issuer.DynamicClientStaleTimeout"}
 	Issuer_DynamicClientUnusedTimeout = DurationParam{"Issuer.DynamicClientUnusedTimeout"}
 	Issuer_RefreshTokenGracePeriod = DurationParam{"Issuer.RefreshTokenGracePeriod"}
+	LocalCache_DefaultPinLifetime = DurationParam{"LocalCache.DefaultPinLifetime"}
+	LocalCache_PeerDigestInterval = DurationParam{"LocalCache.PeerDigestInterval"}
+	LocalCache_PeerTimeout = DurationParam{"LocalCache.PeerTimeout"}
 	Logging_Client_ProgressInterval = DurationParam{"Logging.Client.ProgressInterval"}
 	Lotman_DefaultLotDeletionLifetime = DurationParam{"Lotman.DefaultLotDeletionLifetime"}
 	Lotman_DefaultLotExpirationLifetime = DurationParam{"Lotman.DefaultLotExpirationLifetime"}
 	Monitoring_DataRetention = DurationParam{"Monitoring.DataRetention"}
+	Monitoring_RuleEvaluationInterval = DurationParam{"Monitoring.RuleEvaluationInterval"}
 	Monitoring_StorageHealthCheckInterval = DurationParam{"Monitoring.StorageHealthCheckInterval"}
 	Monitoring_TokenExpiresIn = DurationParam{"Monitoring.TokenExpiresIn"}
 	Monitoring_TokenRefreshInterval = DurationParam{"Monitoring.TokenRefreshInterval"}
+	Origin_ChunkedUploadStagingLifetime = DurationParam{"Origin.ChunkedUploadStagingLifetime"}
 	Origin_DiskUsageCalculationDelay = DurationParam{"Origin.DiskUsageCalculationDelay"}
 	Origin_DiskUsageCalculationInterval = DurationParam{"Origin.DiskUsageCalculationInterval"}
 	Origin_SSH_ChallengeTimeout = DurationParam{"Origin.SSH.ChallengeTimeout"}
@@ -1949,13 +2149,17 @@ var (
 	Origin_SelfTestInterval = DurationParam{"Origin.SelfTestInterval"}
 	Origin_SelfTestMaxAge = DurationParam{"Origin.SelfTestMaxAge"}
 	Origin_UserMapfileRefreshInterval = DurationParam{"Origin.UserMapfileRefreshInterval"}
+	Registry_EventRetention = DurationParam{"Registry.EventRetention"}
 	Registry_InstitutionsUrlReloadMinutes = DurationParam{"Registry.InstitutionsUrlReloadMinutes"}
+	Registry_WebhookTimeout = DurationParam{"Registry.WebhookTimeout"}
 	Server_AdLifetime = DurationParam{"Server.AdLifetime"}
 	Server_AdvertisementInterval = DurationParam{"Server.AdvertisementInterval"}
 	Server_DatabaseBackup_Frequency = DurationParam{"Server.DatabaseBackup.Frequency"}
 	Server_RegistrationRetryInterval = DurationParam{"Server.RegistrationRetryInterval"}
 	Server_StartupTimeout = DurationParam{"Server.StartupTimeout"}
 	Transport_BrokerEndpointCacheTTL = DurationParam{"Transport.BrokerEndpointCacheTTL"}
+	Transport_BrokerPoolIdleTimeout = DurationParam{"Transport.BrokerPoolIdleTimeout"}
+	Transport_BrokerTunnelLifetime = DurationParam{"Transport.BrokerTunnelLifetime"}
 	Transport_DialerKeepAlive = DurationParam{"Transport.DialerKeepAlive"}
 	Transport_DialerTimeout = DurationParam{"Transport.DialerTimeout"}
 	Transport_ExpectContinueTimeout = DurationParam{"Transport.ExpectContinueTimeout"}
@@ -2026,6 +2230,7 @@ func init() {
 		"Director.DefaultResponse": Director_DefaultResponse,
 		"Director.GeoIPLocation": Director_GeoIPLocation,
 		"Director.MaxMindKeyFile": Director_MaxMindKeyFile,
+		"Director.SortPolicyFile": Director_SortPolicyFile,
 		"Director.SupportContactEmail": Director_SupportContactEmail,
 		"Director.SupportContactUrl": Director_SupportContactUrl,
 		"Federation.DiscoveryUrl": Federation_DiscoveryUrl,
@@ -2046,10 +2251,16 @@ func init() {
 		"Issuer.QDLLocation": Issuer_QDLLocation,
 		"Issuer.ScitokensServerLocation": Issuer_ScitokensServerLocation,
 		"Issuer.TomcatLocation": Issuer_TomcatLocation,
+		"LocalCache.BlockSize": LocalCache_BlockSize,
 		"LocalCache.DataLocation": LocalCache_DataLocation,
+		"LocalCache.ListenAddress": LocalCache_ListenAddress,
+		"LocalCache.LotPolicy": LocalCache_LotPolicy,
+		"LocalCache.PeerDiscoveryAddress": LocalCache_PeerDiscoveryAddress,
 		"LocalCache.RunLocation": LocalCache_RunLocation,
 		"LocalCache.Size": LocalCache_Size,
 		"LocalCache.Socket": LocalCache_Socket,
+		"LocalCache.TLSCertificate": LocalCache_TLSCertificate,
+		"LocalCache.TLSKey": LocalCache_TLSKey,
 		"Logging.Cache.Http": Logging_Cache_Http,
 		"Logging.Cache.Lotman": Logging_Cache_Lotman,
 		"Logging.Cache.Ofs": Logging_Cache_Ofs,
@@ -2072,6 +2283,10 @@ func init() {
 		"Lotman.EnabledPolicy": Lotman_EnabledPolicy,
 		"Lotman.LibLocation": Lotman_LibLocation,
 		"Lotman.LotHome": Lotman_LotHome,
+		"Monitoring.AlertEmailFrom": Monitoring_AlertEmailFrom,
+		"Monitoring.AlertEmailRelay": Monitoring_AlertEmailRelay,
+		"Monitoring.AlertWebhookUrl": Monitoring_AlertWebhookUrl,
+		"Monitoring.AlertmanagerUrl": Monitoring_AlertmanagerUrl,
 		"Monitoring.DataLocation": Monitoring_DataLocation,
 		"Monitoring.DataRetentionSize": Monitoring_DataRetentionSize,
 		"OIDC.AuthorizationEndpoint": OIDC_AuthorizationEndpoint,
@@ -2164,6 +2379,9 @@ func init() {
 		"StagePlugin.MountPrefix": StagePlugin_MountPrefix,
 		"StagePlugin.OriginPrefix": StagePlugin_OriginPrefix,
 		"StagePlugin.ShadowOriginPrefix": StagePlugin_ShadowOriginPrefix,
+		"Tracing.Endpoint": Tracing_Endpoint,
+		"Tracing.Exporter": Tracing_Exporter,
+		"Tracing.File": Tracing_File,
 		"Xrootd.Authfile": Xrootd_Authfile,
 		"Xrootd.ConfigFile": Xrootd_ConfigFile,
 		"Xrootd.DetailedMonitoringHost": Xrootd_DetailedMonitoringHost,
@@ -2186,7 +2404,11 @@ func init() {
 		"Director.OriginResponseHostnames": Director_OriginResponseHostnames,
 		"Issuer.GroupRequirements": Issuer_GroupRequirements,
 		"Issuer.RedirectUris": Issuer_RedirectUris,
+		"LocalCache.PeerAllowedHosts": LocalCache_PeerAllowedHosts,
+		"LocalCache.Peers": LocalCache_Peers,
 		"Monitoring.AggregatePrefixes": Monitoring_AggregatePrefixes,
+		"Monitoring.AlertEmailTo": Monitoring_AlertEmailTo,
+		"Monitoring.RuleFiles": Monitoring_RuleFiles,
 		"OIDC.Scopes": OIDC_Scopes,
 		"Origin.DefaultChecksumTypes": Origin_DefaultChecksumTypes,
 		"Origin.ExportVolumes": Origin_ExportVolumes,
@@ -2195,6 +2417,7 @@ func init() {
 		"Origin.ScitokensRestrictedPaths": Origin_ScitokensRestrictedPaths,
 		"Origin.SupportedChecksumTypes": Origin_SupportedChecksumTypes,
 		"Registry.AdminUsers": Registry_AdminUsers,
+		"Registry.WebhookUrls": Registry_WebhookUrls,
 		"Server.AdminGroups": Server_AdminGroups,
 		"Server.DirectorUrls": Server_DirectorUrls,
 		"Server.Modules": Server_Modules,
@@ -2208,9 +2431,15 @@ func init() {
 		"Cache.Port": Cache_Port,
 		"ClientAgent.HistoryRetentionDays": ClientAgent_HistoryRetentionDays,
 		"ClientAgent.MaxConcurrentJobs": ClientAgent_MaxConcurrentJobs,
+		"Client.ChunkedUploadChunkSize": Client_ChunkedUploadChunkSize,
+		"Client.ChunkedUploadStreams": Client_ChunkedUploadStreams,
+		"Client.ChunkedUploadThreshold": Client_ChunkedUploadThreshold,
 		"Client.DirectorRetries": Client_DirectorRetries,
 		"Client.MaximumDownloadSpeed": Client_MaximumDownloadSpeed,
 		"Client.MinimumDownloadSpeed": Client_MinimumDownloadSpeed,
+		"Client.ParallelDownloadEndpoints": Client_ParallelDownloadEndpoints,
+		"Client.ParallelDownloadStreams": Client_ParallelDownloadStreams,
+		"Client.ParallelDownloadThreshold": Client_ParallelDownloadThreshold,
 		"Client.WorkerCount": Client_WorkerCount,
 		"Director.AdaptiveSortTruncateConstant": Director_AdaptiveSortTruncateConstant,
 		"Director.CachePresenceCapacity": Director_CachePresenceCapacity,
@@ -2219,6 +2448,7 @@ func init() {
 		"Director.StatConcurrencyLimit": Director_StatConcurrencyLimit,
 		"LocalCache.HighWaterMarkPercentage": LocalCache_HighWaterMarkPercentage,
 		"LocalCache.LowWaterMarkPercentage": LocalCache_LowWaterMarkPercentage,
+		"LocalCache.PrefetchConcurrency": LocalCache_PrefetchConcurrency,
 		"MinimumDownloadSpeed": MinimumDownloadSpeed,
 		"Monitoring.LabelLimit": Monitoring_LabelLimit,
 		"Monitoring.LabelNameLengthLimit": Monitoring_LabelNameLengthLimit,
@@ -2237,12 +2467,18 @@ func init() {
 		"Origin.SSH.MaxRetries": Origin_SSH_MaxRetries,
 		"Origin.SSH.Port": Origin_SSH_Port,
 		"Plugin.DirectorDecisionPercentage": Plugin_DirectorDecisionPercentage,
+		"Registry.WebhookMaxAttempts": Registry_WebhookMaxAttempts,
 		"Server.DatabaseBackup.MaxCount": Server_DatabaseBackup_MaxCount,
 		"Server.IssuerPort": Server_IssuerPort,
 		"Server.UILoginRateLimit": Server_UILoginRateLimit,
 		"Server.WebPort": Server_WebPort,
 		"Shoveler.PortHigher": Shoveler_PortHigher,
 		"Shoveler.PortLower": Shoveler_PortLower,
+		"Tracing.SamplePercent": Tracing_SamplePercent,
+		"Transport.BrokerPoolPerService": Transport_BrokerPoolPerService,
+		"Transport.BrokerPoolSize": Transport_BrokerPoolSize,
+		"Transport.BrokerTunnelMaxStreams": Transport_BrokerTunnelMaxStreams,
+		"Transport.BrokerTunnelsPerService": Transport_BrokerTunnelsPerService,
 		"Transport.MaxIdleConns": Transport_MaxIdleConns,
 		"Xrootd.DetailedMonitoringPort": Xrootd_DetailedMonitoringPort,
 		"Xrootd.LocalMonitoringPort": Xrootd_LocalMonitoringPort,
@@ -2250,7 +2486,10 @@ func init() {
 		"Xrootd.MaxThreads": Xrootd_MaxThreads,
 		"Xrootd.Port": Xrootd_Port,
 		"Xrootd.SummaryMonitoringPort": Xrootd_SummaryMonitoringPort,
+		"ClientAgent.BandwidthLimit": ClientAgent_BandwidthLimit,
+		"Origin.ReadBandwidthLimit": Origin_ReadBandwidthLimit,
 		"Origin.TransferRateLimit": Origin_TransferRateLimit,
+		"Origin.WriteBandwidthLimit": Origin_WriteBandwidthLimit,
 		"Cache.DirectorTest": Cache_DirectorTest,
 		"Cache.DisableClientX509": Cache_DisableClientX509,
 		"Cache.EnableBroker": Cache_EnableBroker,
@@ -2267,6 +2506,7 @@ func init() {
 		"Client.DisableProxyFallback": Client_DisableProxyFallback,
 		"Client.EnableOverwrites": Client_EnableOverwrites,
 		"Client.IsPlugin": Client_IsPlugin,
+		"Client.ResumeDownloads": Client_ResumeDownloads,
 		"Debug": Debug,
 		"Director.AssumePresenceAtSingleOrigin": Director_AssumePresenceAtSingleOrigin,
 		"Director.CachesPullFromCaches": Director_CachesPullFromCaches,
@@ -2281,6 +2521,7 @@ func init() {
 		"DisableProxyFallback": DisableProxyFallback,
 		"Issuer.OIDCPreferClaimsFromIDToken": Issuer_OIDCPreferClaimsFromIDToken,
 		"Issuer.UserStripDomain": Issuer_UserStripDomain,
+		"LocalCache.EnablePeers": LocalCache_EnablePeers,
 		"Logging.DisableProgressBars": Logging_DisableProgressBars,
 		"Lotman.EnableAPI": Lotman_EnableAPI,
 		"Monitoring.EnablePrometheus": Monitoring_EnablePrometheus,
@@ -2290,6 +2531,7 @@ func init() {
 		"Origin.DisableDirectClients": Origin_DisableDirectClients,
 		"Origin.EnableAtomicUploads": Origin_EnableAtomicUploads,
 		"Origin.EnableBroker": Origin_EnableBroker,
+		"Origin.EnableChunkedUploads": Origin_EnableChunkedUploads,
 		"Origin.EnableCmsd": Origin_EnableCmsd,
 		"Origin.EnableDirListing": Origin_EnableDirListing,
 		"Origin.EnableDirectReads": Origin_EnableDirectReads,
@@ -2298,9 +2540,11 @@ func init() {
 		"Origin.EnableIssuer": Origin_EnableIssuer,
 		"Origin.EnableListings": Origin_EnableListings,
 		"Origin.EnableMacaroons": Origin_EnableMacaroons,
+		"Origin.EnableNativeBackend": Origin_EnableNativeBackend,
 		"Origin.EnableOIDC": Origin_EnableOIDC,
 		"Origin.EnablePublicReads": Origin_EnablePublicReads,
 		"Origin.EnableReads": Origin_EnableReads,
+		"Origin.EnableThirdPartyCopy": Origin_EnableThirdPartyCopy,
 		"Origin.EnableVoms": Origin_EnableVoms,
 		"Origin.EnableWrite": Origin_EnableWrite,
 		"Origin.EnableWrites": Origin_EnableWrites,
@@ -2327,6 +2571,7 @@ func init() {
 		"Topology.DisableDowntime": Topology_DisableDowntime,
 		"Topology.DisableOriginX509": Topology_DisableOriginX509,
 		"Topology.DisableOrigins": Topology_DisableOrigins,
+		"Transport.EnableBrokerTunnels": Transport_EnableBrokerTunnels,
 		"Xrootd.AutoShutdownEnabled": Xrootd_AutoShutdownEnabled,
 		"Xrootd.EnableLocalMonitoring": Xrootd_EnableLocalMonitoring,
 		"Cache.DefaultCacheTimeout": Cache_DefaultCacheTimeout,
@@ -2335,6 +2580,7 @@ func init() {
 		"Cache.SelfTestInterval": Cache_SelfTestInterval,
 		"Cache.SelfTestMaxAge": Cache_SelfTestMaxAge,
 		"ClientAgent.IdleTimeout": ClientAgent_IdleTimeout,
+		"ClientAgent.ProgressEventInterval": ClientAgent_ProgressEventInterval,
 		"ClientAgent.ProgressUpdateInterval": ClientAgent_ProgressUpdateInterval,
 		"Client.SlowTransferRampupTime": Client_SlowTransferRampupTime,
 		"Client.SlowTransferWindow": Client_SlowTransferWindow,
@@ -2351,13 +2597,18 @@ func init() {
 		"Issuer.DynamicClientStaleTimeout": Issuer_DynamicClientStaleTimeout,
 		"Issuer.DynamicClientUnusedTimeout": Issuer_DynamicClientUnusedTimeout,
 		"Issuer.RefreshTokenGracePeriod": Issuer_RefreshTokenGracePeriod,
+		"LocalCache.DefaultPinLifetime": LocalCache_DefaultPinLifetime,
+		"LocalCache.PeerDigestInterval": LocalCache_PeerDigestInterval,
+		"LocalCache.PeerTimeout": LocalCache_PeerTimeout,
 		"Logging.Client.ProgressInterval": Logging_Client_ProgressInterval,
 		"Lotman.DefaultLotDeletionLifetime": Lotman_DefaultLotDeletionLifetime,
 		"Lotman.DefaultLotExpirationLifetime": Lotman_DefaultLotExpirationLifetime,
 		"Monitoring.DataRetention": Monitoring_DataRetention,
+		"Monitoring.RuleEvaluationInterval": Monitoring_RuleEvaluationInterval,
 		"Monitoring.StorageHealthCheckInterval": Monitoring_StorageHealthCheckInterval,
 		"Monitoring.TokenExpiresIn": Monitoring_TokenExpiresIn,
 		"Monitoring.TokenRefreshInterval": Monitoring_TokenRefreshInterval,
+		"Origin.ChunkedUploadStagingLifetime": Origin_ChunkedUploadStagingLifetime,
 		"Origin.DiskUsageCalculationDelay": Origin_DiskUsageCalculationDelay,
 		"Origin.DiskUsageCalculationInterval": Origin_DiskUsageCalculationInterval,
 		"Origin.SSH.ChallengeTimeout": Origin_SSH_ChallengeTimeout,
@@ -2368,13 +2619,17 @@ func init() {
 		"Origin.SelfTestInterval": Origin_SelfTestInterval,
 		"Origin.SelfTestMaxAge": Origin_SelfTestMaxAge,
 		"Origin.UserMapfileRefreshInterval": Origin_UserMapfileRefreshInterval,
+		"Registry.EventRetention": Registry_EventRetention,
 		"Registry.InstitutionsUrlReloadMinutes": Registry_InstitutionsUrlReloadMinutes,
+		"Registry.WebhookTimeout": Registry_WebhookTimeout,
 		"Server.AdLifetime": Server_AdLifetime,
 		"Server.AdvertisementInterval": Server_AdvertisementInterval,
 		"Server.DatabaseBackup.Frequency": Server_DatabaseBackup_Frequency,
 		"Server.RegistrationRetryInterval": Server_RegistrationRetryInterval,
 		"Server.StartupTimeout": Server_StartupTimeout,
 		"Transport.BrokerEndpointCacheTTL": Transport_BrokerEndpointCacheTTL,
+		"Transport.BrokerPoolIdleTimeout": Transport_BrokerPoolIdleTimeout,
+		"Transport.BrokerTunnelLifetime": Transport_BrokerTunnelLifetime,
 		"Transport.DialerKeepAlive": Transport_DialerKeepAlive,
 		"Transport.DialerTimeout": Transport_DialerTimeout,
 		"Transport.ExpectContinueTimeout": Transport_ExpectContinueTimeout,
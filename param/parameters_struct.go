@@ -72,6 +72,9 @@ type Config struct {
 	} `mapstructure:"cache" yaml:"Cache"`
 	Client struct {
 		AssumeDirectorServerHeader bool `mapstructure:"assumedirectorserverheader" yaml:"AssumeDirectorServerHeader"`
+		ChunkedUploadChunkSize int `mapstructure:"chunkeduploadchunksize" yaml:"ChunkedUploadChunkSize"`
+		ChunkedUploadStreams int `mapstructure:"chunkeduploadstreams" yaml:"ChunkedUploadStreams"`
+		ChunkedUploadThreshold int `mapstructure:"chunkeduploadthreshold" yaml:"ChunkedUploadThreshold"`
 		CredentialFile string `mapstructure:"credentialfile" yaml:"CredentialFile"`
 		DirectorRetries int `mapstructure:"directorretries" yaml:"DirectorRetries"`
 		DisableHttpProxy bool `mapstructure:"disablehttpproxy" yaml:"DisableHttpProxy"`
@@ -80,18 +83,24 @@ type Config struct {
 		IsPlugin bool `mapstructure:"isplugin" yaml:"IsPlugin"`
 		MaximumDownloadSpeed int `mapstructure:"maximumdownloadspeed" yaml:"MaximumDownloadSpeed"`
 		MinimumDownloadSpeed int `mapstructure:"minimumdownloadspeed" yaml:"MinimumDownloadSpeed"`
+		ParallelDownloadEndpoints int `mapstructure:"paralleldownloadendpoints" yaml:"ParallelDownloadEndpoints"`
+		ParallelDownloadStreams int `mapstructure:"paralleldownloadstreams" yaml:"ParallelDownloadStreams"`
+		ParallelDownloadThreshold int `mapstructure:"paralleldownloadthreshold" yaml:"ParallelDownloadThreshold"`
 		PreferredCaches []string `mapstructure:"preferredcaches" yaml:"PreferredCaches"`
+		ResumeDownloads bool `mapstructure:"resumedownloads" yaml:"ResumeDownloads"`
 		SlowTransferRampupTime time.Duration `mapstructure:"slowtransferrampuptime" yaml:"SlowTransferRampupTime"`
 		SlowTransferWindow time.Duration `mapstructure:"slowtransferwindow" yaml:"SlowTransferWindow"`
 		StoppedTransferTimeout time.Duration `mapstructure:"stoppedtransfertimeout" yaml:"StoppedTransferTimeout"`
 		WorkerCount int `mapstructure:"workercount" yaml:"WorkerCount"`
 	} `mapstructure:"client" yaml:"Client"`
 	ClientAgent struct {
+		BandwidthLimit byte_rate.ByteRate `mapstructure:"bandwidthlimit" yaml:"BandwidthLimit"`
 		DbLocation string `mapstructure:"dblocation" yaml:"DbLocation"`
 		HistoryRetentionDays int `mapstructure:"historyretentiondays" yaml:"HistoryRetentionDays"`
 		IdleTimeout time.Duration `mapstructure:"idletimeout" yaml:"IdleTimeout"`
 		MaxConcurrentJobs int `mapstructure:"maxconcurrentjobs" yaml:"MaxConcurrentJobs"`
 		PidFile string `mapstructure:"pidfile" yaml:"PidFile"`
+		ProgressEventInterval time.Duration `mapstructure:"progresseventinterval" yaml:"ProgressEventInterval"`
 		ProgressUpdateInterval time.Duration `mapstructure:"progressupdateinterval" yaml:"ProgressUpdateInterval"`
 		Socket string `mapstructure:"socket" yaml:"Socket"`
 	} `mapstructure:"clientagent" yaml:"ClientAgent"`
@@ -128,6 +137,7 @@ type Config struct {
 		OriginCacheHealthTestInterval time.Duration `mapstructure:"origincachehealthtestinterval" yaml:"OriginCacheHealthTestInterval"`
 		OriginResponseHostnames []string `mapstructure:"originresponsehostnames" yaml:"OriginResponseHostnames"`
 		RegistryQueryInterval time.Duration `mapstructure:"registryqueryinterval" yaml:"RegistryQueryInterval"`
+		SortPolicyFile string `mapstructure:"sortpolicyfile" yaml:"SortPolicyFile"`
 		StatConcurrencyLimit int `mapstructure:"statconcurrencylimit" yaml:"StatConcurrencyLimit"`
 		StatTimeout time.Duration `mapstructure:"stattimeout" yaml:"StatTimeout"`
 		SupportContactEmail string `mapstructure:"supportcontactemail" yaml:"SupportContactEmail"`
@@ -173,12 +183,25 @@ type Config struct {
 	IssuerKey string `mapstructure:"issuerkey" yaml:"IssuerKey"`
 	IssuerKeysDirectory string `mapstructure:"issuerkeysdirectory" yaml:"IssuerKeysDirectory"`
 	LocalCache struct {
+		BlockSize string `mapstructure:"blocksize" yaml:"BlockSize"`
 		DataLocation string `mapstructure:"datalocation" yaml:"DataLocation"`
+		DefaultPinLifetime time.Duration `mapstructure:"defaultpinlifetime" yaml:"DefaultPinLifetime"`
+		EnablePeers bool `mapstructure:"enablepeers" yaml:"EnablePeers"`
 		HighWaterMarkPercentage int `mapstructure:"highwatermarkpercentage" yaml:"HighWaterMarkPercentage"`
+		ListenAddress string `mapstructure:"listenaddress" yaml:"ListenAddress"`
+		LotPolicy string `mapstructure:"lotpolicy" yaml:"LotPolicy"`
 		LowWaterMarkPercentage int `mapstructure:"lowwatermarkpercentage" yaml:"LowWaterMarkPercentage"`
+		PeerAllowedHosts []string `mapstructure:"peerallowedhosts" yaml:"PeerAllowedHosts"`
+		PeerDigestInterval time.Duration `mapstructure:"peerdigestinterval" yaml:"PeerDigestInterval"`
+		PeerDiscoveryAddress string `mapstructure:"peerdiscoveryaddress" yaml:"PeerDiscoveryAddress"`
+		PeerTimeout time.Duration `mapstructure:"peertimeout" yaml:"PeerTimeout"`
+		Peers []string `mapstructure:"peers" yaml:"Peers"`
+		PrefetchConcurrency int `mapstructure:"prefetchconcurrency" yaml:"PrefetchConcurrency"`
 		RunLocation string `mapstructure:"runlocation" yaml:"RunLocation"`
 		Size string `mapstructure:"size" yaml:"Size"`
 		Socket string `mapstructure:"socket" yaml:"Socket"`
+		TLSCertificate string `mapstructure:"tlscertificate" yaml:"TLSCertificate"`
+		TLSKey string `mapstructure:"tlskey" yaml:"TLSKey"`
 	} `mapstructure:"localcache" yaml:"LocalCache"`
 	Logging struct {
 		Cache struct {
@@ -221,6 +244,11 @@ type Config struct {
 	MinimumDownloadSpeed int `mapstructure:"minimumdownloadspeed" yaml:"MinimumDownloadSpeed"`
 	Monitoring struct {
 		AggregatePrefixes []string `mapstructure:"aggregateprefixes" yaml:"AggregatePrefixes"`
+		AlertEmailFrom string `mapstructure:"alertemailfrom" yaml:"AlertEmailFrom"`
+		AlertEmailRelay string `mapstructure:"alertemailrelay" yaml:"AlertEmailRelay"`
+		AlertEmailTo []string `mapstructure:"alertemailto" yaml:"AlertEmailTo"`
+		AlertWebhookUrl string `mapstructure:"alertwebhookurl" yaml:"AlertWebhookUrl"`
+		AlertmanagerUrl string `mapstructure:"alertmanagerurl" yaml:"AlertmanagerUrl"`
 		DataLocation string `mapstructure:"datalocation" yaml:"DataLocation"`
 		DataRetention time.Duration `mapstructure:"dataretention" yaml:"DataRetention"`
 		DataRetentionSize string `mapstructure:"dataretentionsize" yaml:"DataRetentionSize"`
@@ -232,6 +260,8 @@ type Config struct {
 		PortHigher int `mapstructure:"porthigher" yaml:"PortHigher"`
 		PortLower int `mapstructure:"portlower" yaml:"PortLower"`
 		PromQLAuthorization bool `mapstructure:"promqlauthorization" yaml:"PromQLAuthorization"`
+		RuleEvaluationInterval time.Duration `mapstructure:"ruleevaluationinterval" yaml:"RuleEvaluationInterval"`
+		RuleFiles []string `mapstructure:"rulefiles" yaml:"RuleFiles"`
 		SampleLimit int `mapstructure:"samplelimit" yaml:"SampleLimit"`
 		StorageCriticalThreshold int `mapstructure:"storagecriticalthreshold" yaml:"StorageCriticalThreshold"`
 		StorageHealthCheckInterval time.Duration `mapstructure:"storagehealthcheckinterval" yaml:"StorageHealthCheckInterval"`
@@ -252,6 +282,7 @@ type Config struct {
 		UserInfoEndpoint string `mapstructure:"userinfoendpoint" yaml:"UserInfoEndpoint"`
 	} `mapstructure:"oidc" yaml:"OIDC"`
 	Origin struct {
+		ChunkedUploadStagingLifetime time.Duration `mapstructure:"chunkeduploadstaginglifetime" yaml:"ChunkedUploadStagingLifetime"`
 		Concurrency int `mapstructure:"concurrency" yaml:"Concurrency"`
 		ConcurrencyDegradedThreshold int `mapstructure:"concurrencydegradedthreshold" yaml:"ConcurrencyDegradedThreshold"`
 		DbLocation string `mapstructure:"dblocation" yaml:"DbLocation"`
@@ -263,6 +294,7 @@ type Config struct {
 		DiskUsageCalculationRateLimit int `mapstructure:"diskusagecalculationratelimit" yaml:"DiskUsageCalculationRateLimit"`
 		EnableAtomicUploads bool `mapstructure:"enableatomicuploads" yaml:"EnableAtomicUploads"`
 		EnableBroker bool `mapstructure:"enablebroker" yaml:"EnableBroker"`
+		EnableChunkedUploads bool `mapstructure:"enablechunkeduploads" yaml:"EnableChunkedUploads"`
 		EnableCmsd bool `mapstructure:"enablecmsd" yaml:"EnableCmsd"`
 		EnableDirListing bool `mapstructure:"enabledirlisting" yaml:"EnableDirListing"`
 		EnableDirectReads bool `mapstructure:"enabledirectreads" yaml:"EnableDirectReads"`
@@ -271,9 +303,11 @@ type Config struct {
 		EnableIssuer bool `mapstructure:"enableissuer" yaml:"EnableIssuer"`
 		EnableListings bool `mapstructure:"enablelistings" yaml:"EnableListings"`
 		EnableMacaroons bool `mapstructure:"enablemacaroons" yaml:"EnableMacaroons"`
+		EnableNativeBackend bool `mapstructure:"enablenativebackend" yaml:"EnableNativeBackend"`
 		EnableOIDC bool `mapstructure:"enableoidc" yaml:"EnableOIDC"`
 		EnablePublicReads bool `mapstructure:"enablepublicreads" yaml:"EnablePublicReads"`
 		EnableReads bool `mapstructure:"enablereads" yaml:"EnableReads"`
+		EnableThirdPartyCopy bool `mapstructure:"enablethirdpartycopy" yaml:"EnableThirdPartyCopy"`
 		EnableVoms bool `mapstructure:"enablevoms" yaml:"EnableVoms"`
 		EnableWrite bool `mapstructure:"enablewrite" yaml:"EnableWrite"`
 		EnableWrites bool `mapstructure:"enablewrites" yaml:"EnableWrites"`
@@ -298,6 +332,7 @@ type Config struct {
 		MultiuserVarlinkSocketPath string `mapstructure:"multiuservarlinksocketpath" yaml:"MultiuserVarlinkSocketPath"`
 		NamespacePrefix string `mapstructure:"namespaceprefix" yaml:"NamespacePrefix"`
 		Port int `mapstructure:"port" yaml:"Port"`
+		ReadBandwidthLimit byte_rate.ByteRate `mapstructure:"readbandwidthlimit" yaml:"ReadBandwidthLimit"`
 		RunLocation string `mapstructure:"runlocation" yaml:"RunLocation"`
 		S3AccessKeyfile string `mapstructure:"s3accesskeyfile" yaml:"S3AccessKeyfile"`
 		S3Bucket string `mapstructure:"s3bucket" yaml:"S3Bucket"`
@@ -346,6 +381,7 @@ type Config struct {
 		UploadTempLocation string `mapstructure:"uploadtemplocation" yaml:"UploadTempLocation"`
 		Url string `mapstructure:"url" yaml:"Url"`
 		UserMapfileRefreshInterval time.Duration `mapstructure:"usermapfilerefreshinterval" yaml:"UserMapfileRefreshInterval"`
+		WriteBandwidthLimit byte_rate.ByteRate `mapstructure:"writebandwidthlimit" yaml:"WriteBandwidthLimit"`
 		XRootDPrefix string `mapstructure:"xrootdprefix" yaml:"XRootDPrefix"`
 		XRootServiceUrl string `mapstructure:"xrootserviceurl" yaml:"XRootServiceUrl"`
 	} `mapstructure:"origin" yaml:"Origin"`
@@ -357,12 +393,16 @@ type Config struct {
 		AdminUsers []string `mapstructure:"adminusers" yaml:"AdminUsers"`
 		CustomRegistrationFields any `mapstructure:"customregistrationfields" yaml:"CustomRegistrationFields"`
 		DbLocation string `mapstructure:"dblocation" yaml:"DbLocation"`
+		EventRetention time.Duration `mapstructure:"eventretention" yaml:"EventRetention"`
 		Institutions any `mapstructure:"institutions" yaml:"Institutions"`
 		InstitutionsUrl string `mapstructure:"institutionsurl" yaml:"InstitutionsUrl"`
 		InstitutionsUrlReloadMinutes time.Duration `mapstructure:"institutionsurlreloadminutes" yaml:"InstitutionsUrlReloadMinutes"`
 		RequireCacheApproval bool `mapstructure:"requirecacheapproval" yaml:"RequireCacheApproval"`
 		RequireKeyChaining bool `mapstructure:"requirekeychaining" yaml:"RequireKeyChaining"`
 		RequireOriginApproval bool `mapstructure:"requireoriginapproval" yaml:"RequireOriginApproval"`
+		WebhookMaxAttempts int `mapstructure:"webhookmaxattempts" yaml:"WebhookMaxAttempts"`
+		WebhookTimeout time.Duration `mapstructure:"webhooktimeout" yaml:"WebhookTimeout"`
+		WebhookUrls []string `mapstructure:"webhookurls" yaml:"WebhookUrls"`
 	} `mapstructure:"registry" yaml:"Registry"`
 	RuntimeDir string `mapstructure:"runtimedir" yaml:"RuntimeDir"`
 	Server struct {
@@ -440,10 +480,23 @@ type Config struct {
 		DisableOriginX509 bool `mapstructure:"disableoriginx509" yaml:"DisableOriginX509"`
 		DisableOrigins bool `mapstructure:"disableorigins" yaml:"DisableOrigins"`
 	} `mapstructure:"topology" yaml:"Topology"`
+	Tracing struct {
+		Endpoint string `mapstructure:"endpoint" yaml:"Endpoint"`
+		Exporter string `mapstructure:"exporter" yaml:"Exporter"`
+		File string `mapstructure:"file" yaml:"File"`
+		SamplePercent int `mapstructure:"samplepercent" yaml:"SamplePercent"`
+	} `mapstructure:"tracing" yaml:"Tracing"`
 	Transport struct {
 		BrokerEndpointCacheTTL time.Duration `mapstructure:"brokerendpointcachettl" yaml:"BrokerEndpointCacheTTL"`
+		BrokerPoolIdleTimeout time.Duration `mapstructure:"brokerpoolidletimeout" yaml:"BrokerPoolIdleTimeout"`
+		BrokerPoolPerService int `mapstructure:"brokerpoolperservice" yaml:"BrokerPoolPerService"`
+		BrokerPoolSize int `mapstructure:"brokerpoolsize" yaml:"BrokerPoolSize"`
+		BrokerTunnelLifetime time.Duration `mapstructure:"brokertunnellifetime" yaml:"BrokerTunnelLifetime"`
+		BrokerTunnelMaxStreams int `mapstructure:"brokertunnelmaxstreams" yaml:"BrokerTunnelMaxStreams"`
+		BrokerTunnelsPerService int `mapstructure:"brokertunnelsperservice" yaml:"BrokerTunnelsPerService"`
 		DialerKeepAlive time.Duration `mapstructure:"dialerkeepalive" yaml:"DialerKeepAlive"`
 		DialerTimeout time.Duration `mapstructure:"dialertimeout" yaml:"DialerTimeout"`
+		EnableBrokerTunnels bool `mapstructure:"enablebrokertunnels" yaml:"EnableBrokerTunnels"`
 		ExpectContinueTimeout time.Duration `mapstructure:"expectcontinuetimeout" yaml:"ExpectContinueTimeout"`
 		IdleConnTimeout time.Duration `mapstructure:"idleconntimeout" yaml:"IdleConnTimeout"`
 		MaxIdleConns int `mapstructure:"maxidleconns" yaml:"MaxIdleConns"`
@@ -527,6 +580,9 @@ type configWithType struct {
 	}
 	Client struct {
 		AssumeDirectorServerHeader struct { Type string; Value bool }
+		ChunkedUploadChunkSize struct { Type string; Value int }
+		ChunkedUploadStreams struct { Type string; Value int }
+		ChunkedUploadThreshold struct { Type string; Value int }
 		CredentialFile struct { Type string; Value string }
 		DirectorRetries struct { Type string; Value int }
 		DisableHttpProxy struct { Type string; Value bool }
@@ -535,18 +591,24 @@ type configWithType struct {
 		IsPlugin struct { Type string; Value bool }
 		MaximumDownloadSpeed struct { Type string; Value int }
 		MinimumDownloadSpeed struct { Type string; Value int }
+		ParallelDownloadEndpoints struct { Type string; Value int }
+		ParallelDownloadStreams struct { Type string; Value int }
+		ParallelDownloadThreshold struct { Type string; Value int }
 		PreferredCaches struct { Type string; Value []string }
+		ResumeDownloads struct { Type string; Value bool }
 		SlowTransferRampupTime struct { Type string; Value time.Duration }
 		SlowTransferWindow struct { Type string; Value time.Duration }
 		StoppedTransferTimeout struct { Type string; Value time.Duration }
 		WorkerCount struct { Type string; Value int }
 	}
 	ClientAgent struct {
+		BandwidthLimit struct { Type string; Value byte_rate.ByteRate }
 		DbLocation struct { Type string; Value string }
 		HistoryRetentionDays struct { Type string; Value int }
 		IdleTimeout struct { Type string; Value time.Duration }
 		MaxConcurrentJobs struct { Type string; Value int }
 		PidFile struct { Type string; Value string }
+		ProgressEventInterval struct { Type string; Value time.Duration }
 		ProgressUpdateInterval struct { Type string; Value time.Duration }
 		Socket struct { Type string; Value string }
 	}
@@ -583,6 +645,7 @@ type configWithType struct {
 		OriginCacheHealthTestInterval struct { Type string; Value time.Duration }
 		OriginResponseHostnames struct { Type string; Value []string }
 		RegistryQueryInterval struct { Type string; Value time.Duration }
+		SortPolicyFile struct { Type string; Value string }
 		StatConcurrencyLimit struct { Type string; Value int }
 		StatTimeout struct { Type string; Value time.Duration }
 		SupportContactEmail struct { Type string; Value string }
@@ -628,12 +691,25 @@ type configWithType struct {
 	IssuerKey struct { Type string; Value string }
 	IssuerKeysDirectory struct { Type string; Value string }
 	LocalCache struct {
+		BlockSize struct { Type string; Value string }
 		DataLocation struct { Type string; Value string }
+		DefaultPinLifetime struct { Type string; Value time.Duration }
+		EnablePeers struct { Type string; Value bool }
 		HighWaterMarkPercentage struct { Type string; Value int }
+		ListenAddress struct { Type string; Value string }
+		LotPolicy struct { Type string; Value string }
 		LowWaterMarkPercentage struct { Type string; Value int }
+		PeerAllowedHosts struct { Type string; Value []string }
+		PeerDigestInterval struct { Type string; Value time.Duration }
+		PeerDiscoveryAddress struct { Type string; Value string }
+		PeerTimeout struct { Type string; Value time.Duration }
+		Peers struct { Type string; Value []string }
+		PrefetchConcurrency struct { Type string; Value int }
 		RunLocation struct { Type string; Value string }
 		Size struct { Type string; Value string }
 		Socket struct { Type string; Value string }
+		TLSCertificate struct { Type string; Value string }
+		TLSKey struct { Type string; Value string }
 	}
 	Logging struct {
 		Cache struct {
@@ -676,6 +752,11 @@ type configWithType struct {
 	MinimumDownloadSpeed struct { Type string; Value int }
 	Monitoring struct {
 		AggregatePrefixes struct { Type string; Value []string }
+		AlertEmailFrom struct { Type string; Value string }
+		AlertEmailRelay struct { Type string; Value string }
+		AlertEmailTo struct { Type string; Value []string }
+		AlertWebhookUrl struct { Type string; Value string }
+		AlertmanagerUrl struct { Type string; Value string }
 		DataLocation struct { Type string; Value string }
 		DataRetention struct { Type string; Value time.Duration }
 		DataRetentionSize struct { Type string; Value string }
@@ -687,6 +768,8 @@ type configWithType struct {
 		PortHigher struct { Type string; Value int }
 		PortLower struct { Type string; Value int }
 		PromQLAuthorization struct { Type string; Value bool }
+		RuleEvaluationInterval struct { Type string; Value time.Duration }
+		RuleFiles struct { Type string; Value []string }
 		SampleLimit struct { Type string; Value int }
 		StorageCriticalThreshold struct { Type string; Value int }
 		StorageHealthCheckInterval struct { Type string; Value time.Duration }
@@ -707,6 +790,7 @@ type configWithType struct {
 		UserInfoEndpoint struct { Type string; Value string }
 	}
 	Origin struct {
+		ChunkedUploadStagingLifetime struct { Type string; Value time.Duration }
 		Concurrency struct { Type string; Value int }
 		ConcurrencyDegradedThreshold struct { Type string; Value int }
 		DbLocation struct { Type string; Value string }
@@ -718,6 +802,7 @@ type configWithType struct {
 		DiskUsageCalculationRateLimit struct { Type string; Value int }
 		EnableAtomicUploads struct { Type string; Value bool }
 		EnableBroker struct { Type string; Value bool }
+		EnableChunkedUploads struct { Type string; Value bool }
 		EnableCmsd struct { Type string; Value bool }
 		EnableDirListing struct { Type string; Value bool }
 		EnableDirectReads struct { Type string; Value bool }
@@ -726,9 +811,11 @@ type configWithType struct {
 		EnableIssuer struct { Type string; Value bool }
 		EnableListings struct { Type string; Value bool }
 		EnableMacaroons struct { Type string; Value bool }
+		EnableNativeBackend struct { Type string; Value bool }
 		EnableOIDC struct { Type string; Value bool }
 		EnablePublicReads struct { Type string; Value bool }
 		EnableReads struct { Type string; Value bool }
+		EnableThirdPartyCopy struct { Type string; Value bool }
 		EnableVoms struct { Type string; Value bool }
 		EnableWrite struct { Type string; Value bool }
 		EnableWrites struct { Type string; Value bool }
@@ -753,6 +840,7 @@ type configWithType struct {
 		MultiuserVarlinkSocketPath struct { Type string; Value string }
 		NamespacePrefix struct { Type string; Value string }
 		Port struct { Type string; Value int }
+		ReadBandwidthLimit struct { Type string; Value byte_rate.ByteRate }
 		RunLocation struct { Type string; Value string }
 		S3AccessKeyfile struct { Type string; Value string }
 		S3Bucket struct { Type string; Value string }
@@ -801,6 +889,7 @@ type configWithType struct {
 		UploadTempLocation struct { Type string; Value string }
 		Url struct { Type string; Value string }
 		UserMapfileRefreshInterval struct { Type string; Value time.Duration }
+		WriteBandwidthLimit struct { Type string; Value byte_rate.ByteRate }
 		XRootDPrefix struct { Type string; Value string }
 		XRootServiceUrl struct { Type string; Value string }
 	}
@@ -812,12 +901,16 @@ type configWithType struct {
 		AdminUsers struct { Type string; Value []string }
 		CustomRegistrationFields struct { Type string; Value any }
 		DbLocation struct { Type string; Value string }
+		EventRetention struct { Type string; Value time.Duration }
 		Institutions struct { Type string; Value any }
 		InstitutionsUrl struct { Type string; Value string }
 		InstitutionsUrlReloadMinutes struct { Type string; Value time.Duration }
 		RequireCacheApproval struct { Type string; Value bool }
 		RequireKeyChaining struct { Type string; Value bool }
 		RequireOriginApproval struct { Type string; Value bool }
+		WebhookMaxAttempts struct { Type string; Value int }
+		WebhookTimeout struct { Type string; Value time.Duration }
+		WebhookUrls struct { Type string; Value []string }
 	}
 	RuntimeDir struct { Type string; Value string }
 	Server struct {
@@ -895,10 +988,23 @@ type configWithType struct {
 		DisableOriginX509 struct { Type string; Value bool }
 		DisableOrigins struct { Type string; Value bool }
 	}
+	Tracing struct {
+		Endpoint struct { Type string; Value string }
+		Exporter struct { Type string; Value string }
+		File struct { Type string; Value string }
+		SamplePercent struct { Type string; Value int }
+	}
 	Transport struct {
 		BrokerEndpointCacheTTL struct { Type string; Value time.Duration }
+		BrokerPoolIdleTimeout struct { Type string; Value time.Duration }
+		BrokerPoolPerService struct { Type string; Value int }
+		BrokerPoolSize struct { Type string; Value int }
+		BrokerTunnelLifetime struct { Type string; Value time.Duration }
+		BrokerTunnelMaxStreams struct { Type string; Value int }
+		BrokerTunnelsPerService struct { Type string; Value int }
 		DialerKeepAlive struct { Type string; Value time.Duration }
 		DialerTimeout struct { Type string; Value time.Duration }
+		EnableBrokerTunnels struct { Type string; Value bool }
 		ExpectContinueTimeout struct { Type string; Value time.Duration }
 		IdleConnTimeout struct { Type string; Value time.Duration }
 		MaxIdleConns struct { Type string; Value int }
@@ -324,7 +324,7 @@ func TestMultiPubKeysRegisteredOnNamespace(t *testing.T) {
 	jwksStr := string(jwksBytes)
 
 	// Test functionality of a namespace registered with multi public keys [p2,p4]
-	err = setRegistrationPubKey(prefix, jwksStr) // set the registered public keys to [p2,p4]
+	err = setRegistrationPubKey(prefix, jwksStr, "test") // set the registered public keys to [p2,p4]
 	require.NoError(t, err)
 	ns, err := getRegistrationByPrefix(prefix)
 	require.NoError(t, err)
@@ -818,7 +818,7 @@ func deleteNamespaceHandler(ctx *gin.Context) {
 	}
 
 	// If we get to this point in the code, we've passed all the security checks and we're ready to delete
-	err = deleteRegistrationByPrefix(prefix)
+	err = deleteRegistrationByPrefix(prefix, parsed.Subject())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
 			Status: server_structs.RespFailed,
@@ -566,6 +566,8 @@ func getRegistrationsByFilter(filterNs server_structs.Registration, pType prefix
 	return registrationsOut, nil
 }
 
+// Add the registration, recording the registration.created event for it in
+// the same transaction
 func AddRegistration(ns *server_structs.Registration) error {
 	if ns.AdminMetadata.SiteName == "" {
 		return errors.New("Site Name is required")
@@ -597,7 +599,7 @@ func AddRegistration(ns *server_structs.Registration) error {
 
 	// Wrap all database operations in a transaction
 	// If any operation fails, all changes are reverted. No partial records left.
-	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
 		// Save the registration
 		if err := tx.Save(&ns).Error; err != nil {
 			return errors.Wrapf(err, "failed to save registration: %s", ns.AdminMetadata.SiteName)
@@ -690,8 +692,13 @@ func AddRegistration(ns *server_structs.Registration) error {
 			}
 		}
 
-		return nil
+		return recordRegistryEvent(tx, EventRegistrationCreated, ns, ns.AdminMetadata.UserID)
 	})
+	if err != nil {
+		return err
+	}
+	signalEvent()
+	return nil
 }
 
 func updateRegistration(ns *server_structs.Registration) error {
@@ -775,18 +782,44 @@ func updateRegistrationStatusById(id int, status server_structs.RegistrationStat
 		return errors.Wrap(err, "Error marshaling admin metadata")
 	}
 
-	return database.ServerDatabase.Model(ns).Where("id = ?", id).Update("admin_metadata", string(adminMetadataByte)).Error
+	err = database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(ns).Where("id = ?", id).Update("admin_metadata", string(adminMetadataByte)).Error; err != nil {
+			return err
+		}
+		return recordRegistryEvent(tx, statusEventType(status), ns, approverId)
+	})
+	if err != nil {
+		return err
+	}
+	signalEvent()
+	return nil
 }
 
-func setRegistrationPubKey(prefix string, pubkeyDbString string) error {
+// Replace the registered public key(s) of the prefix, recording the
+// registration.pubkey_updated event with the given actor in the same
+// transaction
+func setRegistrationPubKey(prefix string, pubkeyDbString string, actor string) error {
 	if prefix == "" {
 		return errors.New("invalid prefix. Prefix must not be empty")
 	}
 	if pubkeyDbString == "" {
 		return errors.New("invalid pubkeyDbString. pubkeyDbString must not be empty")
 	}
-	ns := server_structs.Registration{}
-	return database.ServerDatabase.Model(ns).Where("prefix = ? ", prefix).Update("pubkey", pubkeyDbString).Error
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.Where("prefix = ?", prefix).Last(&ns).Error; err != nil {
+			return errors.Wrapf(err, "failed to get the registration of prefix %s", prefix)
+		}
+		if err := tx.Model(&ns).Update("pubkey", pubkeyDbString).Error; err != nil {
+			return err
+		}
+		return recordRegistryEvent(tx, EventRegistrationPubkeyUpdated, &ns, actor)
+	})
+	if err != nil {
+		return err
+	}
+	signalEvent()
+	return nil
 }
 
 // Note: If this is a server registration, the foreign key constraint applied on the DB will
@@ -794,9 +827,15 @@ func setRegistrationPubKey(prefix string, pubkeyDbString string) error {
 // Additionally, if this server only has this single service registration (i.e., the server is only an
 // origin or only a cache, not both), then delete the corresponding entry in the “servers” table.
 // If services remain, update the server's is_origin and is_cache fields accordingly.
-func deleteRegistrationByID(id int) error {
+// The registration.deleted event is recorded with the given actor in the same transaction.
+func deleteRegistrationByID(id int, actor string) error {
 	// Wrap in a transaction to perform an atomic operation
-	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.First(&ns, id).Error; err != nil {
+			return errors.Wrapf(err, "failed to get the registration with id %d", id)
+		}
+
 		// Determine the server ID associated with this registration via the services table
 		// (Server ID is not empty if this is a server registration)
 		var svc server_structs.Service
@@ -856,13 +895,33 @@ func deleteRegistrationByID(id int) error {
 			}
 		}
 
-		return nil
+		return recordRegistryEvent(tx, EventRegistrationDeleted, &ns, actor)
 	})
+	if err != nil {
+		return err
+	}
+	signalEvent()
+	return nil
 }
 
-func deleteRegistrationByPrefix(prefix string) error {
-	// GORM by default uses transaction for write operations
-	return database.ServerDatabase.Where("prefix = ?", prefix).Delete(&server_structs.Registration{}).Error
+// Delete the registration of the prefix, recording the registration.deleted
+// event with the given actor in the same transaction
+func deleteRegistrationByPrefix(prefix string, actor string) error {
+	err := database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		ns := server_structs.Registration{}
+		if err := tx.Where("prefix = ?", prefix).Last(&ns).Error; err != nil {
+			return errors.Wrapf(err, "failed to get the registration of prefix %s", prefix)
+		}
+		if err := tx.Where("prefix = ?", prefix).Delete(&server_structs.Registration{}).Error; err != nil {
+			return err
+		}
+		return recordRegistryEvent(tx, EventRegistrationDeleted, &ns, actor)
+	})
+	if err != nil {
+		return err
+	}
+	signalEvent()
+	return nil
 }
 
 func deleteServerByID(id string) error {
@@ -71,6 +71,8 @@ func setupMockRegistryDB(t *testing.T) {
 		&database.User{},
 		&database.Group{},
 		&database.GroupMember{},
+		&RegistryEvent{},
+		&registryEventDelivery{},
 	)
 	require.NoError(t, err, "Failed to migrate DB tables")
 }
@@ -83,6 +85,8 @@ func resetMockRegistryDB(t *testing.T) {
 		"service":   &server_structs.Service{},
 		"contact":   &server_structs.Contact{},
 		"endpoint":  &server_structs.Endpoint{},
+		"delivery":  &registryEventDelivery{},
+		"event":     &RegistryEvent{},
 	}
 
 	for name, model := range tablesToClear {
@@ -0,0 +1,481 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+)
+
+type (
+	RegistryEventType string
+
+	// A change to a namespace registration.  Events are stored in the
+	// registry database, which serves as the outbox for their delivery to
+	// each RegistryNotifier, and are served by the events API.
+	RegistryEvent struct {
+		ID             int64             `json:"id" gorm:"primaryKey;autoIncrement"`
+		Type           RegistryEventType `json:"type"`
+		RegistrationID int               `json:"registration_id"`
+		Prefix         string            `json:"prefix"`
+		SiteName       string            `json:"site_name"`
+		Status         string            `json:"status"`
+		// The user that made the change or, for public key updates, the ID of
+		// the registered key the server proved possession of ("key:<kid>")
+		Actor     string    `json:"actor"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// The delivery state of an event to one notifier
+	registryEventDelivery struct {
+		ID            int64 `gorm:"primaryKey;autoIncrement"`
+		EventID       int64
+		Event         RegistryEvent `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+		Target        string
+		Attempts      int
+		NextAttemptAt time.Time
+		DeliveredAt   *time.Time
+		Failed        bool
+		LastError     string
+	}
+
+	// A destination for registry events.  Each notifier registered with
+	// RegisterNotifier receives every event recorded after it is registered;
+	// an event whose Notify fails is retried with exponential backoff.
+	RegistryNotifier interface {
+		// A unique name for the notifier, recorded with each delivery
+		Name() string
+		Notify(ctx context.Context, event *RegistryEvent) error
+	}
+
+	// Sends events as signed JSON to a webhook
+	webhookNotifier struct {
+		url    string
+		client *http.Client
+	}
+
+	listEventsRequest struct {
+		Since   int64         `form:"since"`
+		Timeout time.Duration `form:"timeout"`
+	}
+)
+
+const (
+	EventRegistrationCreated       RegistryEventType = "registration.created"
+	EventRegistrationApproved      RegistryEventType = "registration.approved"
+	EventRegistrationDenied        RegistryEventType = "registration.denied"
+	EventRegistrationDeleted       RegistryEventType = "registration.deleted"
+	EventRegistrationPubkeyUpdated RegistryEventType = "registration.pubkey_updated"
+
+	// The most events returned by one request to the events API
+	maxEventsPerResponse = 100
+	// The longest a long-poll request waits for new events
+	maxEventsPollTimeout = time.Minute
+)
+
+var (
+	notifiersMutex sync.RWMutex
+	notifiers      = map[string]RegistryNotifier{}
+
+	// Closed, and replaced, each time an event is recorded to wake the
+	// dispatcher and any clients waiting for events
+	eventSignalMutex sync.Mutex
+	eventSignal      = make(chan struct{})
+
+	// How often the dispatcher checks for deliveries due for a retry, and
+	// the longest it waits between retries of one delivery
+	eventDispatchInterval = 5 * time.Second
+	eventMaxRetryDelay    = time.Hour
+	eventsKeepaliveDelay  = 15 * time.Second
+)
+
+func (RegistryEvent) TableName() string {
+	return "registry_events"
+}
+
+func (registryEventDelivery) TableName() string {
+	return "registry_event_deliveries"
+}
+
+// Return the event type recorded when a registration is given the status
+func statusEventType(status server_structs.RegistrationStatus) RegistryEventType {
+	if status == server_structs.RegApproved {
+		return EventRegistrationApproved
+	}
+	return EventRegistrationDenied
+}
+
+// Add a notifier to receive registry events, replacing any with the same name
+func RegisterNotifier(n RegistryNotifier) {
+	notifiersMutex.Lock()
+	defer notifiersMutex.Unlock()
+	notifiers[n.Name()] = n
+}
+
+func getNotifier(name string) RegistryNotifier {
+	notifiersMutex.RLock()
+	defer notifiersMutex.RUnlock()
+	return notifiers[name]
+}
+
+func resetNotifiers() {
+	notifiersMutex.Lock()
+	defer notifiersMutex.Unlock()
+	notifiers = map[string]RegistryNotifier{}
+}
+
+func currentEventSignal() <-chan struct{} {
+	eventSignalMutex.Lock()
+	defer eventSignalMutex.Unlock()
+	return eventSignal
+}
+
+func signalEvent() {
+	eventSignalMutex.Lock()
+	defer eventSignalMutex.Unlock()
+	close(eventSignal)
+	eventSignal = make(chan struct{})
+}
+
+// Record an event for the registration, and queue it for delivery to every
+// registered notifier, in the transaction tx making the change the event
+// describes, so that the change and its event are committed together.  Call
+// signalEvent once tx commits.
+func recordRegistryEvent(tx *gorm.DB, eventType RegistryEventType, ns *server_structs.Registration, actor string) error {
+	event := RegistryEvent{
+		Type:           eventType,
+		RegistrationID: ns.ID,
+		Prefix:         ns.Prefix,
+		SiteName:       ns.AdminMetadata.SiteName,
+		Status:         ns.AdminMetadata.Status.String(),
+		Actor:          actor,
+		CreatedAt:      time.Now().UTC(),
+	}
+	notifiersMutex.RLock()
+	targets := make([]string, 0, len(notifiers))
+	for name := range notifiers {
+		targets = append(targets, name)
+	}
+	notifiersMutex.RUnlock()
+
+	if err := tx.Create(&event).Error; err != nil {
+		return errors.Wrapf(err, "failed to record the %s event for prefix %s", eventType, ns.Prefix)
+	}
+	for _, target := range targets {
+		delivery := registryEventDelivery{EventID: event.ID, Target: target, NextAttemptAt: event.CreatedAt}
+		if err := tx.Omit("Event").Create(&delivery).Error; err != nil {
+			return errors.Wrapf(err, "failed to queue the %s event for prefix %s", eventType, ns.Prefix)
+		}
+	}
+	log.Debugf("Recorded the %s event for prefix %s", eventType, ns.Prefix)
+	return nil
+}
+
+// Return up to limit events recorded after the event with ID since
+func listEventsSince(since int64, limit int) ([]RegistryEvent, error) {
+	events := []RegistryEvent{}
+	err := database.ServerDatabase.Where("id > ?", since).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func newWebhookNotifier(webhookUrl string) *webhookNotifier {
+	return &webhookNotifier{url: webhookUrl, client: config.GetClient()}
+}
+
+func (n *webhookNotifier) Name() string {
+	return n.url
+}
+
+// POST the event to the webhook, signed with the registry's issuer key
+func (n *webhookNotifier) Notify(ctx context.Context, event *RegistryEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	signature, err := signEventPayload(body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, param.Registry_WebhookTimeout.GetDuration())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pelican-registry/"+config.GetVersion())
+	req.Header.Set("X-Pelican-Event", string(event.Type))
+	req.Header.Set("X-Pelican-Event-Id", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Pelican-Signature", signature)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Return a compact JWS, with a detached payload, signing the payload with
+// the registry's issuer key
+func signEventPayload(payload []byte) (string, error) {
+	key, err := config.GetIssuerPrivateJWK()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load the key to sign the event")
+	}
+	signed, err := jws.Sign(nil, jws.WithKey(key.Algorithm(), key), jws.WithDetachedPayload(payload))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign the event")
+	}
+	return string(signed), nil
+}
+
+// Return how long to wait before the next attempt of a delivery that has
+// failed the given number of times
+func eventRetryDelay(attempts int) time.Duration {
+	delay := eventDispatchInterval
+	for i := 1; i < attempts && delay < eventMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, eventMaxRetryDelay)
+}
+
+// Attempt every delivery that is due, returning the number attempted
+func dispatchEvents(ctx context.Context) (int, error) {
+	var due []registryEventDelivery
+	err := database.ServerDatabase.Preload("Event").
+		Where("delivered_at IS NULL AND failed = ? AND next_attempt_at <= ?", false, time.Now().UTC()).
+		Order("event_id ASC").Limit(maxEventsPerResponse).Find(&due).Error
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to query the pending event deliveries")
+	}
+	maxAttempts := param.Registry_WebhookMaxAttempts.GetInt()
+	for idx := range due {
+		delivery := &due[idx]
+		delivery.Attempts++
+		n := getNotifier(delivery.Target)
+		if n == nil {
+			err = errors.New("the notifier is no longer configured")
+		} else {
+			err = n.Notify(ctx, &delivery.Event)
+		}
+		if ctx.Err() != nil {
+			return idx, nil
+		}
+		updates := map[string]interface{}{"attempts": delivery.Attempts}
+		if err == nil {
+			updates["delivered_at"] = time.Now().UTC()
+			updates["last_error"] = ""
+		} else {
+			updates["last_error"] = err.Error()
+			if n == nil || delivery.Attempts >= maxAttempts {
+				updates["failed"] = true
+				log.Warningf("Giving up on delivering the %s event for prefix %s to %s after %d attempt(s): %v",
+					delivery.Event.Type, delivery.Event.Prefix, delivery.Target, delivery.Attempts, err)
+			} else {
+				updates["next_attempt_at"] = time.Now().UTC().Add(eventRetryDelay(delivery.Attempts))
+				log.Debugf("Failed to deliver the %s event for prefix %s to %s; will retry: %v",
+					delivery.Event.Type, delivery.Event.Prefix, delivery.Target, err)
+			}
+		}
+		if err := database.ServerDatabase.Model(&registryEventDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+			return idx + 1, errors.Wrap(err, "failed to update the event delivery")
+		}
+	}
+	return len(due), nil
+}
+
+// Remove the events, and their deliveries, older than Registry.EventRetention
+func pruneEvents() error {
+	cutoff := time.Now().UTC().Add(-param.Registry_EventRetention.GetDuration())
+	return database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&RegistryEvent{}).Select("id").Where("created_at < ?", cutoff)
+		if err := tx.Where("event_id IN (?)", stale).Delete(&registryEventDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("created_at < ?", cutoff).Delete(&RegistryEvent{}).Error
+	})
+}
+
+// Register a notifier for each of the Registry.WebhookUrls and launch the
+// goroutine delivering events to the notifiers
+func LaunchNotifications(ctx context.Context, egrp *errgroup.Group) {
+	for _, webhookUrl := range param.Registry_WebhookUrls.GetStringSlice() {
+		RegisterNotifier(newWebhookNotifier(webhookUrl))
+	}
+
+	egrp.Go(func() error {
+		ticker := time.NewTicker(eventDispatchInterval)
+		defer ticker.Stop()
+		lastPrune := time.Time{}
+		for {
+			signal := currentEventSignal()
+			if time.Since(lastPrune) > time.Hour {
+				if err := pruneEvents(); err != nil {
+					log.Warningln("Failed to remove expired registry events:", err)
+				}
+				lastPrune = time.Now()
+			}
+			// Keep going while full batches are due, so a backlog drains promptly
+			for {
+				count, err := dispatchEvents(ctx)
+				if err != nil {
+					log.Warningln("Failed to dispatch registry events:", err)
+				}
+				if err != nil || count < maxEventsPerResponse {
+					break
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			case <-signal:
+			}
+		}
+	})
+}
+
+func writeServerSentEvent(ctx *gin.Context, event *RegistryEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// Stream events as they are recorded, starting after the event with ID since
+func streamEvents(ctx *gin.Context, since int64) {
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	keepalive := time.NewTicker(eventsKeepaliveDelay)
+	defer keepalive.Stop()
+	for {
+		signal := currentEventSignal()
+		events, err := listEventsSince(since, maxEventsPerResponse)
+		if err != nil {
+			log.Errorln("Failed to list registry events:", err)
+			return
+		}
+		for idx := range events {
+			if err := writeServerSentEvent(ctx, &events[idx]); err != nil {
+				return
+			}
+			since = events[idx].ID
+		}
+		ctx.Writer.Flush()
+		if len(events) == maxEventsPerResponse {
+			continue
+		}
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-signal:
+		case <-keepalive.C:
+			if _, err := ctx.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// List the registration events recorded after the event with ID `since`.
+// Clients accepting `text/event-stream` receive a stream of server-sent
+// events, which resumes from the Last-Event-ID header if it is set.
+// Otherwise, the events are returned as a JSON list; if there are none,
+// the request waits up to `timeout` for one to be recorded.
+//
+// GET /events
+func listEvents(ctx *gin.Context) {
+	req := listEventsRequest{}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+			Status: server_structs.RespFailed,
+			Msg:    fmt.Sprint("Invalid query parameters: ", err)})
+		return
+	}
+	if lastId := ctx.GetHeader("Last-Event-ID"); lastId != "" {
+		since, err := strconv.ParseInt(lastId, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, server_structs.SimpleApiResp{
+				Status: server_structs.RespFailed,
+				Msg:    "Invalid Last-Event-ID header; must be an event ID"})
+			return
+		}
+		req.Since = since
+	}
+
+	if strings.Contains(ctx.GetHeader("Accept"), "text/event-stream") {
+		streamEvents(ctx, req.Since)
+		return
+	}
+
+	timeout := time.NewTimer(min(max(req.Timeout, 0), maxEventsPollTimeout))
+	defer timeout.Stop()
+	for {
+		signal := currentEventSignal()
+		events, err := listEventsSince(req.Since, maxEventsPerResponse)
+		if err != nil {
+			log.Errorln("Failed to list registry events:", err)
+			ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
+				Status: server_structs.RespFailed,
+				Msg:    "Failed to list registry events"})
+			return
+		}
+		if len(events) > 0 {
+			ctx.JSON(http.StatusOK, events)
+			return
+		}
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-timeout.C:
+			ctx.JSON(http.StatusOK, events)
+			return
+		case <-signal:
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package registry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/database"
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Name() string {
+	return "failing"
+}
+
+func (failingNotifier) Notify(context.Context, *RegistryEvent) error {
+	return errors.New("always fails")
+}
+
+// Record an event outside of any registration change
+func recordTestEvent(t *testing.T, eventType RegistryEventType, ns *server_structs.Registration, actor string) {
+	assert.NoError(t, database.ServerDatabase.Transaction(func(tx *gorm.DB) error {
+		return recordRegistryEvent(tx, eventType, ns, actor)
+	}))
+	signalEvent()
+}
+
+func TestRegistryEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	t.Cleanup(resetNotifiers)
+
+	keysDir := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.MkdirAll(keysDir, 0750))
+	require.NoError(t, param.IssuerKeysDirectory.Set(keysDir))
+	config.ResetIssuerPrivateKeys()
+	t.Cleanup(config.ResetIssuerPrivateKeys)
+	_, err := config.GeneratePEM(keysDir)
+	require.NoError(t, err)
+	jwks, err := config.GetIssuerPublicJWKS()
+	require.NoError(t, err)
+
+	setupMockRegistryDB(t)
+	t.Cleanup(func() { teardownMockRegistryDB(t) })
+
+	require.NoError(t, param.Registry_WebhookTimeout.Set(10*time.Second))
+	require.NoError(t, param.Registry_WebhookMaxAttempts.Set(10))
+	oldInterval := eventDispatchInterval
+	eventDispatchInterval = 10 * time.Millisecond
+	t.Cleanup(func() { eventDispatchInterval = oldInterval })
+
+	// A webhook failing its first request
+	var mu sync.Mutex
+	var received []RegistryEvent
+	requests := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		_, err = jws.Verify([]byte(r.Header.Get("X-Pelican-Signature")), jws.WithKeySet(jwks), jws.WithDetachedPayload(body))
+		assert.NoError(t, err, "the event signature should verify with the issuer keys")
+		var event RegistryEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, string(event.Type), r.Header.Get("X-Pelican-Event"))
+		received = append(received, event)
+	}))
+	t.Cleanup(webhook.Close)
+	RegisterNotifier(newWebhookNotifier(webhook.URL))
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set("User", "admin") })
+	router.PATCH("/namespaces/:id/approve", func(ctx *gin.Context) {
+		updateNamespaceStatus(ctx, server_structs.RegApproved)
+	})
+	router.DELETE("/namespaces/:id", deleteNamespace)
+	router.GET("/events", listEvents)
+	do := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	require.NoError(t, insertMockDBData([]server_structs.Registration{
+		mockNamespace("/foo", "", "", server_structs.AdminMetadata{UserID: "mockUser", SiteName: "Foo Site"}),
+	}))
+	id, err := getLastNamespaceId()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, fmt.Sprintf("/namespaces/%d/approve", id)).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, fmt.Sprintf("/namespaces/%d", id)).Code)
+
+	t.Run("webhook-delivery-retries", func(t *testing.T) {
+		count, err := dispatchEvents(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		time.Sleep(2 * eventDispatchInterval)
+		count, err = dispatchEvents(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, count, "only the failed delivery should be retried")
+		count, err = dispatchEvents(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, received, 2)
+		types := []RegistryEventType{received[0].Type, received[1].Type}
+		assert.ElementsMatch(t, []RegistryEventType{EventRegistrationApproved, EventRegistrationDeleted}, types)
+		for _, event := range received {
+			assert.Equal(t, "/foo", event.Prefix)
+			assert.Equal(t, "Foo Site", event.SiteName)
+			assert.Equal(t, "admin", event.Actor)
+			assert.Equal(t, id, event.RegistrationID)
+		}
+	})
+
+	t.Run("undeliverable-events-fail", func(t *testing.T) {
+		require.NoError(t, param.Registry_WebhookMaxAttempts.Set(2))
+		RegisterNotifier(failingNotifier{})
+		recordTestEvent(t, EventRegistrationCreated, &server_structs.Registration{ID: 42, Prefix: "/bar"}, "someone")
+		for attempt := 0; attempt < 2; attempt++ {
+			time.Sleep(2 * eventDispatchInterval)
+			_, err := dispatchEvents(context.Background())
+			require.NoError(t, err)
+		}
+		var delivery registryEventDelivery
+		require.NoError(t, database.ServerDatabase.Where("target = ?", "failing").First(&delivery).Error)
+		assert.True(t, delivery.Failed)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, "always fails", delivery.LastError)
+	})
+
+	t.Run("long-poll", func(t *testing.T) {
+		w := do(http.MethodGet, "/events")
+		require.Equal(t, http.StatusOK, w.Code)
+		var events []RegistryEvent
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+		require.Len(t, events, 3)
+		last := events[2].ID
+
+		// With no new events, the request waits for one
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- do(http.MethodGet, fmt.Sprintf("/events?since=%d&timeout=10s", last)) }()
+		time.Sleep(50 * time.Millisecond)
+		recordTestEvent(t, EventRegistrationPubkeyUpdated, &server_structs.Registration{ID: 43, Prefix: "/baz"}, "server")
+		select {
+		case w := <-done:
+			require.Equal(t, http.StatusOK, w.Code)
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+			require.Len(t, events, 1)
+			assert.Equal(t, EventRegistrationPubkeyUpdated, events[0].Type)
+		case <-time.After(5 * time.Second):
+			t.Fatal("The long-poll request did not return the new event")
+		}
+
+		w = do(http.MethodGet, fmt.Sprintf("/events?since=%d&timeout=10ms", events[0].ID))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, "[]", w.Body.String())
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/events?since=abc").Code)
+	})
+
+	t.Run("server-sent-events", func(t *testing.T) {
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+		events, err := listEventsSince(0, maxEventsPerResponse)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", fmt.Sprint(events[len(events)-2].ID))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		go recordTestEvent(t, EventRegistrationDenied, &server_structs.Registration{ID: 44, Prefix: "/qux"}, "admin")
+		scanner := bufio.NewScanner(resp.Body)
+		var types []string
+		for len(types) < 2 && scanner.Scan() {
+			if eventType, found := strings.CutPrefix(scanner.Text(), "event: "); found {
+				types = append(types, eventType)
+			}
+		}
+		assert.Equal(t, []string{string(EventRegistrationPubkeyUpdated), string(EventRegistrationDenied)}, types)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		require.NoError(t, param.Registry_EventRetention.Set(time.Nanosecond))
+		require.NoError(t, pruneEvents())
+		events, err := listEventsSince(0, maxEventsPerResponse)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		var deliveries int64
+		require.NoError(t, database.ServerDatabase.Model(&registryEventDelivery{}).Count(&deliveries).Error)
+		assert.Zero(t, deliveries)
+	})
+}
+
+func TestRegistryEventTransaction(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	t.Cleanup(resetNotifiers)
+	setupMockRegistryDB(t)
+	t.Cleanup(func() { teardownMockRegistryDB(t) })
+
+	require.NoError(t, insertMockDBData([]server_structs.Registration{
+		mockNamespace("/foo", "", "", server_structs.AdminMetadata{UserID: "mockUser", SiteName: "Foo Site", Status: server_structs.RegPending}),
+	}))
+	id, err := getLastNamespaceId()
+	require.NoError(t, err)
+
+	// A change whose event can't be queued for delivery is rolled back
+	RegisterNotifier(failingNotifier{})
+	require.NoError(t, database.ServerDatabase.Migrator().DropTable(&registryEventDelivery{}))
+	assert.Error(t, updateRegistrationStatusById(id, server_structs.RegApproved, "admin"))
+	assert.Error(t, deleteRegistrationByID(id, "admin"))
+	ns, err := getRegistrationById(id)
+	require.NoError(t, err)
+	assert.Equal(t, server_structs.RegPending, ns.AdminMetadata.Status)
+	events, err := listEventsSince(0, maxEventsPerResponse)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Without notifiers, the change and its event are committed together
+	resetNotifiers()
+	require.NoError(t, updateRegistrationStatusById(id, server_structs.RegApproved, "admin"))
+	events, err = listEventsSince(0, maxEventsPerResponse)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventRegistrationApproved, events[0].Type)
+	assert.Equal(t, id, events[0].RegistrationID)
+	assert.Equal(t, "admin", events[0].Actor)
+}
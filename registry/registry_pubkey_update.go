@@ -216,7 +216,8 @@ func updateNsKeySignChallengeCommit(data *RegisteredPrefixUpdate) (map[string]in
 				log.Infof("The public key of prefix %s hasn't changed -- nothing to update!", prefix)
 				return returnMsg, nil
 			} else {
-				err = setRegistrationPubKey(prefix, string(data.AllPubkeys))
+				// The caller is only authenticated by proving possession of this key
+				err = setRegistrationPubKey(prefix, string(data.AllPubkeys), "key:"+data.MatchedKeyId)
 				log.Debugf("New public keys %s just replaced the old ones: %s", string(data.AllPubkeys), existingNs.Pubkey)
 				if err != nil {
 					log.Errorf("Failed to update the public key of namespace %s: %v", prefix, err)
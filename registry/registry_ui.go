@@ -895,12 +895,13 @@ func deleteNamespace(ctx *gin.Context) {
 			Msg:    "Namespace not found"})
 		return
 	}
-	err = deleteRegistrationByID(id)
+	err = deleteRegistrationByID(id, ctx.GetString("User"))
 	if err != nil {
 		log.Errorf("Error deleting the namespace: %v", err)
 		ctx.JSON(http.StatusInternalServerError, server_structs.SimpleApiResp{
 			Status: server_structs.RespFailed,
 			Msg:    "Error deleting the namespace"})
+		return
 	}
 	ctx.JSON(http.StatusOK,
 		server_structs.SimpleApiResp{
@@ -1070,5 +1071,8 @@ func RegisterRegistryWebAPI(router *gin.RouterGroup) error {
 	{
 		registryWebAPI.GET("/institutions", web_ui.AuthHandler, listInstitutions)
 	}
+	{
+		registryWebAPI.GET("/events", web_ui.AuthHandler, web_ui.AdminAuthHandler, listEvents)
+	}
 	return nil
 }
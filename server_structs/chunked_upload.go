@@ -0,0 +1,68 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package server_structs
+
+// The chunked upload protocol between the client and a natively-served origin.
+// All requests go to the object's URL and are selected by query parameters:
+//
+//   - POST ?pelican.upload=create with a ChunkedUploadRequest body opens (or
+//     reopens) the staging area for an upload and returns a ChunkedUploadSession
+//     listing the chunks already received.
+//   - PUT ?pelican.upload=<id>&pelican.chunk=<n> stores chunk n.
+//   - POST ?pelican.upload=<id>&pelican.commit=true assembles the chunks,
+//     verifies them against the request's Digest header, and moves the result
+//     to the object's path.  The Digest header must include a checksum the
+//     origin supports (md5, sha, crc32 or crc32c).
+const (
+	ChunkedUploadQuery       = "pelican.upload"
+	ChunkedUploadChunkQuery  = "pelican.chunk"
+	ChunkedUploadCommitQuery = "pelican.commit"
+	ChunkedUploadCreate      = "create"
+
+	// The largest number of chunks an origin accepts for one upload
+	ChunkedUploadMaxChunks = 100000
+)
+
+type (
+	// Body of the request opening a chunked upload
+	ChunkedUploadRequest struct {
+		// Client-chosen identifier (16-64 lowercase hex digits); reusing it for
+		// the same file lets a later attempt resume the upload
+		ID        string `json:"id"`
+		Size      int64  `json:"size"`
+		ChunkSize int64  `json:"chunkSize"`
+	}
+
+	// State of a chunked upload as reported by the origin
+	ChunkedUploadSession struct {
+		ID        string `json:"id"`
+		Size      int64  `json:"size"`
+		ChunkSize int64  `json:"chunkSize"`
+		Chunks    int    `json:"chunks"`
+		Received  []int  `json:"received"` // Indices of the chunks already stored
+	}
+)
+
+// Return the number of chunks needed for an upload of the given size
+func ChunkedUploadChunkCount(size, chunkSize int64) int {
+	if size <= 0 || chunkSize <= 0 {
+		return 0
+	}
+	return int((size + chunkSize - 1) / chunkSize)
+}
@@ -264,6 +264,8 @@ type (
 		IOLoadWeight       float64 `json:"ioLoadWeight"`
 		StatusWeight       float64 `json:"statusWeight"`
 		AvailabilityWeight float64 `json:"availabilityWeight"`
+		SiteAffinityWeight float64 `json:"siteAffinityWeight,omitempty"` // Only set by the policy sort
+		PreferenceRank     int     `json:"preferenceRank,omitempty"`     // 1-based position in a policy preference list; 0 if unlisted
 	}
 
 	ServerRedirectInfo struct {
@@ -435,6 +437,7 @@ const (
 	DistanceAndLoadType SortType = "distanceAndLoad"
 	RandomType          SortType = "random"
 	AdaptiveType        SortType = "adaptive"
+	PolicyType          SortType = "policy"
 
 	AdAfterFalse   AdAfter = 0 // The ad was *not* generated after the compared one
 	AdAfterTrue    AdAfter = 1 // The ad was generated after the compared one
@@ -34,6 +34,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
+	"github.com/pelicanplatform/pelican/byte_rate"
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
@@ -67,6 +68,11 @@ type (
 		// When set, these override the global Issuer.AuthorizationTemplates
 		// for this export's namespace.
 		AuthorizationTemplates []interface{} `json:"authorizationTemplates,omitempty" mapstructure:"authorizationtemplates" yaml:"AuthorizationTemplates"`
+
+		// Optional bandwidth caps for the export, shared fairly between its
+		// users. Zero means the export is bound only by the origin-wide caps.
+		ReadBandwidthLimit  byte_rate.ByteRate `json:"readBandwidthLimit,omitempty"`
+		WriteBandwidthLimit byte_rate.ByteRate `json:"writeBandwidthLimit,omitempty"`
 	}
 )
 
@@ -179,6 +185,7 @@ func OriginExportsDecoderHook() mapstructure.DecodeHookFunc {
 	return mapstructure.ComposeDecodeHookFunc(
 		StringListToCapsHookFunc(),
 		IssuerUrlsHookFunc(),
+		mapstructure.TextUnmarshallerHookFunc(),
 	)
 }
 
@@ -23,6 +23,9 @@ import (
 	"net/http"
 
 	"golang.org/x/net/webdav"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_structs"
 )
 
 // OriginBackend abstracts a storage backend for the origin server.
@@ -58,6 +61,18 @@ type OriginChecksummer interface {
 	GetDigests(relativePath string, wantDigest string) ([]string, error)
 }
 
+// OriginRequestPreparer is optionally implemented by backends that proxy
+// to an upstream service.  PrepareRequest is called for GET and HEAD
+// requests before they reach the WebDAV handler, which would otherwise
+// report every open failure as a 404.  A returned error is sent to the
+// client with the status chosen by the origin's error mapping (honouring
+// HTTPStatusCoder).  The returned context replaces the request context so
+// that metadata fetched here can be reused by the filesystem calls that
+// follow.
+type OriginRequestPreparer interface {
+	PrepareRequest(ctx context.Context, relativePath string) (context.Context, error)
+}
+
 // HTTPStatusCoder is optionally implemented by errors returned from
 // CheckAvailability to control the HTTP status code sent to clients.
 type HTTPStatusCoder interface {
@@ -103,3 +118,18 @@ func StashPelicanHeaders(r *http.Request) *http.Request {
 	})
 	return r.WithContext(ctx)
 }
+
+// UsesNativeOriginBackend reports whether exports of the given storage type
+// are served by the Go origin_serve handlers rather than by XRootD.  POSIXv2
+// and SSH are always native; S3 and HTTPS are native only when
+// Origin.EnableNativeBackend is set.
+func UsesNativeOriginBackend(storageType server_structs.OriginStorageType) bool {
+	switch storageType {
+	case server_structs.OriginStoragePosixv2, server_structs.OriginStorageSSH:
+		return true
+	case server_structs.OriginStorageS3, server_structs.OriginStorageHTTPS:
+		return param.Origin_EnableNativeBackend.GetBool()
+	default:
+		return false
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pelicanplatform/pelican/byte_rate"
 	"github.com/pelicanplatform/pelican/config"
 	"github.com/pelicanplatform/pelican/param"
 	"github.com/pelicanplatform/pelican/server_structs"
@@ -220,6 +221,10 @@ func TestGetExports(t *testing.T) {
 		assert.True(t, exports[0].Capabilities.Listings)
 		assert.True(t, exports[0].Capabilities.Reads)
 		assert.True(t, exports[0].Capabilities.DirectReads)
+		expectedRate, err := byte_rate.ParseRate("10MB/s")
+		require.NoError(t, err)
+		assert.Equal(t, expectedRate, exports[0].ReadBandwidthLimit)
+		assert.Zero(t, exports[0].WriteBandwidthLimit)
 
 		// Check second export (storage prefix is a real temp dir)
 		info, err = os.Stat(exports[1].StoragePrefix)
@@ -232,6 +237,8 @@ func TestGetExports(t *testing.T) {
 		assert.False(t, exports[1].Capabilities.Listings)
 		assert.False(t, exports[1].Capabilities.Reads)
 		assert.False(t, exports[1].Capabilities.DirectReads)
+		assert.Zero(t, exports[1].ReadBandwidthLimit)
+		assert.Equal(t, byte_rate.ByteRate(1048576), exports[1].WriteBandwidthLimit)
 	})
 
 	t.Run("testTrailingSlashRemovalPosix", func(t *testing.T) {
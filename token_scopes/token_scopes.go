@@ -44,6 +44,7 @@ const (
 	Broker_Retrieve TokenScope = "broker.retrieve"
 	Broker_Callback TokenScope = "broker.callback"
 	Localcache_Purge TokenScope = "localcache.purge"
+	Localcache_Peer TokenScope = "localcache.peer"
 	Collection_Create TokenScope = "collection.create"
 	Collection_Read TokenScope = "collection.read"
 	Collection_Modify TokenScope = "collection.modify"
@@ -0,0 +1,234 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+// Package tracing records OpenTelemetry spans for transfers and propagates
+// the W3C trace context (the `traceparent` header) between the client,
+// director, caches and origins.
+//
+// Propagation is always on: a request that arrives with a trace context
+// passes it on to the requests it makes, even if this process records
+// no spans of its own.  Which spans are recorded, and where they are sent,
+// is set by the Tracing.* parameters and applied by Setup.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/version"
+)
+
+const (
+	tracerName = "github.com/pelicanplatform/pelican"
+
+	// The attribute holding the request ID, as sent in the X-Pelican-JobId header
+	RequestIDKey = attribute.Key("pelican.request_id")
+)
+
+var (
+	// The provider installed by Setup and the file its spans are written to, if any
+	providerLock sync.Mutex
+	provider     *sdktrace.TracerProvider
+	providerFile *os.File
+)
+
+func init() {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Configure span recording from the Tracing.* parameters, identifying this
+// process as serviceName.  Calling Setup again replaces the previous
+// configuration, flushing any spans it had buffered.
+func Setup(ctx context.Context, serviceName string) error {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	if err := shutdownLocked(ctx); err != nil {
+		log.Warningln("Failed to flush trace spans:", err)
+	}
+
+	exporter := strings.ToLower(param.Tracing_Exporter.GetString())
+	if exporter == "" {
+		return nil
+	}
+	samplePercent := param.Tracing_SamplePercent.GetInt()
+	if samplePercent < 0 || samplePercent > 100 {
+		return errors.Errorf("%s is %d; must be between 0 and 100", param.Tracing_SamplePercent.GetName(), samplePercent)
+	}
+
+	var processor sdktrace.SpanProcessor
+	switch exporter {
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if endpoint := param.Tracing_Endpoint.GetString(); endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the OTLP trace exporter")
+		}
+		processor = sdktrace.NewBatchSpanProcessor(exp)
+	case "file":
+		filename := param.Tracing_File.GetString()
+		if filename == "" {
+			return errors.Errorf("%s is %q but %s is not set", param.Tracing_Exporter.GetName(), exporter, param.Tracing_File.GetName())
+		}
+		file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return errors.Wrap(err, "failed to open the trace file")
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return errors.Wrap(err, "failed to create the file trace exporter")
+		}
+		providerFile = file
+		// Write each span as it ends so the file is complete even if the
+		// process exits without calling Shutdown
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	default:
+		return errors.Errorf("unknown %s %q; must be \"otlp\" or \"file\"", param.Tracing_Exporter.GetName(), exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version.GetVersion()),
+	))
+	if err != nil {
+		return errors.Wrap(err, "failed to describe the trace resource")
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(samplePercent)/100))),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	log.Debugf("Recording %d%% of new traces with the %s exporter", samplePercent, exporter)
+	return nil
+}
+
+// Flush any buffered spans and stop recording new ones
+func Shutdown(ctx context.Context) error {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	return shutdownLocked(ctx)
+}
+
+func shutdownLocked(ctx context.Context) (err error) {
+	if provider == nil {
+		return nil
+	}
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	err = provider.Shutdown(ctx)
+	provider = nil
+	if providerFile != nil {
+		if closeErr := providerFile.Close(); err == nil {
+			err = closeErr
+		}
+		providerFile = nil
+	}
+	return
+}
+
+// Start a span for work done within this process
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Start a span for a request this process sends to another service.  The
+// caller should Inject the returned context into the request's headers.
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// End the span, recording err as its status if it is non-nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Return the attribute for a request ID, as sent in the X-Pelican-JobId header
+func RequestID(id string) attribute.KeyValue {
+	return RequestIDKey.String(id)
+}
+
+// Add the trace context in ctx to the headers of an outgoing request
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Return ctx with the trace context, if any, sent in the request headers
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Start a server span for the request, continuing the trace sent by the
+// caller, and replace the request's context with one holding the span.
+// The span is tagged with the request ID the caller sent, if any.
+func StartRequest(ginCtx *gin.Context, name string, attrs ...attribute.KeyValue) trace.Span {
+	req := ginCtx.Request
+	attrs = append(attrs, semconv.HTTPRequestMethodKey.String(req.Method), semconv.URLPath(req.URL.Path))
+	if jobId := req.Header.Get("X-Pelican-JobId"); jobId != "" {
+		attrs = append(attrs, RequestID(jobId))
+	}
+	ctx, span := otel.Tracer(tracerName).Start(Extract(req.Context(), req.Header), name,
+		trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindServer))
+	ginCtx.Request = req.WithContext(ctx)
+	return span
+}
+
+// End a span started by StartRequest, recording the response status
+func EndRequest(ginCtx *gin.Context, span trace.Span) {
+	status := ginCtx.Writer.Status()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
+
+// Return a middleware recording a server span, named after the component
+// and the request method, for each request
+func Middleware(component string) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		span := StartRequest(ginCtx, component+" "+ginCtx.Request.Method)
+		defer EndRequest(ginCtx, span)
+		ginCtx.Next()
+	}
+}
@@ -0,0 +1,155 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+)
+
+const testTraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+// Serve a request through the middleware, returning the trace context the
+// handler would send to the next hop
+func serveTraced(t *testing.T, header http.Header) string {
+	engine := gin.New()
+	engine.Use(Middleware("test"))
+	var forwarded string
+	engine.GET("/obj", func(ginCtx *gin.Context) {
+		ctx, span := StartClient(ginCtx.Request.Context(), "next-hop")
+		defer span.End()
+		out := http.Header{}
+		Inject(ctx, out)
+		forwarded = out.Get("traceparent")
+	})
+	req := httptest.NewRequest(http.MethodGet, "/obj", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	return forwarded
+}
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() {
+		require.NoError(t, Shutdown(context.Background()))
+		require.NoError(t, param.Reset())
+	})
+
+	t.Run("propagates-without-exporter", func(t *testing.T) {
+		require.NoError(t, Setup(context.Background(), "test"))
+		forwarded := serveTraced(t, http.Header{"Traceparent": {testTraceParent}})
+		// The trace and sampling decision are passed on unchanged
+		assert.Equal(t, testTraceParent, forwarded)
+		assert.Empty(t, serveTraced(t, nil))
+	})
+
+	t.Run("file-exporter", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "spans.json")
+		require.NoError(t, param.Tracing_Exporter.Set("file"))
+		require.NoError(t, param.Tracing_File.Set(filename))
+		require.NoError(t, Setup(context.Background(), "test"))
+
+		forwarded := serveTraced(t, http.Header{
+			"Traceparent":     {testTraceParent},
+			"X-Pelican-Jobid": {"job-1234"},
+		})
+		assert.Regexp(t, "^00-0af7651916cd43dd8448eb211c80319c-[0-9a-f]{16}-01$", forwarded)
+		assert.NotEqual(t, testTraceParent, forwarded)
+		require.NoError(t, Shutdown(context.Background()))
+
+		type span struct {
+			Name        string
+			SpanContext struct {
+				TraceID string
+				SpanID  string
+			}
+			Parent struct {
+				SpanID string
+			}
+			Attributes []struct {
+				Key   string
+				Value struct{ Value any }
+			}
+		}
+		file, err := os.Open(filename)
+		require.NoError(t, err)
+		defer file.Close()
+		spans := map[string]span{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var s span
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &s))
+			spans[s.Name] = s
+		}
+		require.NoError(t, scanner.Err())
+		require.Len(t, spans, 2)
+
+		server, client := spans["test GET"], spans["next-hop"]
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", server.SpanContext.TraceID)
+		assert.Equal(t, "b7ad6b7169203331", server.Parent.SpanID)
+		assert.Equal(t, server.SpanContext.SpanID, client.Parent.SpanID)
+		attrs := map[string]any{}
+		for _, attr := range server.Attributes {
+			attrs[attr.Key] = attr.Value.Value
+		}
+		assert.Equal(t, "job-1234", attrs[string(RequestIDKey)])
+		assert.Equal(t, float64(http.StatusOK), attrs["http.response.status_code"])
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		require.NoError(t, param.Tracing_Exporter.Set("file"))
+		require.NoError(t, param.Tracing_File.Set(filepath.Join(t.TempDir(), "spans.json")))
+		require.NoError(t, param.Tracing_SamplePercent.Set(0))
+		require.NoError(t, Setup(context.Background(), "test"))
+
+		// New traces aren't recorded, but sampled traces from callers are
+		assert.Regexp(t, "-00$", serveTraced(t, nil))
+		assert.Regexp(t, "-01$", serveTraced(t, http.Header{"Traceparent": {testTraceParent}}))
+	})
+
+	t.Run("invalid-config", func(t *testing.T) {
+		require.NoError(t, param.Tracing_Exporter.Set("file"))
+		require.NoError(t, param.Tracing_File.Set(""))
+		assert.Error(t, Setup(context.Background(), "test"))
+		require.NoError(t, param.Tracing_Exporter.Set("carrier-pigeon"))
+		assert.Error(t, Setup(context.Background(), "test"))
+
+		// Sample percentages outside of 0-100 are rejected rather than clamped
+		require.NoError(t, param.Tracing_Exporter.Set("file"))
+		require.NoError(t, param.Tracing_File.Set(filepath.Join(t.TempDir(), "spans.json")))
+		for _, samplePercent := range []int{-1, 101} {
+			require.NoError(t, param.Tracing_SamplePercent.Set(samplePercent))
+			assert.ErrorContains(t, Setup(context.Background(), "test"), "Tracing.SamplePercent")
+		}
+	})
+}
@@ -0,0 +1,441 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package web_ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/notifier"
+	"github.com/prometheus/prometheus/rules"
+	log "github.com/sirupsen/logrus"
+
+	pelican_config "github.com/pelicanplatform/pelican/config"
+	"github.com/pelicanplatform/pelican/param"
+)
+
+type (
+	// Delivers the alerts raised by the embedded Prometheus's alerting rules.
+	//
+	// The rule manager re-sends firing alerts every resend delay and resolved
+	// alerts for a while after they resolve.  Alertmanager expects that and
+	// receives every batch; the webhook and email only hear about changes.
+	alertNotifier struct {
+		webhookUrl      string
+		alertmanagerUrl *url.URL
+		emailRelay      string
+		emailFrom       string
+		emailTo         []string
+		serverUrl       string
+		client          *http.Client
+
+		targets []*alertTarget
+	}
+
+	// A single delivery target.  Each target has its own queue and delivery
+	// goroutine, so one that is slow or unreachable doesn't hold up the rest.
+	alertTarget struct {
+		name  string
+		queue chan []*notifier.Alert
+		// Set for targets that receive every batch
+		sendAll func(ctx context.Context, alerts []*notifier.Alert) error
+		// Set for targets that only hear about changes
+		sendChanges func(ctx context.Context, alerts []webhookAlert) error
+		// Hashes of the label sets of the alerts last successfully reported
+		// as firing to this target
+		firing map[uint64]bool
+	}
+
+	// An alert as sent to the webhook
+	webhookAlert struct {
+		Status       string            `json:"status"`
+		Labels       map[string]string `json:"labels"`
+		Annotations  map[string]string `json:"annotations"`
+		StartsAt     time.Time         `json:"startsAt"`
+		EndsAt       *time.Time        `json:"endsAt,omitempty"` // Set once the alert is resolved
+		GeneratorURL string            `json:"generatorURL,omitempty"`
+	}
+
+	webhookPayload struct {
+		Server string         `json:"server"`
+		Status string         `json:"status"`
+		Alerts []webhookAlert `json:"alerts"`
+	}
+
+	// A firing alert, as listed by the alerts API
+	FiringAlert struct {
+		Name        string            `json:"name"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+		Value       float64           `json:"value"`
+		ActiveAt    time.Time         `json:"activeAt"`
+		FiredAt     time.Time         `json:"firedAt"`
+	}
+)
+
+const (
+	alertStatusFiring   = "firing"
+	alertStatusResolved = "resolved"
+)
+
+var (
+	// The rule manager of the running embedded Prometheus, if it has rules
+	ruleManager atomic.Pointer[rules.Manager]
+
+	// The rule metrics are shared by every rule manager this process creates
+	ruleMetrics     *rules.Metrics
+	ruleMetricsOnce sync.Once
+
+	// How long to wait before retrying a failed delivery, and how many
+	// times to try
+	alertRetryDelay    = 10 * time.Second
+	alertDeliveryTries = 3
+)
+
+// Return the notifier configured by the Monitoring.Alert* parameters, or nil
+// if no delivery target is configured
+func newAlertNotifier() (*alertNotifier, error) {
+	n := &alertNotifier{
+		webhookUrl: param.Monitoring_AlertWebhookUrl.GetString(),
+		emailRelay: param.Monitoring_AlertEmailRelay.GetString(),
+		emailFrom:  param.Monitoring_AlertEmailFrom.GetString(),
+		emailTo:    param.Monitoring_AlertEmailTo.GetStringSlice(),
+		serverUrl:  param.Server_ExternalWebUrl.GetString(),
+		client:     pelican_config.GetClient(),
+	}
+	if amUrl := param.Monitoring_AlertmanagerUrl.GetString(); amUrl != "" {
+		parsed, err := url.Parse(amUrl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", param.Monitoring_AlertmanagerUrl.GetName())
+		}
+		n.alertmanagerUrl = parsed
+	}
+	if n.emailRelay != "" && (n.emailFrom == "" || len(n.emailTo) == 0) {
+		return nil, errors.Errorf("%s is set but %s or %s is not", param.Monitoring_AlertEmailRelay.GetName(),
+			param.Monitoring_AlertEmailFrom.GetName(), param.Monitoring_AlertEmailTo.GetName())
+	}
+	n.addTargets()
+	if len(n.targets) == 0 {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// Create a target for each configured destination
+func (n *alertNotifier) addTargets() {
+	newTarget := func(name string) *alertTarget {
+		target := &alertTarget{name: name, queue: make(chan []*notifier.Alert, 100)}
+		n.targets = append(n.targets, target)
+		return target
+	}
+	if n.alertmanagerUrl != nil {
+		newTarget("Alertmanager").sendAll = n.sendToAlertmanager
+	}
+	if n.webhookUrl != "" {
+		target := newTarget("the webhook")
+		target.sendChanges = n.sendToWebhook
+		target.firing = make(map[uint64]bool)
+	}
+	if n.emailRelay != "" {
+		target := newTarget("the email relay")
+		target.sendChanges = n.sendEmail
+		target.firing = make(map[uint64]bool)
+	}
+}
+
+// Queue alerts for delivery to every target; implements rules.Sender.  Rule
+// evaluation shouldn't wait on delivery, so a target whose queue is full
+// misses the alerts.
+func (n *alertNotifier) Send(alerts ...*notifier.Alert) {
+	for _, target := range n.targets {
+		select {
+		case target.queue <- alerts:
+		default:
+			log.Warningf("Alert delivery to %s is falling behind; dropping %d alerts", target.name, len(alerts))
+		}
+	}
+}
+
+func (n *alertNotifier) Alertmanagers() []*url.URL {
+	if n.alertmanagerUrl == nil {
+		return []*url.URL{}
+	}
+	return []*url.URL{n.alertmanagerUrl.JoinPath("/api/v2/alerts")}
+}
+
+func (n *alertNotifier) DroppedAlertmanagers() []*url.URL {
+	return []*url.URL{}
+}
+
+// Deliver queued alerts to every target until ctx is cancelled
+func (n *alertNotifier) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, target := range n.targets {
+		wg.Add(1)
+		go func(target *alertTarget) {
+			defer wg.Done()
+			target.run(ctx)
+		}(target)
+	}
+	wg.Wait()
+}
+
+func (t *alertTarget) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alerts := <-t.queue:
+			t.deliver(ctx, alerts)
+		}
+	}
+}
+
+func (t *alertTarget) deliver(ctx context.Context, alerts []*notifier.Alert) {
+	if t.sendAll != nil {
+		if err := retryAlertDelivery(ctx, func() error { return t.sendAll(ctx, alerts) }); err != nil {
+			log.Warningf("Failed to send alerts to %s: %v", t.name, err)
+		}
+		return
+	}
+
+	changed, firing := t.changedAlerts(alerts)
+	if len(changed) == 0 {
+		return
+	}
+	if err := retryAlertDelivery(ctx, func() error { return t.sendChanges(ctx, changed) }); err != nil {
+		// The state isn't recorded, so the rule manager's next re-send of
+		// these alerts reports the changes again
+		log.Warningf("Failed to send alerts to %s: %v", t.name, err)
+		return
+	}
+	t.firing = firing
+}
+
+// Return the alerts that started firing or were resolved since the last
+// successful delivery, along with the state to record once the changes are
+// delivered
+func (t *alertTarget) changedAlerts(alerts []*notifier.Alert) (changed []webhookAlert, firing map[uint64]bool) {
+	firing = make(map[uint64]bool, len(t.firing))
+	for hash := range t.firing {
+		firing[hash] = true
+	}
+	for _, alert := range alerts {
+		hash := alert.Labels.Hash()
+		resolved := alert.Resolved()
+		if resolved == !firing[hash] {
+			continue
+		}
+		wa := webhookAlert{
+			Status:       alertStatusFiring,
+			Labels:       alert.Labels.Map(),
+			Annotations:  alert.Annotations.Map(),
+			StartsAt:     alert.StartsAt,
+			GeneratorURL: alert.GeneratorURL,
+		}
+		if resolved {
+			wa.Status = alertStatusResolved
+			wa.EndsAt = &alert.EndsAt
+			delete(firing, hash)
+		} else {
+			firing[hash] = true
+		}
+		changed = append(changed, wa)
+	}
+	return
+}
+
+func retryAlertDelivery(ctx context.Context, send func() error) (err error) {
+	for try := 0; try < alertDeliveryTries; try++ {
+		if try > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(alertRetryDelay):
+			}
+		}
+		if err = send(); err == nil {
+			return
+		}
+	}
+	return
+}
+
+func (n *alertNotifier) post(ctx context.Context, target string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pelican-server/"+pelican_config.GetVersion())
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("%s responded with %s", target, resp.Status)
+	}
+	return nil
+}
+
+func (n *alertNotifier) sendToAlertmanager(ctx context.Context, alerts []*notifier.Alert) error {
+	return n.post(ctx, n.Alertmanagers()[0].String(), alerts)
+}
+
+func (n *alertNotifier) sendToWebhook(ctx context.Context, alerts []webhookAlert) error {
+	payload := webhookPayload{Server: n.serverUrl, Status: alertStatusResolved, Alerts: alerts}
+	for _, alert := range alerts {
+		if alert.Status == alertStatusFiring {
+			payload.Status = alertStatusFiring
+		}
+	}
+	return n.post(ctx, n.webhookUrl, payload)
+}
+
+func (n *alertNotifier) sendEmail(_ context.Context, alerts []webhookAlert) error {
+	return smtp.SendMail(n.emailRelay, nil, n.emailFrom, n.emailTo, n.buildEmail(alerts))
+}
+
+// Build the email describing the alerts
+func (n *alertNotifier) buildEmail(alerts []webhookAlert) []byte {
+	subject := ""
+	if len(alerts) == 1 {
+		subject = fmt.Sprintf("%s: %s", strings.ToUpper(alerts[0].Status), alerts[0].Labels[labels.AlertName])
+	} else {
+		numFiring := 0
+		for _, alert := range alerts {
+			if alert.Status == alertStatusFiring {
+				numFiring++
+			}
+		}
+		subject = fmt.Sprintf("%d alerts firing, %d resolved", numFiring, len(alerts)-numFiring)
+	}
+
+	body := &strings.Builder{}
+	fmt.Fprintf(body, "From: %s\r\n", n.emailFrom)
+	fmt.Fprintf(body, "To: %s\r\n", strings.Join(n.emailTo, ", "))
+	fmt.Fprintf(body, "Subject: [Pelican] %s\r\n", subject)
+	fmt.Fprintf(body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(body, "Alerts from %s:\r\n", n.serverUrl)
+	for _, alert := range alerts {
+		fmt.Fprintf(body, "\r\n[%s] %s\r\n", strings.ToUpper(alert.Status), alert.Labels[labels.AlertName])
+		fmt.Fprintf(body, "  Started: %s\r\n", alert.StartsAt.Format(time.RFC3339))
+		if alert.EndsAt != nil {
+			fmt.Fprintf(body, "  Resolved: %s\r\n", alert.EndsAt.Format(time.RFC3339))
+		}
+		writeSortedMap(body, alert.Annotations)
+		writeSortedMap(body, alert.Labels)
+	}
+	return []byte(body.String())
+}
+
+func writeSortedMap(body *strings.Builder, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(body, "  %s: %s\r\n", key, values[key])
+	}
+}
+
+// Load the rule files matching the globs in Monitoring.RuleFiles into the
+// manager, returning an error if a rule file can't be parsed
+func loadRuleFiles(manager *rules.Manager, externalUrl string) error {
+	files := []string{}
+	for _, pattern := range param.Monitoring_RuleFiles.GetStringSlice() {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid rule file pattern %q", pattern)
+		}
+		if len(matches) == 0 {
+			log.Warningf("No rule files match %q", pattern)
+		}
+		files = append(files, matches...)
+	}
+	interval := param.Monitoring_RuleEvaluationInterval.GetDuration()
+	if interval <= 0 {
+		return errors.Errorf("%s must be positive", param.Monitoring_RuleEvaluationInterval.GetName())
+	}
+	// Update only logs the reasons rules fail to load, so check them first
+	if _, errs := manager.LoadGroups(interval, labels.EmptyLabels(), externalUrl, nil, files...); len(errs) > 0 {
+		return errors.Wrap(errs[0], "failed to load the rule files")
+	}
+	return manager.Update(interval, files, labels.EmptyLabels(), externalUrl, nil)
+}
+
+func getRuleMetrics() *rules.Metrics {
+	ruleMetricsOnce.Do(func() {
+		ruleMetrics = rules.NewGroupMetrics(prometheus.DefaultRegisterer)
+	})
+	return ruleMetrics
+}
+
+// Return the alerts that are firing, oldest first
+func getFiringAlerts() []FiringAlert {
+	firing := []FiringAlert{}
+	manager := ruleManager.Load()
+	if manager == nil {
+		return firing
+	}
+	for _, rule := range manager.AlertingRules() {
+		for _, alert := range rule.ActiveAlerts() {
+			if alert.State != rules.StateFiring {
+				continue
+			}
+			firing = append(firing, FiringAlert{
+				Name:        rule.Name(),
+				Labels:      alert.Labels.Map(),
+				Annotations: alert.Annotations.Map(),
+				Value:       alert.Value,
+				ActiveAt:    alert.ActiveAt,
+				FiredAt:     alert.FiredAt,
+			})
+		}
+	}
+	sort.Slice(firing, func(i, j int) bool { return firing[i].FiredAt.Before(firing[j].FiredAt) })
+	return firing
+}
+
+// List the alerts that are firing
+func handleListFiringAlerts(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, getFiringAlerts())
+}
@@ -0,0 +1,357 @@
+/***************************************************************
+ *
+ * Copyright (C) 2026, Pelican Project, Morgridge Institute for Research
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you
+ * may not use this file except in compliance with the License.  You may
+ * obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ ***************************************************************/
+
+package web_ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/notifier"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pelicanplatform/pelican/param"
+	"github.com/pelicanplatform/pelican/server_utils"
+)
+
+// Accept SMTP sessions, returning the messages received
+func fakeSmtpRelay(t *testing.T) (addr string, message <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	msgChan := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSmtpSession(conn, msgChan)
+		}
+	}()
+	return ln.Addr().String(), msgChan
+}
+
+func serveSmtpSession(conn net.Conn, msgChan chan<- string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 relay ready")
+	data := &strings.Builder{}
+	for inData := false; ; {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch {
+		case inData && line == ".\r\n":
+			inData = false
+			msgChan <- data.String()
+			reply("250 queued")
+		case inData:
+			data.WriteString(line)
+		case strings.HasPrefix(line, "DATA"):
+			inData = true
+			reply("354 go ahead")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// Deliver the alerts to each of the notifier's targets in turn
+func deliverAlerts(n *alertNotifier, alerts ...*notifier.Alert) {
+	for _, target := range n.targets {
+		target.deliver(context.Background(), alerts)
+	}
+}
+
+func TestAlertNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var webhookPayloads []webhookPayload
+	var amBatches [][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/hook":
+			var payload webhookPayload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			webhookPayloads = append(webhookPayloads, payload)
+		case "/am/api/v2/alerts":
+			var batch []map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+			amBatches = append(amBatches, batch)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	amUrl, err := url.Parse(server.URL + "/am")
+	require.NoError(t, err)
+	relay, emails := fakeSmtpRelay(t)
+
+	n := &alertNotifier{
+		webhookUrl:      server.URL + "/hook",
+		alertmanagerUrl: amUrl,
+		emailRelay:      relay,
+		emailFrom:       "pelican@example.com",
+		emailTo:         []string{"admin@example.com"},
+		serverUrl:       "https://origin.example.com:8444",
+		client:          server.Client(),
+	}
+	n.addTargets()
+	require.Len(t, n.targets, 3)
+	started := time.Now().Add(-time.Minute)
+	alert := func(endsAt time.Time) *notifier.Alert {
+		return &notifier.Alert{
+			Labels:      labels.FromStrings(labels.AlertName, "OriginDown", "instance", "origin"),
+			Annotations: labels.FromStrings("summary", "The origin is down"),
+			StartsAt:    started,
+			EndsAt:      endsAt,
+		}
+	}
+
+	// A firing alert is sent everywhere once, but Alertmanager gets every re-send
+	deliverAlerts(n, alert(time.Now().Add(time.Hour)))
+	select {
+	case msg := <-emails:
+		assert.Contains(t, msg, "Subject: [Pelican] FIRING: OriginDown")
+		assert.Contains(t, msg, "summary: The origin is down")
+		assert.Contains(t, msg, "To: admin@example.com")
+	case <-time.After(5 * time.Second):
+		t.Fatal("No email was sent")
+	}
+	deliverAlerts(n, alert(time.Now().Add(time.Hour)))
+	resolvedAt := time.Now().Add(-time.Second)
+	deliverAlerts(n, alert(resolvedAt))
+	select {
+	case msg := <-emails:
+		assert.Contains(t, msg, "Subject: [Pelican] RESOLVED: OriginDown")
+	case <-time.After(5 * time.Second):
+		t.Fatal("No email was sent")
+	}
+	assert.Empty(t, emails)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, amBatches, 3)
+	require.Len(t, webhookPayloads, 2)
+	assert.Equal(t, "firing", webhookPayloads[0].Status)
+	assert.Equal(t, "https://origin.example.com:8444", webhookPayloads[0].Server)
+	require.Len(t, webhookPayloads[0].Alerts, 1)
+	assert.Equal(t, "OriginDown", webhookPayloads[0].Alerts[0].Labels[labels.AlertName])
+	assert.Nil(t, webhookPayloads[0].Alerts[0].EndsAt)
+	assert.Equal(t, "resolved", webhookPayloads[1].Status)
+	require.Len(t, webhookPayloads[1].Alerts, 1)
+	require.NotNil(t, webhookPayloads[1].Alerts[0].EndsAt)
+	assert.WithinDuration(t, resolvedAt, *webhookPayloads[1].Alerts[0].EndsAt, time.Millisecond)
+	for _, target := range n.targets {
+		assert.Empty(t, target.firing)
+	}
+}
+
+func TestAlertWebhookFailure(t *testing.T) {
+	oldDelay := alertRetryDelay
+	alertRetryDelay = time.Millisecond
+	t.Cleanup(func() { alertRetryDelay = oldDelay })
+
+	// The webhook is down until a request tells it otherwise, while
+	// Alertmanager hangs until the test ends
+	var webhookUp atomic.Bool
+	var webhookFailures atomic.Int32
+	webhookPayloads := make(chan webhookPayload, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook" {
+			<-release
+			return
+		}
+		if !webhookUp.Load() {
+			webhookFailures.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload webhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		webhookPayloads <- payload
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	amUrl, err := url.Parse(server.URL + "/am")
+	require.NoError(t, err)
+
+	n := &alertNotifier{
+		webhookUrl:      server.URL + "/hook",
+		alertmanagerUrl: amUrl,
+		client:          server.Client(),
+	}
+	n.addTargets()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	alert := &notifier.Alert{
+		Labels:   labels.FromStrings(labels.AlertName, "OriginDown"),
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(time.Hour),
+	}
+	// A failed delivery isn't recorded, so the firing alert is reported
+	// with the next re-send despite the Alertmanager that never responds
+	n.Send(alert)
+	require.Eventually(t, func() bool { return webhookFailures.Load() == int32(alertDeliveryTries) }, 5*time.Second, time.Millisecond)
+	webhookUp.Store(true)
+	n.Send(alert)
+	select {
+	case payload := <-webhookPayloads:
+		assert.Equal(t, "firing", payload.Status)
+		require.Len(t, payload.Alerts, 1)
+		assert.Equal(t, "OriginDown", payload.Alerts[0].Labels[labels.AlertName])
+	case <-time.After(5 * time.Second):
+		t.Fatal("The alert was not re-sent to the webhook")
+	}
+}
+
+func TestAlertmanagerRetry(t *testing.T) {
+	oldDelay := alertRetryDelay
+	alertRetryDelay = time.Millisecond
+	t.Cleanup(func() { alertRetryDelay = oldDelay })
+
+	// Alertmanager fails the first request
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+	amUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	n := &alertNotifier{
+		alertmanagerUrl: amUrl,
+		client:          server.Client(),
+	}
+	n.addTargets()
+	deliverAlerts(n, &notifier.Alert{
+		Labels:   labels.FromStrings(labels.AlertName, "OriginDown"),
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(time.Hour),
+	})
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestNewAlertNotifier(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+
+	n, err := newAlertNotifier()
+	require.NoError(t, err)
+	assert.Nil(t, n, "no notifier is needed without delivery targets")
+
+	require.NoError(t, param.Monitoring_AlertEmailRelay.Set("localhost:25"))
+	_, err = newAlertNotifier()
+	assert.ErrorContains(t, err, param.Monitoring_AlertEmailFrom.GetName())
+
+	require.NoError(t, param.Monitoring_AlertEmailFrom.Set("pelican@example.com"))
+	require.NoError(t, param.Monitoring_AlertEmailTo.Set([]string{"admin@example.com"}))
+	require.NoError(t, param.Monitoring_AlertmanagerUrl.Set("https://alertmanager.example.com:9093"))
+	n, err = newAlertNotifier()
+	require.NoError(t, err)
+	require.NotNil(t, n)
+	require.Len(t, n.Alertmanagers(), 1)
+	assert.Equal(t, "https://alertmanager.example.com:9093/api/v2/alerts", n.Alertmanagers()[0].String())
+}
+
+func TestLoadRuleFiles(t *testing.T) {
+	server_utils.ResetTestState()
+	t.Cleanup(server_utils.ResetTestState)
+	require.NoError(t, param.Monitoring_RuleEvaluationInterval.Set(time.Hour))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "good.yaml"), []byte(`
+groups:
+  - name: origin
+    rules:
+      - alert: OriginUnhealthy
+        expr: pelican_component_health_status{component="xrootd"} < 3
+        for: 5m
+      - record: pelican:health:min
+        expr: min(pelican_component_health_status)
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yml"), []byte(`
+groups:
+  - name: broken
+    rules:
+      - alert: Broken
+        expr: sum(
+`), 0644))
+
+	newManager := func(t *testing.T) *rules.Manager {
+		manager := rules.NewManager(&rules.ManagerOptions{
+			Context: context.Background(),
+			Logger:  log.NewNopLogger(),
+			Metrics: getRuleMetrics(),
+		})
+		go manager.Run()
+		t.Cleanup(manager.Stop)
+		return manager
+	}
+
+	require.NoError(t, param.Monitoring_RuleFiles.Set([]string{filepath.Join(dir, "*.yaml")}))
+	manager := newManager(t)
+	require.NoError(t, loadRuleFiles(manager, "https://origin.example.com:8444"))
+	require.Len(t, manager.RuleGroups(), 1)
+	assert.Len(t, manager.RuleGroups()[0].Rules(), 2)
+	assert.Len(t, manager.AlertingRules(), 1)
+
+	// No alerts have fired yet
+	ruleManager.Store(manager)
+	t.Cleanup(func() { ruleManager.Store(nil) })
+	assert.Empty(t, getFiringAlerts())
+
+	require.NoError(t, param.Monitoring_RuleFiles.Set([]string{filepath.Join(dir, "*")}))
+	err := loadRuleFiles(newManager(t), "https://origin.example.com:8444")
+	assert.ErrorContains(t, err, "bad.yml")
+}
@@ -538,7 +538,7 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 
 	var (
 		//ctxWeb, cancelWeb = context.WithCancel(context.Background())
-		//ctxRule           = context.Background()
+		ctxRule = context.Background()
 
 		ctxScrape, cancelScrape = context.WithCancel(context.Background())
 		discoveryManagerScrape  discoveryManager
@@ -574,6 +574,41 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 	}
 	scraper.Set(scrapeManager)
 
+	// Evaluate the operator's recording and alerting rules, if any, and
+	// deliver the resulting alerts
+	var (
+		ruleMgr       *rules.Manager
+		alertNotifier *alertNotifier
+	)
+	if len(param.Monitoring_RuleFiles.GetStringSlice()) > 0 {
+		if alertNotifier, err = newAlertNotifier(); err != nil {
+			cancelScrape()
+			return err
+		}
+		notifyFunc := func(context.Context, string, ...*rules.Alert) {}
+		if alertNotifier != nil {
+			notifyFunc = rules.SendAlerts(alertNotifier, external_url.String())
+		}
+		ruleMgr = rules.NewManager(&rules.ManagerOptions{
+			Appendable:      fanoutStorage,
+			Queryable:       localStorage,
+			QueryFunc:       rules.EngineQueryFunc(queryEngine, fanoutStorage),
+			NotifyFunc:      notifyFunc,
+			Context:         ctxRule,
+			ExternalURL:     external_url,
+			Registerer:      prometheus.DefaultRegisterer,
+			Logger:          log.With(logger, "component", "rule manager"),
+			OutageTolerance: time.Duration(cfg.outageTolerance),
+			ForGracePeriod:  time.Duration(cfg.forGracePeriod),
+			ResendDelay:     time.Duration(cfg.resendDelay),
+			Metrics:         getRuleMetrics(),
+		})
+		if err = loadRuleFiles(ruleMgr, external_url.String()); err != nil {
+			cancelScrape()
+			return err
+		}
+	}
+
 	TSDBDir := localStoragePath
 
 	Version := &web.PrometheusVersion{
@@ -597,8 +632,18 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 
 	factorySPr := func(_ context.Context) api_v1.ScrapePoolsRetriever { return scrapeManager }
 	factoryTr := func(_ context.Context) api_v1.TargetRetriever { return scrapeManager }
-	factoryAr := func(_ context.Context) api_v1.AlertmanagerRetriever { return stubAlertmanagerRetriever{} }
-	factoryRr := func(_ context.Context) api_v1.RulesRetriever { return stubRulesRetriever{} }
+	factoryAr := func(_ context.Context) api_v1.AlertmanagerRetriever {
+		if alertNotifier != nil {
+			return alertNotifier
+		}
+		return stubAlertmanagerRetriever{}
+	}
+	factoryRr := func(_ context.Context) api_v1.RulesRetriever {
+		if ruleMgr != nil {
+			return ruleMgr
+		}
+		return stubRulesRetriever{}
+	}
 
 	readyHandler := ReadyHandler{}
 	readyHandler.SetReady(false)
@@ -987,6 +1032,36 @@ func ConfigureEmbeddedPrometheus(ctx context.Context, engine *gin.Engine, dialCo
 			},
 		)
 	}
+	if ruleMgr != nil {
+		// Rule manager.
+		g.Add(
+			func() error {
+				// Rules are only evaluated once the TSDB is open and the
+				// config is loaded
+				<-reloadReady.C
+				ruleManager.Store(ruleMgr)
+				ruleMgr.Run()
+				return nil
+			},
+			func(err error) {
+				ruleManager.CompareAndSwap(ruleMgr, nil)
+				ruleMgr.Stop()
+			},
+		)
+	}
+	if alertNotifier != nil {
+		// Alert delivery.
+		notifyCtx, notifyCancel := context.WithCancel(context.Background())
+		g.Add(
+			func() error {
+				alertNotifier.run(notifyCtx)
+				return nil
+			},
+			func(err error) {
+				notifyCancel()
+			},
+		)
+	}
 	go func() {
 		if err := g.Run(); err != nil {
 			err = level.Error(logger).Log("err", err)
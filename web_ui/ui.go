@@ -761,6 +761,7 @@ func configureMetrics(engine *gin.Engine) error {
 	} else {
 		engine.GET("/api/v1.0/metrics/health", AuthHandler, AdminAuthHandler, healthFunc)
 	}
+	engine.GET("/api/v1.0/metrics/alerts", AuthHandler, AdminAuthHandler, handleListFiringAlerts)
 	return nil
 }
 